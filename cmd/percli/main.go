@@ -17,17 +17,21 @@ import (
 	"os"
 
 	"github.com/perses/perses/internal/cli/cmd/apply"
+	"github.com/perses/perses/internal/cli/cmd/auth"
 	"github.com/perses/perses/internal/cli/cmd/conf"
 	"github.com/perses/perses/internal/cli/cmd/dac"
 	"github.com/perses/perses/internal/cli/cmd/describe"
+	"github.com/perses/perses/internal/cli/cmd/export"
 	"github.com/perses/perses/internal/cli/cmd/get"
 	"github.com/perses/perses/internal/cli/cmd/lint"
 	"github.com/perses/perses/internal/cli/cmd/login"
 	"github.com/perses/perses/internal/cli/cmd/migrate"
 	"github.com/perses/perses/internal/cli/cmd/plugin"
 	"github.com/perses/perses/internal/cli/cmd/project"
+	"github.com/perses/perses/internal/cli/cmd/projecttemplate"
 	"github.com/perses/perses/internal/cli/cmd/refresh"
 	"github.com/perses/perses/internal/cli/cmd/remove"
+	"github.com/perses/perses/internal/cli/cmd/user"
 	"github.com/perses/perses/internal/cli/cmd/version"
 	"github.com/perses/perses/internal/cli/cmd/whoami"
 	"github.com/perses/perses/internal/cli/config"
@@ -48,17 +52,21 @@ func newRootCommand() *cobra.Command {
 
 	// The list of supported commands
 	cmd.AddCommand(apply.NewCMD())
+	cmd.AddCommand(auth.NewCMD())
 	cmd.AddCommand(conf.NewCMD())
 	cmd.AddCommand(dac.NewCMD())
 	cmd.AddCommand(describe.NewCMD())
+	cmd.AddCommand(export.NewCMD())
 	cmd.AddCommand(get.NewCMD())
 	cmd.AddCommand(lint.NewCMD())
 	cmd.AddCommand(login.NewCMD())
 	cmd.AddCommand(migrate.NewCMD())
 	cmd.AddCommand(plugin.NewCMD())
 	cmd.AddCommand(project.NewCMD())
+	cmd.AddCommand(projecttemplate.NewCMD())
 	cmd.AddCommand(refresh.NewCMD())
 	cmd.AddCommand(remove.NewCMD())
+	cmd.AddCommand(user.NewCMD())
 	cmd.AddCommand(version.NewCMD())
 	cmd.AddCommand(whoami.NewCMD())
 
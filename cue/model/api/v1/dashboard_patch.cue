@@ -43,6 +43,21 @@ import (
 	duration:         common.#DurationString | *"1h" @go(Duration)
 	refreshInterval?: common.#DurationString         @go(RefreshInterval)
 	links?: [...#Link] @go(Links,[]Link)
+	annotations?: [...#Annotation] @go(Annotations,[]Annotation)
+}
+
+#Annotation: {
+	kind: "Annotation" @go(Kind)
+	spec: {
+		display: {
+			name:         string @go(Name)
+			description?: string @go(Description)
+			hidden?:      bool   @go(Hidden)
+		} @go(Display,*AnnotationDisplay)
+		enabled: bool           @go(Enabled)
+		color?:  string         @go(Color)
+		plugin:  common.#Plugin @go(Plugin)
+	} @go(Spec,AnnotationSpec)
 }
 
 #Dashboard: {
@@ -16,7 +16,9 @@ package v1
 	#KindGlobalRoleBinding |
 	#KindGlobalVariable |
 	#KindGlobalSecret |
+	#KindGroup |
 	#KindProject |
+	#KindProjectTemplate |
 	#KindRole |
 	#KindRoleBinding |
 	#KindSecret |
@@ -32,7 +34,9 @@ package v1
 #KindGlobalRoleBinding:  #Kind & "GlobalRoleBinding"
 #KindGlobalVariable:     #Kind & "GlobalVariable"
 #KindGlobalSecret:       #Kind & "GlobalSecret"
+#KindGroup:              #Kind & "Group"
 #KindProject:            #Kind & "Project"
+#KindProjectTemplate:    #Kind & "ProjectTemplate"
 #KindRole:               #Kind & "Role"
 #KindRoleBinding:        #Kind & "RoleBinding"
 #KindSecret:             #Kind & "Secret"
@@ -6,8 +6,24 @@ package v1
 
 import "github.com/perses/perses/cue/model/api/v1/common"
 
+// ProjectTemplateReference is the ProjectTemplate a project is created from.
+#ProjectTemplateReference: {
+	// Name of the ProjectTemplate.
+	name: string @go(Name)
+
+	// Parameters are the values given to the parameters of the template.
+	parameters?: {[string]: string} @go(Parameters,map[string]string)
+
+	// Owner is the user who instantiated the template. It's set by the server and used as `.Username` when the
+	// template is instantiated again, so syncing the template doesn't give the project to the user running the sync.
+	owner?: string @go(Owner)
+}
+
 #ProjectSpec: {
 	display?: null | common.#Display @go(Display,*common.Display)
+
+	// Template is the ProjectTemplate instantiated when the project is created or when the template is synced.
+	template?: null | #ProjectTemplateReference @go(Template,*ProjectTemplateReference)
 }
 
 #Project: _
@@ -7,7 +7,9 @@ package v1
 import "github.com/perses/perses/cue/model/api/v1/secret"
 
 #PublicNativeProvider: {
-	password?: secret.#Hidden @go(Password)
+	password?:           secret.#Hidden @go(Password)
+	mustChangePassword?: bool           @go(MustChangePassword)
+	twoFactorEnabled?:   bool           @go(TwoFactorEnabled)
 }
 
 #PublicUserSpec: {
@@ -15,6 +17,9 @@ import "github.com/perses/perses/cue/model/api/v1/secret"
 	lastName?:       string                @go(LastName)
 	nativeProvider?: #PublicNativeProvider @go(NativeProvider)
 	oauthProviders?: [...#OAuthProvider] @go(OauthProviders,[]OAuthProvider)
+	disabled?:       bool                  @go(Disabled)
+	disabledReason?: string                @go(DisabledReason)
+	disabledAt?:     null | string         @go(DisabledAt,*time.Time)
 }
 
 #PublicUser: {
@@ -16,7 +16,9 @@ package role
 	#GlobalRoleBindingScope |
 	#GlobalSecretScope |
 	#GlobalVariableScope |
+	#GroupScope |
 	#ProjectScope |
+	#ProjectTemplateScope |
 	#RoleScope |
 	#RoleBindingScope |
 	#SecretScope |
@@ -33,7 +35,9 @@ package role
 #GlobalRoleBindingScope:  #Scope & "GlobalRoleBinding"
 #GlobalSecretScope:       #Scope & "GlobalSecret"
 #GlobalVariableScope:     #Scope & "GlobalVariable"
+#GroupScope:              #Scope & "Group"
 #ProjectScope:            #Scope & "Project"
+#ProjectTemplateScope:    #Scope & "ProjectTemplate"
 #RoleScope:               #Scope & "Role"
 #RoleBindingScope:        #Scope & "RoleBinding"
 #SecretScope:             #Scope & "Secret"
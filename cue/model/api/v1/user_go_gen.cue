@@ -6,8 +6,45 @@ package v1
 
 #WildcardProject: "*"
 
+// PasswordResetToken is the one-time token generated when an admin resets the password of a user.
+// Only the hash of the token is stored.
+#PasswordResetToken: {
+	hash:      string @go(Hash)
+	expiresAt: string @go(ExpiresAt,time.Time)
+}
+
+// TOTP contains the time-based one-time password configuration of a user, used as a second authentication factor.
+#TOTP: {
+	// Secret is the secret shared with the authenticator app of the user, encrypted with the encryption key of the server.
+	secret: string @go(Secret)
+
+	// Enabled is false as long as the user didn't confirm the enrollment with a valid code.
+	enabled?: bool @go(Enabled)
+
+	// RecoveryCodes contains the hashes of the recovery codes that haven't been used yet.
+	recoveryCodes?: [...string] @go(RecoveryCodes,[]string)
+
+	// LastUsedStep is the time step of the last code accepted. It is used to prevent a code from being used twice.
+	lastUsedStep?: int64 @go(LastUsedStep)
+}
+
 #NativeProvider: {
 	password?: string @go(Password)
+
+	// PasswordHistory contains the hashes of the previous passwords, the most recent first.
+	// It is managed by the server and used to prevent the reuse of a previous password.
+	passwordHistory?: [...string] @go(PasswordHistory,[]string)
+
+	// MustChangePassword forces the user to change the password before being able to log in again.
+	mustChangePassword?: bool @go(MustChangePassword)
+
+	// ResetToken is set when an admin resets the password of the user.
+	// It is managed by the server.
+	resetToken?: null | #PasswordResetToken @go(ResetToken,*PasswordResetToken)
+
+	// TOTP is set when the user enrolls an authenticator app for the two-factor authentication.
+	// It is managed by the server.
+	totp?: null | #TOTP @go(TOTP,*TOTP)
 }
 
 #OAuthProvider: {
@@ -21,6 +58,15 @@ package v1
 	lastName?:       string          @go(LastName)
 	nativeProvider?: #NativeProvider @go(NativeProvider)
 	oauthProviders?: [...#OAuthProvider] @go(OauthProviders,[]OAuthProvider)
+
+	// Disabled prevents the user from logging in and removes all the permissions of the user.
+	disabled?: bool @go(Disabled)
+
+	// DisabledReason explains why the user has been suspended.
+	disabledReason?: string @go(DisabledReason)
+
+	// DisabledAt is the time when the user has been suspended. It is managed by the server.
+	disabledAt?: null | string @go(DisabledAt,*time.Time)
 }
 
 #User: _
@@ -21,9 +21,11 @@ import (
 	"github.com/perses/perses/internal/api/crypto"
 	"github.com/perses/perses/internal/api/interface/v1/globalrole"
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
+	"github.com/perses/perses/internal/api/interface/v1/group"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/user"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
@@ -75,6 +77,11 @@ type Authorization interface {
 	// Be aware that this function cannot be called from an anonymous endpoint.
 	// In case the user information is not found in the context, the implementation should return an error.
 	GetPermissions(ctx echo.Context) (map[string][]*v1Role.Permission, error)
+	// CanI evaluates if the subject of the request (a user, groups or both) can perform the action on the scope in the project.
+	// Contrary to HasPermission, the subject is not the user from the context, and the result explains which bindings and roles
+	// granted or failed to grant the permission. It is meant to be used by admins to debug the RBAC configuration.
+	// The context is only used to carry the request context, and the caller is expected to check the permissions of the user beforehand.
+	CanI(ctx echo.Context, request *modelAPI.CanIRequest) (*modelAPI.CanIResponse, error)
 	// RefreshPermissions refreshes the permissions.
 	// We know this method is relative to the implementation and should not appear in the interface.
 	// This is convenient to have it here when the implementation is keeping the permissions in memory.
@@ -84,7 +91,7 @@ type Authorization interface {
 }
 
 func New(userDAO user.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
-	globalRoleDAO globalrole.DAO, globalRoleBindingDAO globalrolebinding.DAO, conf config.Config) (Authorization, error) {
+	globalRoleDAO globalrole.DAO, globalRoleBindingDAO globalrolebinding.DAO, groupDAO group.DAO, conf config.Config) (Authorization, error) {
 	// If the higher level auth enabled is false then ignore all authorization configuration
 	if !conf.Security.EnableAuth {
 		return &disabledImpl{}, nil
@@ -95,6 +102,6 @@ func New(userDAO user.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
 	}
 
 	// If no providers are explicitly set but auth is enabled, then use the perses native authz
-	return native.New(userDAO, roleDAO, roleBindingDAO, globalRoleDAO, globalRoleBindingDAO, conf)
+	return native.New(userDAO, roleDAO, roleBindingDAO, globalRoleDAO, globalRoleBindingDAO, groupDAO, conf)
 
 }
@@ -15,10 +15,12 @@ package authorization
 
 import (
 	"context"
+	"maps"
 	"time"
 
 	"github.com/perses/common/async"
 	"github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/user"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
 )
@@ -32,36 +34,73 @@ type rbacTask struct {
 	authz           Authorization
 	persesDAO       model.DAO
 	lastRefreshTime time.Time
+	// The users are saved for many reasons that don't change the permissions (a login with a TOTP code, for example).
+	// So instead of refreshing the cache each time the users are updated, only the users and their disabled state
+	// are compared with the ones seen at the previous check.
+	lastUserCheckTime time.Time
+	users             map[string]bool
 }
 
 func (r *rbacTask) Execute(_ context.Context, _ context.CancelFunc) error {
-	lastUpdateTime, err := r.persesDAO.GetLatestUpdateTime([]modelV1.Kind{modelV1.KindRole, modelV1.KindRoleBinding, modelV1.KindGlobalRole, modelV1.KindGlobalRoleBinding})
+	lastUpdateTimeParsed, err := r.getLatestUpdateTime([]modelV1.Kind{modelV1.KindRole, modelV1.KindRoleBinding, modelV1.KindGlobalRole, modelV1.KindGlobalRoleBinding, modelV1.KindGroup})
 	if err != nil {
 		logrus.WithError(err).Error("failed to retrieve last update time")
 		return nil
 	}
 
+	usersChanged := r.haveUsersChanged()
+	if r.lastRefreshTime.Before(lastUpdateTimeParsed) || usersChanged {
+		logrus.Debugf("refreshing rbac cache, previous last refresh time %v", r.lastRefreshTime)
+		if err := r.authz.RefreshPermissions(); err != nil {
+			logrus.WithError(err).Error("failed to refresh cache")
+			// The users are compared again at the next execution, so their changes are not lost.
+			r.lastUserCheckTime = time.Time{}
+			r.users = nil
+			return nil
+		}
+		r.lastRefreshTime = lastUpdateTimeParsed
+	}
+	return nil
+}
+
+func (r *rbacTask) getLatestUpdateTime(kinds []modelV1.Kind) (time.Time, error) {
+	lastUpdateTime, err := r.persesDAO.GetLatestUpdateTime(kinds)
+	if err != nil {
+		return time.Time{}, err
+	}
+
 	// If tables have never been updated, using older date than now and that can't be wrong with any timezones
 	if lastUpdateTime == nil {
 		timestampZero := "1970-01-01 12:00:00"
 		lastUpdateTime = &timestampZero
 	}
 
-	lastUpdateTimeParsed, err := time.Parse("2006-01-02 15:04:05", *lastUpdateTime)
+	return time.Parse("2006-01-02 15:04:05", *lastUpdateTime)
+}
+
+// haveUsersChanged returns true when a user has been created, deleted, disabled or enabled since the previous check.
+func (r *rbacTask) haveUsersChanged() bool {
+	lastUpdateTime, err := r.getLatestUpdateTime([]modelV1.Kind{modelV1.KindUser})
 	if err != nil {
-		logrus.WithError(err).Error("failed to parse last update time")
-		return nil
+		logrus.WithError(err).Error("failed to retrieve last update time of the users")
+		return false
 	}
-
-	if r.lastRefreshTime.Before(lastUpdateTimeParsed) {
-		logrus.Debugf("refreshing rbac cache, previous last refresh time %v", r.lastRefreshTime)
-		if err := r.authz.RefreshPermissions(); err != nil {
-			logrus.WithError(err).Error("failed to refresh cache")
-			return nil
-		}
-		r.lastRefreshTime = lastUpdateTimeParsed
+	if !r.lastUserCheckTime.Before(lastUpdateTime) {
+		return false
 	}
-	return nil
+	var users []*modelV1.User
+	if queryErr := r.persesDAO.Query(&user.Query{}, &users); queryErr != nil {
+		logrus.WithError(queryErr).Error("failed to retrieve the users")
+		return false
+	}
+	current := make(map[string]bool, len(users))
+	for _, usr := range users {
+		current[usr.Metadata.Name] = usr.Spec.Disabled
+	}
+	changed := !maps.Equal(current, r.users)
+	r.users = current
+	r.lastUserCheckTime = lastUpdateTime
+	return changed
 }
 
 func (r *rbacTask) String() string {
@@ -0,0 +1,85 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/perses/perses/internal/api/database/model"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRefreshAuthorization struct {
+	Authorization
+	refreshCount int
+}
+
+func (a *fakeRefreshAuthorization) RefreshPermissions() error {
+	a.refreshCount++
+	return nil
+}
+
+type fakeDAO struct {
+	model.DAO
+	roleUpdateTime string
+	userUpdateTime string
+	users          []*modelV1.User
+}
+
+func (d *fakeDAO) GetLatestUpdateTime(kinds []modelV1.Kind) (*string, error) {
+	if len(kinds) == 1 && kinds[0] == modelV1.KindUser {
+		return &d.userUpdateTime, nil
+	}
+	return &d.roleUpdateTime, nil
+}
+
+func (d *fakeDAO) Query(_ model.Query, slice any) error {
+	*(slice.(*[]*modelV1.User)) = d.users
+	return nil
+}
+
+func TestRBACTaskUserChanges(t *testing.T) {
+	authz := &fakeRefreshAuthorization{}
+	dao := &fakeDAO{
+		roleUpdateTime: "2026-01-01 00:00:00",
+		userUpdateTime: "2026-01-01 00:00:00",
+		users:          []*modelV1.User{{Metadata: modelV1.Metadata{Name: "jdoe"}}},
+	}
+	task := &rbacTask{authz: authz, persesDAO: dao}
+	execute := func() {
+		assert.NoError(t, task.Execute(context.Background(), nil))
+	}
+	execute()
+	assert.Equal(t, 1, authz.refreshCount)
+
+	// A user saved without any change of its state doesn't refresh the permissions
+	dao.userUpdateTime = "2026-01-01 00:01:00"
+	execute()
+	assert.Equal(t, 1, authz.refreshCount)
+
+	// Disabling a user does
+	dao.userUpdateTime = "2026-01-01 00:02:00"
+	dao.users = []*modelV1.User{{Metadata: modelV1.Metadata{Name: "jdoe"}, Spec: modelV1.UserSpec{Disabled: true}}}
+	execute()
+	assert.Equal(t, 2, authz.refreshCount)
+
+	// Like any change of the roles
+	dao.roleUpdateTime = "2026-01-01 00:03:00"
+	execute()
+	assert.Equal(t, 3, authz.refreshCount)
+	execute()
+	assert.Equal(t, 3, authz.refreshCount)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/perses/perses/internal/api/crypto"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
 )
 
@@ -67,6 +68,10 @@ func (r *disabledImpl) GetPermissions(_ echo.Context) (map[string][]*v1Role.Perm
 	return nil, nil
 }
 
+func (r *disabledImpl) CanI(_ echo.Context, _ *modelAPI.CanIRequest) (*modelAPI.CanIResponse, error) {
+	return &modelAPI.CanIResponse{Allowed: true, Reason: "authorization is disabled"}, nil
+}
+
 func (r *disabledImpl) RefreshPermissions() error {
 	return nil
 }
@@ -0,0 +1,88 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// CanI implements [Authorization].
+// Every check is a SubjectAccessReview, and its reason, when provided by the Kubernetes authorizer,
+// names the RoleBinding or ClusterRoleBinding and the Role or ClusterRole that allowed the request.
+func (k *k8sImpl) CanI(ctx echo.Context, request *modelAPI.CanIRequest) (*modelAPI.CanIResponse, error) {
+	subject := newSubject(request.User, request.Groups)
+	scopes := []v1Role.Scope{request.Scope}
+	if request.Scope == v1Role.ProjectScope {
+		// Like checkNamespaceAccess, the access to a project is the access to any Perses resource in the namespace.
+		scopes = projectScopesToCheck
+	}
+	result := &modelAPI.CanIResponse{}
+	for _, scope := range scopes {
+		attributes := k.getAttributes(request.Project, subject, request.Action, scope)
+		decision, reason, err := k.authorizer.Authorize(ctx.Request().Context(), attributes)
+		if err != nil {
+			return nil, err
+		}
+		evaluation := modelAPI.CanIEvaluation{
+			Project:  attributes.Namespace,
+			Resource: formatResource(attributes),
+			Allowed:  decision == authorizer.DecisionAllow,
+			Reason:   reason,
+		}
+		if len(evaluation.Reason) == 0 {
+			evaluation.Reason = fmt.Sprintf("%q on %q is not allowed", attributes.Verb, evaluation.Resource)
+			if evaluation.Allowed {
+				evaluation.Reason = fmt.Sprintf("%q on %q is allowed", attributes.Verb, evaluation.Resource)
+			}
+		}
+		result.Evaluations = append(result.Evaluations, evaluation)
+		if evaluation.Allowed {
+			result.Allowed = true
+			result.Reason = evaluation.Reason
+			return result, nil
+		}
+	}
+	result.Reason = "no Kubernetes RBAC rule allows the request"
+	return result, nil
+}
+
+// newSubject returns the Kubernetes user to evaluate.
+// Like the Kubernetes authenticator, it adds the groups that every authenticated user
+// and every service account belong to.
+func newSubject(username string, groups []string) user.Info {
+	allGroups := slices.Clone(groups)
+	if namespace, _, err := serviceaccount.SplitUsername(username); err == nil {
+		allGroups = append(allGroups, serviceaccount.MakeGroupNames(namespace)...)
+	}
+	if !slices.Contains(allGroups, user.AllAuthenticated) {
+		allGroups = append(allGroups, user.AllAuthenticated)
+	}
+	return &user.DefaultInfo{Name: username, Groups: allGroups}
+}
+
+// formatResource returns the resource as written in a Kubernetes Role, i.e. <resource>.<group>.
+func formatResource(attributes authorizer.AttributesRecord) string {
+	if len(attributes.APIGroup) == 0 {
+		return attributes.Resource
+	}
+	return fmt.Sprintf("%s.%s", attributes.Resource, attributes.APIGroup)
+}
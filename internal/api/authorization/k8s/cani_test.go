@@ -0,0 +1,96 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// folderAuthorizer only allows the access to the folders and records the attributes it is called with.
+type folderAuthorizer struct {
+	attributes []authorizer.Attributes
+}
+
+func (f *folderAuthorizer) Authorize(_ context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	f.attributes = append(f.attributes, attr)
+	if attr.GetResource() == string(k8sFolderScope) {
+		return authorizer.DecisionAllow, `RBAC: allowed by RoleBinding "folders/perses" of ClusterRole "folder-editor" to ServiceAccount "sync/perses"`, nil
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+func TestCanI(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader(""))
+	ctx := e.NewContext(req, httptest.NewRecorder())
+
+	testSuites := []struct {
+		title          string
+		request        *modelAPI.CanIRequest
+		expected       *modelAPI.CanIResponse
+		expectedGroups []string
+	}{
+		{
+			title:   "denied",
+			request: &modelAPI.CanIRequest{User: "user0", Action: v1Role.CreateAction, Scope: v1Role.DashboardScope, Project: projectZero},
+			expected: &modelAPI.CanIResponse{
+				Reason: "no Kubernetes RBAC rule allows the request",
+				Evaluations: []modelAPI.CanIEvaluation{
+					{Project: projectZero, Resource: "persesdashboards.perses.dev", Reason: `"create" on "persesdashboards.perses.dev" is not allowed`},
+				},
+			},
+			expectedGroups: []string{"system:authenticated"},
+		},
+		{
+			title:   "project access from a service account",
+			request: &modelAPI.CanIRequest{User: "system:serviceaccount:sync:perses", Action: v1Role.ReadAction, Scope: v1Role.ProjectScope, Project: projectZero},
+			expected: &modelAPI.CanIResponse{
+				Allowed: true,
+				Reason:  `RBAC: allowed by RoleBinding "folders/perses" of ClusterRole "folder-editor" to ServiceAccount "sync/perses"`,
+				Evaluations: []modelAPI.CanIEvaluation{
+					{Project: projectZero, Resource: "persesdashboards.perses.dev", Reason: `"get" on "persesdashboards.perses.dev" is not allowed`},
+					{Project: projectZero, Resource: "persesdatasources.perses.dev", Reason: `"get" on "persesdatasources.perses.dev" is not allowed`},
+					{Project: projectZero, Resource: "secrets", Reason: `"get" on "secrets" is not allowed`},
+					{Project: projectZero, Resource: "persesvariables.perses.dev", Reason: `"get" on "persesvariables.perses.dev" is not allowed`},
+					{Project: projectZero, Resource: "persesephemeraldashboards.perses.dev", Reason: `"get" on "persesephemeraldashboards.perses.dev" is not allowed`},
+					{Project: projectZero, Resource: "persesfolders.perses.dev", Allowed: true, Reason: `RBAC: allowed by RoleBinding "folders/perses" of ClusterRole "folder-editor" to ServiceAccount "sync/perses"`},
+				},
+			},
+			expectedGroups: []string{"system:serviceaccounts", "system:serviceaccounts:sync", "system:authenticated"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			authz := &folderAuthorizer{}
+			k := &k8sImpl{authorizer: authz, resources: newK8sResources(nil)}
+			result, err := k.CanI(ctx, test.request)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+			for _, attr := range authz.attributes {
+				assert.Equal(t, test.request.User, attr.GetUser().GetName())
+				assert.Equal(t, test.expectedGroups, attr.GetUser().GetGroups())
+			}
+		})
+	}
+}
@@ -79,6 +79,7 @@ func New(conf config.Config) (*k8sImpl, error) {
 		authenticator: kubernetesAuthenticator,
 		authorizer:    k8sAuthorizer,
 		kubeClient:    kubeClient,
+		resources:     newK8sResources(conf.Security.Authorization.Provider.Kubernetes.Resources),
 	}, nil
 }
 
@@ -86,6 +87,8 @@ type k8sImpl struct {
 	authenticator authenticator.Request
 	authorizer    authorizer.Authorizer
 	kubeClient    kubernetes.Interface
+	// resources maps every Perses scope to the Kubernetes resource its permissions are checked against.
+	resources map[v1Role.Scope]k8sResource
 }
 
 // IsEnabled implements [Authorization]
@@ -388,37 +391,37 @@ func (k *k8sImpl) checkSpecificPermission(ctx echo.Context, namespace string, us
 	if scope == v1Role.ProjectScope {
 		return k.checkNamespaceAccess(ctx, namespace, user, action)
 	}
+	authorized, _, err = k.authorizer.Authorize(ctx.Request().Context(), k.getAttributes(namespace, user, action, scope))
+	return authorized, err
+}
 
-	translatedK8sScope := getK8sScope(scope)
-
-	// For resources without a K8s CRD (e.g. Variable, Folder, etc.),
-	// fall back to checking the user's permission on the dashboard resource resource.
-	if translatedK8sScope == "" {
-		logrus.Debugf("scope %q has no k8s CRD equivalent, falling back to dashboard permission check", scope)
-		translatedK8sScope = k8sDashboardScope
+// getAttributes returns the attributes of the SubjectAccessReview checking the permission of the user
+// to perform the action on the Kubernetes resource corresponding to the scope.
+func (k *k8sImpl) getAttributes(namespace string, user user.Info, action v1Role.Action, scope v1Role.Scope) authorizer.AttributesRecord {
+	resource, ok := k.resources[scope]
+	// For resources without a K8s equivalent (e.g. User, Group),
+	// fall back to checking the user's permission on the dashboard resource.
+	if !ok {
+		logrus.Debugf("scope %q has no k8s equivalent, falling back to dashboard permission check", scope)
+		resource = k.resources[v1Role.DashboardScope]
 	}
 
-	apiGroup := getK8sAPIGroup(translatedK8sScope)
-	apiVersion := getK8sAPIVersion(translatedK8sScope)
-
 	// To align with Perses RBAC any Global resource is not namespaced
 	if slices.Contains(globalScopes, scope) {
 		namespace = v1.WildcardProject
 	}
 
-	attributes := authorizer.AttributesRecord{
+	return authorizer.AttributesRecord{
 		User:            user,
 		Verb:            string(getK8sAction(action)),
 		Namespace:       namespace,
-		APIGroup:        apiGroup,
-		APIVersion:      apiVersion,
-		Resource:        string(translatedK8sScope),
+		APIGroup:        resource.group,
+		APIVersion:      resource.version,
+		Resource:        string(resource.resource),
 		Subresource:     "",
 		Name:            "",
 		ResourceRequest: true,
 	}
-	authorized, _, err = k.authorizer.Authorize(ctx.Request().Context(), attributes)
-	return authorized, err
 }
 
 // Kubernetes requires both a namespace and a resource to check permissions against. Checking the permissions
@@ -427,7 +430,7 @@ func (k *k8sImpl) checkSpecificPermission(ctx echo.Context, namespace string, us
 // user has access to a project, in the perses sense
 func (k *k8sImpl) checkNamespaceAccess(ctx echo.Context, namespace string, user user.Info, action v1Role.Action) (authorized authorizer.Decision, err error) {
 	var decision authorizer.Decision
-	for _, scope := range kubernetesResourcesProjectScopesToCheck {
+	for _, scope := range projectScopesToCheck {
 		decision, err := k.checkSpecificPermission(ctx, namespace, user, action, scope)
 		// If the request errors, then assume the rest of the requests will also error and break
 		// out early
@@ -463,28 +466,6 @@ func (k *k8sImpl) getNamespaceList() []string {
 	return namespaces
 }
 
-// getK8sAPIVersion is used to determine which API version the authorization request should use, v1
-// for namespaces and v1alpha1 for all other perses related kubernetes resources
-func getK8sAPIVersion(scope k8sScope) string {
-	switch scope {
-	case k8sProjectScope, k8sSecretScope:
-		return "v1"
-	default:
-		return "v1alpha1"
-	}
-}
-
-// getK8aAPIGroup is used to determine which API group the authorization request should use, empty
-// string for namespaces and the perses.dev for all other perses related kubernetes resources
-func getK8sAPIGroup(scope k8sScope) string {
-	switch scope {
-	case k8sProjectScope, k8sSecretScope:
-		return ""
-	default:
-		return "perses.dev"
-	}
-}
-
 // helper function to convert any type into a user.Info. This function should not error, and it is
 // expected that the struct being passed in is user.Info
 func getK8sUser(userStruct any) (user.Info, error) {
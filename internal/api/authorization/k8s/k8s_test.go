@@ -137,6 +137,7 @@ func newK8sMock(t *testing.T) *k8sImpl {
 		authenticator: fakeAuthenticator,
 		authorizer:    fakeAuthorizer,
 		kubeClient:    clientset,
+		resources:     newK8sResources(nil),
 	}
 }
 
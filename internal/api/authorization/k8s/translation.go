@@ -14,6 +14,9 @@
 package k8s
 
 import (
+	"slices"
+
+	"github.com/perses/perses/pkg/model/api/config"
 	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
 )
 
@@ -30,34 +33,95 @@ const (
 type k8sScope string
 
 const (
-	k8sWildcardScope         k8sScope = "*"
-	k8sDashboardScope        k8sScope = "persesdashboards"
-	k8sGlobalDatasourceScope k8sScope = "persesglobaldatasources"
-	k8sDatasourceScope       k8sScope = "persesdatasources"
-	k8sProjectScope          k8sScope = "namespaces"
-	k8sSecretScope           k8sScope = "secrets"
+	k8sWildcardScope           k8sScope = "*"
+	k8sDashboardScope          k8sScope = "persesdashboards"
+	k8sEphemeralDashboardScope k8sScope = "persesephemeraldashboards"
+	k8sFolderScope             k8sScope = "persesfolders"
+	k8sGlobalDatasourceScope   k8sScope = "persesglobaldatasources"
+	k8sDatasourceScope         k8sScope = "persesdatasources"
+	k8sGlobalVariableScope     k8sScope = "persesglobalvariables"
+	k8sVariableScope           k8sScope = "persesvariables"
+	k8sProjectScope            k8sScope = "namespaces"
+	k8sSecretScope             k8sScope = "secrets"
+	k8sRoleScope               k8sScope = "roles"
+	k8sRoleBindingScope        k8sScope = "rolebindings"
+	k8sGlobalRoleScope         k8sScope = "clusterroles"
+	k8sGlobalRoleBindingScope  k8sScope = "clusterrolebindings"
 )
 
-// Contains all project-scoped resources that have a corresponding kubernetes resource to
-// check against. Used when determining if a user has access to any resource within a namespace
-var kubernetesResourcesProjectScopesToCheck = []v1Role.Scope{
-	v1Role.DashboardScope,
-	v1Role.DatasourceScope,
-	v1Role.SecretScope,
+const (
+	persesAPIGroup   = "perses.dev"
+	persesAPIVersion = "v1alpha1"
+	rbacAPIGroup     = "rbac.authorization.k8s.io"
+	coreAPIGroup     = ""
+	coreAPIVersion   = "v1"
+)
+
+// k8sResource is the Kubernetes resource against which the permissions of a Perses scope are checked.
+type k8sResource struct {
+	group    string
+	version  string
+	resource k8sScope
+}
+
+// defaultK8sResources maps the Perses scopes to their Kubernetes resource.
+// The scopes missing here (users and groups) have no Kubernetes equivalent.
+var defaultK8sResources = map[v1Role.Scope]k8sResource{
+	v1Role.WildcardScope:           {group: persesAPIGroup, version: persesAPIVersion, resource: k8sWildcardScope},
+	v1Role.DashboardScope:          {group: persesAPIGroup, version: persesAPIVersion, resource: k8sDashboardScope},
+	v1Role.EphemeralDashboardScope: {group: persesAPIGroup, version: persesAPIVersion, resource: k8sEphemeralDashboardScope},
+	v1Role.FolderScope:             {group: persesAPIGroup, version: persesAPIVersion, resource: k8sFolderScope},
+	v1Role.DatasourceScope:         {group: persesAPIGroup, version: persesAPIVersion, resource: k8sDatasourceScope},
+	v1Role.GlobalDatasourceScope:   {group: persesAPIGroup, version: persesAPIVersion, resource: k8sGlobalDatasourceScope},
+	v1Role.VariableScope:           {group: persesAPIGroup, version: persesAPIVersion, resource: k8sVariableScope},
+	v1Role.GlobalVariableScope:     {group: persesAPIGroup, version: persesAPIVersion, resource: k8sGlobalVariableScope},
+	v1Role.ProjectScope:            {group: coreAPIGroup, version: coreAPIVersion, resource: k8sProjectScope},
+	// Map Perses secrets to native K8s secrets so that accessing Perses secrets
+	// requires the user to have K8s secret permissions in the relevant namespace.
+	// This is a naive check (not per-secret) but prevents privilege escalation
+	// where namespace access alone would grant secret access.
+	v1Role.SecretScope:       {group: coreAPIGroup, version: coreAPIVersion, resource: k8sSecretScope},
+	v1Role.GlobalSecretScope: {group: coreAPIGroup, version: coreAPIVersion, resource: k8sSecretScope},
+	// Same for the roles: managing Perses roles requires the user to be able to manage the K8s RBAC.
+	v1Role.RoleScope:              {group: rbacAPIGroup, version: coreAPIVersion, resource: k8sRoleScope},
+	v1Role.RoleBindingScope:       {group: rbacAPIGroup, version: coreAPIVersion, resource: k8sRoleBindingScope},
+	v1Role.GlobalRoleScope:        {group: rbacAPIGroup, version: coreAPIVersion, resource: k8sGlobalRoleScope},
+	v1Role.GlobalRoleBindingScope: {group: rbacAPIGroup, version: coreAPIVersion, resource: k8sGlobalRoleBindingScope},
+}
+
+// newK8sResources returns the default mapping between the Perses scopes and the Kubernetes resources,
+// overridden by the resources set in the configuration.
+func newK8sResources(overrides map[v1Role.Scope]config.KubernetesResource) map[v1Role.Scope]k8sResource {
+	resources := make(map[v1Role.Scope]k8sResource, len(defaultK8sResources))
+	for scope, resource := range defaultK8sResources {
+		resources[scope] = resource
+	}
+	for scope, resource := range overrides {
+		resources[scope] = k8sResource{
+			group:    resource.Group,
+			version:  resource.Version,
+			resource: k8sScope(resource.Resource),
+		}
+	}
+	return resources
 }
 
 // projectScopesToCheck contains all project-scoped resources that should be checked per-namespace
-// when computing permissions. Used to create full permissions lists
-var projectScopesToCheck = append(kubernetesResourcesProjectScopesToCheck,
+// when computing permissions. Used to create full permissions lists, and when determining if a user
+// has access to any resource within a namespace
+var projectScopesToCheck = []v1Role.Scope{
+	v1Role.DashboardScope,
+	v1Role.DatasourceScope,
+	v1Role.SecretScope,
 	v1Role.VariableScope,
 	v1Role.EphemeralDashboardScope,
 	v1Role.FolderScope,
-)
+}
 
 // globalScopesToCheck contains all scopes that should be checked at the wildcard (all-namespace)
 // level when computing permissions. This includes both global-only scopes and project-scoped
 // resources that benefit from an initial wildcard check to avoid redundant per-namespace checks.
-var globalScopesToCheck = append(projectScopesToCheck, v1Role.GlobalDatasourceScope,
+var globalScopesToCheck = append(slices.Clone(projectScopesToCheck), v1Role.GlobalDatasourceScope,
 	v1Role.GlobalVariableScope,
 	v1Role.GlobalSecretScope,
 )
@@ -68,6 +132,8 @@ var globalScopes = []v1Role.Scope{
 	v1Role.GlobalDatasourceScope,
 	v1Role.GlobalVariableScope,
 	v1Role.GlobalSecretScope,
+	v1Role.GlobalRoleScope,
+	v1Role.GlobalRoleBindingScope,
 }
 
 func getK8sAction(action v1Role.Action) k8sAction {
@@ -86,29 +152,3 @@ func getK8sAction(action v1Role.Action) k8sAction {
 		return ""
 	}
 }
-
-// getK8sScope translates a Perses scope to its corresponding Kubernetes resource name.
-// Returns an empty string if the scope has no direct K8s CRD equivalent. In that case,
-// checkSpecificPermission falls back to checking the project/namespace permission instead.
-func getK8sScope(scope v1Role.Scope) k8sScope {
-	switch scope {
-	case v1Role.DashboardScope:
-		return k8sDashboardScope
-	case v1Role.GlobalDatasourceScope:
-		return k8sGlobalDatasourceScope
-	case v1Role.DatasourceScope:
-		return k8sDatasourceScope
-	case v1Role.ProjectScope:
-		return k8sProjectScope
-	case v1Role.WildcardScope:
-		return k8sWildcardScope
-	case v1Role.SecretScope, v1Role.GlobalSecretScope:
-		// Map Perses secrets to native K8s secrets so that accessing Perses secrets
-		// requires the user to have K8s secret permissions in the relevant namespace.
-		// This is a naive check (not per-secret) but prevents privilege escalation
-		// where namespace access alone would grant secret access.
-		return k8sSecretScope
-	default:
-		return "" // Scope doesn't have a k8s equivalent; callers should fall back to project scope
-	}
-}
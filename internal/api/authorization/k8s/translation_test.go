@@ -0,0 +1,157 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// fakeAuthorizer records the attributes it is called with and allows every request.
+type fakeAuthorizer struct {
+	attributes []authorizer.Attributes
+}
+
+func (f *fakeAuthorizer) Authorize(_ context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	f.attributes = append(f.attributes, attr)
+	return authorizer.DecisionAllow, "", nil
+}
+
+func TestCheckSpecificPermission_Resources(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader(""))
+	rec := httptest.NewRecorder()
+	usr := &user.DefaultInfo{Name: "user0"}
+
+	testSuites := []struct {
+		title             string
+		overrides         map[v1Role.Scope]config.KubernetesResource
+		scope             v1Role.Scope
+		expectedNamespace string
+		expectedGroup     string
+		expectedVersion   string
+		expectedResource  string
+	}{
+		{
+			title:             "variable",
+			scope:             v1Role.VariableScope,
+			expectedNamespace: projectZero,
+			expectedGroup:     "perses.dev",
+			expectedVersion:   "v1alpha1",
+			expectedResource:  "persesvariables",
+		},
+		{
+			title:             "folder",
+			scope:             v1Role.FolderScope,
+			expectedNamespace: projectZero,
+			expectedGroup:     "perses.dev",
+			expectedVersion:   "v1alpha1",
+			expectedResource:  "persesfolders",
+		},
+		{
+			title:             "ephemeral dashboard",
+			scope:             v1Role.EphemeralDashboardScope,
+			expectedNamespace: projectZero,
+			expectedGroup:     "perses.dev",
+			expectedVersion:   "v1alpha1",
+			expectedResource:  "persesephemeraldashboards",
+		},
+		{
+			title:             "global variable is not namespaced",
+			scope:             v1Role.GlobalVariableScope,
+			expectedNamespace: v1.WildcardProject,
+			expectedGroup:     "perses.dev",
+			expectedVersion:   "v1alpha1",
+			expectedResource:  "persesglobalvariables",
+		},
+		{
+			title:             "role",
+			scope:             v1Role.RoleScope,
+			expectedNamespace: projectZero,
+			expectedGroup:     "rbac.authorization.k8s.io",
+			expectedVersion:   "v1",
+			expectedResource:  "roles",
+		},
+		{
+			title:             "global role binding is not namespaced",
+			scope:             v1Role.GlobalRoleBindingScope,
+			expectedNamespace: v1.WildcardProject,
+			expectedGroup:     "rbac.authorization.k8s.io",
+			expectedVersion:   "v1",
+			expectedResource:  "clusterrolebindings",
+		},
+		{
+			title:             "scope without k8s equivalent falls back to dashboard",
+			scope:             v1Role.UserScope,
+			expectedNamespace: projectZero,
+			expectedGroup:     "perses.dev",
+			expectedVersion:   "v1alpha1",
+			expectedResource:  "persesdashboards",
+		},
+		{
+			title: "overridden resource",
+			overrides: map[v1Role.Scope]config.KubernetesResource{
+				v1Role.VariableScope: {Resource: "variables", Group: "monitoring.example.com", Version: "v1beta1"},
+			},
+			scope:             v1Role.VariableScope,
+			expectedNamespace: projectZero,
+			expectedGroup:     "monitoring.example.com",
+			expectedVersion:   "v1beta1",
+			expectedResource:  "variables",
+		},
+		{
+			title: "override doesn't impact other scopes",
+			overrides: map[v1Role.Scope]config.KubernetesResource{
+				v1Role.VariableScope: {Resource: "variables", Group: "monitoring.example.com", Version: "v1beta1"},
+			},
+			scope:             v1Role.FolderScope,
+			expectedNamespace: projectZero,
+			expectedGroup:     "perses.dev",
+			expectedVersion:   "v1alpha1",
+			expectedResource:  "persesfolders",
+		},
+	}
+	for i := range testSuites {
+		test := testSuites[i]
+		t.Run(test.title, func(t *testing.T) {
+			fake := &fakeAuthorizer{}
+			k := &k8sImpl{
+				authorizer: fake,
+				resources:  newK8sResources(test.overrides),
+			}
+			decision, err := k.checkSpecificPermission(e.NewContext(req, rec), projectZero, usr, v1Role.UpdateAction, test.scope)
+			assert.NoError(t, err)
+			assert.Equal(t, authorizer.DecisionAllow, decision)
+			if assert.Len(t, fake.attributes, 1) {
+				attr := fake.attributes[0]
+				assert.Equal(t, "patch", attr.GetVerb())
+				assert.Equal(t, test.expectedNamespace, attr.GetNamespace())
+				assert.Equal(t, test.expectedGroup, attr.GetAPIGroup())
+				assert.Equal(t, test.expectedVersion, attr.GetAPIVersion())
+				assert.Equal(t, test.expectedResource, attr.GetResource())
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package native
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+// CanI implements [Authorization].
+// Contrary to HasPermission, it doesn't rely on the cache but on the content of the database,
+// so a binding can be checked right after being created.
+func (n *native) CanI(_ echo.Context, request *modelAPI.CanIRequest) (*modelAPI.CanIResponse, error) {
+	data, err := n.loadRBAC()
+	if err != nil {
+		return nil, err
+	}
+	return evaluate(data, n.guestPermissions, request), nil
+}
+
+// evaluate follows the same rules as buildPermissions and HasPermission,
+// but it keeps track of every binding applying to the subject to explain the decision.
+func evaluate(data *rbac, guestPermissions []*v1Role.Permission, request *modelAPI.CanIRequest) *modelAPI.CanIResponse {
+	groups := slices.Clone(request.Groups)
+	if len(request.User) > 0 {
+		usr := findUser(data.users, request.User)
+		if usr == nil {
+			return &modelAPI.CanIResponse{Reason: fmt.Sprintf("user %q does not exist", request.User)}
+		}
+		if usr.Spec.Disabled {
+			return &modelAPI.CanIResponse{Reason: fmt.Sprintf("user %q is disabled", request.User)}
+		}
+		for _, grp := range findUserGroups(data.groups, request.User) {
+			if !slices.Contains(groups, grp) {
+				groups = append(groups, grp)
+			}
+		}
+	}
+
+	var evaluations []modelAPI.CanIEvaluation
+	if listHasPermission(guestPermissions, request.Action, request.Scope) {
+		evaluations = append(evaluations, modelAPI.CanIEvaluation{
+			Allowed: true,
+			Reason:  "granted by the guest permissions",
+		})
+	}
+	for _, binding := range data.globalRoleBindings {
+		subject := findSubject(&binding.Spec, request.User, groups)
+		if len(subject) == 0 {
+			continue
+		}
+		evaluation := modelAPI.CanIEvaluation{
+			BindingKind: string(v1.KindGlobalRoleBinding),
+			Binding:     binding.Metadata.Name,
+			RoleKind:    string(v1.KindGlobalRole),
+			Role:        binding.Spec.Role,
+			Subject:     subject,
+		}
+		if globalRole := findGlobalRole(data.globalRoles, binding.Spec.Role); globalRole == nil {
+			evaluation.Reason = "the global role does not exist"
+		} else {
+			evaluation.Allowed, evaluation.Reason = evaluateRole(globalRole.Spec.Permissions, request.Action, request.Scope)
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+	// A role binding only grants permissions in its own project, never globally.
+	if request.Project != v1.WildcardProject {
+		for _, binding := range data.roleBindings {
+			if binding.Metadata.Project != request.Project {
+				continue
+			}
+			subject := findSubject(&binding.Spec, request.User, groups)
+			if len(subject) == 0 {
+				continue
+			}
+			evaluation := modelAPI.CanIEvaluation{
+				BindingKind: string(v1.KindRoleBinding),
+				Binding:     binding.Metadata.Name,
+				RoleKind:    string(v1.KindRole),
+				Role:        binding.Spec.Role,
+				Project:     binding.Metadata.Project,
+				Subject:     subject,
+			}
+			if projectRole := findRole(data.roles, binding.Metadata.Project, binding.Spec.Role); projectRole == nil {
+				evaluation.Reason = "the role does not exist"
+			} else {
+				evaluation.Allowed, evaluation.Reason = evaluateRole(projectRole.Spec.Permissions, request.Action, request.Scope)
+			}
+			evaluations = append(evaluations, evaluation)
+		}
+	}
+
+	result := &modelAPI.CanIResponse{Evaluations: evaluations}
+	if i := slices.IndexFunc(evaluations, func(e modelAPI.CanIEvaluation) bool { return e.Allowed }); i >= 0 {
+		result.Allowed = true
+		result.Reason = describeGrant(evaluations[i])
+	} else if len(evaluations) == 0 {
+		result.Reason = "no role binding applies to the subject"
+	} else {
+		result.Reason = "none of the roles bound to the subject grant the permission"
+	}
+	return result
+}
+
+func evaluateRole(permissions []v1Role.Permission, requestAction v1Role.Action, requestScope v1Role.Scope) (bool, string) {
+	list := make([]*v1Role.Permission, 0, len(permissions))
+	for i := range permissions {
+		list = append(list, &permissions[i])
+	}
+	if listHasPermission(list, requestAction, requestScope) {
+		return true, fmt.Sprintf("the role grants %q on %q", requestAction, requestScope)
+	}
+	return false, fmt.Sprintf("the role doesn't grant %q on %q", requestAction, requestScope)
+}
+
+func describeGrant(evaluation modelAPI.CanIEvaluation) string {
+	if len(evaluation.BindingKind) == 0 {
+		return "allowed by the guest permissions"
+	}
+	binding := evaluation.Binding
+	if len(evaluation.Project) > 0 {
+		binding = fmt.Sprintf("%s/%s", evaluation.Project, evaluation.Binding)
+	}
+	return fmt.Sprintf("allowed by %s %q of %s %q to %s", evaluation.BindingKind, binding, evaluation.RoleKind, evaluation.Role, evaluation.Subject)
+}
+
+// findSubject returns the subject of the binding matching the user or one of the groups, formatted as "<kind> <name>".
+// It returns an empty string when the binding doesn't apply.
+func findSubject(binding *v1.RoleBindingSpec, username string, groups []string) string {
+	if len(username) > 0 && binding.Has(v1.KindUser, username) {
+		return fmt.Sprintf("%s %s", v1.KindUser, username)
+	}
+	for _, grp := range groups {
+		if binding.Has(v1.KindGroup, grp) {
+			return fmt.Sprintf("%s %s", v1.KindGroup, grp)
+		}
+	}
+	return ""
+}
+
+// findUser is a helper to find a user in a slice
+func findUser(users []*v1.User, name string) *v1.User {
+	for _, usr := range users {
+		if usr.Metadata.Name == name {
+			return usr
+		}
+	}
+	return nil
+}
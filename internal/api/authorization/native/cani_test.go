@@ -0,0 +1,129 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package native
+
+import (
+	"testing"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	data := &rbac{
+		users: []*v1.User{
+			{Kind: v1.KindUser, Metadata: v1.Metadata{Name: "alice"}},
+			{Kind: v1.KindUser, Metadata: v1.Metadata{Name: "bob"}},
+			{Kind: v1.KindUser, Metadata: v1.Metadata{Name: "carol"}, Spec: v1.UserSpec{Disabled: true}},
+		},
+		groups: []*v1.Group{
+			{Kind: v1.KindGroup, Metadata: v1.Metadata{Name: "dev"}, Spec: v1.GroupSpec{Members: []string{"bob", "carol"}}},
+		},
+		roles: []*v1.Role{
+			{Kind: v1.KindRole, Metadata: v1.ProjectMetadata{Metadata: v1.Metadata{Name: "editor"}, ProjectMetadataWrapper: v1.ProjectMetadataWrapper{Project: "perses"}}, Spec: v1.RoleSpec{
+				Permissions: []role.Permission{{Actions: []role.Action{role.WildcardAction}, Scopes: []role.Scope{role.DashboardScope}}},
+			}},
+		},
+		roleBindings: []*v1.RoleBinding{
+			{Kind: v1.KindRoleBinding, Metadata: v1.ProjectMetadata{Metadata: v1.Metadata{Name: "editor"}, ProjectMetadataWrapper: v1.ProjectMetadataWrapper{Project: "perses"}}, Spec: v1.RoleBindingSpec{
+				Role:     "editor",
+				Subjects: []v1.Subject{{Kind: v1.KindGroup, Name: "dev"}},
+			}},
+			{Kind: v1.KindRoleBinding, Metadata: v1.ProjectMetadata{Metadata: v1.Metadata{Name: "ghost"}, ProjectMetadataWrapper: v1.ProjectMetadataWrapper{Project: "perses"}}, Spec: v1.RoleBindingSpec{
+				Role:     "ghost",
+				Subjects: []v1.Subject{{Kind: v1.KindUser, Name: "bob"}},
+			}},
+		},
+		globalRoles: []*v1.GlobalRole{
+			{Kind: v1.KindGlobalRole, Metadata: v1.Metadata{Name: "viewer"}, Spec: v1.RoleSpec{
+				Permissions: []role.Permission{{Actions: []role.Action{role.ReadAction}, Scopes: []role.Scope{role.WildcardScope}}},
+			}},
+		},
+		globalRoleBindings: []*v1.GlobalRoleBinding{
+			{Kind: v1.KindGlobalRoleBinding, Metadata: v1.Metadata{Name: "viewer"}, Spec: v1.RoleBindingSpec{
+				Role:     "viewer",
+				Subjects: []v1.Subject{{Kind: v1.KindUser, Name: "alice"}},
+			}},
+		},
+	}
+	guestPermissions := []*role.Permission{{Actions: []role.Action{role.ReadAction}, Scopes: []role.Scope{role.ProjectScope}}}
+
+	testSuites := []struct {
+		title    string
+		request  *modelAPI.CanIRequest
+		expected *modelAPI.CanIResponse
+	}{
+		{
+			title:   "unknown user",
+			request: &modelAPI.CanIRequest{User: "dave", Action: role.ReadAction, Scope: role.DashboardScope, Project: "perses"},
+			expected: &modelAPI.CanIResponse{
+				Reason: `user "dave" does not exist`,
+			},
+		},
+		{
+			title:   "disabled user",
+			request: &modelAPI.CanIRequest{User: "carol", Action: role.ReadAction, Scope: role.DashboardScope, Project: "perses"},
+			expected: &modelAPI.CanIResponse{
+				Reason: `user "carol" is disabled`,
+			},
+		},
+		{
+			title:   "guest permissions",
+			request: &modelAPI.CanIRequest{User: "bob", Action: role.ReadAction, Scope: role.ProjectScope, Project: v1.WildcardProject},
+			expected: &modelAPI.CanIResponse{
+				Allowed: true,
+				Reason:  "allowed by the guest permissions",
+				Evaluations: []modelAPI.CanIEvaluation{
+					{Allowed: true, Reason: "granted by the guest permissions"},
+				},
+			},
+		},
+		{
+			title:   "allowed through a group",
+			request: &modelAPI.CanIRequest{User: "bob", Action: role.UpdateAction, Scope: role.DashboardScope, Project: "perses"},
+			expected: &modelAPI.CanIResponse{
+				Allowed: true,
+				Reason:  `allowed by RoleBinding "perses/editor" of Role "editor" to Group dev`,
+				Evaluations: []modelAPI.CanIEvaluation{
+					{BindingKind: "RoleBinding", Binding: "editor", RoleKind: "Role", Role: "editor", Project: "perses", Subject: "Group dev", Allowed: true, Reason: `the role grants "update" on "Dashboard"`},
+					{BindingKind: "RoleBinding", Binding: "ghost", RoleKind: "Role", Role: "ghost", Project: "perses", Subject: "User bob", Reason: "the role does not exist"},
+				},
+			},
+		},
+		{
+			title:   "role bindings don't apply globally",
+			request: &modelAPI.CanIRequest{Groups: []string{"dev"}, Action: role.UpdateAction, Scope: role.DashboardScope, Project: v1.WildcardProject},
+			expected: &modelAPI.CanIResponse{
+				Reason: "no role binding applies to the subject",
+			},
+		},
+		{
+			title:   "denied by the global role",
+			request: &modelAPI.CanIRequest{User: "alice", Action: role.DeleteAction, Scope: role.DashboardScope, Project: "perses"},
+			expected: &modelAPI.CanIResponse{
+				Reason: "none of the roles bound to the subject grant the permission",
+				Evaluations: []modelAPI.CanIEvaluation{
+					{BindingKind: "GlobalRoleBinding", Binding: "viewer", RoleKind: "GlobalRole", Role: "viewer", Subject: "User alice", Reason: `the role doesn't grant "delete" on "Dashboard"`},
+				},
+			},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.expected, evaluate(data, guestPermissions, test.request))
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/globalrole"
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
+	"github.com/perses/perses/internal/api/interface/v1/group"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/user"
@@ -39,7 +40,7 @@ import (
 )
 
 func New(userDAO user.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
-	globalRoleDAO globalrole.DAO, globalRoleBindingDAO globalrolebinding.DAO, conf config.Config) (*native, error) {
+	globalRoleDAO globalrole.DAO, globalRoleBindingDAO globalrolebinding.DAO, groupDAO group.DAO, conf config.Config) (*native, error) {
 	key, err := hex.DecodeString(string(conf.Security.EncryptionKey))
 	if err != nil {
 		return nil, err
@@ -51,6 +52,7 @@ func New(userDAO user.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
 		roleBindingDAO:       roleBindingDAO,
 		globalRoleDAO:        globalRoleDAO,
 		globalRoleBindingDAO: globalRoleBindingDAO,
+		groupDAO:             groupDAO,
 		guestPermissions:     conf.Security.Authorization.Provider.Native.GuestPermissions,
 		accessKey:            key,
 	}, err
@@ -67,6 +69,7 @@ type native struct {
 	roleBindingDAO       rolebinding.DAO
 	globalRoleDAO        globalrole.DAO
 	globalRoleBindingDAO globalrolebinding.DAO
+	groupDAO             group.DAO
 	guestPermissions     []*v1Role.Permission
 	// mutex is used to protect the cache from concurrent access.
 	mutex sync.RWMutex
@@ -163,10 +166,6 @@ func (n *native) Middleware(skipper middleware.Skipper) echo.MiddlewareFunc {
 }
 
 func (n *native) GetUserProjects(ctx echo.Context, requestAction v1Role.Action, requestScope v1Role.Scope) ([]string, error) {
-	if listHasPermission(n.guestPermissions, requestAction, requestScope) {
-		return []string{v1.WildcardProject}, nil
-	}
-
 	username, err := n.GetUsername(ctx)
 	if err != nil {
 		return nil, err
@@ -176,6 +175,14 @@ func (n *native) GetUserProjects(ctx echo.Context, requestAction v1Role.Action,
 		logrus.Error("failed to get username from context to list the user projects")
 		return nil, apiInterface.InternalError
 	}
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	if n.cache.isDisabled(username) {
+		return nil, nil
+	}
+	if listHasPermission(n.guestPermissions, requestAction, requestScope) {
+		return []string{v1.WildcardProject}, nil
+	}
 	projectPermission := n.cache.permissions[username]
 	if globalPermissions, ok := projectPermission[v1.WildcardProject]; ok && listHasPermission(globalPermissions, requestAction, requestScope) {
 		return []string{v1.WildcardProject}, nil
@@ -211,13 +218,17 @@ func (n *native) HasPermission(ctx echo.Context, requestAction v1Role.Action, re
 		logrus.Error("no username found in the context, this should not happen in a native RBAC implementation")
 		return false // No username found, cannot check permissions
 	}
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	// A disabled user doesn't have any permission, not even the default ones.
+	if n.cache.isDisabled(username) {
+		return false
+	}
 	// Checking default permissions
 	if ok := listHasPermission(n.guestPermissions, requestAction, requestScope); ok {
 		return true
 	}
 	// Checking cached permissions
-	n.mutex.RLock()
-	defer n.mutex.RUnlock()
 	return n.cache.hasPermission(username, requestAction, requestProject, requestScope)
 }
 
@@ -239,6 +250,9 @@ func (n *native) GetPermissions(ctx echo.Context) (map[string][]*v1Role.Permissi
 		return nil, apiInterface.InternalError
 	}
 	userPermissions := make(map[string][]*v1Role.Permission)
+	if n.cache.isDisabled(username) {
+		return userPermissions, nil
+	}
 	userPermissions[v1.WildcardProject] = n.guestPermissions
 	for project, projectPermissions := range n.cache.permissions[username] {
 		userPermissions[project] = append(userPermissions[project], projectPermissions...)
@@ -247,18 +261,39 @@ func (n *native) GetPermissions(ctx echo.Context) (map[string][]*v1Role.Permissi
 }
 
 func (n *native) RefreshPermissions() error {
-	permissions, err := n.loadAllPermissions()
+	permissions, disabledUsers, err := n.loadAllPermissions()
 	if err != nil {
 		return err
 	}
 	n.mutex.Lock()
 	n.cache.permissions = permissions
+	n.cache.disabledUsers = disabledUsers
 	n.mutex.Unlock()
 	return nil
 }
 
 // loadAllPermissions is loading all permissions for all users.
-func (n *native) loadAllPermissions() (usersPermissions, error) {
+// It also returns the list of the disabled users as they must not get any permission.
+func (n *native) loadAllPermissions() (usersPermissions, map[string]struct{}, error) {
+	data, err := n.loadRBAC()
+	if err != nil {
+		return nil, nil, err
+	}
+	permissions, disabledUsers := buildPermissions(data.users, data.groups, data.roles, data.roleBindings, data.globalRoles, data.globalRoleBindings)
+	return permissions, disabledUsers, nil
+}
+
+// rbac contains every resource involved in the computation of the permissions.
+type rbac struct {
+	users              []*v1.User
+	groups             []*v1.Group
+	roles              []*v1.Role
+	roleBindings       []*v1.RoleBinding
+	globalRoles        []*v1.GlobalRole
+	globalRoleBindings []*v1.GlobalRoleBinding
+}
+
+func (n *native) loadRBAC() (*rbac, error) {
 	users, err := n.userDAO.List(&user.Query{})
 	if err != nil {
 		return nil, err
@@ -279,12 +314,33 @@ func (n *native) loadAllPermissions() (usersPermissions, error) {
 	if err != nil {
 		return nil, err
 	}
+	groups, err := n.groupDAO.List(&group.Query{})
+	if err != nil {
+		return nil, err
+	}
+	return &rbac{
+		users:              users,
+		groups:             groups,
+		roles:              roles,
+		roleBindings:       roleBindings,
+		globalRoles:        globalRoles,
+		globalRoleBindings: globalRoleBindings,
+	}, nil
+}
 
+func buildPermissions(users []*v1.User, groups []*v1.Group, roles []*v1.Role, roleBindings []*v1.RoleBinding,
+	globalRoles []*v1.GlobalRole, globalRoleBindings []*v1.GlobalRoleBinding) (usersPermissions, map[string]struct{}) {
 	// Build cache
 	permissionBuild := make(usersPermissions)
+	disabledUsers := make(map[string]struct{})
 	for _, usr := range users {
+		if usr.Spec.Disabled {
+			disabledUsers[usr.Metadata.Name] = struct{}{}
+			continue
+		}
+		userGroups := findUserGroups(groups, usr.Metadata.Name)
 		for _, globalRoleBinding := range globalRoleBindings {
-			if globalRoleBinding.Spec.Has(v1.KindUser, usr.Metadata.Name) {
+			if isSubject(&globalRoleBinding.Spec, usr.Metadata.Name, userGroups) {
 				globalRole := findGlobalRole(globalRoles, globalRoleBinding.Spec.Role)
 				if globalRole == nil {
 					logrus.Warningf("global role %q listed in the global role binding %q does not exist", globalRoleBinding.Spec.Role, globalRoleBinding.Metadata.Name)
@@ -296,11 +352,8 @@ func (n *native) loadAllPermissions() (usersPermissions, error) {
 				}
 			}
 		}
-	}
-
-	for _, usr := range users {
 		for _, roleBinding := range roleBindings {
-			if roleBinding.Spec.Has(v1.KindUser, usr.Metadata.Name) {
+			if isSubject(&roleBinding.Spec, usr.Metadata.Name, userGroups) {
 				projectRole := findRole(roles, roleBinding.Metadata.Project, roleBinding.Spec.Role)
 				if projectRole == nil {
 					logrus.Warningf("role %q listed in the role binding %s/%s does not exist", roleBinding.Spec.Role, roleBinding.Metadata.Project, roleBinding.Metadata.Name)
@@ -313,5 +366,5 @@ func (n *native) loadAllPermissions() (usersPermissions, error) {
 			}
 		}
 	}
-	return permissionBuild, nil
+	return permissionBuild, disabledUsers
 }
@@ -211,6 +211,48 @@ func TestCacheHasPermission(t *testing.T) {
 	}
 }
 
+func TestBuildPermissions(t *testing.T) {
+	users := []*v1.User{
+		{Kind: v1.KindUser, Metadata: v1.Metadata{Name: "alice"}},
+		{Kind: v1.KindUser, Metadata: v1.Metadata{Name: "bob"}},
+		{Kind: v1.KindUser, Metadata: v1.Metadata{Name: "carol"}, Spec: v1.UserSpec{Disabled: true}},
+	}
+	groups := []*v1.Group{
+		{Kind: v1.KindGroup, Metadata: v1.Metadata{Name: "dev"}, Spec: v1.GroupSpec{Members: []string{"bob", "carol"}}},
+	}
+	roles := []*v1.Role{
+		{Kind: v1.KindRole, Metadata: v1.ProjectMetadata{Metadata: v1.Metadata{Name: "editor"}, ProjectMetadataWrapper: v1.ProjectMetadataWrapper{Project: "perses"}}, Spec: v1.RoleSpec{
+			Permissions: []role.Permission{{Actions: []role.Action{role.WildcardAction}, Scopes: []role.Scope{role.DashboardScope}}},
+		}},
+	}
+	roleBindings := []*v1.RoleBinding{
+		{Kind: v1.KindRoleBinding, Metadata: v1.ProjectMetadata{Metadata: v1.Metadata{Name: "editor"}, ProjectMetadataWrapper: v1.ProjectMetadataWrapper{Project: "perses"}}, Spec: v1.RoleBindingSpec{
+			Role:     "editor",
+			Subjects: []v1.Subject{{Kind: v1.KindGroup, Name: "dev"}},
+		}},
+	}
+	globalRoles := []*v1.GlobalRole{
+		{Kind: v1.KindGlobalRole, Metadata: v1.Metadata{Name: "admin"}, Spec: v1.RoleSpec{
+			Permissions: []role.Permission{{Actions: []role.Action{role.WildcardAction}, Scopes: []role.Scope{role.WildcardScope}}},
+		}},
+	}
+	globalRoleBindings := []*v1.GlobalRoleBinding{
+		{Kind: v1.KindGlobalRoleBinding, Metadata: v1.Metadata{Name: "admin"}, Spec: v1.RoleBindingSpec{
+			Role:     "admin",
+			Subjects: []v1.Subject{{Kind: v1.KindUser, Name: "alice"}, {Kind: v1.KindUser, Name: "carol"}},
+		}},
+	}
+	permissions, disabledUsers := buildPermissions(users, groups, roles, roleBindings, globalRoles, globalRoleBindings)
+	c := cache{permissions: permissions, disabledUsers: disabledUsers}
+
+	assert.True(t, c.hasPermission("alice", role.DeleteAction, "perses", role.ProjectScope))
+	assert.True(t, c.hasPermission("bob", role.UpdateAction, "perses", role.DashboardScope))
+	assert.False(t, c.hasPermission("bob", role.UpdateAction, "other", role.DashboardScope))
+	assert.False(t, c.hasPermission("carol", role.ReadAction, "perses", role.DashboardScope))
+	assert.True(t, c.isDisabled("carol"))
+	assert.False(t, c.isDisabled("bob"))
+}
+
 func BenchmarkCacheHasPermission(b *testing.B) {
 	benchSuites := []struct {
 		userCount          int
@@ -37,6 +37,13 @@ func (p usersPermissions) addEntry(user string, project string, permission *v1Ro
 
 type cache struct {
 	permissions usersPermissions
+	// disabledUsers contains the name of the users that are disabled and therefore don't have any permission.
+	disabledUsers map[string]struct{}
+}
+
+func (c *cache) isDisabled(user string) bool {
+	_, ok := c.disabledUsers[user]
+	return ok
 }
 
 func (c *cache) hasPermission(user string, requestAction v1Role.Action, requestProject string, requestScope v1Role.Scope) bool {
@@ -76,6 +83,30 @@ func listHasPermission(permissions []*v1Role.Permission, requestAction v1Role.Ac
 	return false
 }
 
+// isSubject returns true if the user, or one of the groups the user belongs to, is a subject of the binding.
+func isSubject(binding *v1.RoleBindingSpec, username string, userGroups []string) bool {
+	if binding.Has(v1.KindUser, username) {
+		return true
+	}
+	for _, grp := range userGroups {
+		if binding.Has(v1.KindGroup, grp) {
+			return true
+		}
+	}
+	return false
+}
+
+// findUserGroups returns the name of the groups the user belongs to.
+func findUserGroups(groups []*v1.Group, username string) []string {
+	var result []string
+	for _, grp := range groups {
+		if grp.Spec.Has(username) {
+			result = append(result, grp.Metadata.Name)
+		}
+	}
+	return result
+}
+
 // findRole is a helper to find a role in a slice
 func findRole(roles []*v1.Role, project string, name string) *v1.Role {
 	for _, rle := range roles {
@@ -43,7 +43,14 @@ func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, ba
 	if dbInitError := persesDAO.Init(); dbInitError != nil {
 		return nil, nil, fmt.Errorf("unable to initialize the database: %w", dbInitError)
 	}
-	persesAPI := NewPersesAPI(dependencyManager, conf)
+	var provisioningTask provisioning.Provisioning
+	if len(conf.Provisioning.Folders) > 0 || len(conf.Provisioning.Git) > 0 {
+		provisioningTask, err = provisioning.New(dependencyManager.Service(), dependencyManager.Persistence().GetGlobalSecret(), conf.Provisioning, persesDAO.IsCaseSensitive())
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to instantiate the provisioning tasks: %w", err)
+		}
+	}
+	persesAPI := NewPersesAPI(dependencyManager, provisioningTask, conf)
 	persesFrontend := ui.NewPersesFrontend(conf, dependencyManager.Service().GetPlugin())
 	runner := app.NewRunner().WithDefaultHTTPServerAndPrometheusRegisterer(utils.MetricNamespace, registry, registry).SetBanner(banner)
 
@@ -56,11 +63,10 @@ func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, ba
 		runner.WithTimerTasks(time.Duration(conf.EphemeralDashboard.CleanupInterval), ephemeralDashboardsCleaner)
 	}
 
-	if len(conf.Provisioning.Folders) > 0 {
-		provisioningTask := provisioning.New(dependencyManager.Service(), conf.Provisioning.Folders, persesDAO.IsCaseSensitive())
-		runner.WithTimerTasks(time.Duration(conf.Provisioning.Interval), provisioningTask)
+	if provisioningTask != nil {
+		runner.WithTaskHelpers(provisioningTask.Tasks()...)
 	}
-	if len(conf.Datasource.Global.Discovery) > 0 {
+	if len(conf.Datasource.Global.Discovery) > 0 || len(conf.Datasource.Project.Discovery) > 0 {
 		datasourceDiscoveryTasks, sdErr := discovery.New(conf, dependencyManager.Service(), persesDAO.IsCaseSensitive())
 		if sdErr != nil {
 			return nil, nil, fmt.Errorf("unable to instantiate the tasks for datasource discovery: %w", sdErr)
@@ -81,6 +87,13 @@ func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, ba
 		if pluginErr := dependencyManager.Service().GetPlugin().Load(); pluginErr != nil {
 			logrus.WithError(pluginErr).Error("unable to load the plugins")
 		}
+		// The plugins required in the configuration and not present yet are downloaded from their registry.
+		// It's done in the background, so a registry slow to answer doesn't delay the start of the server.
+		go func() {
+			if _, syncErr := dependencyManager.Service().GetPlugin().SyncRequirements(); syncErr != nil {
+				logrus.WithError(syncErr).Error("unable to install the plugins required")
+			}
+		}()
 	}
 
 	// register the API
@@ -19,9 +19,13 @@ import (
 	"github.com/perses/perses/internal/api/core/middleware"
 	"github.com/perses/perses/internal/api/dependency"
 	authendpoint "github.com/perses/perses/internal/api/impl/auth"
+	authzendpoint "github.com/perses/perses/internal/api/impl/authz"
 	configendpoint "github.com/perses/perses/internal/api/impl/config"
+	exportendpoint "github.com/perses/perses/internal/api/impl/export"
 	migrateendpoint "github.com/perses/perses/internal/api/impl/migrate"
+	provisioningendpoint "github.com/perses/perses/internal/api/impl/provisioning"
 	"github.com/perses/perses/internal/api/impl/proxy"
+	"github.com/perses/perses/internal/api/impl/scim"
 	"github.com/perses/perses/internal/api/impl/v1/dashboard"
 	"github.com/perses/perses/internal/api/impl/v1/datasource"
 	"github.com/perses/perses/internal/api/impl/v1/ephemeraldashboard"
@@ -31,9 +35,11 @@ import (
 	"github.com/perses/perses/internal/api/impl/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/impl/v1/globalsecret"
 	"github.com/perses/perses/internal/api/impl/v1/globalvariable"
+	"github.com/perses/perses/internal/api/impl/v1/group"
 	"github.com/perses/perses/internal/api/impl/v1/health"
 	"github.com/perses/perses/internal/api/impl/v1/plugin"
 	"github.com/perses/perses/internal/api/impl/v1/project"
+	"github.com/perses/perses/internal/api/impl/v1/projecttemplate"
 	"github.com/perses/perses/internal/api/impl/v1/role"
 	"github.com/perses/perses/internal/api/impl/v1/rolebinding"
 	"github.com/perses/perses/internal/api/impl/v1/secret"
@@ -41,6 +47,7 @@ import (
 	"github.com/perses/perses/internal/api/impl/v1/variable"
 	"github.com/perses/perses/internal/api/impl/v1/view"
 	validateendpoint "github.com/perses/perses/internal/api/impl/validate"
+	"github.com/perses/perses/internal/api/provisioning"
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/pkg/model/api/config"
@@ -52,29 +59,33 @@ type api struct {
 	apiV1Endpoints         []route.Endpoint
 	apiEndpoints           []route.Endpoint
 	proxyEndpoint          route.Endpoint
+	scimEndpoint           route.Endpoint
+	headerAuthnMiddleware  echo.MiddlewareFunc
 	authorizationMiddlware echo.MiddlewareFunc
 	apiPrefix              string
 }
 
-func NewPersesAPI(dependencyManager dependency.Manager, cfg config.Config) echoUtils.Register {
+func NewPersesAPI(dependencyManager dependency.Manager, provisioningTask provisioning.Provisioning, cfg config.Config) echoUtils.Register {
 	readonly := cfg.Security.Readonly
+	provisioningPolicy := cfg.Provisioning.EditPolicy
 	persistenceManager := dependencyManager.Persistence()
 	serviceManager := dependencyManager.Service()
 	caseSensitive := persistenceManager.GetPersesDAO().IsCaseSensitive()
 	apiV1Endpoints := []route.Endpoint{
-		dashboard.NewEndpoint(serviceManager.GetDashboard(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-		datasource.NewEndpoint(cfg.Datasource, serviceManager.GetDatasource(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		dashboard.NewEndpoint(serviceManager.GetDashboard(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+		datasource.NewEndpoint(cfg.Datasource, serviceManager.GetDatasource(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
 		ephemeraldashboard.NewEndpoint(serviceManager.GetEphemeralDashboard(), serviceManager.GetAuthorization(), readonly, caseSensitive, cfg.EphemeralDashboard.Enable),
-		folder.NewEndpoint(serviceManager.GetFolder(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-		globaldatasource.NewEndpoint(cfg.Datasource, serviceManager.GetGlobalDatasource(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-		globalsecret.NewEndpoint(serviceManager.GetGlobalSecret(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-		globalvariable.NewEndpoint(cfg.Variable, serviceManager.GetGlobalVariable(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		folder.NewEndpoint(serviceManager.GetFolder(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+		globaldatasource.NewEndpoint(cfg.Datasource, serviceManager.GetGlobalDatasource(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+		globalsecret.NewEndpoint(serviceManager.GetGlobalSecret(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+		globalvariable.NewEndpoint(cfg.Variable, serviceManager.GetGlobalVariable(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
 		health.NewEndpoint(serviceManager.GetHealth()),
-		plugin.NewEndpoint(serviceManager.GetPlugin(), cfg.Plugin.EnableDev),
-		project.NewEndpoint(serviceManager.GetProject(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-		secret.NewEndpoint(serviceManager.GetSecret(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-		user.NewEndpoint(serviceManager.GetUser(), serviceManager.GetAuthorization(), cfg.Security.Authentication.DisableSignUp, readonly, caseSensitive),
-		variable.NewEndpoint(cfg.Variable, serviceManager.GetVariable(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		plugin.NewEndpoint(serviceManager.GetPlugin(), serviceManager.GetAuthorization(), cfg.Plugin.EnableDev, cfg.Plugin.EnableRuntimeInstall && !readonly),
+		project.NewEndpoint(serviceManager.GetProject(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+		projecttemplate.NewEndpoint(serviceManager.GetProjectTemplate(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+		secret.NewEndpoint(serviceManager.GetSecret(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+		user.NewEndpoint(serviceManager.GetUser(), serviceManager.GetAuthorization(), cfg.Security.Authentication.DisableSignUp, readonly, caseSensitive, provisioningPolicy),
+		variable.NewEndpoint(cfg.Variable, serviceManager.GetVariable(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
 		view.NewEndpoint(serviceManager.GetView(), serviceManager.GetAuthorization(), serviceManager.GetDashboard()),
 	}
 
@@ -82,15 +93,17 @@ func NewPersesAPI(dependencyManager dependency.Manager, cfg config.Config) echoU
 		// When the authorization is provided by a third-party service, roles are not managed by the Perses API.
 		// Therefore, we provide endpoints to manage them only if the native authorization is enabled.
 		apiV1Endpoints = append(apiV1Endpoints,
-			globalrole.NewEndpoint(serviceManager.GetGlobalRole(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-			globalrolebinding.NewEndpoint(serviceManager.GetGlobalRoleBinding(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-			role.NewEndpoint(serviceManager.GetRole(), serviceManager.GetAuthorization(), readonly, caseSensitive),
-			rolebinding.NewEndpoint(serviceManager.GetRoleBinding(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+			globalrole.NewEndpoint(serviceManager.GetGlobalRole(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+			globalrolebinding.NewEndpoint(serviceManager.GetGlobalRoleBinding(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+			group.NewEndpoint(serviceManager.GetGroup(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+			role.NewEndpoint(serviceManager.GetRole(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
+			rolebinding.NewEndpoint(serviceManager.GetRoleBinding(), serviceManager.GetAuthorization(), readonly, caseSensitive, provisioningPolicy),
 		)
 	}
 
 	authEndpoint, err := authendpoint.New(
 		persistenceManager.GetUser(),
+		serviceManager.GetUser(),
 		serviceManager.GetJWT(),
 		serviceManager.GetAuthorization(),
 		cfg.Security.Authentication.Providers,
@@ -101,20 +114,37 @@ func NewPersesAPI(dependencyManager dependency.Manager, cfg config.Config) echoU
 		logrus.WithError(err).Fatal("error initializing authentication endpoints")
 	}
 	apiEndpoints := []route.Endpoint{
+		authzendpoint.New(serviceManager.GetAuthorization()),
 		configendpoint.New(cfg),
+		exportendpoint.New(serviceManager.GetExport()),
 		migrateendpoint.New(serviceManager.GetMigration()),
+		provisioningendpoint.New(provisioningTask, cfg.Provisioning, serviceManager.GetAuthorization()),
 		validateendpoint.New(serviceManager.GetSchema(), serviceManager.GetDashboard()),
 		authEndpoint,
 	}
+	var scimEndpoint route.Endpoint
+	if cfg.Security.SCIM.Enable {
+		scimEndpoint = scim.New(persistenceManager.GetUser(), persistenceManager.GetGroup(), serviceManager.GetAuthorization(), cfg.Security.SCIM, readonly, cfg.APIPrefix)
+	}
+	var headerAuthnMiddleware echo.MiddlewareFunc
+	if cfg.Security.EnableAuth && cfg.Security.Authentication.Providers.Header.Enable {
+		headerAuthnMiddleware, err = authendpoint.NewHeaderMiddleware(persistenceManager.GetUser(), persistenceManager.GetGroup(), serviceManager.GetAuthorization(), cfg.Security.Authentication.Providers.Header)
+		if err != nil {
+			logrus.WithError(err).Fatal("error initializing the header authentication middleware")
+		}
+	}
 	return &api{
 		apiV1Endpoints: apiV1Endpoints,
 		apiEndpoints:   apiEndpoints,
 		proxyEndpoint: proxy.New(cfg.Datasource, persistenceManager.GetDashboard(), persistenceManager.GetSecret(), persistenceManager.GetGlobalSecret(),
 			persistenceManager.GetDatasource(), persistenceManager.GetGlobalDatasource(), serviceManager.GetCrypto(), serviceManager.GetAuthorization()),
-		authorizationMiddlware: serviceManager.GetAuthorization().Middleware(func(_ echo.Context) bool {
-			return !cfg.Security.EnableAuth
+		authorizationMiddlware: serviceManager.GetAuthorization().Middleware(func(c echo.Context) bool {
+			// The user can already be authenticated by the header middleware.
+			return !cfg.Security.EnableAuth || c.Get(utils.ContextKeyUser) != nil
 		}),
-		apiPrefix: cfg.APIPrefix,
+		headerAuthnMiddleware: headerAuthnMiddleware,
+		scimEndpoint:          scimEndpoint,
+		apiPrefix:             cfg.APIPrefix,
 	}
 }
 
@@ -154,6 +184,9 @@ func (a *api) RegisterRoute(e *echo.Echo) {
 		for _, rte := range el.group.Routes {
 			mdws := []echo.MiddlewareFunc{middleware.HandleAnonymous(rte.IsAnonymous)}
 			if !rte.IsAnonymous {
+				if a.headerAuthnMiddleware != nil {
+					mdws = append(mdws, a.headerAuthnMiddleware)
+				}
 				mdws = append(mdws, a.authorizationMiddlware)
 			}
 			mdws = append(mdws, rte.Middlewares...)
@@ -173,5 +206,11 @@ func (a *api) collectRoutes() []*route.Group {
 	}
 	proxyGroup := &route.Group{Path: a.apiPrefix + "/proxy"}
 	a.proxyEndpoint.CollectRoutes(proxyGroup)
-	return []*route.Group{apiGroup, apiV1Group, proxyGroup}
+	groups := []*route.Group{apiGroup, apiV1Group, proxyGroup}
+	if a.scimEndpoint != nil {
+		scimGroup := &route.Group{Path: a.apiPrefix + utils.SCIMV2Prefix}
+		a.scimEndpoint.CollectRoutes(scimGroup)
+		groups = append(groups, scimGroup)
+	}
+	return groups
 }
@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"slices"
 	"time"
 
 	"github.com/perses/perses/pkg/model/api/config"
@@ -32,6 +33,10 @@ import (
 type Crypto interface {
 	Encrypt(spec *modelV1.SecretSpec) error
 	Decrypt(spec *modelV1.SecretSpec) error
+	// EncryptString encrypts a single sensitive value that is not part of a secret, like a TOTP secret.
+	EncryptString(value string) (string, error)
+	// DecryptString decrypts a value encrypted with EncryptString.
+	DecryptString(value string) (string, error)
 }
 
 func New(security config.Security) (Crypto, JWT, error) {
@@ -50,7 +55,8 @@ func New(security config.Security) (Crypto, JWT, error) {
 		},
 		&jwtImpl{
 			accessKey:       key,
-			refreshKey:      append(key, []byte("-refresh")...),
+			refreshKey:      slices.Concat(key, []byte("-refresh")),
+			twoFactorKey:    slices.Concat(key, []byte("-two-factor")),
 			accessTokenTTL:  time.Duration(security.Authentication.AccessTokenTTL),
 			refreshTokenTTL: time.Duration(security.Authentication.RefreshTokenTTL),
 			cookieConfig:    security.Cookie,
@@ -151,6 +157,14 @@ func (c *crypto) Decrypt(spec *modelV1.SecretSpec) error {
 	return nil
 }
 
+func (c *crypto) EncryptString(value string) (string, error) {
+	return c.encrypt(value)
+}
+
+func (c *crypto) DecryptString(value string) (string, error) {
+	return c.decrypt(value)
+}
+
 func (c *crypto) encrypt(stringToEncrypt string) (string, error) {
 	if len(stringToEncrypt) == 0 {
 		return "", nil
@@ -14,6 +14,11 @@
 package crypto
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -32,3 +37,37 @@ func ComparePasswords(hashedPwd string, plainPwd string) bool {
 	}
 	return true
 }
+
+// MatchesAnyPassword returns true if the plain password matches one of the given hashes.
+// Unlike ComparePasswords, a mismatch is expected here and is therefore not logged.
+func MatchesAnyPassword(hashedPwds []string, plainPwd string) bool {
+	for _, hashedPwd := range hashedPwds {
+		if bcrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(plainPwd)) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateToken returns a random token and its hash.
+// Only the hash should be stored, the token itself is meant to be given once to the user.
+func GenerateToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hash of a token generated with GenerateToken.
+// As the token is random and long enough, a simple SHA-256 is sufficient.
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// CompareToken checks in constant time that the token matches the given hash.
+func CompareToken(hashedToken string, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashedToken), []byte(HashToken(token))) == 1
+}
@@ -14,12 +14,15 @@
 package crypto
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/pkg/model/api/config"
 )
 
@@ -28,6 +31,8 @@ const (
 	CookieKeyJWTSignature = "jwtSignature"
 	CookieKeyRefreshToken = "jwtRefreshToken"
 	cookiePath            = "/"
+	// twoFactorTokenTTL is the time given to a user to provide the second factor once the password has been checked.
+	twoFactorTokenTTL = 5 * time.Minute
 )
 
 type ProviderInfo struct {
@@ -66,11 +71,17 @@ type JWT interface {
 	CreateRefreshTokenCookie(refreshToken string) *http.Cookie
 	DeleteRefreshTokenCookie() *http.Cookie
 	ValidateRefreshToken(token string) (*JWTClaims, error)
+	// SignedTwoFactorToken returns a short-lived token proving the user passed the first authentication step.
+	// It cannot be used to access the API, only to complete the login with the second factor.
+	// The token carries a unique ID (the claim "jti") so the caller can make it single-use.
+	SignedTwoFactorToken(login string) (string, error)
+	ValidateTwoFactorToken(token string) (*JWTClaims, error)
 }
 
 type jwtImpl struct {
 	accessKey       []byte
 	refreshKey      []byte
+	twoFactorKey    []byte
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
 	cookieConfig    config.Cookie
@@ -158,8 +169,35 @@ func (j *jwtImpl) DeleteRefreshTokenCookie() *http.Cookie {
 }
 
 func (j *jwtImpl) ValidateRefreshToken(token string) (*JWTClaims, error) {
+	return validateToken(token, j.refreshKey)
+}
+
+func (j *jwtImpl) SignedTwoFactorToken(login string) (string, error) {
+	// Each token gets a unique ID, so it can be consumed once the login is completed or invalidated after too many wrong codes.
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, &JWTClaims{
+		ProviderInfo: ProviderInfo{ProviderKind: utils.AuthnKindNative},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(id),
+			Subject:   login,
+			ExpiresAt: jwt.NewNumericDate(now.Add(twoFactorTokenTTL)),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	})
+	return token.SignedString(j.twoFactorKey)
+}
+
+func (j *jwtImpl) ValidateTwoFactorToken(token string) (*JWTClaims, error) {
+	return validateToken(token, j.twoFactorKey)
+}
+
+func validateToken(token string, key []byte) (*JWTClaims, error) {
 	parsedToken, err := jwt.ParseWithClaims(token, &JWTClaims{}, func(_ *jwt.Token) (any, error) {
-		return j.refreshKey, nil
+		return key, nil
 	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Name}))
 	if err != nil {
 		return nil, err
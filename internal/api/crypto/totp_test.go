@@ -0,0 +1,106 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the secret used by the test vectors of the RFC 6238, encoded in base32.
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestValidateTOTP(t *testing.T) {
+	// Test vectors of the RFC 6238 (SHA-1), truncated to 6 digits.
+	testSuites := []struct {
+		title string
+		time  int64
+		code  string
+	}{
+		{title: "59", time: 59, code: "287082"},
+		{title: "1111111109", time: 1111111109, code: "081804"},
+		{title: "1111111111", time: 1111111111, code: "050471"},
+		{title: "1234567890", time: 1234567890, code: "005924"},
+		{title: "2000000000", time: 2000000000, code: "279037"},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfcSecret, test.code, time.Unix(test.time, 0), 0)
+			assert.True(t, ok)
+			assert.Equal(t, test.time/totpPeriod, step)
+		})
+	}
+}
+
+func TestValidateTOTP_Skew(t *testing.T) {
+	now := time.Unix(59, 0)
+	// the code of the previous and next periods are accepted
+	_, ok := ValidateTOTP(rfcSecret, "287082", now.Add(totpPeriod*time.Second), 0)
+	assert.True(t, ok)
+	_, ok = ValidateTOTP(rfcSecret, "287082", now.Add(-totpPeriod*time.Second), 0)
+	assert.True(t, ok)
+	// but not further
+	_, ok = ValidateTOTP(rfcSecret, "287082", now.Add(2*totpPeriod*time.Second), 0)
+	assert.False(t, ok)
+}
+
+func TestValidateTOTP_Replay(t *testing.T) {
+	now := time.Unix(59, 0)
+	step, ok := ValidateTOTP(rfcSecret, "287082", now, 0)
+	assert.True(t, ok)
+	_, ok = ValidateTOTP(rfcSecret, "287082", now, step)
+	assert.False(t, ok)
+}
+
+func TestValidateTOTP_Invalid(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "123456"} {
+		_, ok := ValidateTOTP(rfcSecret, code, now, 0)
+		assert.False(t, ok, code)
+	}
+	_, ok := ValidateTOTP("not base32!", "287082", now, 0)
+	assert.False(t, ok)
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+	key, err := totpEncoding.DecodeString(secret)
+	assert.NoError(t, err)
+	assert.Len(t, key, totpSecretSize)
+	code, err := GenerateTOTPCode(secret, time.Now())
+	assert.NoError(t, err)
+	_, ok := ValidateTOTP(secret, code, time.Now(), 0)
+	assert.True(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	assert.Equal(t,
+		"otpauth://totp/Perses:jdoe?algorithm=SHA1&digits=6&issuer=Perses&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		TOTPURI("Perses", "jdoe", rfcSecret))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes(3)
+	assert.NoError(t, err)
+	assert.Len(t, codes, 3)
+	assert.Len(t, hashes, 3)
+	assert.Equal(t, 1, MatchRecoveryCode(hashes, codes[1]))
+	assert.Equal(t, 2, MatchRecoveryCode(hashes, strings.ToUpper(strings.ReplaceAll(codes[2], "-", ""))))
+	assert.Equal(t, -1, MatchRecoveryCode(hashes, "0000-0000-0000-0000"))
+	assert.Equal(t, -1, MatchRecoveryCode(hashes, ""))
+}
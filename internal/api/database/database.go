@@ -21,47 +21,79 @@ import (
 
 	"github.com/go-sql-driver/mysql"
 	databaseFile "github.com/perses/perses/internal/api/database/file"
+	databaseK8s "github.com/perses/perses/internal/api/database/kubernetes"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	databaseSQL "github.com/perses/perses/internal/api/database/sql"
+	clientConfig "github.com/perses/perses/pkg/client/config"
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
+	"k8s.io/client-go/dynamic"
 )
 
 type dao struct {
 	databaseModel.DAO
 	client databaseModel.DAO
+	// k8s stores some kinds as custom resources. It is nil when the Kubernetes storage is not configured.
+	k8s *databaseK8s.DAO
+}
+
+// clientForKind returns the database storing the given kind.
+func (d *dao) clientForKind(kind modelV1.Kind) databaseModel.DAO {
+	if d.k8s != nil && d.k8s.IsManaged(kind) {
+		return d.k8s
+	}
+	return d.client
+}
+
+// clientForQuery returns the database storing the kind concerned by the query.
+func (d *dao) clientForQuery(query databaseModel.Query) databaseModel.DAO {
+	if d.k8s != nil && d.k8s.IsManagedQuery(query) {
+		return d.k8s
+	}
+	return d.client
 }
 
 func (d *dao) Close() error {
+	if d.k8s != nil {
+		if err := d.k8s.Close(); err != nil {
+			return err
+		}
+	}
 	return d.client.Close()
 }
 
 func (d *dao) Init() error {
-	return d.client.Init()
+	if err := d.client.Init(); err != nil {
+		return err
+	}
+	if d.k8s != nil {
+		return d.k8s.Init()
+	}
+	return nil
 }
 func (d *dao) IsCaseSensitive() bool {
 	return d.client.IsCaseSensitive()
 }
 func (d *dao) Create(entity modelAPI.Entity) error {
-	return d.client.Create(entity)
+	return d.clientForKind(modelV1.Kind(entity.GetKind())).Create(entity)
 }
 func (d *dao) Upsert(entity modelAPI.Entity) error {
-	return d.client.Upsert(entity)
+	return d.clientForKind(modelV1.Kind(entity.GetKind())).Upsert(entity)
 }
 func (d *dao) Get(kind modelV1.Kind, metadata modelAPI.Metadata, entity modelAPI.Entity) error {
-	return d.client.Get(kind, metadata, entity)
+	return d.clientForKind(kind).Get(kind, metadata, entity)
 }
 func (d *dao) Query(query databaseModel.Query, slice any) error {
-	return d.client.Query(query, slice)
+	return d.clientForQuery(query).Query(query, slice)
 }
 func (d *dao) RawQuery(query databaseModel.Query) ([]json.RawMessage, error) {
-	return d.client.RawQuery(query)
+	return d.clientForQuery(query).RawQuery(query)
 }
 func (d *dao) RawMetadataQuery(query databaseModel.Query, kind modelV1.Kind) ([]json.RawMessage, error) {
-	raws, err := d.client.RawQuery(query)
+	raws, err := d.clientForQuery(query).RawQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +106,15 @@ func (d *dao) RawMetadataQuery(query databaseModel.Query, kind modelV1.Kind) ([]
 	return result, nil
 }
 func (d *dao) Delete(kind modelV1.Kind, metadata modelAPI.Metadata) error {
-	return d.client.Delete(kind, metadata)
+	return d.clientForKind(kind).Delete(kind, metadata)
 }
 func (d *dao) DeleteByQuery(query databaseModel.Query) error {
-	return d.client.DeleteByQuery(query)
+	return d.clientForQuery(query).DeleteByQuery(query)
 }
 func (d *dao) HealthCheck() bool {
+	if d.k8s != nil && !d.k8s.HealthCheck() {
+		return false
+	}
 	return d.client.HealthCheck()
 }
 func (d *dao) GetLatestUpdateTime(kind []modelV1.Kind) (*string, error) {
@@ -150,5 +185,25 @@ func New(conf config.Database) (databaseModel.DAO, error) {
 	} else {
 		return nil, fmt.Errorf("no dao defined")
 	}
-	return &dao{client: client}, nil
+	result := &dao{client: client}
+	if conf.Kubernetes != nil {
+		k8sDAO, err := newKubernetesDAO(*conf.Kubernetes, client.IsCaseSensitive())
+		if err != nil {
+			return nil, err
+		}
+		result.k8s = k8sDAO
+	}
+	return result, nil
+}
+
+func newKubernetesDAO(conf config.KubernetesDatabase, caseSensitive bool) (*databaseK8s.DAO, error) {
+	kubeconfig, err := clientConfig.InitKubeConfig(conf.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return databaseK8s.New(client, conf, caseSensitive)
 }
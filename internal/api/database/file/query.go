@@ -29,7 +29,9 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/group"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
@@ -92,9 +94,15 @@ func (d *DAO) buildQuery(query databaseModel.Query) (pathFolder string, prefix s
 	case *globalvariable.Query:
 		pathFolder = d.generateResourceQuery(v1.KindGlobalVariable)
 		prefix = qt.NamePrefix
+	case *group.Query:
+		pathFolder = d.generateResourceQuery(v1.KindGroup)
+		prefix = qt.NamePrefix
 	case *project.Query:
 		pathFolder = d.generateResourceQuery(v1.KindProject)
 		prefix = qt.NamePrefix
+	case *projecttemplate.Query:
+		pathFolder = d.generateResourceQuery(v1.KindProjectTemplate)
+		prefix = qt.NamePrefix
 	case *role.Query:
 		pathFolder = d.generateProjectResourceQuery(v1.KindRole, qt.Project)
 		prefix = qt.NamePrefix
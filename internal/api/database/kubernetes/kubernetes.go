@@ -0,0 +1,365 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databasek8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DAO stores the resources as Perses custom resources through the Kubernetes API.
+// A Perses project is a Kubernetes namespace.
+//
+// The reads are served by a cache kept up to date by watching the custom resources,
+// so a change made with kubectl is immediately visible through the Perses API and vice versa.
+// Only the kinds returned by IsManaged are supported, the other ones must be stored in another database.
+type DAO struct {
+	databaseModel.DAO
+	client        dynamic.Interface
+	caseSensitive bool
+	syncTimeout   time.Duration
+	resources     map[modelV1.Kind]*resource
+	factory       dynamicinformer.DynamicSharedInformerFactory
+	stopCh        chan struct{}
+}
+
+func New(client dynamic.Interface, conf config.KubernetesDatabase, caseSensitive bool) (*DAO, error) {
+	gv, err := schema.ParseGroupVersion(conf.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api version %q: %w", conf.APIVersion, err)
+	}
+	d := &DAO{
+		client:        client,
+		caseSensitive: caseSensitive,
+		syncTimeout:   time.Duration(conf.SyncTimeout),
+		resources:     make(map[modelV1.Kind]*resource, len(conf.Kinds)),
+		factory:       dynamicinformer.NewDynamicSharedInformerFactory(client, time.Duration(conf.ResyncInterval)),
+		stopCh:        make(chan struct{}),
+	}
+	for _, kind := range conf.Kinds {
+		r := newResource(gv, kind)
+		r.informer = d.factory.ForResource(r.gvr)
+		d.resources[kind] = r
+	}
+	return d, nil
+}
+
+// IsManaged returns true if the given kind is stored as a custom resource.
+func (d *DAO) IsManaged(kind modelV1.Kind) bool {
+	_, ok := d.resources[kind]
+	return ok
+}
+
+// IsManagedQuery returns true if the given query concerns a kind stored as a custom resource.
+func (d *DAO) IsManagedQuery(query databaseModel.Query) bool {
+	kind, _, _, err := buildQuery(query)
+	return err == nil && d.IsManaged(kind)
+}
+
+// Init starts watching the custom resources and waits for the cache to be filled.
+func (d *DAO) Init() error {
+	d.factory.Start(d.stopCh)
+	ctx, cancel := context.WithTimeout(context.Background(), d.syncTimeout)
+	defer cancel()
+	for kind, r := range d.resources {
+		if !cache.WaitForCacheSync(ctx.Done(), r.informer.Informer().HasSynced) {
+			return fmt.Errorf("unable to list the %s %s, check that the custom resource definition is installed and that Perses is allowed to watch it", r.gvr.Resource, r.gvr.GroupVersion())
+		}
+		logrus.Debugf("cache of the kind %s filled from the custom resources %s", kind, r.gvr.Resource)
+	}
+	return nil
+}
+
+func (d *DAO) IsCaseSensitive() bool {
+	return d.caseSensitive
+}
+
+func (d *DAO) Close() error {
+	close(d.stopCh)
+	d.factory.Shutdown()
+	return nil
+}
+
+func (d *DAO) Create(entity modelAPI.Entity) error {
+	entity.GetMetadata().Flatten(d.caseSensitive)
+	r, err := d.getResource(modelV1.Kind(entity.GetKind()))
+	if err != nil {
+		return err
+	}
+	obj, err := r.toUnstructured(entity)
+	if err != nil {
+		return err
+	}
+	created, err := d.client.Resource(r.gvr).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+	if err != nil {
+		return r.convertError(obj.GetNamespace(), obj.GetName(), err)
+	}
+	// The watch will eventually update the cache, but we don't want a read following the write to miss it.
+	return r.informer.Informer().GetStore().Add(created)
+}
+
+func (d *DAO) Upsert(entity modelAPI.Entity) error {
+	entity.GetMetadata().Flatten(d.caseSensitive)
+	r, err := d.getResource(modelV1.Kind(entity.GetKind()))
+	if err != nil {
+		return err
+	}
+	obj, err := r.toUnstructured(entity)
+	if err != nil {
+		return err
+	}
+	client := d.client.Resource(r.gvr).Namespace(obj.GetNamespace())
+	existing, err := client.Get(context.Background(), obj.GetName(), metav1.GetOptions{})
+	var result *unstructured.Unstructured
+	switch {
+	case k8sErrors.IsNotFound(err):
+		result, err = client.Create(context.Background(), obj, metav1.CreateOptions{})
+	case err == nil:
+		// Keep what is not managed by Perses (labels, owner references, etc.)
+		// and the resource version that protects us from concurrent updates.
+		// The fields of the custom resource spec that are only used by the operator are kept as well.
+		existing.SetAnnotations(mergeAnnotations(existing.GetAnnotations(), obj.GetAnnotations()))
+		var spec any
+		if spec, err = r.getSpec(obj); err != nil {
+			return err
+		}
+		if err = r.setSpec(existing, spec); err != nil {
+			return err
+		}
+		result, err = client.Update(context.Background(), existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return r.convertError(obj.GetNamespace(), obj.GetName(), err)
+	}
+	return r.informer.Informer().GetStore().Update(result)
+}
+
+func (d *DAO) Get(kind modelV1.Kind, metadata modelAPI.Metadata, entity modelAPI.Entity) error {
+	metadata.Flatten(d.caseSensitive)
+	r, err := d.getResource(kind)
+	if err != nil {
+		return err
+	}
+	project, err := getProject(metadata)
+	if err != nil {
+		return err
+	}
+	obj, err := r.informer.Lister().ByNamespace(project).Get(metadata.GetName())
+	if err != nil {
+		return r.convertError(project, metadata.GetName(), err)
+	}
+	data, err := r.toJSON(obj.(*unstructured.Unstructured))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, entity)
+}
+
+func (d *DAO) RawMetadataQuery(_ databaseModel.Query, _ modelV1.Kind) ([]json.RawMessage, error) {
+	return nil, fmt.Errorf("raw metadata query not implemented")
+}
+
+func (d *DAO) RawQuery(query databaseModel.Query) ([]json.RawMessage, error) {
+	r, objects, err := d.list(query)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]json.RawMessage, 0, len(objects))
+	for _, obj := range objects {
+		data, convertErr := r.toJSON(obj)
+		if convertErr != nil {
+			logrus.WithError(convertErr).Errorf("%s %s/%s ignored because it is not a valid %s", r.gvr.Resource, obj.GetNamespace(), obj.GetName(), r.kind)
+			continue
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+func (d *DAO) Query(query databaseModel.Query, slice any) error {
+	typeParameter := reflect.TypeOf(slice)
+	result := reflect.ValueOf(slice)
+	// to avoid any miss usage when using this method, slice should be a pointer to a slice.
+	if typeParameter.Kind() != reflect.Ptr {
+		return fmt.Errorf("slice in parameter is not a pointer to a slice but a %q", typeParameter.Kind())
+	}
+	typeParameter = typeParameter.Elem()
+	if typeParameter.Kind() != reflect.Slice {
+		return fmt.Errorf("slice in parameter is not actually a slice but a %q", typeParameter.Kind())
+	}
+	raws, err := d.RawQuery(query)
+	if err != nil {
+		return err
+	}
+	// Initialize the slice, so we never return a nil slice.
+	sliceElem := reflect.MakeSlice(typeParameter, 0, len(raws))
+	for _, raw := range raws {
+		var value reflect.Value
+		if typeParameter.Elem().Kind() != reflect.Ptr {
+			value = reflect.New(typeParameter.Elem())
+		} else {
+			// in case it's a pointer, then we should create a pointer of the struct and not a pointer of a pointer
+			value = reflect.New(typeParameter.Elem().Elem())
+		}
+		if unmarshalErr := json.Unmarshal(raw, value.Interface()); unmarshalErr != nil {
+			return unmarshalErr
+		}
+		if typeParameter.Elem().Kind() != reflect.Ptr {
+			sliceElem = reflect.Append(sliceElem, value.Elem())
+		} else {
+			sliceElem = reflect.Append(sliceElem, value)
+		}
+	}
+	result.Elem().Set(sliceElem)
+	return nil
+}
+
+func (d *DAO) Delete(kind modelV1.Kind, metadata modelAPI.Metadata) error {
+	r, err := d.getResource(kind)
+	if err != nil {
+		return err
+	}
+	project, err := getProject(metadata)
+	if err != nil {
+		return err
+	}
+	return d.delete(r, project, metadata.GetName())
+}
+
+func (d *DAO) DeleteByQuery(query databaseModel.Query) error {
+	r, objects, err := d.list(query)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if deleteErr := d.delete(r, obj.GetNamespace(), obj.GetName()); deleteErr != nil && !databaseModel.IsKeyNotFound(deleteErr) {
+			return deleteErr
+		}
+	}
+	return nil
+}
+
+func (d *DAO) HealthCheck() bool {
+	for _, r := range d.resources {
+		if !r.informer.Informer().HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *DAO) GetLatestUpdateTime(_ []modelV1.Kind) (*string, error) {
+	return nil, nil
+}
+
+func (d *DAO) getResource(kind modelV1.Kind) (*resource, error) {
+	r, ok := d.resources[kind]
+	if !ok {
+		return nil, fmt.Errorf("the kind %q is not stored in Kubernetes", kind)
+	}
+	return r, nil
+}
+
+func (d *DAO) delete(r *resource, project string, name string) error {
+	err := d.client.Resource(r.gvr).Namespace(project).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil {
+		return r.convertError(project, name, err)
+	}
+	return r.informer.Informer().GetStore().Delete(&unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": name, "namespace": project},
+	}})
+}
+
+// list returns the custom resources matching the query, sorted by namespace and name.
+func (d *DAO) list(query databaseModel.Query) (*resource, []*unstructured.Unstructured, error) {
+	kind, project, prefix, err := buildQuery(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := d.getResource(kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	var objects []runtime.Object
+	if len(project) == 0 {
+		objects, err = r.informer.Lister().List(labels.Everything())
+	} else {
+		if !d.caseSensitive {
+			project = strings.ToLower(project)
+		}
+		objects, err = r.informer.Lister().ByNamespace(project).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !d.caseSensitive {
+		prefix = strings.ToLower(prefix)
+	}
+	result := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, object := range objects {
+		obj := object.(*unstructured.Unstructured)
+		name := obj.GetName()
+		if !d.caseSensitive {
+			name = strings.ToLower(name)
+		}
+		if strings.HasPrefix(name, prefix) {
+			result = append(result, obj)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].GetNamespace() != result[j].GetNamespace() {
+			return result[i].GetNamespace() < result[j].GetNamespace()
+		}
+		return result[i].GetName() < result[j].GetName()
+	})
+	return r, result, nil
+}
+
+func getProject(metadata modelAPI.Metadata) (string, error) {
+	m, ok := metadata.(*modelV1.ProjectMetadata)
+	if !ok {
+		return "", fmt.Errorf("metadata %T not managed", metadata)
+	}
+	return m.Project, nil
+}
+
+func mergeAnnotations(existing map[string]string, annotations map[string]string) map[string]string {
+	if existing == nil {
+		existing = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		existing[k] = v
+	}
+	return existing
+}
@@ -0,0 +1,295 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databasek8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	variableInterface "github.com/perses/perses/internal/api/interface/v1/variable"
+	"github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/spec/go/dashboard/variable"
+	"github.com/stretchr/testify/assert"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func newTestDAO(t *testing.T) (*DAO, *fake.FakeDynamicClient) {
+	conf := config.KubernetesDatabase{}
+	assert.NoError(t, conf.Verify())
+	gv := schema.GroupVersion{Group: "perses.dev", Version: "v1alpha1"}
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, kind := range conf.Kinds {
+		r := newResource(gv, kind)
+		listKinds[r.gvr] = r.gvk.Kind + "List"
+	}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	d, err := New(client, conf, false)
+	assert.NoError(t, err)
+	assert.NoError(t, d.Init())
+	t.Cleanup(func() {
+		assert.NoError(t, d.Close())
+	})
+	return d, client
+}
+
+func newVariable(project string, name string) *modelV1.Variable {
+	entity := &modelV1.Variable{
+		Kind:     modelV1.KindVariable,
+		Metadata: *modelV1.NewProjectMetadata(project, name),
+		Spec: modelV1.VariableSpec{
+			Kind: variable.KindText,
+			Spec: &variable.TextSpec{
+				Value: "value",
+			},
+		},
+	}
+	entity.Metadata.CreateNow()
+	return entity
+}
+
+func TestDAO_CreateAndGet(t *testing.T) {
+	d, client := newTestDAO(t)
+	entity := newVariable("perses", "MyVariable")
+	entity.Metadata.Tags = map[string]struct{}{"team": {}}
+	assert.NoError(t, d.Create(entity))
+	assert.True(t, databaseModel.IsKeyConflict(d.Create(newVariable("perses", "myvariable"))))
+
+	// The variable is stored as a custom resource in the namespace of the project.
+	obj, err := client.Resource(newResource(schema.GroupVersion{Group: "perses.dev", Version: "v1alpha1"}, modelV1.KindVariable).gvr).
+		Namespace("perses").Get(context.Background(), "myvariable", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "PersesVariable", obj.GetKind())
+	assert.Equal(t, "TextVariable", obj.Object["spec"].(map[string]any)["kind"])
+
+	result := &modelV1.Variable{}
+	assert.NoError(t, d.Get(modelV1.KindVariable, modelV1.NewProjectMetadata("perses", "myvariable"), result))
+	assert.Equal(t, entity, result)
+
+	notFound := d.Get(modelV1.KindVariable, modelV1.NewProjectMetadata("perses", "unknown"), &modelV1.Variable{})
+	assert.True(t, databaseModel.IsKeyNotFound(notFound))
+}
+
+func TestDAO_Upsert(t *testing.T) {
+	d, _ := newTestDAO(t)
+	entity := newVariable("perses", "myvariable")
+	assert.NoError(t, d.Upsert(entity))
+
+	updated := newVariable("perses", "myvariable")
+	updated.Metadata.Update(entity.Metadata)
+	updated.Spec.Spec = &variable.TextSpec{Value: "new value"}
+	assert.NoError(t, d.Upsert(updated))
+
+	result := &modelV1.Variable{}
+	assert.NoError(t, d.Get(modelV1.KindVariable, modelV1.NewProjectMetadata("perses", "myvariable"), result))
+	assert.Equal(t, uint64(1), result.Metadata.Version)
+	assert.Equal(t, "new value", result.Spec.Spec.(*variable.TextSpec).Value)
+}
+
+func TestDAO_QueryAndDelete(t *testing.T) {
+	d, _ := newTestDAO(t)
+	assert.NoError(t, d.Create(newVariable("perses", "prefix-b")))
+	assert.NoError(t, d.Create(newVariable("perses", "prefix-a")))
+	assert.NoError(t, d.Create(newVariable("perses", "other")))
+	assert.NoError(t, d.Create(newVariable("team", "prefix-c")))
+
+	var result []*modelV1.Variable
+	assert.NoError(t, d.Query(&variableInterface.Query{Project: "perses", NamePrefix: "Prefix"}, &result))
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, "prefix-a", result[0].Metadata.Name)
+		assert.Equal(t, "prefix-b", result[1].Metadata.Name)
+	}
+
+	assert.NoError(t, d.Query(&variableInterface.Query{}, &result))
+	assert.Len(t, result, 4)
+
+	assert.NoError(t, d.Delete(modelV1.KindVariable, modelV1.NewProjectMetadata("perses", "other")))
+	assert.True(t, databaseModel.IsKeyNotFound(d.Delete(modelV1.KindVariable, modelV1.NewProjectMetadata("perses", "other"))))
+
+	assert.NoError(t, d.DeleteByQuery(&variableInterface.Query{Project: "perses"}))
+	assert.NoError(t, d.Query(&variableInterface.Query{}, &result))
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "team", result[0].Metadata.Project)
+	}
+}
+
+// TestDAO_Watch checks that a custom resource managed outside Perses (e.g. with kubectl) is visible through the DAO.
+func TestDAO_Watch(t *testing.T) {
+	d, client := newTestDAO(t)
+	r := newResource(schema.GroupVersion{Group: "perses.dev", Version: "v1alpha1"}, modelV1.KindVariable)
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"kind": "TextVariable",
+			"spec": map[string]any{"value": "value"},
+		},
+	}}
+	obj.SetGroupVersionKind(r.gvk)
+	obj.SetName("kubectl")
+	obj.SetNamespace("perses")
+	_, err := client.Resource(r.gvr).Namespace("perses").Create(context.Background(), obj, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return d.Get(modelV1.KindVariable, modelV1.NewProjectMetadata("perses", "kubectl"), &modelV1.Variable{}) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, client.Resource(r.gvr).Namespace("perses").Delete(context.Background(), "kubectl", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		var result []*modelV1.Variable
+		return d.Query(&variableInterface.Query{Project: "perses"}, &result) == nil && len(result) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func loadCustomResource(t *testing.T, file string) *unstructured.Unstructured {
+	data, err := os.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &unstructured.Unstructured{}
+	if unmarshalErr := yaml.Unmarshal(data, &obj.Object); unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	return obj
+}
+
+// TestResource_OperatorCustomResources checks that the custom resources defined by the Perses operator
+// are read as Perses resources, and that Perses writes them back with the same layout.
+func TestResource_OperatorCustomResources(t *testing.T) {
+	testSuites := []struct {
+		file         string
+		kind         modelV1.Kind
+		expectedPath []string
+	}{
+		{
+			file:         "v1alpha1/persesdashboard.yaml",
+			kind:         modelV1.KindDashboard,
+			expectedPath: []string{"spec"},
+		},
+		{
+			file:         "v1alpha1/persesdatasource.yaml",
+			kind:         modelV1.KindDatasource,
+			expectedPath: []string{"spec", "config"},
+		},
+		{
+			file:         "v1alpha2/persesdashboard.yaml",
+			kind:         modelV1.KindDashboard,
+			expectedPath: []string{"spec", "config"},
+		},
+		{
+			file:         "v1alpha2/persesdatasource.yaml",
+			kind:         modelV1.KindDatasource,
+			expectedPath: []string{"spec", "config"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.file, func(t *testing.T) {
+			obj := loadCustomResource(t, test.file)
+			r := newResource(obj.GroupVersionKind().GroupVersion(), test.kind)
+			assert.Equal(t, obj.GetKind(), r.gvk.Kind)
+			assert.Equal(t, test.expectedPath, r.specPath)
+
+			data, err := r.toJSON(obj)
+			assert.NoError(t, err)
+			entity, err := modelV1.GetStruct(test.kind)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(data, entity))
+			assert.Equal(t, "monitoring", entity.GetMetadata().(*modelV1.ProjectMetadata).Project)
+			assert.Equal(t, obj.GetName(), entity.GetMetadata().GetName())
+
+			converted, err := r.toUnstructured(entity)
+			assert.NoError(t, err)
+			expectedSpec, _, _ := unstructured.NestedFieldNoCopy(obj.Object, test.expectedPath...)
+			spec, _, _ := unstructured.NestedFieldNoCopy(converted.Object, test.expectedPath...)
+			expectedJSON, _ := json.Marshal(expectedSpec)
+			specJSON, _ := json.Marshal(spec)
+			assert.JSONEq(t, string(expectedJSON), string(specJSON))
+		})
+	}
+}
+
+// TestDAO_UpsertKeepsOperatorFields checks that the fields only used by the operator are kept when Perses updates a resource.
+func TestDAO_UpsertKeepsOperatorFields(t *testing.T) {
+	d, client := newTestDAO(t)
+	obj := loadCustomResource(t, "v1alpha1/persesdatasource.yaml")
+	r := newResource(obj.GroupVersionKind().GroupVersion(), modelV1.KindDatasource)
+	_, err := client.Resource(r.gvr).Namespace("monitoring").Create(context.Background(), obj, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	entity := &modelV1.Datasource{}
+	data, err := r.toJSON(obj)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, entity))
+	entity.Spec.Default = false
+	assert.NoError(t, d.Upsert(entity))
+
+	updated, err := client.Resource(r.gvr).Namespace("monitoring").Get(context.Background(), "prometheus", metav1.GetOptions{})
+	assert.NoError(t, err)
+	isDefault, _, _ := unstructured.NestedBool(updated.Object, "spec", "config", "default")
+	assert.False(t, isDefault)
+	caName, _, _ := unstructured.NestedString(updated.Object, "spec", "client", "tls", "caCert", "name")
+	assert.Equal(t, "prometheus-ca", caName)
+}
+
+func TestDAO_CreateRejected(t *testing.T) {
+	testSuites := []struct {
+		title  string
+		name   string
+		err    error
+		result string
+	}{
+		{
+			title:  "missing namespace",
+			name:   "myvariable",
+			err:    k8sErrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "unknown"),
+			result: `the namespace "unknown" of the project doesn't exist`,
+		},
+		{
+			title: "name rejected by Kubernetes",
+			name:  "my_variable",
+			err: k8sErrors.NewInvalid(schema.GroupKind{Group: "perses.dev", Kind: "PersesVariable"}, "my_variable", field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "name"), "my_variable", "a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters"),
+			}),
+			result: `the Variable "my_variable" can't be stored in Kubernetes`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			d, client := newTestDAO(t)
+			reactor := func(k8sTesting.Action) (bool, runtime.Object, error) {
+				return true, nil, test.err
+			}
+			client.PrependReactor("create", "*", reactor)
+			client.PrependReactor("update", "*", reactor)
+			err := d.Create(newVariable("unknown", test.name))
+			assert.True(t, errors.Is(err, apiInterface.BadRequestError))
+			assert.ErrorContains(t, err, test.result)
+			assert.True(t, errors.Is(d.Upsert(newVariable("unknown", test.name)), apiInterface.BadRequestError))
+		})
+	}
+}
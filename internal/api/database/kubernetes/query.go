@@ -0,0 +1,37 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databasek8s
+
+import (
+	"fmt"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/variable"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+func buildQuery(query databaseModel.Query) (kind v1.Kind, project string, prefix string, err error) {
+	switch qt := query.(type) {
+	case *dashboard.Query:
+		return v1.KindDashboard, qt.Project, qt.NamePrefix, nil
+	case *datasource.Query:
+		return v1.KindDatasource, qt.Project, qt.NamePrefix, nil
+	case *variable.Query:
+		return v1.KindVariable, qt.Project, qt.NamePrefix, nil
+	default:
+		return "", "", "", fmt.Errorf("this type of query '%T' is not managed", qt)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databasek8s
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+)
+
+// MetadataAnnotation holds the Perses metadata that have no equivalent in the Kubernetes metadata
+// (creation and update time, version, tags, etc.), so they survive a round trip through the Kubernetes API.
+const MetadataAnnotation = "perses.dev/metadata"
+
+// resource is the custom resource used to store a Perses kind.
+type resource struct {
+	kind modelV1.Kind
+	gvk  schema.GroupVersionKind
+	gvr  schema.GroupVersionResource
+	// specPath is the path of the Perses spec in the custom resource.
+	specPath []string
+	informer informers.GenericInformer
+}
+
+// newResource returns the custom resource used by the Perses operator for the given kind.
+// For example, the dashboards are stored as PersesDashboard (persesdashboards).
+func newResource(gv schema.GroupVersion, kind modelV1.Kind) *resource {
+	specPath := []string{"spec"}
+	if field := specField(gv.Version, kind); len(field) > 0 {
+		specPath = append(specPath, field)
+	}
+	return &resource{
+		kind:     kind,
+		gvk:      gv.WithKind("Perses" + string(kind)),
+		gvr:      gv.WithResource("perses" + modelV1.PluralKindMap[kind]),
+		specPath: specPath,
+	}
+}
+
+// specField returns the field of the custom resource spec that holds the Perses spec,
+// following the custom resource definitions of the Perses operator.
+// An empty field means the Perses spec is the custom resource spec itself.
+func specField(version string, kind modelV1.Kind) string {
+	switch kind {
+	case modelV1.KindDatasource:
+		// The other fields of a PersesDatasource (like spec.client) are only used by the operator.
+		return "config"
+	case modelV1.KindDashboard:
+		// The dashboard is inlined in the spec of a PersesDashboard v1alpha1, it is moved to spec.config afterward.
+		if version == "v1alpha1" {
+			return ""
+		}
+		return "config"
+	default:
+		// The operator doesn't provide any custom resource for the variables, the spec of a PersesVariable is the variable itself.
+		return ""
+	}
+}
+
+// key returns the key used in the errors returned by the DAO. It follows the same format as the file database.
+func (r *resource) key(project string, name string) string {
+	return path.Join(modelV1.PluralKindMap[r.kind], project, name)
+}
+
+func (r *resource) convertError(project string, name string, err error) error {
+	switch {
+	case isNamespaceNotFound(err):
+		return apiInterface.HandleBadRequestError(fmt.Sprintf("the namespace %q of the project doesn't exist, it must be created before storing any %s in it", project, r.kind))
+	case k8sErrors.IsInvalid(err):
+		// Kubernetes is stricter than Perses on the names (lowercase, length, etc.).
+		return apiInterface.HandleBadRequestError(fmt.Sprintf("the %s %q can't be stored in Kubernetes: %s", r.kind, name, err))
+	case k8sErrors.IsNotFound(err):
+		return &databaseModel.Error{Key: r.key(project, name), Code: databaseModel.ErrorCodeNotFound}
+	case k8sErrors.IsAlreadyExists(err), k8sErrors.IsConflict(err):
+		return &databaseModel.Error{Key: r.key(project, name), Code: databaseModel.ErrorCodeConflict}
+	}
+	return err
+}
+
+// isNamespaceNotFound returns true if the error is due to a missing namespace,
+// which happens when a resource is created in a project that has no namespace.
+func isNamespaceNotFound(err error) bool {
+	if !k8sErrors.IsNotFound(err) {
+		return false
+	}
+	var status k8sErrors.APIStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+	details := status.Status().Details
+	return details != nil && details.Kind == "namespaces"
+}
+
+// getSpec returns the Perses spec held by the custom resource.
+func (r *resource) getSpec(obj *unstructured.Unstructured) (any, error) {
+	spec, _, err := unstructured.NestedFieldNoCopy(obj.Object, r.specPath...)
+	return spec, err
+}
+
+// setSpec replaces the Perses spec held by the custom resource. The other fields of the custom resource spec are kept.
+func (r *resource) setSpec(obj *unstructured.Unstructured, spec any) error {
+	return unstructured.SetNestedField(obj.Object, spec, r.specPath...)
+}
+
+// toUnstructured converts a Perses entity to its custom resource.
+func (r *resource) toUnstructured(entity modelAPI.Entity) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var content struct {
+		Metadata map[string]any `json:"metadata"`
+		Spec     any            `json:"spec"`
+	}
+	if unmarshalErr := json.Unmarshal(data, &content); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	project, _ := content.Metadata["project"].(string)
+	if len(project) == 0 {
+		return nil, fmt.Errorf("%s %q cannot be stored in Kubernetes without a project", r.kind, entity.GetMetadata().GetName())
+	}
+	// The name and the project are carried by the Kubernetes metadata, there is no need to keep them twice.
+	delete(content.Metadata, "name")
+	delete(content.Metadata, "project")
+	metadata, err := json.Marshal(content.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: make(map[string]any)}
+	if setErr := r.setSpec(obj, content.Spec); setErr != nil {
+		return nil, setErr
+	}
+	obj.SetGroupVersionKind(r.gvk)
+	obj.SetName(entity.GetMetadata().GetName())
+	obj.SetNamespace(project)
+	obj.SetAnnotations(map[string]string{MetadataAnnotation: string(metadata)})
+	return obj, nil
+}
+
+// toJSON converts a custom resource to the JSON of the Perses entity.
+func (r *resource) toJSON(obj *unstructured.Unstructured) (json.RawMessage, error) {
+	metadata := make(map[string]any)
+	if raw, ok := obj.GetAnnotations()[MetadataAnnotation]; ok && len(strings.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			return nil, fmt.Errorf("invalid annotation %q: %w", MetadataAnnotation, err)
+		}
+	} else {
+		// The custom resource has been created outside Perses (with kubectl for example).
+		creation := obj.GetCreationTimestamp().UTC()
+		metadata["createdAt"] = creation
+		metadata["updatedAt"] = creation
+	}
+	metadata["name"] = obj.GetName()
+	metadata["project"] = obj.GetNamespace()
+	spec, err := r.getSpec(obj)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(map[string]any{
+		"kind":     r.kind,
+		"metadata": metadata,
+		"spec":     spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Validate the custom resource, as it may have been modified outside Perses.
+	entity, err := modelV1.GetStruct(r.kind)
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr := json.Unmarshal(data, entity); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return data, nil
+}
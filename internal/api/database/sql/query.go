@@ -29,7 +29,9 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/group"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
@@ -132,8 +134,12 @@ func (d *DAO) buildQuery(query databaseModel.Query) (string, []any, error) {
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableGlobalSecret), "", qt.NamePrefix)
 	case *globalvariable.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableGlobalVariable), "", qt.NamePrefix)
+	case *group.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableGroup), "", qt.NamePrefix)
 	case *project.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableProject), "", qt.NamePrefix)
+	case *projecttemplate.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableProjectTemplate), "", qt.NamePrefix)
 	case *role.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableRole), qt.Project, qt.NamePrefix)
 	case *rolebinding.Query:
@@ -191,8 +197,12 @@ func (d *DAO) buildDeleteQuery(query databaseModel.Query) (string, []any, error)
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableGlobalSecret), "", qt.NamePrefix)
 	case *globalvariable.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableGlobalVariable), "", qt.NamePrefix)
+	case *group.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableGroup), "", qt.NamePrefix)
 	case *project.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableProject), "", qt.NamePrefix)
+	case *projecttemplate.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableProjectTemplate), "", qt.NamePrefix)
 	case *role.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableRole), qt.Project, qt.NamePrefix)
 	case *rolebinding.Query:
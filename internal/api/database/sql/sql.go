@@ -38,7 +38,9 @@ const (
 	tableGlobalRoleBinding  = "globalrolebinding"
 	tableGlobalSecret       = "globalsecret"
 	tableGlobalVariable     = "globalvariable"
+	tableGroup              = "usergroup" // "group" is a reserved keyword in SQL
 	tableProject            = "project"
+	tableProjectTemplate    = "projecttemplate"
 	tableRole               = "role"
 	tableRoleBinding        = "rolebinding"
 	tableSecret             = "secret"
@@ -71,8 +73,12 @@ func getTableName(kind modelV1.Kind) (string, error) {
 		return tableGlobalSecret, nil
 	case modelV1.KindGlobalVariable:
 		return tableGlobalVariable, nil
+	case modelV1.KindGroup:
+		return tableGroup, nil
 	case modelV1.KindProject:
 		return tableProject, nil
+	case modelV1.KindProjectTemplate:
+		return tableProjectTemplate, nil
 	case modelV1.KindRole:
 		return tableRole, nil
 	case modelV1.KindRoleBinding:
@@ -112,7 +118,9 @@ func (d *DAO) Init() error {
 		d.createResourceTable(tableGlobalRoleBinding),
 		d.createResourceTable(tableGlobalSecret),
 		d.createResourceTable(tableGlobalVariable),
+		d.createResourceTable(tableGroup),
 		d.createResourceTable(tableProject),
+		d.createResourceTable(tableProjectTemplate),
 		d.createResourceTable(tableUser),
 
 		d.createProjectResourceTable(tableDashboard),
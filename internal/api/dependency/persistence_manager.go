@@ -25,8 +25,10 @@ import (
 	globalRoleBindingImpl "github.com/perses/perses/internal/api/impl/v1/globalrolebinding"
 	globalSecretImpl "github.com/perses/perses/internal/api/impl/v1/globalsecret"
 	globalVariableImpl "github.com/perses/perses/internal/api/impl/v1/globalvariable"
+	groupImpl "github.com/perses/perses/internal/api/impl/v1/group"
 	healthImpl "github.com/perses/perses/internal/api/impl/v1/health"
 	projectImpl "github.com/perses/perses/internal/api/impl/v1/project"
+	projectTemplateImpl "github.com/perses/perses/internal/api/impl/v1/projecttemplate"
 	roleImpl "github.com/perses/perses/internal/api/impl/v1/role"
 	roleBindingImpl "github.com/perses/perses/internal/api/impl/v1/rolebinding"
 	secretImpl "github.com/perses/perses/internal/api/impl/v1/secret"
@@ -41,8 +43,10 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/group"
 	"github.com/perses/perses/internal/api/interface/v1/health"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
@@ -61,9 +65,11 @@ type PersistenceManager interface {
 	GetGlobalRoleBinding() globalrolebinding.DAO
 	GetGlobalSecret() globalsecret.DAO
 	GetGlobalVariable() globalvariable.DAO
+	GetGroup() group.DAO
 	GetHealth() health.DAO
 	GetPersesDAO() databaseModel.DAO
 	GetProject() project.DAO
+	GetProjectTemplate() projecttemplate.DAO
 	GetRole() role.DAO
 	GetRoleBinding() rolebinding.DAO
 	GetSecret() secret.DAO
@@ -82,9 +88,11 @@ type persistence struct {
 	globalRoleBinding  globalrolebinding.DAO
 	globalSecret       globalsecret.DAO
 	globalVariable     globalvariable.DAO
+	group              group.DAO
 	health             health.DAO
 	perses             databaseModel.DAO
 	project            project.DAO
+	projectTemplate    projecttemplate.DAO
 	role               role.DAO
 	roleBinding        rolebinding.DAO
 	secret             secret.DAO
@@ -106,8 +114,10 @@ func newPersistenceManager(conf config.Database) (PersistenceManager, error) {
 	globalRoleBindingDAO := globalRoleBindingImpl.NewDAO(persesDAO)
 	globalSecretDAO := globalSecretImpl.NewDAO(persesDAO)
 	globalVariableDAO := globalVariableImpl.NewDAO(persesDAO)
+	groupDAO := groupImpl.NewDAO(persesDAO)
 	healthDAO := healthImpl.NewDAO(persesDAO)
 	projectDAO := projectImpl.NewDAO(persesDAO)
+	projectTemplateDAO := projectTemplateImpl.NewDAO(persesDAO)
 	roleDAO := roleImpl.NewDAO(persesDAO)
 	roleBindingDAO := roleBindingImpl.NewDAO(persesDAO)
 	secretDAO := secretImpl.NewDAO(persesDAO)
@@ -123,9 +133,11 @@ func newPersistenceManager(conf config.Database) (PersistenceManager, error) {
 		globalRoleBinding:  globalRoleBindingDAO,
 		globalSecret:       globalSecretDAO,
 		globalVariable:     globalVariableDAO,
+		group:              groupDAO,
 		health:             healthDAO,
 		perses:             persesDAO,
 		project:            projectDAO,
+		projectTemplate:    projectTemplateDAO,
 		role:               roleDAO,
 		roleBinding:        roleBindingDAO,
 		secret:             secretDAO,
@@ -170,6 +182,10 @@ func (p *persistence) GetGlobalVariable() globalvariable.DAO {
 	return p.globalVariable
 }
 
+func (p *persistence) GetGroup() group.DAO {
+	return p.group
+}
+
 func (p *persistence) GetHealth() health.DAO {
 	return p.health
 }
@@ -182,6 +198,10 @@ func (p *persistence) GetProject() project.DAO {
 	return p.project
 }
 
+func (p *persistence) GetProjectTemplate() projecttemplate.DAO {
+	return p.projectTemplate
+}
+
 func (p *persistence) GetRole() role.DAO {
 	return p.role
 }
@@ -27,8 +27,10 @@ import (
 	globalRoleBindingImpl "github.com/perses/perses/internal/api/impl/v1/globalrolebinding"
 	globalSecretImpl "github.com/perses/perses/internal/api/impl/v1/globalsecret"
 	globalVariableImpl "github.com/perses/perses/internal/api/impl/v1/globalvariable"
+	groupImpl "github.com/perses/perses/internal/api/impl/v1/group"
 	healthImpl "github.com/perses/perses/internal/api/impl/v1/health"
 	projectImpl "github.com/perses/perses/internal/api/impl/v1/project"
+	projectTemplateImpl "github.com/perses/perses/internal/api/impl/v1/projecttemplate"
 	roleImpl "github.com/perses/perses/internal/api/impl/v1/role"
 	roleBindingImpl "github.com/perses/perses/internal/api/impl/v1/rolebinding"
 	secretImpl "github.com/perses/perses/internal/api/impl/v1/secret"
@@ -44,8 +46,10 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/group"
 	"github.com/perses/perses/internal/api/interface/v1/health"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
@@ -53,6 +57,7 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/variable"
 	"github.com/perses/perses/internal/api/interface/v1/view"
 	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/internal/api/plugin/export"
 	"github.com/perses/perses/internal/api/plugin/migrate"
 	"github.com/perses/perses/internal/api/plugin/schema"
 	"github.com/perses/perses/pkg/model/api/config"
@@ -64,17 +69,20 @@ type ServiceManager interface {
 	GetDashboard() dashboard.Service
 	GetDatasource() datasource.Service
 	GetEphemeralDashboard() ephemeraldashboard.Service
+	GetExport() export.Export
 	GetFolder() folder.Service
 	GetGlobalDatasource() globaldatasource.Service
 	GetGlobalRole() globalrole.Service
 	GetGlobalRoleBinding() globalrolebinding.Service
 	GetGlobalSecret() globalsecret.Service
 	GetGlobalVariable() globalvariable.Service
+	GetGroup() group.Service
 	GetHealth() health.Service
 	GetJWT() crypto.JWT
 	GetMigration() migrate.Migration
 	GetPlugin() plugin.Plugin
 	GetProject() project.Service
+	GetProjectTemplate() projecttemplate.Service
 	GetSchema() schema.Schema
 	GetRole() role.Service
 	GetRoleBinding() rolebinding.Service
@@ -91,17 +99,20 @@ type service struct {
 	dashboard          dashboard.Service
 	datasource         datasource.Service
 	ephemeralDashboard ephemeraldashboard.Service
+	export             export.Export
 	folder             folder.Service
 	globalDatasource   globaldatasource.Service
 	globalRole         globalrole.Service
 	globalRoleBinding  globalrolebinding.Service
 	globalSecret       globalsecret.Service
 	globalVariable     globalvariable.Service
+	group              group.Service
 	health             health.Service
 	jwt                crypto.JWT
 	migrate            migrate.Migration
 	plugin             plugin.Plugin
 	project            project.Service
+	projectTemplate    projecttemplate.Service
 	schema             schema.Schema
 	role               role.Service
 	roleBinding        rolebinding.Service
@@ -116,13 +127,14 @@ func newServiceManager(dao PersistenceManager, conf config.Config) (ServiceManag
 	if err != nil {
 		return nil, err
 	}
-	authzService, err := authorization.New(dao.GetUser(), dao.GetRole(), dao.GetRoleBinding(), dao.GetGlobalRole(), dao.GetGlobalRoleBinding(), conf)
+	authzService, err := authorization.New(dao.GetUser(), dao.GetRole(), dao.GetRoleBinding(), dao.GetGlobalRole(), dao.GetGlobalRoleBinding(), dao.GetGroup(), conf)
 	if err != nil {
 		return nil, err
 	}
 	pluginService := plugin.New(conf.Plugin)
 	schemaService := pluginService.Schema()
 	migrateService := pluginService.Migration()
+	exportService := pluginService.Export()
 	dashboardService := dashboardImpl.NewService(conf, dao.GetDashboard(), dao.GetGlobalVariable(), dao.GetVariable(), schemaService)
 	datasourceService := datasourceImpl.NewService(dao.GetDatasource(), schemaService)
 	ephemeralDashboardService := ephemeralDashboardImpl.NewService(dao.GetEphemeralDashboard(), dao.GetGlobalVariable(), dao.GetVariable(), schemaService)
@@ -133,12 +145,16 @@ func newServiceManager(dao PersistenceManager, conf config.Config) (ServiceManag
 	globalRoleBinding := globalRoleBindingImpl.NewService(dao.GetGlobalRoleBinding(), dao.GetGlobalRole(), dao.GetUser(), authzService, schemaService)
 	globalSecret := globalSecretImpl.NewService(dao.GetGlobalSecret(), cryptoService)
 	globalVariableService := globalVariableImpl.NewService(dao.GetGlobalVariable(), schemaService)
+	groupService := groupImpl.NewService(dao.GetGroup(), authzService)
 	healthService := healthImpl.NewService(dao.GetHealth())
-	projectService := projectImpl.NewService(dao.GetProject(), dao.GetFolder(), dao.GetDatasource(), dao.GetDashboard(), dao.GetRole(), dao.GetRoleBinding(), dao.GetSecret(), dao.GetVariable(), authzService)
 	roleService := roleImpl.NewService(dao.GetRole(), authzService, schemaService)
 	roleBindingService := roleBindingImpl.NewService(dao.GetRoleBinding(), dao.GetRole(), dao.GetUser(), authzService, schemaService)
 	secretService := secretImpl.NewService(dao.GetSecret(), cryptoService)
-	userService := userImpl.NewService(dao.GetUser(), authzService)
+	// The project service instantiates the ProjectTemplate through the services of each kind, so it's created after them.
+	projectService := projectImpl.NewService(dao.GetProject(), dao.GetFolder(), dao.GetDatasource(), dao.GetDashboard(), dao.GetRole(), dao.GetRoleBinding(), dao.GetSecret(), dao.GetVariable(), dao.GetProjectTemplate(),
+		folderService, datasourceService, dashboardService, roleService, roleBindingService, secretService, variableService, authzService)
+	projectTemplateService := projectTemplateImpl.NewService(dao.GetProjectTemplate(), dao.GetProject(), projectService)
+	userService := userImpl.NewService(dao.GetUser(), authzService, cryptoService, conf.Security.Authentication.Providers.Native)
 	viewService := viewImpl.NewMetricsViewService()
 
 	svc := &service{
@@ -147,17 +163,20 @@ func newServiceManager(dao PersistenceManager, conf config.Config) (ServiceManag
 		dashboard:          dashboardService,
 		datasource:         datasourceService,
 		ephemeralDashboard: ephemeralDashboardService,
+		export:             exportService,
 		folder:             folderService,
 		globalDatasource:   globalDatasourceService,
 		globalRole:         globalRole,
 		globalRoleBinding:  globalRoleBinding,
 		globalSecret:       globalSecret,
 		globalVariable:     globalVariableService,
+		group:              groupService,
 		health:             healthService,
 		jwt:                jwtService,
 		migrate:            migrateService,
 		plugin:             pluginService,
 		project:            projectService,
+		projectTemplate:    projectTemplateService,
 		role:               roleService,
 		roleBinding:        roleBindingService,
 		schema:             schemaService,
@@ -189,6 +208,10 @@ func (s *service) GetEphemeralDashboard() ephemeraldashboard.Service {
 	return s.ephemeralDashboard
 }
 
+func (s *service) GetExport() export.Export {
+	return s.export
+}
+
 func (s *service) GetFolder() folder.Service {
 	return s.folder
 }
@@ -213,6 +236,10 @@ func (s *service) GetGlobalVariable() globalvariable.Service {
 	return s.globalVariable
 }
 
+func (s *service) GetGroup() group.Service {
+	return s.group
+}
+
 func (s *service) GetHealth() health.Service {
 	return s.health
 }
@@ -233,6 +260,10 @@ func (s *service) GetProject() project.Service {
 	return s.project
 }
 
+func (s *service) GetProjectTemplate() projecttemplate.Service {
+	return s.projectTemplate
+}
+
 func (s *service) GetSchema() schema.Schema {
 	return s.schema
 }
@@ -0,0 +1,220 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consulsd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/perses/common/async/taskhelper"
+	"github.com/perses/perses/internal/api/discovery/service"
+	"github.com/perses/perses/internal/api/discovery/target"
+	"github.com/perses/perses/internal/api/plugin/schema"
+	clientConfig "github.com/perses/perses/pkg/client/config"
+	"github.com/perses/perses/pkg/client/perseshttp"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/spec/go/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultNameTemplate = "{{ .Labels.node }}.{{ .Labels.id }}"
+	// consulService is the service registered by Consul itself, it is never a datasource.
+	consulSelfService = "consul"
+	metaLabelPrefix   = "meta_"
+)
+
+type consulNode struct {
+	Node       string `json:"Node"`
+	Address    string `json:"Address"`
+	Datacenter string `json:"Datacenter"`
+}
+
+type consulService struct {
+	ID      string            `json:"ID"`
+	Service string            `json:"Service"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Tags    []string          `json:"Tags"`
+	Meta    map[string]string `json:"Meta"`
+}
+
+// serviceEntry is an element of the response of the endpoint /v1/health/service/:service.
+type serviceEntry struct {
+	Node    consulNode    `json:"Node"`
+	Service consulService `json:"Service"`
+}
+
+type query struct {
+	datacenter string
+	passing    bool
+}
+
+func (q *query) GetValues() url.Values {
+	values := make(url.Values)
+	if len(q.datacenter) > 0 {
+		values.Set("dc", q.datacenter)
+	}
+	if q.passing {
+		values.Set("passing", "true")
+	}
+	return values
+}
+
+// NewDiscovery creates the discovery. When project is set, the datasources are created in this project.
+func NewDiscovery(discoveryName string, refreshInterval common.Duration, cfg *config.ConsulDiscovery, project string, svc *service.ApplyService, sch schema.Schema) (taskhelper.Helper, error) {
+	client, err := clientConfig.NewRESTClient(cfg.RestConfigClient)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := target.NewBuilder(discoveryName, project, cfg.DatasourcePluginKind, cfg.Scheme, cfg.NameTemplate, defaultNameTemplate, sch)
+	if err != nil {
+		return nil, err
+	}
+	sd := &discovery{
+		cfg:        cfg,
+		restClient: client,
+		svc:        svc,
+		builder:    builder,
+		name:       discoveryName,
+	}
+	return taskhelper.NewTick(sd, time.Duration(refreshInterval))
+}
+
+type discovery struct {
+	async.SimpleTask
+	cfg        *config.ConsulDiscovery
+	restClient *perseshttp.RESTClient
+	svc        *service.ApplyService
+	builder    *target.Builder
+	name       string
+}
+
+func (d *discovery) Execute(_ context.Context, _ context.CancelFunc) error {
+	targets, err := d.lookup()
+	if err != nil {
+		logrus.Errorf("failed to execute consul discovery %q: %v", d.name, err)
+		return nil
+	}
+	result, err := d.builder.Build(targets)
+	if err != nil {
+		logrus.Errorf("failed to build the datasources of the consul discovery %q: %v", d.name, err)
+		return nil
+	}
+	d.svc.Apply(result)
+	return nil
+}
+
+func (d *discovery) String() string {
+	return fmt.Sprintf("datasource discovery %q", d.name)
+}
+
+// lookup returns the healthy instances of the services configured.
+// If any of the requests fails, an error is returned, so the garbage collection doesn't remove the datasources
+// that were found previously.
+func (d *discovery) lookup() ([]target.Target, error) {
+	services := d.cfg.Services
+	if len(services) == 0 {
+		var err error
+		if services, err = d.listServices(); err != nil {
+			return nil, err
+		}
+	}
+	var result []target.Target
+	for _, name := range services {
+		var entries []serviceEntry
+		err := d.restClient.Get().
+			APIPrefix("").
+			Resource("health/service").
+			Name(name).
+			Query(&query{datacenter: d.cfg.Datacenter, passing: true}).
+			Do().
+			Object(&entries)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the instances of the service %q: %w", name, err)
+		}
+		for _, entry := range entries {
+			if !d.hasTags(entry.Service.Tags) {
+				continue
+			}
+			result = append(result, entryToTarget(entry))
+		}
+	}
+	return result, nil
+}
+
+// listServices returns every service of the catalog having the tags configured.
+func (d *discovery) listServices() ([]string, error) {
+	var catalog map[string][]string
+	err := d.restClient.Get().
+		APIPrefix("").
+		Resource("catalog/services").
+		Query(&query{datacenter: d.cfg.Datacenter}).
+		Do().
+		Object(&catalog)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the services of the catalog: %w", err)
+	}
+	var services []string
+	for name, tags := range catalog {
+		// The tags of the catalog are the union of the tags of every instance, so the instances are filtered again later.
+		if name == consulSelfService || !d.hasTags(tags) {
+			continue
+		}
+		services = append(services, name)
+	}
+	// We sort the services to have a deterministic order.
+	sort.Strings(services)
+	return services, nil
+}
+
+func (d *discovery) hasTags(tags []string) bool {
+	for _, tag := range d.cfg.Tags {
+		if !slices.Contains(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func entryToTarget(entry serviceEntry) target.Target {
+	address := entry.Service.Address
+	if len(address) == 0 {
+		address = entry.Node.Address
+	}
+	port := strconv.Itoa(entry.Service.Port)
+	labels := map[string]string{
+		"service":    entry.Service.Service,
+		"id":         entry.Service.ID,
+		"node":       entry.Node.Node,
+		"address":    address,
+		"port":       port,
+		"datacenter": entry.Node.Datacenter,
+	}
+	for k, v := range entry.Service.Meta {
+		labels[metaLabelPrefix+k] = v
+	}
+	return target.Target{
+		Address: net.JoinHostPort(address, port),
+		Labels:  labels,
+		Tags:    entry.Service.Tags,
+	}
+}
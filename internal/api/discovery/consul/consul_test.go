@@ -0,0 +1,153 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consulsd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/perses/perses/internal/api/discovery/target"
+	clientConfig "github.com/perses/perses/pkg/client/config"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeConsul returns an HTTP server standing in for the Consul API.
+func newFakeConsul(t *testing.T) *httptest.Server {
+	catalog := map[string][]string{
+		"consul":     {},
+		"prometheus": {"metrics", "prod"},
+		"thanos":     {"metrics"},
+	}
+	instances := map[string][]serviceEntry{
+		"prometheus": {
+			{
+				Node:    consulNode{Node: "vm-1", Address: "10.0.0.1", Datacenter: "dc1"},
+				Service: consulService{ID: "prometheus-1", Service: "prometheus", Port: 9090, Tags: []string{"metrics", "prod"}, Meta: map[string]string{"team": "infra"}},
+			},
+			{
+				Node:    consulNode{Node: "vm-2", Address: "10.0.0.2", Datacenter: "dc1"},
+				Service: consulService{ID: "prometheus-2", Service: "prometheus", Address: "192.168.0.2", Port: 9090, Tags: []string{"metrics"}},
+			},
+		},
+		"thanos": {
+			{
+				Node:    consulNode{Node: "vm-3", Address: "10.0.0.3", Datacenter: "dc1"},
+				Service: consulService{ID: "thanos", Service: "thanos", Port: 10902, Tags: []string{"metrics"}},
+			},
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/catalog/services", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "dc1", r.URL.Query().Get("dc"))
+		_ = json.NewEncoder(w).Encode(catalog)
+	})
+	mux.HandleFunc("/v1/health/service/{service}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "dc1", r.URL.Query().Get("dc"))
+		assert.Equal(t, "true", r.URL.Query().Get("passing"))
+		entries, ok := instances[r.PathValue("service")]
+		if !ok {
+			entries = []serviceEntry{}
+		}
+		_ = json.NewEncoder(w).Encode(entries)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiscovery_Lookup(t *testing.T) {
+	server := newFakeConsul(t)
+	prometheus1 := target.Target{
+		Address: "10.0.0.1:9090",
+		Labels: map[string]string{
+			"service": "prometheus", "id": "prometheus-1", "node": "vm-1", "address": "10.0.0.1", "port": "9090",
+			"datacenter": "dc1", "meta_team": "infra",
+		},
+		Tags: []string{"metrics", "prod"},
+	}
+	prometheus2 := target.Target{
+		Address: "192.168.0.2:9090",
+		Labels: map[string]string{
+			"service": "prometheus", "id": "prometheus-2", "node": "vm-2", "address": "192.168.0.2", "port": "9090",
+			"datacenter": "dc1",
+		},
+		Tags: []string{"metrics"},
+	}
+	thanos := target.Target{
+		Address: "10.0.0.3:10902",
+		Labels: map[string]string{
+			"service": "thanos", "id": "thanos", "node": "vm-3", "address": "10.0.0.3", "port": "10902",
+			"datacenter": "dc1",
+		},
+		Tags: []string{"metrics"},
+	}
+	testSuites := []struct {
+		title    string
+		services []string
+		tags     []string
+		result   []target.Target
+	}{
+		{
+			title:  "every service of the catalog",
+			result: []target.Target{prometheus1, prometheus2, thanos},
+		},
+		{
+			title:    "given services",
+			services: []string{"thanos"},
+			result:   []target.Target{thanos},
+		},
+		{
+			title:  "filtered by tags",
+			tags:   []string{"prod"},
+			result: []target.Target{prometheus1},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			cfg := &config.ConsulDiscovery{
+				RestConfigClient: clientConfig.RestConfigClient{URL: common.MustParseURL(server.URL)},
+				Services:         test.services,
+				Tags:             test.tags,
+				Datacenter:       "dc1",
+			}
+			client, err := clientConfig.NewRESTClient(cfg.RestConfigClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := &discovery{cfg: cfg, restClient: client, name: "test"}
+			result, err := d.lookup()
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestDiscovery_LookupFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	cfg := &config.ConsulDiscovery{RestConfigClient: clientConfig.RestConfigClient{URL: common.MustParseURL(server.URL)}}
+	client, err := clientConfig.NewRESTClient(cfg.RestConfigClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &discovery{cfg: cfg, restClient: client, name: "test"}
+	_, err = d.lookup()
+	assert.Error(t, err)
+}
@@ -16,6 +16,8 @@ package discovery
 import (
 	"github.com/perses/common/async/taskhelper"
 	"github.com/perses/perses/internal/api/dependency"
+	consulsd "github.com/perses/perses/internal/api/discovery/consul"
+	dnssd "github.com/perses/perses/internal/api/discovery/dns"
 	httpsd "github.com/perses/perses/internal/api/discovery/http"
 	kubesd "github.com/perses/perses/internal/api/discovery/kubernetes"
 	"github.com/perses/perses/internal/api/discovery/service"
@@ -24,15 +26,17 @@ import (
 
 func New(cfg config.Config, serviceManager dependency.ServiceManager, caseSensitive bool) ([]taskhelper.Helper, error) {
 	var helpers []taskhelper.Helper
-	svc := service.New(caseSensitive, serviceManager.GetGlobalDatasource())
 	for _, c := range cfg.Datasource.Global.Discovery {
-		var helper taskhelper.Helper
-		var err error
-		if c.HTTPDiscovery != nil {
-			helper, err = httpsd.NewDiscovery(c.Name, c.RefreshInterval, c.HTTPDiscovery, svc)
-		} else if c.KubernetesDiscovery != nil {
-			helper, err = kubesd.NewDiscovery(c.Name, c.RefreshInterval, c.KubernetesDiscovery, svc, serviceManager.GetSchema())
+		svc := service.New(c.Name, c.GarbageCollection, caseSensitive, serviceManager.GetGlobalDatasource())
+		helper, err := newDiscovery(c, nil, svc, serviceManager)
+		if err != nil {
+			return nil, err
 		}
+		helpers = append(helpers, helper)
+	}
+	for _, c := range cfg.Datasource.Project.Discovery {
+		svc := service.NewProject(c.Name, c.GarbageCollection, caseSensitive, serviceManager.GetDatasource())
+		helper, err := newDiscovery(c.GlobalDatasourceDiscovery, &c.Project, svc, serviceManager)
 		if err != nil {
 			return nil, err
 		}
@@ -40,3 +44,20 @@ func New(cfg config.Config, serviceManager dependency.ServiceManager, caseSensit
 	}
 	return helpers, nil
 }
+
+// newDiscovery creates the task running the discovery mechanism configured.
+// project is nil when the discovery creates global datasources.
+func newDiscovery(c config.GlobalDatasourceDiscovery, project *config.DiscoveryProject, svc *service.ApplyService, serviceManager dependency.ServiceManager) (taskhelper.Helper, error) {
+	var projectName string
+	if project != nil {
+		projectName = project.Name
+	}
+	if c.HTTPDiscovery != nil {
+		return httpsd.NewDiscovery(c.Name, c.RefreshInterval, c.HTTPDiscovery, projectName, svc)
+	} else if c.KubernetesDiscovery != nil {
+		return kubesd.NewDiscovery(c.Name, c.RefreshInterval, c.KubernetesDiscovery, project, svc, serviceManager.GetSchema())
+	} else if c.DNSDiscovery != nil {
+		return dnssd.NewDiscovery(c.Name, c.RefreshInterval, c.DNSDiscovery, projectName, svc, serviceManager.GetSchema())
+	}
+	return consulsd.NewDiscovery(c.Name, c.RefreshInterval, c.ConsulDiscovery, projectName, svc, serviceManager.GetSchema())
+}
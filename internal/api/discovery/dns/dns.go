@@ -0,0 +1,168 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnssd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/perses/common/async/taskhelper"
+	"github.com/perses/perses/internal/api/discovery/service"
+	"github.com/perses/perses/internal/api/discovery/target"
+	"github.com/perses/perses/internal/api/plugin/schema"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/spec/go/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultNameTemplate = "{{ .Labels.host }}-{{ .Labels.port }}"
+	lookupTimeout       = 30 * time.Second
+)
+
+// resolver is the subset of net.Resolver used by the discovery. It can be replaced in the tests.
+type resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+// NewDiscovery creates the discovery. When project is set, the datasources are created in this project.
+func NewDiscovery(discoveryName string, refreshInterval common.Duration, cfg *config.DNSDiscovery, project string, svc *service.ApplyService, sch schema.Schema) (taskhelper.Helper, error) {
+	builder, err := target.NewBuilder(discoveryName, project, cfg.DatasourcePluginKind, cfg.Scheme, cfg.NameTemplate, defaultNameTemplate, sch)
+	if err != nil {
+		return nil, err
+	}
+	sd := &discovery{
+		cfg:      cfg,
+		svc:      svc,
+		builder:  builder,
+		resolver: net.DefaultResolver,
+		name:     discoveryName,
+	}
+	return taskhelper.NewTick(sd, time.Duration(refreshInterval))
+}
+
+type discovery struct {
+	async.SimpleTask
+	cfg      *config.DNSDiscovery
+	svc      *service.ApplyService
+	builder  *target.Builder
+	resolver resolver
+	name     string
+}
+
+func (d *discovery) Execute(ctx context.Context, _ context.CancelFunc) error {
+	targets, err := d.lookup(ctx)
+	if err != nil {
+		logrus.Errorf("failed to execute dns discovery %q: %v", d.name, err)
+		return nil
+	}
+	result, err := d.builder.Build(targets)
+	if err != nil {
+		logrus.Errorf("failed to build the datasources of the dns discovery %q: %v", d.name, err)
+		return nil
+	}
+	d.svc.Apply(result)
+	return nil
+}
+
+func (d *discovery) String() string {
+	return fmt.Sprintf("datasource discovery %q", d.name)
+}
+
+// lookup queries every DNS name configured.
+// A name that doesn't exist anymore has no target, so the garbage collection removes its datasources.
+// If any of the other queries fails, an error is returned, so the garbage collection doesn't remove the datasources
+// that were found previously.
+func (d *discovery) lookup(ctx context.Context) ([]target.Target, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	var result []target.Target
+	for _, name := range d.cfg.Names {
+		var targets []target.Target
+		var err error
+		if d.cfg.Type == config.DNSRecordTypeSRV {
+			targets, err = d.lookupSRV(ctx, name)
+		} else {
+			targets, err = d.lookupIP(ctx, name)
+		}
+		if isNotFound(err) {
+			logrus.Debugf("no %s record found for %q by the dns discovery %q", d.cfg.Type, name, d.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve the %s records of %q: %w", d.cfg.Type, name, err)
+		}
+		result = append(result, targets...)
+	}
+	return result, nil
+}
+
+func (d *discovery) lookupSRV(ctx context.Context, name string) ([]target.Target, error) {
+	_, records, err := d.resolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]target.Target, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		port := strconv.Itoa(int(record.Port))
+		result = append(result, target.Target{
+			Address: net.JoinHostPort(host, port),
+			Labels: map[string]string{
+				"name": name,
+				"host": host,
+				"port": port,
+			},
+		})
+	}
+	return result, nil
+}
+
+func (d *discovery) lookupIP(ctx context.Context, name string) ([]target.Target, error) {
+	network := "ip4"
+	if d.cfg.Type == config.DNSRecordTypeAAAA {
+		network = "ip6"
+	}
+	ips, err := d.resolver.LookupIP(ctx, network, name)
+	if err != nil {
+		return nil, err
+	}
+	port := strconv.Itoa(d.cfg.Port)
+	result := make([]target.Target, 0, len(ips))
+	for _, ip := range ips {
+		host := ip.String()
+		result = append(result, target.Target{
+			Address: net.JoinHostPort(host, port),
+			Labels: map[string]string{
+				"name": name,
+				"host": host,
+				"port": port,
+			},
+		})
+	}
+	return result, nil
+}
+
+// isNotFound returns true if the error means that the name doesn't exist (NXDOMAIN) or has no record of the requested type.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
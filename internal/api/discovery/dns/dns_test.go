@@ -0,0 +1,133 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnssd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/perses/perses/internal/api/discovery/target"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver struct {
+	srv map[string][]*net.SRV
+	ips map[string][]net.IP
+}
+
+func (r *fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	records, ok := r.srv[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return name, records, nil
+}
+
+func (r *fakeResolver) LookupIP(_ context.Context, network, host string) ([]net.IP, error) {
+	ips, ok := r.ips[network+"/"+host]
+	if !ok {
+		return nil, fmt.Errorf("no %s address for %q", network, host)
+	}
+	return ips, nil
+}
+
+func TestDiscovery_Lookup(t *testing.T) {
+	resolver := &fakeResolver{
+		srv: map[string][]*net.SRV{
+			"_prometheus._tcp.example.com": {
+				{Target: "prom-1.example.com.", Port: 9090},
+				{Target: "prom-2.example.com.", Port: 9091},
+			},
+		},
+		ips: map[string][]net.IP{
+			"ip4/thanos.example.com": {net.ParseIP("10.0.0.1")},
+			"ip6/thanos.example.com": {net.ParseIP("2001:db8::1")},
+		},
+	}
+	testSuites := []struct {
+		title  string
+		cfg    config.DNSDiscovery
+		result []target.Target
+		err    bool
+	}{
+		{
+			title: "SRV records",
+			cfg:   config.DNSDiscovery{Type: config.DNSRecordTypeSRV, Names: []string{"_prometheus._tcp.example.com"}},
+			result: []target.Target{
+				{
+					Address: "prom-1.example.com:9090",
+					Labels:  map[string]string{"name": "_prometheus._tcp.example.com", "host": "prom-1.example.com", "port": "9090"},
+				},
+				{
+					Address: "prom-2.example.com:9091",
+					Labels:  map[string]string{"name": "_prometheus._tcp.example.com", "host": "prom-2.example.com", "port": "9091"},
+				},
+			},
+		},
+		{
+			title: "A records",
+			cfg:   config.DNSDiscovery{Type: config.DNSRecordTypeA, Port: 10902, Names: []string{"thanos.example.com"}},
+			result: []target.Target{
+				{
+					Address: "10.0.0.1:10902",
+					Labels:  map[string]string{"name": "thanos.example.com", "host": "10.0.0.1", "port": "10902"},
+				},
+			},
+		},
+		{
+			title: "AAAA records",
+			cfg:   config.DNSDiscovery{Type: config.DNSRecordTypeAAAA, Port: 10902, Names: []string{"thanos.example.com"}},
+			result: []target.Target{
+				{
+					Address: "[2001:db8::1]:10902",
+					Labels:  map[string]string{"name": "thanos.example.com", "host": "2001:db8::1", "port": "10902"},
+				},
+			},
+		},
+		{
+			title: "a name that doesn't exist has no target",
+			cfg:   config.DNSDiscovery{Type: config.DNSRecordTypeSRV, Names: []string{"unknown.example.com", "_prometheus._tcp.example.com"}},
+			result: []target.Target{
+				{
+					Address: "prom-1.example.com:9090",
+					Labels:  map[string]string{"name": "_prometheus._tcp.example.com", "host": "prom-1.example.com", "port": "9090"},
+				},
+				{
+					Address: "prom-2.example.com:9091",
+					Labels:  map[string]string{"name": "_prometheus._tcp.example.com", "host": "prom-2.example.com", "port": "9091"},
+				},
+			},
+		},
+		{
+			title: "a failing name fails the whole lookup",
+			cfg:   config.DNSDiscovery{Type: config.DNSRecordTypeA, Port: 10902, Names: []string{"thanos.example.com", "unknown.example.com"}},
+			err:   true,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			d := &discovery{cfg: &test.cfg, resolver: resolver, name: "test"}
+			result, err := d.lookup(context.Background())
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
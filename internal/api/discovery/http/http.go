@@ -29,7 +29,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func NewDiscovery(Name string, refreshInterval common.Duration, cfg *config.HTTPDiscovery, svc *service.ApplyService) (taskhelper.Helper, error) {
+// NewDiscovery creates the discovery fetching the datasources from an HTTP endpoint.
+// When project is set, the datasources are created in this project.
+func NewDiscovery(Name string, refreshInterval common.Duration, cfg *config.HTTPDiscovery, project string, svc *service.ApplyService) (taskhelper.Helper, error) {
 	client, err := clientConfig.NewRESTClient(cfg.RestConfigClient)
 	if err != nil {
 		return nil, err
@@ -38,6 +40,7 @@ func NewDiscovery(Name string, refreshInterval common.Duration, cfg *config.HTTP
 		restClient: client,
 		svc:        svc,
 		name:       Name,
+		project:    project,
 	}
 	return taskhelper.NewTick(sd, time.Duration(refreshInterval))
 }
@@ -47,6 +50,7 @@ type discovery struct {
 	restClient *perseshttp.RESTClient
 	svc        *service.ApplyService
 	name       string
+	project    string
 }
 
 func (d *discovery) Execute(_ context.Context, _ context.CancelFunc) error {
@@ -59,7 +63,18 @@ func (d *discovery) Execute(_ context.Context, _ context.CancelFunc) error {
 		logrus.Errorf("failed to execute http discovery %q: %v", d.name, err)
 		return nil
 	}
-	d.svc.Apply(result)
+	datasources := make([]*v1.Datasource, 0, len(result))
+	for _, dts := range result {
+		datasources = append(datasources, &v1.Datasource{
+			Kind: v1.KindDatasource,
+			Metadata: v1.ProjectMetadata{
+				Metadata:               dts.Metadata,
+				ProjectMetadataWrapper: v1.ProjectMetadataWrapper{Project: d.project},
+			},
+			Spec: dts.Spec,
+		})
+	}
+	d.svc.Apply(datasources)
 	return nil
 }
 
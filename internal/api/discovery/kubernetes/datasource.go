@@ -0,0 +1,203 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubesd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"text/template"
+
+	"github.com/perses/perses/internal/api/discovery/cuetils"
+	"github.com/perses/perses/internal/api/discovery/target"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
+	specCommon "github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/datasource"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultNameTemplate = "{{ .Namespace }}.{{ .Name }}"
+
+	annotationPrefix = "perses.dev/datasource-"
+	// AnnotationDiscovery opts a service or a pod in ("true") or out ("false") of the discovery.
+	AnnotationDiscovery = annotationPrefix + "discovery"
+	// AnnotationDisplayName overrides the display name of the datasource.
+	AnnotationDisplayName = annotationPrefix + "display-name"
+	// AnnotationDescription sets the description of the datasource.
+	AnnotationDescription = annotationPrefix + "description"
+	// AnnotationDefault sets whether the datasource is the default one for its plugin kind.
+	AnnotationDefault = annotationPrefix + "default"
+	// AnnotationAllowedEndpoints restricts the endpoints reachable through the proxy.
+	// It is a JSON list of objects with the fields endpointPattern and method.
+	AnnotationAllowedEndpoints = annotationPrefix + "allowed-endpoints"
+	// AnnotationHeaders sets the headers forwarded by the proxy. It is a JSON object.
+	// It is only accepted when enabled in the configuration.
+	AnnotationHeaders = annotationPrefix + "headers"
+	// AnnotationSecret is the name of the secret used by the proxy.
+	// Only the secrets listed in the configuration can be used.
+	AnnotationSecret = annotationPrefix + "secret"
+)
+
+// datasourceBuilder converts a Kubernetes resource (service or pod) into a datasource.
+// It is shared by the service and the pod discoveries.
+type datasourceBuilder struct {
+	discoveryName string
+	project       projectResolver
+	name          *target.NameTemplate
+	displayName   *template.Template
+	optIn         bool
+	// allowedSecrets and allowHeaders restrict the annotations that would let the owner of a resource
+	// receive the credentials of Perses, by pointing a datasource to an endpoint they control.
+	allowedSecrets []string
+	allowHeaders   bool
+}
+
+func newDatasourceBuilder(discoveryName string, cfg *config.KubernetesDiscovery, project *config.DiscoveryProject) (*datasourceBuilder, error) {
+	name, err := target.NewNameTemplate(cfg.NameTemplate, defaultNameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	b := &datasourceBuilder{
+		discoveryName:  discoveryName,
+		project:        newProjectResolver(project),
+		name:           name,
+		optIn:          cfg.AnnotationOptIn,
+		allowedSecrets: cfg.AnnotationAllowedSecrets,
+		allowHeaders:   cfg.EnableAnnotationHeaders,
+	}
+	if len(cfg.DisplayNameTemplate) > 0 {
+		if b.displayName, err = template.New("display").Option("missingkey=zero").Parse(cfg.DisplayNameTemplate); err != nil {
+			return nil, fmt.Errorf("invalid display name template %q: %w", cfg.DisplayNameTemplate, err)
+		}
+	}
+	return b, nil
+}
+
+// isSelected returns true when the resource is not opted out of the discovery,
+// and when it is opted in if the discovery requires it.
+func (b *datasourceBuilder) isSelected(meta metav1.ObjectMeta) bool {
+	value, ok := meta.Annotations[AnnotationDiscovery]
+	if !ok {
+		return !b.optIn
+	}
+	selected, err := strconv.ParseBool(value)
+	if err != nil {
+		logrus.Warningf("%q/%q ignored by the discovery %q because the value %q of the annotation %q is not a boolean", meta.Namespace, meta.Name, b.discoveryName, value, AnnotationDiscovery)
+		return false
+	}
+	return selected
+}
+
+// build returns the datasource matching the resource. It returns nil when the resource must be dropped.
+func (b *datasourceBuilder) build(meta metav1.ObjectMeta, url *common.URL, decodedSchema []*cuetils.Node) (*v1.Datasource, error) {
+	project, ok := b.project(meta)
+	if !ok {
+		logrus.Tracef("%q/%q dropped for the discovery %q because its project cannot be determined", meta.Namespace, meta.Name, b.discoveryName)
+		return nil, nil
+	}
+	// A resource that cannot be named or that has an invalid annotation must not stop the whole discovery.
+	name, err := b.name.Execute(meta)
+	if err != nil {
+		logrus.WithError(err).Errorf("%q/%q dropped for the discovery %q because the name of the datasource cannot be built", meta.Namespace, meta.Name, b.discoveryName)
+		return nil, nil
+	}
+	spec, err := b.buildSpec(meta)
+	if err != nil {
+		logrus.WithError(err).Errorf("%q/%q dropped for the discovery %q because of an invalid annotation", meta.Namespace, meta.Name, b.discoveryName)
+		return nil, nil
+	}
+	proxy, err := b.buildProxy(meta, url)
+	if err != nil {
+		logrus.WithError(err).Errorf("%q/%q dropped for the discovery %q because of an invalid annotation", meta.Namespace, meta.Name, b.discoveryName)
+		return nil, nil
+	}
+	spec.Plugin, err = cuetils.BuildPluginAndInjectProxy(decodedSchema, proxy)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Datasource{
+		Kind: v1.KindDatasource,
+		Metadata: v1.ProjectMetadata{
+			Metadata: v1.Metadata{
+				Name: name,
+			},
+			ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+				Project: project,
+			},
+		},
+		Spec: spec,
+	}, nil
+}
+
+func (b *datasourceBuilder) buildSpec(meta metav1.ObjectMeta) (datasource.Spec, error) {
+	spec := datasource.Spec{}
+	display := &specCommon.Display{}
+	if b.displayName != nil {
+		var buffer bytes.Buffer
+		if err := b.displayName.Execute(&buffer, meta); err != nil {
+			return spec, fmt.Errorf("unable to build the display name: %w", err)
+		}
+		display.Name = buffer.String()
+	}
+	if value, ok := meta.Annotations[AnnotationDisplayName]; ok {
+		display.Name = value
+	}
+	if value, ok := meta.Annotations[AnnotationDescription]; ok {
+		display.Description = value
+	}
+	if len(display.Name) > 0 || len(display.Description) > 0 {
+		spec.Display = display
+	}
+	if value, ok := meta.Annotations[AnnotationDefault]; ok {
+		isDefault, err := strconv.ParseBool(value)
+		if err != nil {
+			return spec, fmt.Errorf("invalid value %q for the annotation %q: %w", value, AnnotationDefault, err)
+		}
+		spec.Default = isDefault
+	}
+	return spec, nil
+}
+
+func (b *datasourceBuilder) buildProxy(meta metav1.ObjectMeta, url *common.URL) (http.Config, error) {
+	proxy := http.Config{
+		URL: url,
+	}
+	if value, ok := meta.Annotations[AnnotationSecret]; ok {
+		if !slices.Contains(b.allowedSecrets, value) {
+			return proxy, fmt.Errorf("the secret %q of the annotation %q is not allowed by the discovery", value, AnnotationSecret)
+		}
+		proxy.Secret = value
+	}
+	if value, ok := meta.Annotations[AnnotationAllowedEndpoints]; ok {
+		if err := json.Unmarshal([]byte(value), &proxy.AllowedEndpoints); err != nil {
+			return proxy, fmt.Errorf("invalid value for the annotation %q: %w", AnnotationAllowedEndpoints, err)
+		}
+	}
+	if value, ok := meta.Annotations[AnnotationHeaders]; ok {
+		if !b.allowHeaders {
+			return proxy, fmt.Errorf("the annotation %q is not enabled for the discovery", AnnotationHeaders)
+		}
+		if err := json.Unmarshal([]byte(value), &proxy.Headers); err != nil {
+			return proxy, fmt.Errorf("invalid value for the annotation %q: %w", AnnotationHeaders, err)
+		}
+	}
+	return proxy, nil
+}
@@ -0,0 +1,74 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubesd
+
+import (
+	"testing"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsSelected(t *testing.T) {
+	tests := []struct {
+		name        string
+		optIn       bool
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:     "no annotation",
+			expected: true,
+		},
+		{
+			name:        "opted out",
+			annotations: map[string]string{AnnotationDiscovery: "false"},
+			expected:    false,
+		},
+		{
+			name:     "opt-in required without annotation",
+			optIn:    true,
+			expected: false,
+		},
+		{
+			name:        "opted in",
+			optIn:       true,
+			annotations: map[string]string{AnnotationDiscovery: "true"},
+			expected:    true,
+		},
+		{
+			name:        "invalid annotation value",
+			annotations: map[string]string{AnnotationDiscovery: "maybe"},
+			expected:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder, err := newDatasourceBuilder("kubernetes", &config.KubernetesDiscovery{AnnotationOptIn: tt.optIn}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			meta := metav1.ObjectMeta{Name: "prometheus", Namespace: "monitoring", Annotations: tt.annotations}
+			assert.Equal(t, tt.expected, builder.isSelected(meta))
+		})
+	}
+}
+
+func TestNewDatasourceBuilder_InvalidTemplate(t *testing.T) {
+	_, err := newDatasourceBuilder("kubernetes", &config.KubernetesDiscovery{NameTemplate: "{{ .Name "}, nil)
+	assert.Error(t, err)
+	_, err = newDatasourceBuilder("kubernetes", &config.KubernetesDiscovery{DisplayNameTemplate: "{{ .Name "}, nil)
+	assert.Error(t, err)
+}
@@ -30,6 +30,7 @@ import (
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/spec/go/common"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -48,11 +49,36 @@ func buildLabelSelector(labels map[string]string) string {
 	return strings.Join(builder, ",")
 }
 
+// projectResolver returns the project of the datasource built from the given Kubernetes resource.
+// It returns false when the project cannot be determined, in which case the resource is dropped.
+type projectResolver func(meta metav1.ObjectMeta) (string, bool)
+
+// newProjectResolver creates the projectResolver matching the config.
+// When the config is nil, the discovery is creating global datasources, so the project is always empty.
+func newProjectResolver(cfg *config.DiscoveryProject) projectResolver {
+	return func(meta metav1.ObjectMeta) (string, bool) {
+		var project string
+		switch {
+		case cfg == nil:
+			return "", true
+		case len(cfg.Name) > 0:
+			project = cfg.Name
+		case cfg.FromNamespace:
+			project = meta.Namespace
+		case len(cfg.Label) > 0:
+			project = meta.Labels[cfg.Label]
+		default:
+			project = meta.Annotations[cfg.Annotation]
+		}
+		return project, len(project) > 0
+	}
+}
+
 type clientDiscovery interface {
-	discover(decodedSchema []*cuetils.Node) ([]*v1.GlobalDatasource, error)
+	discover(decodedSchema []*cuetils.Node) ([]*v1.Datasource, error)
 }
 
-func NewDiscovery(discoveryName string, refreshInterval common.Duration, cfg *config.KubernetesDiscovery, svc *service.ApplyService, schema schema.Schema) (taskhelper.Helper, error) {
+func NewDiscovery(discoveryName string, refreshInterval common.Duration, cfg *config.KubernetesDiscovery, project *config.DiscoveryProject, svc *service.ApplyService, schema schema.Schema) (taskhelper.Helper, error) {
 	kubeConfig, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, fmt.Errorf("unable to get a kubeConfig: %w", err)
@@ -61,20 +87,28 @@ func NewDiscovery(discoveryName string, refreshInterval common.Duration, cfg *co
 	if err != nil {
 		return nil, fmt.Errorf("unable to create the kube client: %w", err)
 	}
+	builder, err := newDatasourceBuilder(discoveryName, cfg, project)
+	if err != nil {
+		return nil, err
+	}
 	var d clientDiscovery
 	if cfg.ServiceConfiguration.Enable {
 		d = &serviceDiscovery{
+			discoveryName: discoveryName,
 			kubeClient:    kubeClient,
 			cfg:           cfg.ServiceConfiguration,
 			namespace:     cfg.Namespace,
 			labelSelector: buildLabelSelector(cfg.Labels),
+			builder:       builder,
 		}
 	} else {
 		d = &podDiscovery{
+			discoveryName: discoveryName,
 			kubeClient:    kubeClient,
 			cfg:           cfg.PodConfiguration,
 			namespace:     cfg.Namespace,
 			labelSelector: buildLabelSelector(cfg.Labels),
+			builder:       builder,
 		}
 	}
 
@@ -119,8 +153,9 @@ func (d *discovery) String() string {
 func (d *discovery) decodeSchema() ([]*cuetils.Node, error) {
 	sch, err := d.schema.GetDatasourceSchema(d.cfg.DatasourcePluginKind)
 	if err != nil {
-		logrus.WithError(err).Error("failed to get datasource schema")
-		return nil, nil
+		// The error must be returned, otherwise the discovery would return nothing and the garbage collection
+		// would consider every discovered datasource as orphaned.
+		return nil, fmt.Errorf("failed to get datasource schema: %w", err)
 	}
 	ctx := cuecontext.New()
 	return cuetils.NewFromSchema(ctx.BuildInstance(sch))
@@ -16,7 +16,9 @@ package kubesd
 import (
 	"testing"
 
+	"github.com/perses/perses/pkg/model/api/config"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestBuildLabelSelector(t *testing.T) {
@@ -59,3 +61,61 @@ func TestBuildLabelSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectResolver(t *testing.T) {
+	meta := metav1.ObjectMeta{
+		Name:        "prometheus",
+		Namespace:   "team-a",
+		Labels:      map[string]string{"team": "team-b"},
+		Annotations: map[string]string{"perses.dev/project": "team-c"},
+	}
+	testSuite := []struct {
+		name    string
+		cfg     *config.DiscoveryProject
+		project string
+		ok      bool
+	}{
+		{
+			name:    "global discovery",
+			cfg:     nil,
+			project: "",
+			ok:      true,
+		},
+		{
+			name:    "fixed project",
+			cfg:     &config.DiscoveryProject{Name: "monitoring"},
+			project: "monitoring",
+			ok:      true,
+		},
+		{
+			name:    "namespace",
+			cfg:     &config.DiscoveryProject{FromNamespace: true},
+			project: "team-a",
+			ok:      true,
+		},
+		{
+			name:    "label",
+			cfg:     &config.DiscoveryProject{Label: "team"},
+			project: "team-b",
+			ok:      true,
+		},
+		{
+			name:    "annotation",
+			cfg:     &config.DiscoveryProject{Annotation: "perses.dev/project"},
+			project: "team-c",
+			ok:      true,
+		},
+		{
+			name: "missing label",
+			cfg:  &config.DiscoveryProject{Label: "owner"},
+			ok:   false,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.name, func(t *testing.T) {
+			project, ok := newProjectResolver(test.cfg)(meta)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.project, project)
+		})
+	}
+}
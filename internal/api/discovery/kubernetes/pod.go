@@ -21,8 +21,6 @@ import (
 	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/common"
-	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
-	"github.com/perses/spec/go/datasource"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,18 +31,23 @@ type podDiscovery struct {
 	discoveryName string
 	namespace     string
 	labelSelector string
+	builder       *datasourceBuilder
 	cfg           config.KubePodDiscovery
 	kubeClient    *kubernetes.Clientset
 }
 
-func (d *podDiscovery) discover(decodedSchema []*cuetils.Node) ([]*v1.GlobalDatasource, error) {
+func (d *podDiscovery) discover(decodedSchema []*cuetils.Node) ([]*v1.Datasource, error) {
 	response, err := d.kubeClient.CoreV1().Pods(d.namespace).List(context.Background(), metav1.ListOptions{LabelSelector: d.labelSelector})
 	if err != nil {
 		return nil, err
 	}
-	var result []*v1.GlobalDatasource
+	var result []*v1.Datasource
 	for _, item := range response.Items {
-		dts, convertErr := d.podToGlobalDatasource(item, decodedSchema)
+		if !d.builder.isSelected(item.ObjectMeta) {
+			logrus.Tracef("pod %q/%q opted out of the discovery %q", item.Namespace, item.Name, d.discoveryName)
+			continue
+		}
+		dts, convertErr := d.podToDatasource(item, decodedSchema)
 		if convertErr != nil {
 			return nil, convertErr
 		}
@@ -55,7 +58,7 @@ func (d *podDiscovery) discover(decodedSchema []*cuetils.Node) ([]*v1.GlobalData
 	return result, nil
 }
 
-func (d *podDiscovery) podToGlobalDatasource(pod corev1.Pod, decodedSchema []*cuetils.Node) (*v1.GlobalDatasource, error) {
+func (d *podDiscovery) podToDatasource(pod corev1.Pod, decodedSchema []*cuetils.Node) (*v1.Datasource, error) {
 	container := d.extractContainer(pod)
 	if container == nil {
 		return nil, nil
@@ -69,23 +72,7 @@ func (d *podDiscovery) podToGlobalDatasource(pod corev1.Pod, decodedSchema []*cu
 		return nil, fmt.Errorf("unable to create the URL for the pod %s: %v", pod.Name, err)
 	}
 
-	proxy := http.Config{
-		URL: url,
-	}
-	plugin, err := cuetils.BuildPluginAndInjectProxy(decodedSchema, proxy)
-	if err != nil {
-		return nil, err
-	}
-
-	return &v1.GlobalDatasource{
-		Kind: v1.KindGlobalDatasource,
-		Metadata: v1.Metadata{
-			Name: fmt.Sprintf("%s.%s", pod.Namespace, pod.Name),
-		},
-		Spec: datasource.Spec{
-			Plugin: plugin,
-		},
-	}, nil
+	return d.builder.build(pod.ObjectMeta, url, decodedSchema)
 }
 
 func (d *podDiscovery) extractContainer(pod corev1.Pod) *corev1.Container {
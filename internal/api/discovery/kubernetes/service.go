@@ -21,8 +21,6 @@ import (
 	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/common"
-	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
-	"github.com/perses/spec/go/datasource"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,22 +31,27 @@ type serviceDiscovery struct {
 	discoveryName string
 	namespace     string
 	labelSelector string
+	builder       *datasourceBuilder
 	cfg           config.KubeServiceDiscovery
 	kubeClient    *kubernetes.Clientset
 }
 
-func (d *serviceDiscovery) discover(decodedSchema []*cuetils.Node) ([]*v1.GlobalDatasource, error) {
+func (d *serviceDiscovery) discover(decodedSchema []*cuetils.Node) ([]*v1.Datasource, error) {
 	response, err := d.kubeClient.CoreV1().Services(d.namespace).List(context.Background(), metav1.ListOptions{LabelSelector: d.labelSelector})
 	if err != nil {
 		return nil, err
 	}
-	var result []*v1.GlobalDatasource
+	var result []*v1.Datasource
 	for _, item := range response.Items {
+		if !d.builder.isSelected(item.ObjectMeta) {
+			logrus.Tracef("svc %q/%q opted out of the discovery %q", item.Namespace, item.Name, d.discoveryName)
+			continue
+		}
 		if len(d.cfg.ServiceType) != 0 && d.cfg.ServiceType != string(item.Spec.Type) {
 			logrus.Tracef("service type %q doesn't match the configured service type %q", item.Spec.Type, d.cfg.ServiceType)
 			continue
 		}
-		dts, convertErr := d.serviceToGlobalDatasource(item, decodedSchema)
+		dts, convertErr := d.serviceToDatasource(item, decodedSchema)
 		if convertErr != nil {
 			return nil, convertErr
 		}
@@ -59,7 +62,7 @@ func (d *serviceDiscovery) discover(decodedSchema []*cuetils.Node) ([]*v1.Global
 	return result, nil
 }
 
-func (d *serviceDiscovery) serviceToGlobalDatasource(svc corev1.Service, decodedSchema []*cuetils.Node) (*v1.GlobalDatasource, error) {
+func (d *serviceDiscovery) serviceToDatasource(svc corev1.Service, decodedSchema []*cuetils.Node) (*v1.Datasource, error) {
 	port := d.extractPort(svc)
 	if port == nil {
 		// we don't return any error, as the service doesn't match the configuration, so we have to drop it.
@@ -71,23 +74,7 @@ func (d *serviceDiscovery) serviceToGlobalDatasource(svc corev1.Service, decoded
 		return nil, fmt.Errorf("unable to create the URL for the service %s: %v", svc.Name, err)
 	}
 
-	proxy := http.Config{
-		URL: url,
-	}
-	plugin, err := cuetils.BuildPluginAndInjectProxy(decodedSchema, proxy)
-	if err != nil {
-		return nil, err
-	}
-
-	return &v1.GlobalDatasource{
-		Kind: v1.KindGlobalDatasource,
-		Metadata: v1.Metadata{
-			Name: fmt.Sprintf("%s.%s", svc.Namespace, svc.Name),
-		},
-		Spec: datasource.Spec{
-			Plugin: plugin,
-		},
-	}, nil
+	return d.builder.build(svc.ObjectMeta, url, decodedSchema)
 }
 
 func (d *serviceDiscovery) extractPort(svc corev1.Service) *corev1.ServicePort {
@@ -24,12 +24,18 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func TestServiceToGlobalDatasource(t *testing.T) {
+func TestServiceToDatasource(t *testing.T) {
 	tests := []struct {
 		name               string
 		k8sServicePath     string
 		datasourcePlugin   string
 		serviceConfig      config.KubeServiceDiscovery
+		project            *config.DiscoveryProject
+		nameTemplate       string
+		displayTemplate    string
+		annotations        map[string]string
+		allowedSecrets     []string
+		allowHeaders       bool
 		expectedYAMLResult string
 	}{
 		{
@@ -40,12 +46,13 @@ func TestServiceToGlobalDatasource(t *testing.T) {
 				ServiceType: string(corev1.ServiceTypeClusterIP),
 			},
 			datasourcePlugin: "PrometheusDatasource",
-			expectedYAMLResult: `kind: GlobalDatasource
+			expectedYAMLResult: `kind: Datasource
 metadata:
     name: kube-monitoring.prometheus-prometheus
     createdAt: 0001-01-01T00:00:00Z
     updatedAt: 0001-01-01T00:00:00Z
     version: 0
+    project: ""
 spec:
     default: false
     plugin:
@@ -57,15 +64,217 @@ spec:
                     url: http://prometheus-prometheus.kube-monitoring.svc:9090
 `,
 		},
+		{
+			name:           "project from the namespace",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			project:          &config.DiscoveryProject{FromNamespace: true},
+			datasourcePlugin: "PrometheusDatasource",
+			expectedYAMLResult: `kind: Datasource
+metadata:
+    name: kube-monitoring.prometheus-prometheus
+    createdAt: 0001-01-01T00:00:00Z
+    updatedAt: 0001-01-01T00:00:00Z
+    version: 0
+    project: kube-monitoring
+spec:
+    default: false
+    plugin:
+        kind: PrometheusDatasource
+        spec:
+            proxy:
+                kind: HTTPProxy
+                spec:
+                    url: http://prometheus-prometheus.kube-monitoring.svc:9090
+`,
+		},
+		{
+			name:           "project from a label",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			project:          &config.DiscoveryProject{Label: "app"},
+			datasourcePlugin: "PrometheusDatasource",
+			expectedYAMLResult: `kind: Datasource
+metadata:
+    name: kube-monitoring.prometheus-prometheus
+    createdAt: 0001-01-01T00:00:00Z
+    updatedAt: 0001-01-01T00:00:00Z
+    version: 0
+    project: prometheus-prometheus
+spec:
+    default: false
+    plugin:
+        kind: PrometheusDatasource
+        spec:
+            proxy:
+                kind: HTTPProxy
+                spec:
+                    url: http://prometheus-prometheus.kube-monitoring.svc:9090
+`,
+		},
+		{
+			name:           "project from a missing annotation",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			project:            &config.DiscoveryProject{Annotation: "perses.dev/project"},
+			datasourcePlugin:   "PrometheusDatasource",
+			expectedYAMLResult: "null\n",
+		},
+		{
+			name:           "templated names and annotation overrides",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			nameTemplate:    `{{ index .Labels "app" }}`,
+			displayTemplate: `Prometheus ({{ .Namespace }})`,
+			annotations: map[string]string{
+				AnnotationDescription:      "Main Prometheus",
+				AnnotationDefault:          "true",
+				AnnotationSecret:           "prometheus-auth",
+				AnnotationAllowedEndpoints: `[{"endpointPattern":"/api/v1/query","method":"POST"}]`,
+				AnnotationHeaders:          `{"X-Scope-OrgID":"team-a"}`,
+			},
+			allowedSecrets:   []string{"prometheus-auth"},
+			allowHeaders:     true,
+			datasourcePlugin: "PrometheusDatasource",
+			expectedYAMLResult: `kind: Datasource
+metadata:
+    name: prometheus-prometheus
+    createdAt: 0001-01-01T00:00:00Z
+    updatedAt: 0001-01-01T00:00:00Z
+    version: 0
+    project: ""
+spec:
+    display:
+        name: Prometheus (kube-monitoring)
+        description: Main Prometheus
+    default: true
+    plugin:
+        kind: PrometheusDatasource
+        spec:
+            proxy:
+                kind: HTTPProxy
+                spec:
+                    url: http://prometheus-prometheus.kube-monitoring.svc:9090
+                    allowedEndpoints:
+                        - endpointPattern: /api/v1/query
+                          method: POST
+                    headers:
+                        X-Scope-OrgID: team-a
+                    secret: prometheus-auth
+`,
+		},
+		{
+			name:           "display name annotation takes precedence over the template",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			displayTemplate: `Prometheus ({{ .Namespace }})`,
+			annotations: map[string]string{
+				AnnotationDisplayName: "Production",
+			},
+			datasourcePlugin: "PrometheusDatasource",
+			expectedYAMLResult: `kind: Datasource
+metadata:
+    name: kube-monitoring.prometheus-prometheus
+    createdAt: 0001-01-01T00:00:00Z
+    updatedAt: 0001-01-01T00:00:00Z
+    version: 0
+    project: ""
+spec:
+    display:
+        name: Production
+    default: false
+    plugin:
+        kind: PrometheusDatasource
+        spec:
+            proxy:
+                kind: HTTPProxy
+                spec:
+                    url: http://prometheus-prometheus.kube-monitoring.svc:9090
+`,
+		},
+		{
+			name:           "invalid annotation drops the service",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			annotations: map[string]string{
+				AnnotationAllowedEndpoints: `[{"endpointPattern":"/api/v1/query","method":"TRACE"}]`,
+			},
+			datasourcePlugin:   "PrometheusDatasource",
+			expectedYAMLResult: "null\n",
+		},
+		{
+			name:           "empty name drops the service",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			nameTemplate:       "{{ .Labels.missing }}",
+			datasourcePlugin:   "PrometheusDatasource",
+			expectedYAMLResult: "null\n",
+		},
+		{
+			name:           "secret not allowed drops the service",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			annotations: map[string]string{
+				AnnotationSecret: "admin-credentials",
+			},
+			allowedSecrets:     []string{"prometheus-auth"},
+			datasourcePlugin:   "PrometheusDatasource",
+			expectedYAMLResult: "null\n",
+		},
+		{
+			name:           "headers not enabled drops the service",
+			k8sServicePath: filepath.Join("testdata", "service.json"),
+			serviceConfig: config.KubeServiceDiscovery{
+				PortName:    "http-web",
+				ServiceType: string(corev1.ServiceTypeClusterIP),
+			},
+			annotations: map[string]string{
+				AnnotationHeaders: `{"Authorization":"Bearer token"}`,
+			},
+			datasourcePlugin:   "PrometheusDatasource",
+			expectedYAMLResult: "null\n",
+		},
 	}
 	sch := plugin.StrictLoad().Schema()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var k8sSVC corev1.Service
 			test.JSONUnmarshalFromFile(tt.k8sServicePath, &k8sSVC)
+			for k, v := range tt.annotations {
+				k8sSVC.Annotations[k] = v
+			}
 			cfg := &config.KubernetesDiscovery{
 				DatasourcePluginKind: tt.datasourcePlugin,
 				ServiceConfiguration: tt.serviceConfig,
+				NameTemplate:         tt.nameTemplate,
+				DisplayNameTemplate:  tt.displayTemplate,
+
+				AnnotationAllowedSecrets: tt.allowedSecrets,
+				EnableAnnotationHeaders:  tt.allowHeaders,
 			}
 			k8sDiscovery := &discovery{
 				schema: sch,
@@ -75,10 +284,15 @@ spec:
 			if err != nil {
 				t.Fatal(err)
 			}
+			builder, err := newDatasourceBuilder("kubernetes", cfg, tt.project)
+			if err != nil {
+				t.Fatal(err)
+			}
 			svc := &serviceDiscovery{
-				cfg: tt.serviceConfig,
+				cfg:     tt.serviceConfig,
+				builder: builder,
 			}
-			dts, err := svc.serviceToGlobalDatasource(k8sSVC, nodes)
+			dts, err := svc.serviceToDatasource(k8sSVC, nodes)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -14,44 +14,174 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/perses/common/set"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
-	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
 )
 
-func New(caseSensitive bool, svc globaldatasource.Service) *ApplyService {
+const (
+	// discoveryTagPrefix is the prefix of the tag set on every datasource created by a discovery.
+	// It is followed by the name of the discovery.
+	discoveryTagPrefix = "discovery:"
+	// OrphanedTag is set on the datasources no longer returned by their discovery when the garbage collection
+	// action is "mark".
+	OrphanedTag = "discovery-orphaned"
+)
+
+// Tag returns the tag identifying the datasources created by the given discovery.
+func Tag(discoveryName string) string {
+	return discoveryTagPrefix + discoveryName
+}
+
+// New creates the service saving the datasources of a discovery as global datasources.
+func New(discoveryName string, gc config.DiscoveryGarbageCollection, caseSensitive bool, svc globaldatasource.Service) *ApplyService {
+	return newApplyService(discoveryName, gc, caseSensitive, &globalStore{svc: svc})
+}
+
+// NewProject creates the service saving the datasources of a discovery in the project set on each of them.
+func NewProject(discoveryName string, gc config.DiscoveryGarbageCollection, caseSensitive bool, svc datasource.Service) *ApplyService {
+	return newApplyService(discoveryName, gc, caseSensitive, &projectStore{svc: svc})
+}
+
+func newApplyService(discoveryName string, gc config.DiscoveryGarbageCollection, caseSensitive bool, s store) *ApplyService {
 	return &ApplyService{
+		discoveryName: discoveryName,
+		gc:            gc,
 		caseSensitive: caseSensitive,
-		svc:           svc,
+		store:         s,
+		missingSince:  make(map[string]time.Time),
+		now:           time.Now,
 	}
 }
 
+// ApplyService creates or updates the datasources returned by a discovery,
+// and when the garbage collection is enabled, takes care of the ones that are not returned anymore.
 type ApplyService struct {
+	discoveryName string
+	gc            config.DiscoveryGarbageCollection
 	caseSensitive bool
-	svc           globaldatasource.Service
+	store         store
+	// missingSince is the time when a datasource created by the discovery has been found missing for the first time.
+	// It is only accessed by the discovery task, so there is no need for a lock.
+	missingSince map[string]time.Time
+	now          func() time.Time
+}
+
+func key(entity *v1.Datasource) string {
+	return fmt.Sprintf("%s/%s", entity.Metadata.Project, entity.Metadata.Name)
 }
 
-func (a *ApplyService) Apply(entities []*v1.GlobalDatasource) {
+// Apply saves the datasources returned by the discovery.
+// The project of the datasources must be empty when the discovery manages global datasources.
+func (a *ApplyService) Apply(entities []*v1.Datasource) {
+	discovered := set.New[string]()
 	for _, entity := range entities {
 		entity.GetMetadata().Flatten(a.caseSensitive)
-		_, createErr := a.svc.Create(nil, entity)
-		if createErr == nil {
-			continue
+		if entity.Metadata.Tags == nil {
+			entity.Metadata.Tags = set.New[string]()
 		}
+		entity.Metadata.Tags.Add(Tag(a.discoveryName))
+		discovered.Add(key(entity))
+		a.upsert(entity)
+	}
+	if a.gc.Enable {
+		a.collect(discovered)
+	}
+}
 
-		if !databaseModel.IsKeyConflict(createErr) {
-			logrus.WithError(createErr).Errorf("unable to create the globaldatasource %q", entity.Metadata.Name)
+func (a *ApplyService) upsert(entity *v1.Datasource) {
+	createErr := a.store.create(entity)
+	if createErr == nil {
+		return
+	}
+
+	if !databaseModel.IsKeyConflict(createErr) {
+		a.log(entity).WithError(createErr).Errorf("unable to create the %s", a.store.kind())
+		return
+	}
+
+	if updateError := a.store.update(entity); updateError != nil {
+		a.log(entity).WithError(updateError).Errorf("unable to update the %s", a.store.kind())
+	}
+}
+
+func (a *ApplyService) log(entity *v1.Datasource) *logrus.Entry {
+	log := logrus.WithField("discovery", a.discoveryName).WithField("name", entity.Metadata.Name)
+	if len(entity.Metadata.Project) > 0 {
+		log = log.WithField("project", entity.Metadata.Project)
+	}
+	return log
+}
+
+// collect looks for the datasources created by the discovery that are not part of the last result.
+// Once they are missing for longer than the grace period, they are deleted or marked as orphaned.
+func (a *ApplyService) collect(discovered set.Set[string]) {
+	list, err := a.store.list()
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to list the %s created by the discovery %q", a.store.kind(), a.discoveryName)
+		return
+	}
+	now := a.now()
+	owned := set.New[string]()
+	for _, entity := range list {
+		name := key(entity)
+		if !entity.Metadata.Tags.Contains(Tag(a.discoveryName)) {
 			continue
 		}
-
-		param := apiInterface.Parameters{
-			Name: entity.Metadata.Name,
+		owned.Add(name)
+		if discovered.Contains(name) {
+			delete(a.missingSince, name)
+			continue
+		}
+		if entity.Metadata.Tags.Contains(OrphanedTag) {
+			// Already marked, nothing more to do.
+			continue
 		}
+		since, ok := a.missingSince[name]
+		if !ok {
+			a.missingSince[name] = now
+			since = now
+		}
+		if now.Sub(since) < time.Duration(a.gc.GracePeriod) {
+			continue
+		}
+		a.orphan(entity)
+	}
+	// Forget the datasources that have been removed in the meantime.
+	for name := range a.missingSince {
+		if !owned.Contains(name) {
+			delete(a.missingSince, name)
+		}
+	}
+}
 
-		if _, updateError := a.svc.Update(nil, entity, param); updateError != nil {
-			logrus.WithError(updateError).Errorf("unable to update the globaldatasource %q", entity.Metadata.Name)
+func (a *ApplyService) orphan(entity *v1.Datasource) {
+	kind := a.store.kind()
+	log := a.log(entity)
+	if a.gc.DryRun {
+		log.Infof("%s is no longer discovered and would be collected with the action %q (dry run)", kind, a.gc.Action)
+		return
+	}
+	if a.gc.Action == config.DiscoveryGCActionMark {
+		entity.Metadata.Tags.Add(OrphanedTag)
+		if err := a.store.update(entity); err != nil {
+			log.WithError(err).Errorf("unable to mark the orphaned %s", kind)
+			return
+		}
+		log.Infof("%s is no longer discovered and has been marked as orphaned", kind)
+	} else {
+		if err := a.store.delete(entity.Metadata.Project, entity.Metadata.Name); err != nil && !databaseModel.IsKeyNotFound(err) {
+			log.WithError(err).Errorf("unable to delete the orphaned %s", kind)
+			return
 		}
+		log.Infof("%s is no longer discovered and has been deleted", kind)
 	}
+	delete(a.missingSince, key(entity))
 }
@@ -0,0 +1,209 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/spec/go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	globaldatasource.Service
+	entities map[string]*v1.GlobalDatasource
+}
+
+func (s *fakeService) Create(_ echo.Context, entity *v1.GlobalDatasource) (*v1.GlobalDatasource, error) {
+	if _, ok := s.entities[entity.Metadata.Name]; ok {
+		return nil, &databaseModel.Error{Key: entity.Metadata.Name, Code: databaseModel.ErrorCodeConflict}
+	}
+	s.entities[entity.Metadata.Name] = entity
+	return entity, nil
+}
+
+func (s *fakeService) Update(_ echo.Context, entity *v1.GlobalDatasource, _ apiInterface.Parameters) (*v1.GlobalDatasource, error) {
+	s.entities[entity.Metadata.Name] = entity
+	return entity, nil
+}
+
+func (s *fakeService) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
+	delete(s.entities, parameters.Name)
+	return nil
+}
+
+func (s *fakeService) List(_ *globaldatasource.Query, _ apiInterface.Parameters) ([]*v1.GlobalDatasource, error) {
+	var result []*v1.GlobalDatasource
+	for _, entity := range s.entities {
+		result = append(result, entity)
+	}
+	return result, nil
+}
+
+func newGlobalDatasource(name string) *v1.GlobalDatasource {
+	return &v1.GlobalDatasource{Kind: v1.KindGlobalDatasource, Metadata: v1.Metadata{Name: name}}
+}
+
+func newDatasource(project string, name string) *v1.Datasource {
+	return &v1.Datasource{
+		Kind: v1.KindDatasource,
+		Metadata: v1.ProjectMetadata{
+			Metadata:               v1.Metadata{Name: name},
+			ProjectMetadataWrapper: v1.ProjectMetadataWrapper{Project: project},
+		},
+	}
+}
+
+func newTestApplyService(gc config.DiscoveryGarbageCollection) (*ApplyService, *fakeService, *time.Time) {
+	fake := &fakeService{entities: map[string]*v1.GlobalDatasource{
+		// A datasource created by hand must never be collected.
+		"manual": newGlobalDatasource("manual"),
+	}}
+	svc := New("prom", gc, true, fake)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	return svc, fake, &now
+}
+
+func TestApply_Tag(t *testing.T) {
+	svc, fake, _ := newTestApplyService(config.DiscoveryGarbageCollection{})
+	svc.Apply([]*v1.Datasource{newDatasource("", "a")})
+	assert.True(t, fake.entities["a"].Metadata.Tags.Contains("discovery:prom"))
+}
+
+func TestApply_GarbageCollection(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		gc      config.DiscoveryGarbageCollection
+		deleted bool
+		marked  bool
+	}{
+		{
+			title:   "delete",
+			gc:      config.DiscoveryGarbageCollection{Enable: true, Action: config.DiscoveryGCActionDelete},
+			deleted: true,
+		},
+		{
+			title:  "mark",
+			gc:     config.DiscoveryGarbageCollection{Enable: true, Action: config.DiscoveryGCActionMark},
+			marked: true,
+		},
+		{
+			title: "dry run",
+			gc:    config.DiscoveryGarbageCollection{Enable: true, Action: config.DiscoveryGCActionDelete, DryRun: true},
+		},
+		{
+			title: "disabled",
+			gc:    config.DiscoveryGarbageCollection{},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			svc, fake, _ := newTestApplyService(test.gc)
+			svc.Apply([]*v1.Datasource{newDatasource("", "a"), newDatasource("", "b")})
+			svc.Apply([]*v1.Datasource{newDatasource("", "a")})
+			assert.Contains(t, fake.entities, "a")
+			assert.Contains(t, fake.entities, "manual")
+			b, exists := fake.entities["b"]
+			assert.Equal(t, !test.deleted, exists)
+			if exists {
+				assert.Equal(t, test.marked, b.Metadata.Tags.Contains(OrphanedTag))
+			}
+		})
+	}
+}
+
+func TestApply_GracePeriod(t *testing.T) {
+	svc, fake, now := newTestApplyService(config.DiscoveryGarbageCollection{
+		Enable:      true,
+		GracePeriod: common.Duration(10 * time.Minute),
+		Action:      config.DiscoveryGCActionDelete,
+	})
+	svc.Apply([]*v1.Datasource{newDatasource("", "a")})
+
+	// The datasource disappears, but not long enough to be collected.
+	svc.Apply(nil)
+	*now = now.Add(5 * time.Minute)
+	svc.Apply(nil)
+	assert.Contains(t, fake.entities, "a")
+
+	// It comes back, so the grace period starts again on the next disappearance.
+	svc.Apply([]*v1.Datasource{newDatasource("", "a")})
+	*now = now.Add(6 * time.Minute)
+	svc.Apply(nil)
+	assert.Contains(t, fake.entities, "a")
+
+	*now = now.Add(10 * time.Minute)
+	svc.Apply(nil)
+	assert.NotContains(t, fake.entities, "a")
+	assert.Empty(t, svc.missingSince)
+}
+
+type fakeProjectService struct {
+	datasource.Service
+	entities map[string]*v1.Datasource
+}
+
+func (s *fakeProjectService) Create(_ echo.Context, entity *v1.Datasource) (*v1.Datasource, error) {
+	k := key(entity)
+	if _, ok := s.entities[k]; ok {
+		return nil, &databaseModel.Error{Key: k, Code: databaseModel.ErrorCodeConflict}
+	}
+	s.entities[k] = entity
+	return entity, nil
+}
+
+func (s *fakeProjectService) Update(_ echo.Context, entity *v1.Datasource, _ apiInterface.Parameters) (*v1.Datasource, error) {
+	s.entities[key(entity)] = entity
+	return entity, nil
+}
+
+func (s *fakeProjectService) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
+	delete(s.entities, parameters.Project+"/"+parameters.Name)
+	return nil
+}
+
+func (s *fakeProjectService) List(_ *datasource.Query, _ apiInterface.Parameters) ([]*v1.Datasource, error) {
+	var result []*v1.Datasource
+	for _, entity := range s.entities {
+		result = append(result, entity)
+	}
+	return result, nil
+}
+
+func TestApply_Project(t *testing.T) {
+	fake := &fakeProjectService{entities: map[string]*v1.Datasource{
+		// A datasource created by hand must never be collected.
+		"team-b/manual": newDatasource("team-b", "manual"),
+	}}
+	svc := NewProject("prom", config.DiscoveryGarbageCollection{Enable: true, Action: config.DiscoveryGCActionDelete}, true, fake)
+	svc.Apply([]*v1.Datasource{newDatasource("team-a", "prometheus"), newDatasource("team-c", "prometheus")})
+	assert.True(t, fake.entities["team-a/prometheus"].Metadata.Tags.Contains("discovery:prom"))
+	assert.Contains(t, fake.entities, "team-c/prometheus")
+
+	// The datasource moved from the project team-c to team-b.
+	svc.Apply([]*v1.Datasource{newDatasource("team-a", "prometheus"), newDatasource("team-b", "prometheus")})
+	assert.Contains(t, fake.entities, "team-a/prometheus")
+	assert.Contains(t, fake.entities, "team-b/prometheus")
+	assert.NotContains(t, fake.entities, "team-c/prometheus")
+	assert.Contains(t, fake.entities, "team-b/manual")
+}
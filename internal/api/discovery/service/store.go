@@ -0,0 +1,104 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+// store saves the discovered datasources, either as global datasources or as project datasources.
+// Whatever the store, the datasources are manipulated as project datasources, with an empty project for the global ones.
+type store interface {
+	// kind is used for logging purposes.
+	kind() v1.Kind
+	create(entity *v1.Datasource) error
+	update(entity *v1.Datasource) error
+	delete(project string, name string) error
+	list() ([]*v1.Datasource, error)
+}
+
+type globalStore struct {
+	svc globaldatasource.Service
+}
+
+func toGlobal(entity *v1.Datasource) *v1.GlobalDatasource {
+	return &v1.GlobalDatasource{
+		Kind:     v1.KindGlobalDatasource,
+		Metadata: entity.Metadata.Metadata,
+		Spec:     entity.Spec,
+	}
+}
+
+func (s *globalStore) kind() v1.Kind {
+	return v1.KindGlobalDatasource
+}
+
+func (s *globalStore) create(entity *v1.Datasource) error {
+	_, err := s.svc.Create(nil, toGlobal(entity))
+	return err
+}
+
+func (s *globalStore) update(entity *v1.Datasource) error {
+	_, err := s.svc.Update(nil, toGlobal(entity), apiInterface.Parameters{Name: entity.Metadata.Name})
+	return err
+}
+
+func (s *globalStore) delete(_ string, name string) error {
+	return s.svc.Delete(nil, apiInterface.Parameters{Name: name})
+}
+
+func (s *globalStore) list() ([]*v1.Datasource, error) {
+	list, err := s.svc.List(&globaldatasource.Query{}, apiInterface.Parameters{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1.Datasource, 0, len(list))
+	for _, entity := range list {
+		result = append(result, &v1.Datasource{
+			Kind:     v1.KindDatasource,
+			Metadata: v1.ProjectMetadata{Metadata: entity.Metadata},
+			Spec:     entity.Spec,
+		})
+	}
+	return result, nil
+}
+
+type projectStore struct {
+	svc datasource.Service
+}
+
+func (s *projectStore) kind() v1.Kind {
+	return v1.KindDatasource
+}
+
+func (s *projectStore) create(entity *v1.Datasource) error {
+	_, err := s.svc.Create(nil, entity)
+	return err
+}
+
+func (s *projectStore) update(entity *v1.Datasource) error {
+	_, err := s.svc.Update(nil, entity, apiInterface.Parameters{Project: entity.Metadata.Project, Name: entity.Metadata.Name})
+	return err
+}
+
+func (s *projectStore) delete(project string, name string) error {
+	return s.svc.Delete(nil, apiInterface.Parameters{Project: project, Name: name})
+}
+
+func (s *projectStore) list() ([]*v1.Datasource, error) {
+	return s.svc.List(&datasource.Query{}, apiInterface.Parameters{})
+}
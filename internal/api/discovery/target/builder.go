@@ -0,0 +1,140 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package target
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/perses/common/set"
+	"github.com/perses/perses/internal/api/discovery/cuetils"
+	"github.com/perses/perses/internal/api/plugin/schema"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
+	"github.com/perses/spec/go/datasource"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxTags leaves room in the datasource tags for the ones set by the discovery itself.
+	maxTags      = 18
+	maxTagLength = 50
+)
+
+// Builder converts the targets found by a discovery mechanism into project datasources,
+// using the schema of the datasource plugin to inject the URL of each target in the proxy.
+// When the project of the builder is empty, the datasources are saved as global datasources by the store.
+type Builder struct {
+	discoveryName string
+	pluginKind    string
+	scheme        string
+	project       string
+	name          *NameTemplate
+	schema        schema.Schema
+}
+
+// NewBuilder creates the Builder of the datasources. When project is set, the datasources are created in this project.
+func NewBuilder(discoveryName string, project string, pluginKind string, scheme string, nameTemplate string, defaultNameTemplate string, sch schema.Schema) (*Builder, error) {
+	name, err := NewNameTemplate(nameTemplate, defaultNameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &Builder{
+		discoveryName: discoveryName,
+		pluginKind:    pluginKind,
+		scheme:        scheme,
+		project:       project,
+		name:          name,
+		schema:        sch,
+	}, nil
+}
+
+func (b *Builder) Build(targets []Target) ([]*v1.Datasource, error) {
+	decodedSchema, err := b.decodeSchema()
+	if err != nil {
+		return nil, err
+	}
+	names := set.New[string]()
+	var result []*v1.Datasource
+	for _, t := range targets {
+		name, nameErr := b.name.Execute(t)
+		if nameErr != nil {
+			return nil, fmt.Errorf("unable to build the name of the target %q: %w", t.Address, nameErr)
+		}
+		if names.Contains(name) {
+			logrus.Warningf("target %q dropped by the discovery %q because another target has the same name %q", t.Address, b.discoveryName, name)
+			continue
+		}
+		names.Add(name)
+		url, urlErr := common.ParseURL(fmt.Sprintf("%s://%s", b.scheme, t.Address))
+		if urlErr != nil {
+			return nil, fmt.Errorf("unable to create the URL for the target %q: %w", t.Address, urlErr)
+		}
+		plugin, pluginErr := cuetils.BuildPluginAndInjectProxy(decodedSchema, http.Config{URL: url})
+		if pluginErr != nil {
+			return nil, pluginErr
+		}
+		result = append(result, &v1.Datasource{
+			Kind: v1.KindDatasource,
+			Metadata: v1.ProjectMetadata{
+				Metadata: v1.Metadata{
+					Name: name,
+					Tags: b.buildTags(t),
+				},
+				ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+					Project: b.project,
+				},
+			},
+			Spec: datasource.Spec{
+				Plugin: plugin,
+			},
+		})
+	}
+	return result, nil
+}
+
+// buildTags keeps the tags of the target that are valid datasource tags.
+func (b *Builder) buildTags(t Target) set.Set[string] {
+	tags := set.New[string]()
+	for _, tag := range t.Tags {
+		tag = strings.TrimSpace(tag)
+		if len(tag) == 0 || utf8.RuneCountInString(tag) > maxTagLength {
+			logrus.Debugf("tag %q of the target %q ignored by the discovery %q", tag, t.Address, b.discoveryName)
+			continue
+		}
+		if len(tags) == maxTags {
+			logrus.Debugf("too many tags on the target %q, the remaining ones are ignored by the discovery %q", t.Address, b.discoveryName)
+			break
+		}
+		tags.Add(tag)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+func (b *Builder) decodeSchema() ([]*cuetils.Node, error) {
+	sch, err := b.schema.GetDatasourceSchema(b.pluginKind)
+	if err != nil {
+		// The error must be returned, otherwise the discovery would return nothing and the garbage collection
+		// would consider every discovered datasource as orphaned.
+		return nil, fmt.Errorf("failed to get datasource schema: %w", err)
+	}
+	ctx := cuecontext.New()
+	return cuetils.NewFromSchema(ctx.BuildInstance(sch))
+}
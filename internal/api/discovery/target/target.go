@@ -0,0 +1,78 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package target
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// maxNameLength is the maximum length of the name of a resource.
+const maxNameLength = 75
+
+// nameHashLength is the length of the hash added to a truncated name, so two long names sharing the same prefix remain different.
+const nameHashLength = 8
+
+// invalidNameChars matches every character that is not allowed in the name of a resource.
+var invalidNameChars = regexp.MustCompile("[^a-zA-Z0-9_.-]+")
+
+// Target is an endpoint found by a discovery mechanism, from which a datasource is built.
+type Target struct {
+	// Address is the host and the port to reach the datasource.
+	Address string
+	// Labels describe the target. They can be used in the name template.
+	Labels map[string]string
+	// Tags are carried over to the tags of the datasource.
+	Tags []string
+}
+
+// NameTemplate builds the name of the datasource from a target.
+type NameTemplate struct {
+	tmpl *template.Template
+}
+
+// NewNameTemplate parses the given Go template. If it is empty, the default template is used.
+func NewNameTemplate(text string, defaultText string) (*NameTemplate, error) {
+	if len(text) == 0 {
+		text = defaultText
+	}
+	tmpl, err := template.New("name").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template %q: %w", text, err)
+	}
+	return &NameTemplate{tmpl: tmpl}, nil
+}
+
+// Execute renders the name of the datasource from the given data. Any character not allowed in a name is replaced by a dash.
+// A name too long is truncated and suffixed by a hash of the full name.
+func (n *NameTemplate) Execute(data any) (string, error) {
+	var buffer bytes.Buffer
+	if err := n.tmpl.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+	name := strings.Trim(invalidNameChars.ReplaceAllString(buffer.String(), "-"), "-")
+	if len(name) == 0 {
+		return "", fmt.Errorf("the name template returns an empty name")
+	}
+	if len(name) > maxNameLength {
+		sum := sha256.Sum256([]byte(name))
+		name = fmt.Sprintf("%s-%s", strings.TrimRight(name[:maxNameLength-nameHashLength-1], "-."), hex.EncodeToString(sum[:])[:nameHashLength])
+	}
+	return name, nil
+}
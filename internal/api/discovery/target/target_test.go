@@ -0,0 +1,81 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package target
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameTemplate_Execute(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		template string
+		target   Target
+		result   string
+		err      bool
+	}{
+		{
+			title:    "default template",
+			template: "",
+			target:   Target{Address: "10.0.0.1:9090", Labels: map[string]string{"host": "prometheus.example.com", "port": "9090"}},
+			result:   "prometheus.example.com-9090",
+		},
+		{
+			title:    "invalid characters replaced",
+			template: "{{ .Labels.service }}/{{ .Labels.node }}",
+			target:   Target{Labels: map[string]string{"service": "prometheus", "node": "vm 1"}},
+			result:   "prometheus-vm-1",
+		},
+		{
+			title:    "using the address",
+			template: "{{ .Address }}",
+			target:   Target{Address: "10.0.0.1:9090"},
+			result:   "10.0.0.1-9090",
+		},
+		{
+			title:    "long name truncated",
+			template: "{{ .Labels.host }}",
+			target:   Target{Labels: map[string]string{"host": "prometheus-k8s-0.prometheus-operated.monitoring.svc.cluster.local.example.com"}},
+			result:   "prometheus-k8s-0.prometheus-operated.monitoring.svc.cluster.local-bc05cdeb",
+		},
+		{
+			title:    "empty name",
+			template: "{{ .Labels.missing }}",
+			target:   Target{Address: "10.0.0.1:9090"},
+			err:      true,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			tmpl, err := NewNameTemplate(test.template, "{{ .Labels.host }}-{{ .Labels.port }}")
+			if !assert.NoError(t, err) {
+				return
+			}
+			name, err := tmpl.Execute(test.target)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, name)
+		})
+	}
+}
+
+func TestNewNameTemplate_Invalid(t *testing.T) {
+	_, err := NewNameTemplate("{{ .Labels.host", "")
+	assert.Error(t, err)
+}
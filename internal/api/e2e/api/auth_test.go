@@ -20,8 +20,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gavv/httpexpect/v2"
+	"github.com/perses/perses/internal/api/crypto"
 	"github.com/perses/perses/internal/api/dependency"
 	e2eframework "github.com/perses/perses/internal/api/e2e/framework"
 	"github.com/perses/perses/internal/api/utils"
@@ -80,6 +82,69 @@ func TestAuth_EmptyPassword(t *testing.T) {
 	})
 }
 
+func TestAuth_TwoFactorRequired(t *testing.T) {
+	conf := e2eframework.DefaultAuthConfig()
+	conf.Security.Authentication.Providers.Native.RequireTwoFactor = true
+	e2eframework.WithServerConfig(t, conf, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []modelAPI.Entity {
+		usrEntity := e2eframework.NewUser("foo", "password")
+		expect.POST(fmt.Sprintf("%s/%s", utils.APIV1Prefix, utils.PathUser)).
+			WithJSON(usrEntity).
+			Expect().
+			Status(http.StatusOK)
+
+		authEntity := modelAPI.Auth{
+			Login:    usrEntity.GetMetadata().GetName(),
+			Password: usrEntity.Spec.NativeProvider.Password,
+		}
+		loginPath := fmt.Sprintf("%s/%s/%s/%s", utils.APIPrefix, utils.PathAuthProviders, utils.AuthnKindNative, utils.PathLogin)
+		twoFactorPath := fmt.Sprintf("%s/%s", loginPath, utils.PathTOTP)
+
+		// The user never enrolled an authenticator app, so the enrollment is part of the login.
+		challenge := &modelAPI.TwoFactorChallenge{}
+		expect.POST(loginPath).
+			WithJSON(authEntity).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Decode(challenge)
+		assert.NotEmpty(t, challenge.TwoFactorToken)
+		assert.NotNil(t, challenge.Enrollment)
+
+		expect.POST(twoFactorPath).
+			WithJSON(modelAPI.TwoFactorLoginRequest{TwoFactorToken: challenge.TwoFactorToken, Code: "000000"}).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		code, err := crypto.GenerateTOTPCode(challenge.Enrollment.Secret, time.Now())
+		assert.NoError(t, err)
+		response := &modelAPI.TwoFactorLoginResponse{}
+		expect.POST(twoFactorPath).
+			WithJSON(modelAPI.TwoFactorLoginRequest{TwoFactorToken: challenge.TwoFactorToken, Code: code}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Decode(response)
+		assert.NotEmpty(t, response.AccessToken)
+		assert.Len(t, response.RecoveryCodes, 10)
+
+		// Once enrolled, the login requires a code without a new enrollment.
+		challenge = &modelAPI.TwoFactorChallenge{}
+		expect.POST(loginPath).
+			WithJSON(authEntity).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Decode(challenge)
+		assert.Nil(t, challenge.Enrollment)
+
+		jsonToken := expect.POST(twoFactorPath).
+			WithJSON(modelAPI.TwoFactorLoginRequest{TwoFactorToken: challenge.TwoFactorToken, Code: response.RecoveryCodes[0]}).
+			Expect().
+			Status(http.StatusOK).
+			JSON()
+		jsonToken.Path("$.access_token").NotNull().NotEqual("")
+		jsonToken.Object().NotContainsKey("recoveryCodes")
+		return []modelAPI.Entity{usrEntity}
+	})
+}
+
 // TestAuth_OAuthProvider_AuthEndpoint
 // Send a GET request to the /login endpoint of the provider, in order to be redirected.
 // So we expect just a 200 status with a fake body proving that it comes from the test provider.
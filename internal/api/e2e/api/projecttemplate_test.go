@@ -0,0 +1,87 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/dependency"
+	e2eframework "github.com/perses/perses/internal/api/e2e/framework"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainScenarioProjectTemplate(t *testing.T) {
+	e2eframework.MainTestScenario(t, utils.PathProjectTemplate, func(name string) api.Entity {
+		return e2eframework.NewProjectTemplate(name)
+	})
+}
+
+func TestCreateProjectFromTemplate(t *testing.T) {
+	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []api.Entity {
+		tmpl := e2eframework.NewProjectTemplate("team")
+		e2eframework.CreateAndWaitUntilEntityExists(t, manager, tmpl)
+		project := e2eframework.NewProject("perses")
+		project.Spec.Template = &v1.ProjectTemplateReference{
+			Name:       tmpl.Metadata.Name,
+			Parameters: map[string]string{"username": "Basil"},
+		}
+		expect.POST(fmt.Sprintf("%s/%s", utils.APIV1Prefix, utils.PathProject)).
+			WithJSON(project).
+			Expect().
+			Status(http.StatusOK)
+
+		scrt, err := manager.GetSecret().Get("perses", "perses-auth")
+		assert.NoError(t, err)
+		assert.Equal(t, "Basil", scrt.Spec.BasicAuth.Username)
+
+		// Syncing the template restores the resources modified in the project
+		scrt.Spec.BasicAuth.Username = "Hercule"
+		assert.NoError(t, manager.GetSecret().Update(scrt))
+		expect.POST(fmt.Sprintf("%s/%s/%s/sync", utils.APIV1Prefix, utils.PathProjectTemplate, tmpl.Metadata.Name)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("synced").Array().IsEqual([]string{"perses"})
+		scrt, err = manager.GetSecret().Get("perses", "perses-auth")
+		assert.NoError(t, err)
+		assert.Equal(t, "Basil", scrt.Spec.BasicAuth.Username)
+		return []api.Entity{project, tmpl, scrt}
+	})
+}
+
+func TestCreateProjectFromTemplateMissingParameter(t *testing.T) {
+	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []api.Entity {
+		tmpl := e2eframework.NewProjectTemplate("team")
+		e2eframework.CreateAndWaitUntilEntityExists(t, manager, tmpl)
+		project := e2eframework.NewProject("perses")
+		project.Spec.Template = &v1.ProjectTemplateReference{Name: tmpl.Metadata.Name}
+		expect.POST(fmt.Sprintf("%s/%s", utils.APIV1Prefix, utils.PathProject)).
+			WithJSON(project).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		_, err := manager.GetProject().Get("perses")
+		assert.True(t, databaseModel.IsKeyNotFound(err))
+		return []api.Entity{tmpl}
+	})
+}
@@ -116,11 +116,11 @@ func newDashboard(projectName string, dashboardName string, dtsName string, dts
 				Project: projectName,
 			},
 		},
-		Spec: dashboard.Spec{
+		Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 			Datasources: map[string]*datasourceSpec.Spec{
 				dtsName: &dts.Spec,
 			},
-		},
+		}},
 	}
 	entity.Metadata.CreateNow()
 	return entity
@@ -0,0 +1,171 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/perses/perses/internal/api/dependency"
+	e2eframework "github.com/perses/perses/internal/api/e2e/framework"
+	"github.com/perses/perses/internal/api/utils"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	apiConfig "github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+)
+
+const scimToken = "scim-secret-token"
+
+func scimConfig() apiConfig.Config {
+	conf := e2eframework.DefaultAuthConfig()
+	conf.Security.SCIM = apiConfig.SCIMConfig{
+		Enable:      true,
+		BearerToken: secret.Hidden(scimToken),
+	}
+	return conf
+}
+
+func TestSCIM_Unauthorized(t *testing.T) {
+	server, expect, manager := e2eframework.CreateServer(t, scimConfig())
+	defer manager.Persistence().GetPersesDAO().Close()
+	defer server.Close()
+
+	expect.GET(fmt.Sprintf("%s/Users", utils.SCIMV2Prefix)).
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().HasValue("status", "401")
+
+	expect.GET(fmt.Sprintf("%s/Users", utils.SCIMV2Prefix)).
+		WithHeader(e2eframework.CreateAuthorizationHeader("wrong-token")).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestSCIM_Offboarding(t *testing.T) {
+	server, expect, manager := e2eframework.CreateServer(t, scimConfig())
+	defer manager.Persistence().GetPersesDAO().Close()
+	defer server.Close()
+	scimHeader, scimHeaderValue := e2eframework.CreateAuthorizationHeader(scimToken)
+
+	// bob signed up natively before being provisioned by the identity provider.
+	usrEntity := e2eframework.NewUser("bob", "password")
+	expect.POST(fmt.Sprintf("%s/%s", utils.APIV1Prefix, utils.PathUser)).
+		WithJSON(usrEntity).
+		Expect().
+		Status(http.StatusOK)
+
+	// The identity provider is looking for bob using the email as userName.
+	expect.GET(fmt.Sprintf("%s/Users", utils.SCIMV2Prefix)).
+		WithHeader(scimHeader, scimHeaderValue).
+		WithQuery("filter", `userName eq "bob@example.com"`).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("totalResults", 1)
+
+	expect.PUT(fmt.Sprintf("%s/Users/bob", utils.SCIMV2Prefix)).
+		WithHeader(scimHeader, scimHeaderValue).
+		WithJSON(map[string]any{
+			"schemas":  []string{"urn:ietf:params:scim:schemas:core:2.0:User"},
+			"userName": "bob@example.com",
+			"name":     map[string]string{"givenName": "Bob", "familyName": "Smith"},
+			"active":   true,
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("id", "bob").HasValue("active", true)
+
+	expect.POST(fmt.Sprintf("%s/Groups", utils.SCIMV2Prefix)).
+		WithHeader(scimHeader, scimHeaderValue).
+		WithJSON(map[string]any{
+			"schemas":     []string{"urn:ietf:params:scim:schemas:core:2.0:Group"},
+			"displayName": "Dev Team",
+			"members":     []map[string]string{{"value": "bob"}},
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().HasValue("id", "Dev-Team")
+	grp := &v1.Group{Kind: v1.KindGroup, Metadata: v1.Metadata{Name: "Dev-Team"}}
+
+	// The group is bound to a global role that gives every permission.
+	globalRole := e2eframework.NewGlobalRole("admin")
+	e2eframework.CreateAndWaitUntilEntityExists(t, manager.Persistence(), globalRole)
+	globalRoleBinding := e2eframework.NewGlobalRoleBinding("admin")
+	globalRoleBinding.Spec.Subjects = []v1.Subject{{Kind: v1.KindGroup, Name: grp.Metadata.Name}}
+	e2eframework.CreateAndWaitUntilEntityExists(t, manager.Persistence(), globalRoleBinding)
+	refreshPermissions(t, manager)
+
+	authResponse := expect.POST(fmt.Sprintf("%s/%s/%s/%s", utils.APIPrefix, utils.PathAuthProviders, utils.AuthnKindNative, utils.PathLogin)).
+		WithJSON(modelAPI.Auth{Login: "bob", Password: "password"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	token := authResponse.Value("access_token").String().Raw()
+	refreshToken := authResponse.Value("refresh_token").String().Raw()
+
+	expect.GET(fmt.Sprintf("%s/%s/bob/permissions", utils.APIV1Prefix, utils.PathUser)).
+		WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value(v1.WildcardProject).Array().ContainsAny(globalRole.Spec.Permissions[0])
+
+	// Offboarding bob from the identity provider deactivates the user.
+	expect.PATCH(fmt.Sprintf("%s/Users/bob", utils.SCIMV2Prefix)).
+		WithHeader(scimHeader, scimHeaderValue).
+		WithJSON(map[string]any{
+			"schemas":    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+			"Operations": []map[string]any{{"op": "Replace", "path": "active", "value": "False"}},
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().HasValue("active", false)
+
+	expect.GET(fmt.Sprintf("%s/%s", utils.APIV1Prefix, utils.PathUser)).
+		WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+		Expect().
+		Status(http.StatusForbidden)
+
+	expect.POST(fmt.Sprintf("%s/%s/%s", utils.APIPrefix, utils.PathAuth, utils.PathRefresh)).
+		WithJSON(modelAPI.RefreshRequest{RefreshToken: refreshToken}).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	expect.POST(fmt.Sprintf("%s/%s/%s/%s", utils.APIPrefix, utils.PathAuthProviders, utils.AuthnKindNative, utils.PathLogin)).
+		WithJSON(modelAPI.Auth{Login: "bob", Password: "password"}).
+		Expect().
+		Status(http.StatusForbidden)
+
+	// Deleting bob removes the user from the groups.
+	expect.DELETE(fmt.Sprintf("%s/Users/bob", utils.SCIMV2Prefix)).
+		WithHeader(scimHeader, scimHeaderValue).
+		Expect().
+		Status(http.StatusNoContent)
+
+	expect.GET(fmt.Sprintf("%s/Groups/%s", utils.SCIMV2Prefix, grp.Metadata.Name)).
+		WithHeader(scimHeader, scimHeaderValue).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().NotContainsKey("members")
+
+	e2eframework.ClearAllKeys(t, manager.Persistence().GetPersesDAO(), grp, globalRole, globalRoleBinding)
+}
+
+func refreshPermissions(t *testing.T, manager dependency.Manager) {
+	if err := manager.Service().GetAuthorization().RefreshPermissions(); err != nil {
+		t.Fatalf("failed to refresh permissions: %v", err)
+	}
+}
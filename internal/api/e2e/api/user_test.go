@@ -78,3 +78,49 @@ func TestMainScenarioUser(t *testing.T) {
 	e2eframework.DeleteTestScenario(t, path, creator)
 	e2eframework.NotFoundTestScenario(t, path, creator)
 }
+
+func TestSuspendUser(t *testing.T) {
+	e2eframework.WithServerAuthConfig(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.Manager, token string) []modelAPI.Entity {
+		usrEntity := e2eframework.NewUser("foo", "password")
+		expect.POST(fmt.Sprintf("%s/%s", utils.APIV1Prefix, utils.PathUser)).
+			WithJSON(usrEntity).
+			Expect().
+			Status(http.StatusOK)
+		loginPath := fmt.Sprintf("%s/%s/%s/%s", utils.APIPrefix, utils.PathAuthProviders, utils.AuthnKindNative, utils.PathLogin)
+		authEntity := modelAPI.Auth{Login: "foo", Password: "password"}
+
+		suspended := &modelV1.PublicUser{}
+		expect.POST(fmt.Sprintf("%s/%s/foo/%s", utils.APIV1Prefix, utils.PathUser, utils.PathSuspend)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			WithJSON(modelAPI.UserSuspendRequest{Reason: "left the company"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Decode(suspended)
+		assert.True(t, suspended.Spec.Disabled)
+		assert.Equal(t, "left the company", suspended.Spec.DisabledReason)
+		assert.NotNil(t, suspended.Spec.DisabledAt)
+
+		expect.POST(loginPath).
+			WithJSON(authEntity).
+			Expect().
+			Status(http.StatusForbidden)
+
+		// An admin cannot suspend itself.
+		expect.POST(fmt.Sprintf("%s/%s/alice/%s", utils.APIV1Prefix, utils.PathUser, utils.PathSuspend)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		expect.POST(fmt.Sprintf("%s/%s/foo/%s", utils.APIV1Prefix, utils.PathUser, utils.PathResume)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("spec").Object().NotContainsKey("disabled")
+
+		expect.POST(loginPath).
+			WithJSON(authEntity).
+			Expect().
+			Status(http.StatusOK)
+		return []modelAPI.Entity{usrEntity}
+	})
+}
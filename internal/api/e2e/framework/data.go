@@ -100,6 +100,13 @@ func CreateGetFunc(t *testing.T, persistenceManager dependency.PersistenceManage
 		upsertFunc = func() error {
 			return persistenceManager.GetGlobalVariable().Update(entity)
 		}
+	case *v1.Group:
+		getFunc = func() (api.Entity, error) {
+			return persistenceManager.GetGroup().Get(entity.Metadata.Name)
+		}
+		upsertFunc = func() error {
+			return persistenceManager.GetGroup().Update(entity)
+		}
 	case *v1.Project:
 		getFunc = func() (api.Entity, error) {
 			return persistenceManager.GetProject().Get(entity.Metadata.Name)
@@ -107,6 +114,13 @@ func CreateGetFunc(t *testing.T, persistenceManager dependency.PersistenceManage
 		upsertFunc = func() error {
 			return persistenceManager.GetProject().Update(entity)
 		}
+	case *v1.ProjectTemplate:
+		getFunc = func() (api.Entity, error) {
+			return persistenceManager.GetProjectTemplate().Get(entity.Metadata.Name)
+		}
+		upsertFunc = func() error {
+			return persistenceManager.GetProjectTemplate().Update(entity)
+		}
 	case *v1.Role:
 		getFunc = func() (api.Entity, error) {
 			return persistenceManager.GetRole().Get(entity.Metadata.Project, entity.Metadata.Name)
@@ -187,6 +201,32 @@ func NewProject(name string) *v1.Project {
 	return entity
 }
 
+func NewProjectTemplate(name string) *v1.ProjectTemplate {
+	entity := &v1.ProjectTemplate{
+		Kind:     v1.KindProjectTemplate,
+		Metadata: *v1.NewMetadata(name),
+		Spec: v1.ProjectTemplateSpec{
+			Parameters: []v1.ProjectTemplateParameter{
+				{Name: "username", Required: true},
+			},
+			Resources: []v1.ProjectTemplateResource{
+				{
+					Kind:     v1.KindSecret,
+					Metadata: v1.ProjectTemplateResourceMetadata{Name: "${{ .Project }}-auth"},
+					Spec: map[string]any{
+						"basicAuth": map[string]any{
+							"username": "${{ .Parameters.username }}",
+							"password": "Detective",
+						},
+					},
+				},
+			},
+		},
+	}
+	entity.Metadata.CreateNow()
+	return entity
+}
+
 func newDatasourceSpec(t *testing.T) datasourceSpec.Spec {
 	promURL, err := common.ParseURL("https://prometheus.demo.do.prometheus.io")
 	if err != nil {
@@ -361,6 +401,18 @@ func NewGlobalRole(name string) *v1.GlobalRole {
 	return entity
 }
 
+func NewGroup(name string) *v1.Group {
+	entity := &v1.Group{
+		Kind:     v1.KindGroup,
+		Metadata: *v1.NewMetadata(name),
+		Spec: v1.GroupSpec{
+			Members: []string{"alice"},
+		},
+	}
+	entity.Metadata.CreateNow()
+	return entity
+}
+
 func NewRole(projectName string, name string) *v1.Role {
 	entity := &v1.Role{
 		Kind:     v1.KindRole,
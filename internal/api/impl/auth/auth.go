@@ -26,6 +26,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/authorization"
 	"github.com/perses/perses/internal/api/crypto"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
 	apiinterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/user"
 	"github.com/perses/perses/internal/api/route"
@@ -102,6 +103,7 @@ type authEndpoint interface {
 
 type endpoint struct {
 	endpoints        []authEndpoint
+	dao              user.DAO
 	jwt              crypto.JWT
 	tokenManagement  tokenManagement
 	authz            authorization.Authorization
@@ -109,19 +111,21 @@ type endpoint struct {
 	isDelegatedAuthn bool
 }
 
-func New(dao user.DAO, jwt crypto.JWT, authz authorization.Authorization, providers config.AuthenticationProviders, isAuthnEnable bool, apiPrefix string) (route.Endpoint, error) {
+func New(dao user.DAO, userService user.Service, jwt crypto.JWT, authz authorization.Authorization, providers config.AuthenticationProviders, isAuthnEnable bool, apiPrefix string) (route.Endpoint, error) {
 	ep := &endpoint{
+		dao:             dao,
 		jwt:             jwt,
 		tokenManagement: tokenManagement{jwt: jwt},
 		authz:           authz,
 		isAuthnEnable:   isAuthnEnable,
-		// Currently only k8s is a delegated authentication provider
-		isDelegatedAuthn: providers.KubernetesProvider.Enable,
+		// The authentication is delegated when it is done by k8s, or by a reverse proxy with no other way to log in.
+		isDelegatedAuthn: providers.KubernetesProvider.Enable ||
+			(providers.Header.Enable && !providers.EnableNative && len(providers.OIDC) == 0 && len(providers.OAuth) == 0),
 	}
 
 	// Register the native provider if enabled
 	if providers.EnableNative {
-		ep.endpoints = append(ep.endpoints, newNativeEndpoint(dao, jwt))
+		ep.endpoints = append(ep.endpoints, newNativeEndpoint(dao, userService, jwt, providers.Native.RequireTwoFactor))
 	}
 
 	// Register the OIDC providers if any
@@ -179,6 +183,17 @@ func (e *endpoint) refresh(ctx echo.Context) error {
 	if err != nil {
 		return apiinterface.HandleBadRequestError(err.Error())
 	}
+	// The user may have been removed or disabled since the refresh token has been issued.
+	usr, err := e.dao.Get(claims.Subject)
+	if err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return apiinterface.HandleUnauthorizedError("user not found")
+		}
+		return apiinterface.InternalError
+	}
+	if usr.Spec.Disabled {
+		return apiinterface.HandleUnauthorizedError(api.UserDisabledMessage)
+	}
 	accessToken, err := e.tokenManagement.accessToken(claims.Subject, claims.ProviderInfo, ctx.SetCookie)
 	if err != nil {
 		return err
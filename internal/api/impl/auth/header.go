@@ -0,0 +1,329 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	"github.com/perses/perses/internal/api/crypto"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/group"
+	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// headerSyncInterval is the delay after which the user and its groups are synchronized again with the database,
+	// even if the headers sent by the proxy didn't change.
+	headerSyncInterval = 5 * time.Minute
+	maxGroupNameLength = 75
+)
+
+var invalidGroupNameCharRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// headerUserInfo is the user information extracted from the headers set by the trusted reverse proxy.
+type headerUserInfo struct {
+	login string
+	email string
+	// groups is nil when the proxy didn't send the groups header.
+	// In this case, the group memberships of the user are left untouched.
+	groups []string
+}
+
+func (h *headerUserInfo) GetLogin() string {
+	return h.login
+}
+
+func (h *headerUserInfo) GetProfile() externalUserInfoProfile {
+	return externalUserInfoProfile{Email: h.email}
+}
+
+func (h *headerUserInfo) GetProviderContext() v1.OAuthProvider {
+	return v1.OAuthProvider{
+		Issuer:  utils.AuthnKindHeader,
+		Email:   h.email,
+		Subject: h.login,
+	}
+}
+
+// fingerprint is used to know if the user information changed since the last synchronization.
+func (h *headerUserInfo) fingerprint() string {
+	return fmt.Sprintf("%s|%t|%s", h.email, h.groups != nil, strings.Join(h.groups, ","))
+}
+
+type headerSync struct {
+	fingerprint string
+	syncedAt    time.Time
+}
+
+type headerAuthn struct {
+	service
+	groupDAO     group.DAO
+	conf         config.HeaderAuthnProvider
+	trustedCIDRs []*net.IPNet
+	// mutex only protects synced and lastPrune, it's never held while accessing the database.
+	mutex     sync.Mutex
+	synced    map[string]headerSync
+	lastPrune time.Time
+	// inflight merges the concurrent synchronizations of the same user.
+	inflight singleflight.Group
+	// groupsMutex serializes the updates of the groups, as two users can be added to the same group at the same time.
+	groupsMutex sync.Mutex
+}
+
+// NewHeaderMiddleware returns the middleware authenticating the requests coming from a trusted reverse proxy.
+// When the request is coming from a trusted proxy and carries the user header, the user is created (or updated)
+// and then stored in the context the same way the JWT middleware does.
+// Otherwise, the request is passed as it is to the next middleware.
+func NewHeaderMiddleware(userDAO user.DAO, groupDAO group.DAO, authz authorization.Authorization, conf config.HeaderAuthnProvider) (echo.MiddlewareFunc, error) {
+	h := &headerAuthn{
+		service:  service{dao: userDAO, authz: authz},
+		groupDAO: groupDAO,
+		conf:     conf,
+		synced:   make(map[string]headerSync),
+	}
+	for _, cidr := range conf.TrustedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted CIDR %q: %w", cidr, err)
+		}
+		h.trustedCIDRs = append(h.trustedCIDRs, ipNet)
+	}
+	return h.middleware, nil
+}
+
+func (h *headerAuthn) middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if !h.isTrustedProxy(ctx.Request()) {
+			return next(ctx)
+		}
+		uInfo := h.extractUserInfo(ctx.Request().Header)
+		if uInfo == nil {
+			return next(ctx)
+		}
+		if err := h.sync(uInfo); err != nil {
+			return err
+		}
+		ctx.Set(utils.ContextKeyUser, &jwt.Token{
+			Claims: &crypto.JWTClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: uInfo.login},
+				ProviderInfo:     crypto.ProviderInfo{ProviderKind: utils.AuthnKindHeader},
+			},
+			Valid: true,
+		})
+		return next(ctx)
+	}
+}
+
+// isTrustedProxy checks the address of the peer directly connected to Perses.
+// X-Forwarded-For and X-Real-IP are deliberately ignored as they can be set by anyone.
+func (h *headerAuthn) isTrustedProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range h.trustedCIDRs {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *headerAuthn) extractUserInfo(header http.Header) *headerUserInfo {
+	email := strings.TrimSpace(header.Get(h.conf.EmailHeader))
+	login := strings.TrimSpace(header.Get(h.conf.UserHeader))
+	if len(login) == 0 && len(email) > 0 {
+		login = buildLoginFromEmail(email)
+	}
+	if len(login) == 0 {
+		return nil
+	}
+	uInfo := &headerUserInfo{login: login, email: email}
+	if values, ok := header[http.CanonicalHeaderKey(h.conf.GroupsHeader)]; ok {
+		uInfo.groups = h.parseGroups(values)
+	}
+	return uInfo
+}
+
+func (h *headerAuthn) parseGroups(values []string) []string {
+	groups := []string{}
+	for _, value := range values {
+		for _, grp := range strings.Split(value, h.conf.GroupsSeparator) {
+			groupName := toGroupName(grp)
+			if len(groupName) == 0 {
+				continue
+			}
+			if err := common.ValidateID(groupName); err != nil {
+				logrus.WithError(err).Debugf("ignoring group %q sent by the proxy", grp)
+				continue
+			}
+			if !slices.Contains(groups, groupName) {
+				groups = append(groups, groupName)
+			}
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// toGroupName converts a group sent by the proxy (like "org:team" or "/org/team") to a valid name of the Perses group.
+func toGroupName(grp string) string {
+	groupName := strings.Trim(invalidGroupNameCharRegexp.ReplaceAllString(strings.TrimSpace(grp), "-"), "-")
+	if len(groupName) > maxGroupNameLength {
+		groupName = groupName[:maxGroupNameLength]
+	}
+	return groupName
+}
+
+// sync creates or updates the user and its group memberships.
+// To avoid hitting the database on every request, it is only done when the headers changed or
+// when the previous synchronization is older than headerSyncInterval.
+func (h *headerAuthn) sync(uInfo *headerUserInfo) error {
+	fingerprint := uInfo.fingerprint()
+	if h.isSynced(uInfo.login, fingerprint) {
+		return nil
+	}
+	_, err, _ := h.inflight.Do(uInfo.login+"|"+fingerprint, func() (any, error) {
+		return nil, h.syncNow(uInfo, fingerprint)
+	})
+	return err
+}
+
+func (h *headerAuthn) isSynced(login string, fingerprint string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	previous, ok := h.synced[login]
+	return ok && previous.fingerprint == fingerprint && time.Since(previous.syncedAt) < headerSyncInterval
+}
+
+// setSynced records the synchronization of the user. An empty fingerprint forgets the user.
+// The entries older than headerSyncInterval are useless, so they are evicted from time to time.
+func (h *headerAuthn) setSynced(login string, fingerprint string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	now := time.Now()
+	if len(fingerprint) == 0 {
+		delete(h.synced, login)
+	} else {
+		h.synced[login] = headerSync{fingerprint: fingerprint, syncedAt: now}
+	}
+	if now.Sub(h.lastPrune) < headerSyncInterval {
+		return
+	}
+	for name, previous := range h.synced {
+		if now.Sub(previous.syncedAt) >= headerSyncInterval {
+			delete(h.synced, name)
+		}
+	}
+	h.lastPrune = now
+}
+
+func (h *headerAuthn) syncNow(uInfo *headerUserInfo, fingerprint string) error {
+	if _, err := h.syncUser(uInfo); err != nil {
+		if errors.Is(err, errUserDisabled) {
+			h.setSynced(uInfo.login, "")
+			return err
+		}
+		logrus.WithError(err).Errorf("unable to sync the user %q authenticated by the proxy", uInfo.login)
+		return apiinterface.HandleUnauthorizedError(err.Error())
+	}
+	if uInfo.groups != nil {
+		changed, groupErr := h.syncGroups(uInfo.login, uInfo.groups)
+		if groupErr != nil {
+			logrus.WithError(groupErr).Errorf("unable to sync the groups of the user %q", uInfo.login)
+			return apiinterface.InternalError
+		}
+		if changed {
+			if refreshErr := h.authz.RefreshPermissions(); refreshErr != nil {
+				logrus.WithError(refreshErr).Error("failed to refresh RBAC cache")
+			}
+		}
+	}
+	h.setSynced(uInfo.login, fingerprint)
+	return nil
+}
+
+// syncGroups makes sure the user is a member of the given groups, and of no other group created by this provider.
+// The groups managed through the API or by SCIM are never modified, even when they are listed,
+// as the header couldn't revoke the membership afterward.
+// Missing groups are created. It returns true if at least one group has been modified.
+func (h *headerAuthn) syncGroups(login string, groups []string) (bool, error) {
+	h.groupsMutex.Lock()
+	defer h.groupsMutex.Unlock()
+	existingGroups, err := h.groupDAO.List(&group.Query{})
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	for _, grp := range existingGroups {
+		shouldBeMember := slices.Contains(groups, grp.Metadata.Name)
+		if grp.Spec.ManagedBy != utils.AuthnKindHeader {
+			if shouldBeMember && !grp.Spec.Has(login) {
+				logrus.Debugf("user %q not added to the group %q listed in the header, as the group is not managed by the header", login, grp.Metadata.Name)
+			}
+			continue
+		}
+		if shouldBeMember == grp.Spec.Has(login) {
+			continue
+		}
+		if shouldBeMember {
+			grp.Spec.Members = append(grp.Spec.Members, login)
+		} else {
+			grp.Spec.Members = slices.DeleteFunc(grp.Spec.Members, func(member string) bool { return member == login })
+		}
+		grp.Metadata.Update(grp.Metadata)
+		if updateErr := h.groupDAO.Update(grp); updateErr != nil {
+			return false, updateErr
+		}
+		changed = true
+	}
+	for _, groupName := range groups {
+		if slices.ContainsFunc(existingGroups, func(grp *v1.Group) bool { return grp.Metadata.Name == groupName }) {
+			continue
+		}
+		grp := &v1.Group{
+			Kind:     v1.KindGroup,
+			Metadata: v1.Metadata{Name: groupName},
+			Spec:     v1.GroupSpec{Members: []string{login}, ManagedBy: utils.AuthnKindHeader},
+		}
+		grp.Metadata.CreateNow()
+		if createErr := h.groupDAO.Create(grp); createErr != nil && !databaseModel.IsKeyConflict(createErr) {
+			return false, createErr
+		}
+		changed = true
+	}
+	return changed, nil
+}
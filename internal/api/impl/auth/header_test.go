@@ -0,0 +1,174 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/perses/perses/internal/api/interface/v1/group"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHeaderAuthn(t *testing.T) *headerAuthn {
+	conf := config.HeaderAuthnProvider{Enable: true, TrustedCIDRs: []string{"10.0.0.0/8", "::1/128"}}
+	if err := conf.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	h := &headerAuthn{conf: conf}
+	for _, cidr := range conf.TrustedCIDRs {
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		h.trustedCIDRs = append(h.trustedCIDRs, ipNet)
+	}
+	return h
+}
+
+func TestHeaderAuthnIsTrustedProxy(t *testing.T) {
+	h := newTestHeaderAuthn(t)
+	testSuites := []struct {
+		remoteAddr string
+		expected   bool
+	}{
+		{remoteAddr: "10.1.2.3:4567", expected: true},
+		{remoteAddr: "[::1]:4567", expected: true},
+		{remoteAddr: "192.168.1.1:4567", expected: false},
+		{remoteAddr: "not-an-ip", expected: false},
+	}
+	for _, test := range testSuites {
+		t.Run(test.remoteAddr, func(t *testing.T) {
+			r := &http.Request{RemoteAddr: test.remoteAddr, Header: http.Header{}}
+			// X-Forwarded-For must never be used to decide if the proxy is trusted.
+			r.Header.Set("X-Forwarded-For", "10.0.0.1")
+			assert.Equal(t, test.expected, h.isTrustedProxy(r))
+		})
+	}
+}
+
+func TestHeaderAuthnExtractUserInfo(t *testing.T) {
+	h := newTestHeaderAuthn(t)
+	testSuites := []struct {
+		title    string
+		header   http.Header
+		expected *headerUserInfo
+	}{
+		{
+			title:    "no header",
+			header:   http.Header{},
+			expected: nil,
+		},
+		{
+			title:    "user header only",
+			header:   http.Header{"X-Forwarded-User": []string{"john"}},
+			expected: &headerUserInfo{login: "john"},
+		},
+		{
+			title:    "login built from the email",
+			header:   http.Header{"X-Forwarded-Email": []string{"john.doe@example.com"}},
+			expected: &headerUserInfo{login: "john.doe", email: "john.doe@example.com"},
+		},
+		{
+			title: "groups are sanitized and deduplicated",
+			header: http.Header{
+				"X-Forwarded-User":   []string{"john"},
+				"X-Forwarded-Groups": []string{"org:team, admins,,org/team", "admins"},
+			},
+			expected: &headerUserInfo{login: "john", groups: []string{"admins", "org-team"}},
+		},
+		{
+			title: "empty groups header removes every membership",
+			header: http.Header{
+				"X-Forwarded-User":   []string{"john"},
+				"X-Forwarded-Groups": []string{""},
+			},
+			expected: &headerUserInfo{login: "john", groups: []string{}},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.expected, h.extractUserInfo(test.header))
+		})
+	}
+}
+
+type fakeGroupDAO struct {
+	group.DAO
+	groups map[string]*v1.Group
+}
+
+func (d *fakeGroupDAO) List(_ *group.Query) ([]*v1.Group, error) {
+	var result []*v1.Group
+	for _, grp := range d.groups {
+		result = append(result, grp)
+	}
+	return result, nil
+}
+
+func (d *fakeGroupDAO) Create(entity *v1.Group) error {
+	d.groups[entity.Metadata.Name] = entity
+	return nil
+}
+
+func (d *fakeGroupDAO) Update(entity *v1.Group) error {
+	d.groups[entity.Metadata.Name] = entity
+	return nil
+}
+
+func TestHeaderAuthnSyncGroups(t *testing.T) {
+	dao := &fakeGroupDAO{groups: map[string]*v1.Group{
+		"admins": {Metadata: v1.Metadata{Name: "admins"}, Spec: v1.GroupSpec{Members: []string{"jdoe"}}},
+		"team-a": {Metadata: v1.Metadata{Name: "team-a"}, Spec: v1.GroupSpec{Members: []string{"jdoe"}, ManagedBy: utils.AuthnKindHeader}},
+	}}
+	h := newTestHeaderAuthn(t)
+	h.groupDAO = dao
+
+	changed, err := h.syncGroups("jdoe", []string{"team-b"})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	// The group managed by an admin is left untouched, while the one created by the header is synchronized.
+	assert.Equal(t, []string{"jdoe"}, dao.groups["admins"].Spec.Members)
+	assert.Empty(t, dao.groups["team-a"].Spec.Members)
+	if assert.Contains(t, dao.groups, "team-b") {
+		assert.Equal(t, []string{"jdoe"}, dao.groups["team-b"].Spec.Members)
+		assert.Equal(t, utils.AuthnKindHeader, dao.groups["team-b"].Spec.ManagedBy)
+	}
+
+	changed, err = h.syncGroups("jdoe", []string{"team-b"})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// The header cannot grant a membership in a group it doesn't manage.
+	changed, err = h.syncGroups("alice", []string{"admins"})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, []string{"jdoe"}, dao.groups["admins"].Spec.Members)
+}
+
+func TestHeaderAuthnSyncedEviction(t *testing.T) {
+	h := newTestHeaderAuthn(t)
+	h.synced = map[string]headerSync{
+		"old": {fingerprint: "f", syncedAt: time.Now().Add(-2 * headerSyncInterval)},
+	}
+	h.setSynced("jdoe", "f")
+	assert.True(t, h.isSynced("jdoe", "f"))
+	assert.False(t, h.isSynced("jdoe", "other"))
+	assert.NotContains(t, h.synced, "old")
+
+	h.setSynced("jdoe", "")
+	assert.NotContains(t, h.synced, "jdoe")
+}
@@ -25,14 +25,22 @@ import (
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
 	"github.com/zitadel/oidc/v3/pkg/oidc"
 	"golang.org/x/oauth2"
 )
 
+const errTwoFactorLocked = "too many wrong two-factor codes, try again later"
+
 type nativeEndpoint struct {
 	dao             user.DAO
+	service         user.Service
 	jwt             crypto.JWT
 	tokenManagement tokenManagement
+	// requireTwoFactor forces every user to provide a TOTP code, even the ones who haven't enrolled an authenticator app yet.
+	requireTwoFactor bool
+	twoFactorGuard   *twoFactorGuard
 }
 
 func (e *nativeEndpoint) GetExtraProviderLogoutHandler() echo.HandlerFunc {
@@ -47,16 +55,21 @@ func (e *nativeEndpoint) GetSlugID() string {
 	return "" // no slug ID needed for native auth
 }
 
-func newNativeEndpoint(dao user.DAO, jwt crypto.JWT) authEndpoint {
+func newNativeEndpoint(dao user.DAO, service user.Service, jwt crypto.JWT, requireTwoFactor bool) authEndpoint {
 	return &nativeEndpoint{
-		dao:             dao,
-		jwt:             jwt,
-		tokenManagement: tokenManagement{jwt: jwt},
+		dao:              dao,
+		service:          service,
+		jwt:              jwt,
+		tokenManagement:  tokenManagement{jwt: jwt},
+		requireTwoFactor: requireTwoFactor,
+		twoFactorGuard:   newTwoFactorGuard(),
 	}
 }
 
 func (e *nativeEndpoint) CollectRoutes(g *route.Group) {
 	g.POST(fmt.Sprintf("/%s/%s", utils.AuthnKindNative, utils.PathLogin), e.auth, true)
+	g.POST(fmt.Sprintf("/%s/%s/%s", utils.AuthnKindNative, utils.PathLogin, utils.PathTOTP), e.authTwoFactor, true)
+	g.POST(fmt.Sprintf("/%s/%s", utils.AuthnKindNative, utils.PathPassword), e.changePassword, true)
 }
 
 func (e *nativeEndpoint) auth(ctx echo.Context) error {
@@ -75,23 +88,134 @@ func (e *nativeEndpoint) auth(ctx echo.Context) error {
 	if !crypto.ComparePasswords(usr.Spec.NativeProvider.Password, body.Password) {
 		return apiinterface.HandleBadRequestError("wrong login or password ")
 	}
-	login := body.Login
+	if usr.Spec.NativeProvider.MustChangePassword {
+		return apiinterface.HandleForbiddenError(api.PasswordChangeRequiredMessage)
+	}
+	return e.completeLogin(ctx, usr)
+}
+
+// completeLogin is called once the password of the user has been verified.
+// If the two-factor authentication is enabled or required, the user receives a challenge to solve with authTwoFactor.
+// Otherwise, the user is logged in.
+func (e *nativeEndpoint) completeLogin(ctx echo.Context, usr *v1.User) error {
+	if usr.Spec.Disabled {
+		return apiinterface.HandleForbiddenError(api.UserDisabledMessage)
+	}
+	login := usr.Metadata.Name
+	enabled := usr.Spec.NativeProvider.TOTP.IsEnabled()
+	if !enabled && !e.requireTwoFactor {
+		return e.login(ctx, login)
+	}
+	if e.twoFactorGuard.isLocked(login) {
+		return apiinterface.HandleTooManyRequestsError(errTwoFactorLocked)
+	}
+	token, err := e.jwt.SignedTwoFactorToken(login)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to generate the two-factor token of the user %q", login)
+		return apiinterface.InternalError
+	}
+	challenge := &api.TwoFactorChallenge{TwoFactorToken: token}
+	if !enabled {
+		// The two-factor authentication is required, but the user never enrolled an authenticator app.
+		// The enrollment is then done as part of the login, the first code provided confirming it.
+		// The pending enrollment is reused, so the secret doesn't change each time the password is provided.
+		challenge.Enrollment, err = e.service.PendingTOTPEnrollment(login)
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.JSON(http.StatusOK, challenge)
+}
+
+// authTwoFactor is the second step of the login for the users with the two-factor authentication enabled.
+func (e *nativeEndpoint) authTwoFactor(ctx echo.Context) error {
+	body := &api.TwoFactorLoginRequest{}
+	if err := ctx.Bind(body); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	claims, err := e.jwt.ValidateTwoFactorToken(body.TwoFactorToken)
+	if err != nil || !e.twoFactorGuard.isUsable(claims) {
+		return apiinterface.HandleUnauthorizedError("invalid or expired two-factor token")
+	}
+	login := claims.Subject
+	if e.twoFactorGuard.isLocked(login) {
+		return apiinterface.HandleTooManyRequestsError(errTwoFactorLocked)
+	}
+	usr, err := e.dao.Get(login)
+	if err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return apiinterface.HandleUnauthorizedError("invalid or expired two-factor token")
+		}
+		return apiinterface.InternalError
+	}
+	if usr.Spec.Disabled {
+		return apiinterface.HandleForbiddenError(api.UserDisabledMessage)
+	}
+	response := &api.TwoFactorLoginResponse{}
+	if usr.Spec.NativeProvider.TOTP.IsEnabled() {
+		if verifyErr := e.service.VerifyTOTP(login, body.Code); verifyErr != nil {
+			e.twoFactorGuard.failure(claims)
+			return verifyErr
+		}
+	} else {
+		recoveryCodes, activateErr := e.service.ActivateTOTP(login, body.Code)
+		if activateErr != nil {
+			e.twoFactorGuard.failure(claims)
+			return activateErr
+		}
+		response.RecoveryCodes = recoveryCodes.RecoveryCodes
+	}
+	e.twoFactorGuard.success(claims)
+	token, err := e.issueTokens(ctx, login)
+	if err != nil {
+		return err
+	}
+	response.Token = *token
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// changePassword lets a user define a new password without being logged in.
+// It is used when the user is forced to change the password or when an admin provided a reset token.
+// On success, the user is logged in.
+func (e *nativeEndpoint) changePassword(ctx echo.Context) error {
+	body := &api.PasswordChangeRequest{}
+	if err := ctx.Bind(body); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	if err := e.service.ChangePassword(body); err != nil {
+		return err
+	}
+	usr, err := e.dao.Get(body.Login)
+	if err != nil {
+		return apiinterface.InternalError
+	}
+	return e.completeLogin(ctx, usr)
+}
+
+func (e *nativeEndpoint) login(ctx echo.Context, login string) error {
+	token, err := e.issueTokens(ctx, login)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, token)
+}
+
+func (e *nativeEndpoint) issueTokens(ctx echo.Context, login string) (*oauth2.Token, error) {
 	providerInfo := crypto.ProviderInfo{
 		ProviderKind: utils.AuthnKindNative,
 		ProviderID:   "", // no provider ID needed for native auth
 	}
 	accessToken, err := e.tokenManagement.accessToken(login, providerInfo, ctx.SetCookie)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	refreshToken, err := e.tokenManagement.refreshToken(login, providerInfo, ctx.SetCookie)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	return ctx.JSON(http.StatusOK, oauth2.Token{
+	return &oauth2.Token{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    oidc.BearerToken,
-	})
+	}, nil
 }
@@ -453,6 +453,9 @@ func (e *oAuthEndpoint) tokenHandler(ctx echo.Context) error {
 // performUserSync performs user synchronization and generates access and refresh tokens.
 func (e *oAuthEndpoint) performUserSync(userInfo externalUserInfo, setCookie func(cookie *http.Cookie)) (*oauth2.Token, error) {
 	usr, err := e.svc.syncUser(userInfo)
+	if errors.Is(err, errUserDisabled) {
+		return nil, err
+	}
 	if err != nil {
 		e.logWithError(err).Error("Failed to sync user in database.")
 		return nil, &oauth2.RetrieveError{ErrorCode: string(oidc.InvalidRequest), ErrorDescription: err.Error()}
@@ -286,6 +286,11 @@ func (e *oIDCEndpoint) codeExchange(ctx echo.Context) error {
 		}
 
 		if _, err := e.performUserSync(info, setCookie); err != nil {
+			if errors.Is(err, errUserDisabled) {
+				w.WriteHeader(http.StatusForbidden)
+				writeResponse(w, []byte(api.UserDisabledMessage))
+				return
+			}
 			w.WriteHeader(http.StatusInternalServerError)
 			writeResponse(w, []byte(apiinterface.InternalError.Error()))
 			return
@@ -390,6 +395,9 @@ func (e *oIDCEndpoint) performUserSync(userInfo *oidcUserInfo, setCookie func(co
 	userInfo.issuer = e.issuer
 
 	usr, err := e.svc.syncUser(userInfo)
+	if errors.Is(err, errUserDisabled) {
+		return nil, err
+	}
 	if err != nil {
 		e.logWithError(err).Error("Failed to sync user in database.")
 		return nil, &oidc.Error{ErrorType: oidc.InvalidRequest, Description: err.Error()}
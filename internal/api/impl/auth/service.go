@@ -19,7 +19,9 @@ import (
 
 	"github.com/perses/perses/internal/api/authorization"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiinterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/pkg/model/api"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
 )
@@ -74,6 +76,9 @@ func newSpecIfChanged(old v1.UserSpec, uInfo externalUserInfo) (v1.UserSpec, boo
 	return newSpec, profileChanged || providerChanged, err
 }
 
+// errUserDisabled is returned by syncUser when the user exists but has been suspended.
+var errUserDisabled = apiinterface.HandleForbiddenError(api.UserDisabledMessage)
+
 type service struct {
 	dao   user.DAO
 	authz authorization.Authorization
@@ -100,6 +105,9 @@ func (s *service) syncUser(uInfo externalUserInfo) (*v1.User, error) {
 	if err != nil {
 		return nil, err
 	}
+	if entity.Spec.Disabled {
+		return nil, errUserDisabled
+	}
 
 	var specHasChanged bool
 	entity.Spec, specHasChanged, err = newSpecIfChanged(entity.Spec, uInfo)
@@ -0,0 +1,140 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perses/perses/internal/api/crypto"
+)
+
+const (
+	// maxTwoFactorTokenAttempts is the number of wrong codes accepted with a single two-factor token.
+	// Once reached, the user must provide the password again.
+	maxTwoFactorTokenAttempts = 3
+	// maxTwoFactorUserFailures is the number of wrong codes accepted for a user, whatever the token used,
+	// before the two-factor authentication of the user is locked for twoFactorLockoutDuration.
+	maxTwoFactorUserFailures = 10
+	twoFactorLockoutDuration = 15 * time.Minute
+)
+
+type twoFactorTokenState struct {
+	attempts int
+	revoked  bool
+	expireAt time.Time
+}
+
+type twoFactorUserState struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// twoFactorGuard makes the two-factor tokens single-use and limits the number of codes a user can try,
+// so a 6-digit code cannot be brute-forced by asking for new tokens.
+// The state is kept in memory: with several instances of Perses, the limits apply per instance.
+type twoFactorGuard struct {
+	mutex  sync.Mutex
+	tokens map[string]*twoFactorTokenState
+	users  map[string]*twoFactorUserState
+	now    func() time.Time
+}
+
+func newTwoFactorGuard() *twoFactorGuard {
+	return &twoFactorGuard{
+		tokens: make(map[string]*twoFactorTokenState),
+		users:  make(map[string]*twoFactorUserState),
+		now:    time.Now,
+	}
+}
+
+// isLocked returns true if the user made too many wrong attempts recently.
+func (g *twoFactorGuard) isLocked(login string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.prune()
+	usr, ok := g.users[login]
+	return ok && g.now().Before(usr.lockedUntil)
+}
+
+// isUsable returns true if the token has not been consumed or revoked yet.
+func (g *twoFactorGuard) isUsable(claims *crypto.JWTClaims) bool {
+	if len(claims.ID) == 0 {
+		return false
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.prune()
+	token, ok := g.tokens[claims.ID]
+	return !ok || !token.revoked
+}
+
+// failure records a wrong code provided with the given token.
+func (g *twoFactorGuard) failure(claims *crypto.JWTClaims) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := g.now()
+	token := g.token(claims)
+	token.attempts++
+	if token.attempts >= maxTwoFactorTokenAttempts {
+		token.revoked = true
+	}
+	usr, ok := g.users[claims.Subject]
+	if !ok {
+		usr = &twoFactorUserState{}
+		g.users[claims.Subject] = usr
+	}
+	usr.failures++
+	usr.lastFailure = now
+	if usr.failures >= maxTwoFactorUserFailures {
+		usr.failures = 0
+		usr.lockedUntil = now.Add(twoFactorLockoutDuration)
+	}
+}
+
+// success consumes the token and resets the failures of the user.
+func (g *twoFactorGuard) success(claims *crypto.JWTClaims) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.token(claims).revoked = true
+	delete(g.users, claims.Subject)
+}
+
+func (g *twoFactorGuard) token(claims *crypto.JWTClaims) *twoFactorTokenState {
+	token, ok := g.tokens[claims.ID]
+	if !ok {
+		token = &twoFactorTokenState{}
+		if claims.ExpiresAt != nil {
+			token.expireAt = claims.ExpiresAt.Time
+		}
+		g.tokens[claims.ID] = token
+	}
+	return token
+}
+
+// prune removes the expired tokens and the users without recent failures. The caller must hold the mutex.
+func (g *twoFactorGuard) prune() {
+	now := g.now()
+	for id, token := range g.tokens {
+		if now.After(token.expireAt) {
+			delete(g.tokens, id)
+		}
+	}
+	for login, usr := range g.users {
+		if now.After(usr.lockedUntil) && now.Sub(usr.lastFailure) > twoFactorLockoutDuration {
+			delete(g.users, login)
+		}
+	}
+}
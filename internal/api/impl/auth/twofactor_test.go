@@ -0,0 +1,71 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/perses/perses/internal/api/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTwoFactorClaims(id string, now time.Time) *crypto.JWTClaims {
+	return &crypto.JWTClaims{RegisteredClaims: jwt.RegisteredClaims{
+		ID:        id,
+		Subject:   "jdoe",
+		ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+	}}
+}
+
+func TestTwoFactorGuardSingleUseToken(t *testing.T) {
+	now := time.Now()
+	g := newTwoFactorGuard()
+	g.now = func() time.Time { return now }
+
+	claims := newTwoFactorClaims("1", now)
+	assert.True(t, g.isUsable(claims))
+	g.success(claims)
+	assert.False(t, g.isUsable(claims))
+	// A token without ID cannot be made single-use, so it's rejected
+	assert.False(t, g.isUsable(newTwoFactorClaims("", now)))
+}
+
+func TestTwoFactorGuardAttempts(t *testing.T) {
+	now := time.Now()
+	g := newTwoFactorGuard()
+	g.now = func() time.Time { return now }
+
+	// The token is revoked after too many wrong codes
+	claims := newTwoFactorClaims("1", now)
+	for range maxTwoFactorTokenAttempts {
+		assert.True(t, g.isUsable(claims))
+		g.failure(claims)
+	}
+	assert.False(t, g.isUsable(claims))
+
+	// Asking for new tokens doesn't allow more codes: the user is locked
+	for i := maxTwoFactorTokenAttempts; i < maxTwoFactorUserFailures; i++ {
+		assert.False(t, g.isLocked("jdoe"))
+		g.failure(newTwoFactorClaims("2", now))
+	}
+	assert.True(t, g.isLocked("jdoe"))
+	assert.False(t, g.isLocked("admin"))
+
+	// The lock is released after the lockout duration
+	now = now.Add(twoFactorLockoutDuration + time.Second)
+	assert.False(t, g.isLocked("jdoe"))
+	assert.Empty(t, g.tokens)
+}
@@ -0,0 +1,62 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authzendpoint
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/route"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+type endpoint struct {
+	authz authorization.Authorization
+}
+
+// New creates the endpoint used by admins to evaluate the permissions of any user, group or service account.
+func New(authz authorization.Authorization) route.Endpoint {
+	return &endpoint{
+		authz: authz,
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	g.POST("/authz/can-i", e.canI, false)
+}
+
+func (e *endpoint) canI(ctx echo.Context) error {
+	// The result is exposing how the roles are bound, so only the users able to read every binding can access it.
+	if e.authz.IsEnabled() {
+		for _, scope := range []role.Scope{role.GlobalRoleBindingScope, role.RoleBindingScope} {
+			if !e.authz.HasPermission(ctx, role.ReadAction, v1.WildcardProject, scope) {
+				return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' kind", role.ReadAction, scope))
+			}
+		}
+	}
+	body := &modelAPI.CanIRequest{}
+	if err := ctx.Bind(body); err != nil {
+		return apiInterface.HandleBadRequestError(err.Error())
+	}
+	result, err := e.authz.CanI(ctx, body)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,82 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/plugin/export"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+// Endpoint is the struct that defines all endpoint delivered by the path /export
+type endpoint struct {
+	exportService export.Export
+}
+
+// New create an instance of the object Endpoint.
+// You should have at most one instance of this object as it is only used by the struct api in the method api.registerRoute
+func New(exportService export.Export) route.Endpoint {
+	return &endpoint{
+		exportService: exportService,
+	}
+}
+
+// CollectRoutes is the method to use to register the routes prefixed by /api
+// If the version is not v1, then look at the same method but in the package with the version as the name.
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	g.POST("/export/grafana", e.ExportGrafana, true)
+}
+
+type query struct {
+	// Report is used to get the export report alongside the dashboard.
+	Report bool `query:"report"`
+}
+
+// ExportGrafana is the endpoint that provides the Grafana dashboard corresponding to the provided Perses dashboard.
+func (e *endpoint) ExportGrafana(ctx echo.Context) error {
+	body := &v1.Dashboard{}
+	if err := ctx.Bind(body); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	q := &query{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, q); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	grafanaDashboard, report, err := e.exportService.Export(body)
+	if err != nil {
+		return err
+	}
+	// The warnings are returned as headers to keep the dashboard as the body of the response.
+	for _, warning := range report.Warnings {
+		ctx.Response().Header().Add("Warning", fmt.Sprintf("299 - %q", warning))
+	}
+	if !q.Report {
+		return ctx.JSON(http.StatusOK, grafanaDashboard)
+	}
+	data, err := json.Marshal(grafanaDashboard)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, &api.GrafanaExport{
+		Dashboard: data,
+		Report:    report,
+	})
+}
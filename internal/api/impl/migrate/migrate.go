@@ -15,6 +15,7 @@ package migrate
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -22,6 +23,7 @@ import (
 	"github.com/perses/perses/internal/api/plugin/migrate"
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
 )
 
 // Endpoint is the struct that defines all endpoint delivered by the path /migrate
@@ -43,22 +45,51 @@ func (e *endpoint) CollectRoutes(g *route.Group) {
 	g.POST("/migrate", e.Migrate, true)
 }
 
+type query struct {
+	// Report is used to get the migration report alongside the dashboard.
+	Report bool `query:"report"`
+}
+
 // Migrate is the endpoint that provides the Perses dashboard corresponding to the provided grafana dashboard.
 func (e *endpoint) Migrate(ctx echo.Context) error {
 	body := &api.Migrate{}
 	if err := ctx.Bind(body); err != nil {
 		return apiinterface.HandleBadRequestError(err.Error())
 	}
+	q := &query{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, q); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
 
-	rawGrafanaDashboard := []byte(migrate.ReplaceInputValue(body.Input, string(body.GrafanaDashboard)))
+	libraryPanels := make([]migrate.GrafanaLibraryPanel, len(body.LibraryPanels))
+	for i, libraryPanel := range body.LibraryPanels {
+		if err := json.Unmarshal(libraryPanel, &libraryPanels[i]); err != nil {
+			return apiinterface.HandleBadRequestError(fmt.Sprintf("invalid library panel: %s", err))
+		}
+	}
+	resolvedGrafanaDashboard, err := migrate.ResolveLibraryPanels(body.GrafanaDashboard, libraryPanels)
+	if err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	rawGrafanaDashboard := []byte(migrate.ReplaceInputValue(body.Input, string(resolvedGrafanaDashboard)))
 	grafanaDashboard := &migrate.SimplifiedDashboard{}
 	if err := json.Unmarshal(rawGrafanaDashboard, grafanaDashboard); err != nil {
 		return apiinterface.HandleBadRequestError(err.Error())
 	}
-	persesDashboard, err := e.migrationService.Migrate(grafanaDashboard, body.UseDefaultDatasource)
+	persesDashboard, report, err := e.migrationService.Migrate(grafanaDashboard, body.UseDefaultDatasource)
 	if err != nil {
 		return err
 	}
-
+	migrate.RewriteDatasourceReferences(persesDashboard, body.DatasourceReferences)
+	// The warnings are returned as headers to keep the dashboard as the body of the response.
+	for _, warning := range report.Warnings {
+		ctx.Response().Header().Add("Warning", fmt.Sprintf("299 - %q", warning))
+	}
+	if q.Report {
+		return ctx.JSON(http.StatusOK, &v1.MigrationResult{
+			Dashboard: persesDashboard,
+			Report:    report,
+		})
+	}
 	return ctx.JSON(http.StatusOK, persesDashboard)
 }
@@ -0,0 +1,121 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioningendpoint
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/provisioning"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+// maxWebhookPayload is the maximum size of the webhook payload read to verify its signature.
+const maxWebhookPayload = 25 << 20
+
+type endpoint struct {
+	provisioning provisioning.Provisioning
+	authz        authorization.Authorization
+	// webhookSecrets contains the webhook secret of every git repository that has one.
+	webhookSecrets map[string][]byte
+}
+
+// New creates the endpoint exposing the status of the provisioning and the webhooks of the git repositories.
+// provisioningTask can be nil when the provisioning is not configured.
+func New(provisioningTask provisioning.Provisioning, conf config.ProvisioningConfig, authz authorization.Authorization) route.Endpoint {
+	webhookSecrets := make(map[string][]byte)
+	for _, g := range conf.Git {
+		if len(g.WebhookSecret) > 0 {
+			webhookSecrets[g.Name] = []byte(g.WebhookSecret)
+		}
+	}
+	return &endpoint{
+		provisioning:   provisioningTask,
+		authz:          authz,
+		webhookSecrets: webhookSecrets,
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	g.GET("/provisioning/status", e.getStatus, false)
+	// The webhook is called by the git server, it is authenticated with the webhook secret of the repository.
+	g.POST(fmt.Sprintf("/provisioning/git/:%s/webhook", utils.ParamName), e.webhook, true)
+}
+
+func (e *endpoint) getStatus(ctx echo.Context) error {
+	// The status is exposing the content of every project, so only the users able to read everything can access it.
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.ReadAction, v1.WildcardProject, role.WildcardScope) {
+		return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' scope", role.ReadAction, role.WildcardScope))
+	}
+	if e.provisioning == nil {
+		return ctx.JSON(http.StatusOK, modelAPI.ProvisioningStatus{})
+	}
+	return ctx.JSON(http.StatusOK, e.provisioning.Status())
+}
+
+func (e *endpoint) webhook(ctx echo.Context) error {
+	name := ctx.Param(utils.ParamName)
+	secret, ok := e.webhookSecrets[name]
+	if !ok || e.provisioning == nil {
+		return apiInterface.HandleNotFoundError(fmt.Sprintf("no webhook configured for the git repository %q", name))
+	}
+	body, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxWebhookPayload))
+	if err != nil {
+		return apiInterface.HandleBadRequestError(fmt.Sprintf("unable to read the payload: %s", err))
+	}
+	if !isWebhookAuthorized(ctx.Request().Header, body, secret) {
+		return apiInterface.HandleUnauthorizedError("missing or invalid webhook signature")
+	}
+	if !e.provisioning.SyncGit(name) {
+		return apiInterface.HandleNotFoundError(fmt.Sprintf("git repository %q not found", name))
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+// isWebhookAuthorized checks the request is coming from the git server. The following methods are supported:
+//   - the HMAC-SHA256 signature of the payload in the header X-Hub-Signature-256 (GitHub, Gitea, Forgejo),
+//   - the secret in the header X-Gitlab-Token (GitLab),
+//   - the secret as a bearer token.
+func isWebhookAuthorized(header http.Header, body []byte, secret []byte) bool {
+	if signature, found := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256="); found {
+		expected, err := hex.DecodeString(signature)
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		return hmac.Equal(mac.Sum(nil), expected)
+	}
+	if token := header.Get("X-Gitlab-Token"); len(token) > 0 {
+		return subtle.ConstantTimeCompare([]byte(token), secret) == 1
+	}
+	if token, found := strings.CutPrefix(header.Get(echo.HeaderAuthorization), "Bearer "); found {
+		return subtle.ConstantTimeCompare([]byte(token), secret) == 1
+	}
+	return false
+}
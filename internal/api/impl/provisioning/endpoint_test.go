@@ -0,0 +1,84 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioningendpoint
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWebhookAuthorized(t *testing.T) {
+	secret := []byte("s3cr3t")
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	testSuites := []struct {
+		title  string
+		header http.Header
+		result bool
+	}{
+		{
+			title:  "no header",
+			header: http.Header{},
+			result: false,
+		},
+		{
+			title:  "valid signature",
+			header: http.Header{"X-Hub-Signature-256": []string{signature}},
+			result: true,
+		},
+		{
+			title:  "invalid signature",
+			header: http.Header{"X-Hub-Signature-256": []string{"sha256=" + hex.EncodeToString([]byte("nope"))}},
+			result: false,
+		},
+		{
+			title:  "malformed signature",
+			header: http.Header{"X-Hub-Signature-256": []string{"sha256=xyz"}},
+			result: false,
+		},
+		{
+			title:  "valid gitlab token",
+			header: http.Header{"X-Gitlab-Token": []string{"s3cr3t"}},
+			result: true,
+		},
+		{
+			title:  "invalid gitlab token",
+			header: http.Header{"X-Gitlab-Token": []string{"wrong"}},
+			result: false,
+		},
+		{
+			title:  "valid bearer token",
+			header: http.Header{"Authorization": []string{"Bearer s3cr3t"}},
+			result: true,
+		},
+		{
+			title:  "basic auth is not accepted",
+			header: http.Header{"Authorization": []string{"Basic s3cr3t"}},
+			result: false,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.result, isWebhookAuthorized(test.header, body, secret))
+		})
+	}
+}
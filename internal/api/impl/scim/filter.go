@@ -0,0 +1,57 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scim
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var filterRegexp = regexp.MustCompile(`^\s*([a-zA-Z][\w.]*)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// filter is an equality filter on a single attribute.
+type filter struct {
+	// attribute is the lowercase name of the attribute as the attribute names are case-insensitive.
+	attribute string
+	value     string
+}
+
+// parseFilter parses a SCIM filter expression.
+// Only the operator "eq" is supported as it is the one used by the identity providers to look for an existing resource.
+// An empty expression returns a nil filter that matches every resource.
+func parseFilter(expression string) (*filter, error) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		return nil, nil
+	}
+	matches := filterRegexp.FindStringSubmatch(expression)
+	if matches == nil {
+		return nil, newBadRequestError("invalidFilter", fmt.Sprintf("unsupported filter %q, only the operator 'eq' is supported", expression))
+	}
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return nil, newBadRequestError("invalidFilter", fmt.Sprintf("invalid value in the filter %q", expression))
+	}
+	return &filter{attribute: strings.ToLower(matches[1]), value: value}, nil
+}
+
+// match returns true if the value of the filtered attribute is equal (case-insensitive) to the filter value.
+func (f *filter) match(attributes map[string]string) bool {
+	if f == nil {
+		return true
+	}
+	value, ok := attributes[f.attribute]
+	return ok && strings.EqualFold(value, f.value)
+}
@@ -0,0 +1,291 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/interface/v1/group"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	invalidNameCharRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+	memberPathRegexp      = regexp.MustCompile(`^(?i:members)\[(.+)]$`)
+)
+
+// maxGroupNameLength is the maximum length of a name accepted by common.ValidateID.
+const maxGroupNameLength = 75
+
+// toGroupName converts the SCIM displayName to the name of the Perses group.
+// The displayName is kept as it is in spec.display.name.
+func toGroupName(displayName string) (string, error) {
+	groupName := strings.Trim(invalidNameCharRegexp.ReplaceAllString(displayName, "-"), "-")
+	if len(groupName) > maxGroupNameLength {
+		groupName = groupName[:maxGroupNameLength]
+	}
+	if err := common.ValidateID(groupName); err != nil {
+		return "", newBadRequestError("invalidValue", fmt.Sprintf("invalid displayName: %s", err))
+	}
+	return groupName, nil
+}
+
+func groupDisplayName(grp *v1.Group) string {
+	if grp.Spec.Display != nil && len(grp.Spec.Display.Name) > 0 {
+		return grp.Spec.Display.Name
+	}
+	return grp.Metadata.Name
+}
+
+func (e *endpoint) toGroupResource(ctx echo.Context, grp *v1.Group) *groupResource {
+	res := &groupResource{
+		Schemas:     []string{groupSchema},
+		ID:          grp.Metadata.Name,
+		DisplayName: groupDisplayName(grp),
+		Meta: &meta{
+			ResourceType: resourceTypeGroup,
+			Created:      &grp.Metadata.CreatedAt,
+			LastModified: &grp.Metadata.UpdatedAt,
+			Location:     e.location(ctx, pathGroups, grp.Metadata.Name),
+			Version:      fmt.Sprintf("W/\"%d\"", grp.Metadata.Version),
+		},
+	}
+	for _, member := range grp.Spec.Members {
+		res.Members = append(res.Members, reference{
+			Value: member,
+			Ref:   e.location(ctx, pathUsers, member),
+		})
+	}
+	return res
+}
+
+func (e *endpoint) listGroups(ctx echo.Context) error {
+	flt, err := parseFilter(ctx.QueryParam("filter"))
+	if err != nil {
+		return err
+	}
+	groups, err := e.groupDAO.List(&group.Query{})
+	if err != nil {
+		return err
+	}
+	resources := make([]any, 0, len(groups))
+	for _, grp := range groups {
+		if flt.match(map[string]string{"id": grp.Metadata.Name, "displayname": groupDisplayName(grp)}) {
+			resources = append(resources, e.toGroupResource(ctx, grp))
+		}
+	}
+	return e.writeList(ctx, resources)
+}
+
+func (e *endpoint) getGroup(ctx echo.Context) error {
+	grp, err := e.groupDAO.Get(utils.GetNameParameter(ctx))
+	if err != nil {
+		return err
+	}
+	return writeResponse(ctx, http.StatusOK, e.toGroupResource(ctx, grp))
+}
+
+func (e *endpoint) createGroup(ctx echo.Context) error {
+	body := &groupResource{}
+	if err := decodeBody(ctx, body); err != nil {
+		return err
+	}
+	groupName, err := toGroupName(body.DisplayName)
+	if err != nil {
+		return err
+	}
+	grp := &v1.Group{
+		Kind:     v1.KindGroup,
+		Metadata: v1.Metadata{Name: groupName},
+	}
+	if replaceErr := replaceGroupSpec(&grp.Spec, body); replaceErr != nil {
+		return replaceErr
+	}
+	grp.Metadata.CreateNow()
+	if createErr := e.groupDAO.Create(grp); createErr != nil {
+		return createErr
+	}
+	e.refreshPermissions()
+	ctx.Response().Header().Set(echo.HeaderLocation, e.location(ctx, pathGroups, grp.Metadata.Name))
+	return writeResponse(ctx, http.StatusCreated, e.toGroupResource(ctx, grp))
+}
+
+func (e *endpoint) replaceGroup(ctx echo.Context) error {
+	body := &groupResource{}
+	if err := decodeBody(ctx, body); err != nil {
+		return err
+	}
+	grp, err := e.groupDAO.Get(utils.GetNameParameter(ctx))
+	if err != nil {
+		return err
+	}
+	if replaceErr := replaceGroupSpec(&grp.Spec, body); replaceErr != nil {
+		return replaceErr
+	}
+	return e.updateGroup(ctx, grp)
+}
+
+func (e *endpoint) patchGroup(ctx echo.Context) error {
+	body := &patchRequest{}
+	if err := decodeBody(ctx, body); err != nil {
+		return err
+	}
+	grp, err := e.groupDAO.Get(utils.GetNameParameter(ctx))
+	if err != nil {
+		return err
+	}
+	for _, operation := range body.Operations {
+		if patchErr := patchGroup(grp, operation); patchErr != nil {
+			return patchErr
+		}
+	}
+	return e.updateGroup(ctx, grp)
+}
+
+func (e *endpoint) updateGroup(ctx echo.Context, grp *v1.Group) error {
+	grp.Metadata.Update(grp.Metadata)
+	if err := e.groupDAO.Update(grp); err != nil {
+		return err
+	}
+	e.refreshPermissions()
+	return writeResponse(ctx, http.StatusOK, e.toGroupResource(ctx, grp))
+}
+
+func (e *endpoint) deleteGroup(ctx echo.Context) error {
+	if err := e.groupDAO.Delete(utils.GetNameParameter(ctx)); err != nil {
+		return err
+	}
+	e.refreshPermissions()
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func replaceGroupSpec(spec *v1.GroupSpec, res *groupResource) error {
+	if len(res.DisplayName) > 0 {
+		spec.Display = &common.Display{Name: res.DisplayName}
+	}
+	members, err := memberValues(res.Members)
+	if err != nil {
+		return err
+	}
+	spec.Members = members
+	return nil
+}
+
+func memberValues(refs []reference) ([]string, error) {
+	members := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if len(ref.Value) == 0 {
+			return nil, newBadRequestError("invalidValue", "the value of a member cannot be empty")
+		}
+		if !slices.Contains(members, ref.Value) {
+			members = append(members, ref.Value)
+		}
+	}
+	return members, nil
+}
+
+func patchGroup(grp *v1.Group, operation patchOperation) error {
+	op := strings.ToLower(operation.Op)
+	if op != "add" && op != "replace" && op != "remove" {
+		return newBadRequestError("invalidSyntax", fmt.Sprintf("unsupported patch operation %q", operation.Op))
+	}
+	// A path like members[value eq "alice"] is used to target a single member.
+	if matches := memberPathRegexp.FindStringSubmatch(operation.Path); matches != nil {
+		flt, err := parseFilter(matches[1])
+		if err != nil {
+			return err
+		}
+		if op != "remove" || flt.attribute != "value" {
+			return newBadRequestError("invalidPath", fmt.Sprintf("unsupported path %q", operation.Path))
+		}
+		grp.Spec.Members = slices.DeleteFunc(grp.Spec.Members, func(member string) bool {
+			return flt.match(map[string]string{"value": member})
+		})
+		return nil
+	}
+	if len(operation.Path) > 0 {
+		return patchGroupAttribute(grp, op, strings.ToLower(operation.Path), operation.Value)
+	}
+	if op == "remove" {
+		return newBadRequestError("noTarget", "path is required for the remove operation")
+	}
+	// Without a path, the value is an object containing the attributes to modify.
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+		return newBadRequestError("invalidValue", err.Error())
+	}
+	for attribute, value := range attributes {
+		if err := patchGroupAttribute(grp, op, strings.ToLower(attribute), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchGroupAttribute(grp *v1.Group, op string, path string, value json.RawMessage) error {
+	switch path {
+	case "displayname":
+		if op == "remove" {
+			grp.Spec.Display = nil
+			return nil
+		}
+		var displayName string
+		if err := json.Unmarshal(value, &displayName); err != nil {
+			return newBadRequestError("invalidValue", err.Error())
+		}
+		grp.Spec.Display = &common.Display{Name: displayName}
+	case "members":
+		var refs []reference
+		if len(value) > 0 {
+			if err := json.Unmarshal(value, &refs); err != nil {
+				return newBadRequestError("invalidValue", err.Error())
+			}
+		}
+		members, err := memberValues(refs)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "add":
+			for _, member := range members {
+				if !grp.Spec.Has(member) {
+					grp.Spec.Members = append(grp.Spec.Members, member)
+				}
+			}
+		case "replace":
+			grp.Spec.Members = members
+		case "remove":
+			// Without any value, all the members are removed.
+			if len(members) == 0 {
+				grp.Spec.Members = nil
+				return nil
+			}
+			grp.Spec.Members = slices.DeleteFunc(grp.Spec.Members, func(member string) bool {
+				return slices.Contains(members, member)
+			})
+		}
+	default:
+		logrus.Debugf("scim: ignoring the attribute %q of the group %q", path, grp.Metadata.Name)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scim implements a SCIM 2.0 server (RFC 7643 and RFC 7644) so an identity provider can provision the users
+// and the groups in Perses. Like that, offboarding someone from the identity provider revokes the access to Perses.
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/group"
+	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	pathUsers                 = "Users"
+	pathGroups                = "Groups"
+	pathServiceProviderConfig = "ServiceProviderConfig"
+	// maxResults is the maximum number of resources returned in a single list response.
+	maxResults = 1000
+)
+
+// scimError is an error that is returned to the identity provider using the SCIM error format.
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string {
+	return e.detail
+}
+
+func newBadRequestError(scimType string, detail string) error {
+	return &scimError{status: http.StatusBadRequest, scimType: scimType, detail: detail}
+}
+
+type endpoint struct {
+	userDAO   user.DAO
+	groupDAO  group.DAO
+	authz     authorization.Authorization
+	token     []byte
+	readonly  bool
+	apiPrefix string
+}
+
+// New creates the endpoint serving the SCIM 2.0 resources under the path /scim/v2.
+// The endpoint is not using the JWT of the Perses users, but a dedicated bearer token shared with the identity provider.
+func New(userDAO user.DAO, groupDAO group.DAO, authz authorization.Authorization, conf config.SCIMConfig, readonly bool, apiPrefix string) route.Endpoint {
+	return &endpoint{
+		userDAO:   userDAO,
+		groupDAO:  groupDAO,
+		authz:     authz,
+		token:     []byte(conf.BearerToken),
+		readonly:  readonly,
+		apiPrefix: apiPrefix,
+	}
+}
+
+// CollectRoutes is the method to use to register the routes prefixed by /scim/v2
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	// The routes are anonymous from the Perses point of view as they are protected by the SCIM bearer token.
+	mdws := []echo.MiddlewareFunc{e.handleError, e.checkToken}
+	g.GET(fmt.Sprintf("/%s", pathServiceProviderConfig), e.getServiceProviderConfig, true, mdws...)
+	g.GET(fmt.Sprintf("/%s", pathUsers), e.listUsers, true, mdws...)
+	g.GET(fmt.Sprintf("/%s/:%s", pathUsers, utils.ParamName), e.getUser, true, mdws...)
+	g.GET(fmt.Sprintf("/%s", pathGroups), e.listGroups, true, mdws...)
+	g.GET(fmt.Sprintf("/%s/:%s", pathGroups, utils.ParamName), e.getGroup, true, mdws...)
+	if e.readonly {
+		return
+	}
+	g.POST(fmt.Sprintf("/%s", pathUsers), e.createUser, true, mdws...)
+	g.PUT(fmt.Sprintf("/%s/:%s", pathUsers, utils.ParamName), e.replaceUser, true, mdws...)
+	g.PATCH(fmt.Sprintf("/%s/:%s", pathUsers, utils.ParamName), e.patchUser, true, mdws...)
+	g.DELETE(fmt.Sprintf("/%s/:%s", pathUsers, utils.ParamName), e.deleteUser, true, mdws...)
+	g.POST(fmt.Sprintf("/%s", pathGroups), e.createGroup, true, mdws...)
+	g.PUT(fmt.Sprintf("/%s/:%s", pathGroups, utils.ParamName), e.replaceGroup, true, mdws...)
+	g.PATCH(fmt.Sprintf("/%s/:%s", pathGroups, utils.ParamName), e.patchGroup, true, mdws...)
+	g.DELETE(fmt.Sprintf("/%s/:%s", pathGroups, utils.ParamName), e.deleteGroup, true, mdws...)
+}
+
+// checkToken verifies the request is coming from the identity provider by checking the bearer token.
+func (e *endpoint) checkToken(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		token, found := strings.CutPrefix(ctx.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), e.token) != 1 {
+			ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="scim"`)
+			return &scimError{status: http.StatusUnauthorized, detail: "missing or invalid bearer token"}
+		}
+		return next(ctx)
+	}
+}
+
+// handleError converts any error returned by the handlers to the SCIM error format.
+func (e *endpoint) handleError(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		err := next(ctx)
+		if err == nil {
+			return nil
+		}
+		var sErr *scimError
+		if !errors.As(err, &sErr) {
+			sErr = &scimError{status: http.StatusInternalServerError, detail: apiInterface.InternalError.Error()}
+			var httpErr *echo.HTTPError
+			if errors.As(apiInterface.HandleError(err), &httpErr) {
+				sErr.status = httpErr.Code
+				sErr.detail = fmt.Sprint(httpErr.Message)
+			}
+			if sErr.status == http.StatusConflict {
+				sErr.scimType = "uniqueness"
+			}
+		}
+		return writeResponse(ctx, sErr.status, &errorResponse{
+			Schemas:  []string{errorSchema},
+			Status:   strconv.Itoa(sErr.status),
+			ScimType: sErr.scimType,
+			Detail:   sErr.detail,
+		})
+	}
+}
+
+func (e *endpoint) getServiceProviderConfig(ctx echo.Context) error {
+	return writeResponse(ctx, http.StatusOK, &serviceProviderConfig{
+		Schemas: []string{serviceProviderConfigSchema},
+		Patch:   supported{Supported: true},
+		Filter:  filterSupported{Supported: true, MaxResults: maxResults},
+		AuthenticationSchemes: []authenticationScheme{
+			{
+				Type:        "oauthbearertoken",
+				Name:        "OAuth Bearer Token",
+				Description: "Authentication using the bearer token defined in the Perses configuration",
+			},
+		},
+	})
+}
+
+// refreshPermissions is called after every change as users and groups have an impact on the permissions.
+func (e *endpoint) refreshPermissions() {
+	if err := e.authz.RefreshPermissions(); err != nil {
+		logrus.WithError(err).Error("failed to refresh RBAC cache")
+	}
+}
+
+func (e *endpoint) location(ctx echo.Context, resourcePath string, id string) string {
+	return fmt.Sprintf("%s://%s%s%s/%s/%s", ctx.Scheme(), ctx.Request().Host, e.apiPrefix, utils.SCIMV2Prefix, resourcePath, id)
+}
+
+// writeList paginates the resources according to the query parameters startIndex and count.
+func (e *endpoint) writeList(ctx echo.Context, resources []any) error {
+	startIndex := 1
+	if value := ctx.QueryParam("startIndex"); len(value) > 0 {
+		if i, err := strconv.Atoi(value); err == nil && i > 1 {
+			startIndex = i
+		}
+	}
+	count := maxResults
+	if value := ctx.QueryParam("count"); len(value) > 0 {
+		if i, err := strconv.Atoi(value); err == nil && i >= 0 && i < maxResults {
+			count = i
+		}
+	}
+	page := make([]any, 0, count)
+	if startIndex <= len(resources) {
+		page = append(page, resources[startIndex-1:min(startIndex-1+count, len(resources))]...)
+	}
+	return writeResponse(ctx, http.StatusOK, &listResponse{
+		Schemas:      []string{listResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	})
+}
+
+// decodeBody decodes the body of the request.
+// ctx.Bind cannot be used as it doesn't support the content type application/scim+json.
+func decodeBody(ctx echo.Context, body any) error {
+	if err := json.NewDecoder(ctx.Request().Body).Decode(body); err != nil {
+		return newBadRequestError("invalidSyntax", err.Error())
+	}
+	return nil
+}
+
+func writeResponse(ctx echo.Context, code int, body any) error {
+	ctx.Response().Header().Set(echo.HeaderContentType, contentType)
+	return ctx.JSON(code, body)
+}
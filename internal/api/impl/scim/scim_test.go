@@ -0,0 +1,113 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	testSuites := []struct {
+		title     string
+		filter    string
+		expected  *filter
+		expectErr bool
+	}{
+		{
+			title:    "empty filter",
+			filter:   "",
+			expected: nil,
+		},
+		{
+			title:    "userName filter",
+			filter:   `userName eq "alice@example.com"`,
+			expected: &filter{attribute: "username", value: "alice@example.com"},
+		},
+		{
+			title:    "case-insensitive operator and escaped quote",
+			filter:   `displayName EQ "the \"best\" team"`,
+			expected: &filter{attribute: "displayname", value: `the "best" team`},
+		},
+		{
+			title:     "unsupported operator",
+			filter:    `userName sw "alice"`,
+			expectErr: true,
+		},
+		{
+			title:     "logical expression",
+			filter:    `userName eq "alice" and active eq true`,
+			expectErr: true,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := parseFilter(test.filter)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestToGroupName(t *testing.T) {
+	groupName, err := toGroupName("Perses Admins (EU)")
+	assert.NoError(t, err)
+	assert.Equal(t, "Perses-Admins-EU", groupName)
+
+	_, err = toGroupName("###")
+	assert.Error(t, err)
+}
+
+func TestPatchUser(t *testing.T) {
+	usr := &v1.User{Kind: v1.KindUser, Metadata: v1.Metadata{Name: "alice"}, Spec: v1.UserSpec{FirstName: "Alice", LastName: "Doe"}}
+
+	// Microsoft Entra ID is sending the boolean as a string.
+	assert.NoError(t, patchUser(usr, patchOperation{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)}))
+	assert.True(t, usr.Spec.Disabled)
+	assert.Equal(t, deactivatedReason, usr.Spec.DisabledReason)
+	assert.NotNil(t, usr.Spec.DisabledAt)
+
+	assert.NoError(t, patchUser(usr, patchOperation{Op: "replace", Value: json.RawMessage(`{"active":true,"name.familyName":"Smith"}`)}))
+	assert.False(t, usr.Spec.Disabled)
+	assert.Nil(t, usr.Spec.DisabledAt)
+	assert.Equal(t, "Alice", usr.Spec.FirstName)
+	assert.Equal(t, "Smith", usr.Spec.LastName)
+
+	assert.NoError(t, patchUser(usr, patchOperation{Op: "add", Path: "emails", Value: json.RawMessage(`[{"value":"alice@example.com"}]`)}))
+	assert.Error(t, patchUser(usr, patchOperation{Op: "replace", Path: "userName", Value: json.RawMessage(`"bob@example.com"`)}))
+	assert.Error(t, patchUser(usr, patchOperation{Op: "move", Path: "active", Value: json.RawMessage(`true`)}))
+}
+
+func TestPatchGroup(t *testing.T) {
+	grp := &v1.Group{Kind: v1.KindGroup, Metadata: v1.Metadata{Name: "dev"}, Spec: v1.GroupSpec{Members: []string{"alice"}}}
+
+	assert.NoError(t, patchGroup(grp, patchOperation{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"bob"},{"value":"alice"}]`)}))
+	assert.Equal(t, []string{"alice", "bob"}, grp.Spec.Members)
+
+	assert.NoError(t, patchGroup(grp, patchOperation{Op: "remove", Path: `members[value eq "alice"]`}))
+	assert.Equal(t, []string{"bob"}, grp.Spec.Members)
+
+	assert.NoError(t, patchGroup(grp, patchOperation{Op: "replace", Value: json.RawMessage(`{"displayName":"Developers"}`)}))
+	assert.Equal(t, "Developers", groupDisplayName(grp))
+
+	assert.NoError(t, patchGroup(grp, patchOperation{Op: "remove", Path: "members"}))
+	assert.Empty(t, grp.Spec.Members)
+}
@@ -0,0 +1,332 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/interface/v1/group"
+	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/sirupsen/logrus"
+)
+
+// deactivatedReason is the reason of the suspension of a user deactivated by the identity provider.
+const deactivatedReason = "deactivated by the identity provider"
+
+// toLogin converts the SCIM userName to the name of the Perses user.
+// Like for the OIDC and OAuth providers, when the userName is an email, only the first part of the email is kept.
+// That way, a user provisioned with SCIM is the same as the one logging in with the identity provider.
+func toLogin(userName string) (string, error) {
+	login := strings.Split(userName, "@")[0]
+	if err := common.ValidateID(login); err != nil {
+		return "", newBadRequestError("invalidValue", fmt.Sprintf("invalid userName: %s", err))
+	}
+	return login, nil
+}
+
+func (e *endpoint) toUserResource(ctx echo.Context, usr *v1.User, groups []*v1.Group) *userResource {
+	active := boolean(!usr.Spec.Disabled)
+	res := &userResource{
+		Schemas:  []string{userSchema},
+		ID:       usr.Metadata.Name,
+		UserName: usr.Metadata.Name,
+		Active:   &active,
+		Meta: &meta{
+			ResourceType: resourceTypeUser,
+			Created:      &usr.Metadata.CreatedAt,
+			LastModified: &usr.Metadata.UpdatedAt,
+			Location:     e.location(ctx, pathUsers, usr.Metadata.Name),
+			Version:      fmt.Sprintf("W/\"%d\"", usr.Metadata.Version),
+		},
+	}
+	if len(usr.Spec.FirstName) > 0 || len(usr.Spec.LastName) > 0 {
+		res.Name = &name{
+			Formatted:  strings.TrimSpace(fmt.Sprintf("%s %s", usr.Spec.FirstName, usr.Spec.LastName)),
+			GivenName:  usr.Spec.FirstName,
+			FamilyName: usr.Spec.LastName,
+		}
+		res.DisplayName = res.Name.Formatted
+	}
+	for _, grp := range groups {
+		if grp.Spec.Has(usr.Metadata.Name) {
+			res.Groups = append(res.Groups, reference{
+				Value:   grp.Metadata.Name,
+				Display: groupDisplayName(grp),
+				Ref:     e.location(ctx, pathGroups, grp.Metadata.Name),
+			})
+		}
+	}
+	return res
+}
+
+func (e *endpoint) listUsers(ctx echo.Context) error {
+	flt, err := parseFilter(ctx.QueryParam("filter"))
+	if err != nil {
+		return err
+	}
+	if flt != nil && flt.attribute == "username" {
+		flt.value = strings.Split(flt.value, "@")[0]
+	}
+	users, err := e.userDAO.List(&user.Query{})
+	if err != nil {
+		return err
+	}
+	groups, err := e.groupDAO.List(&group.Query{})
+	if err != nil {
+		return err
+	}
+	resources := make([]any, 0, len(users))
+	for _, usr := range users {
+		if flt.match(map[string]string{"id": usr.Metadata.Name, "username": usr.Metadata.Name}) {
+			resources = append(resources, e.toUserResource(ctx, usr, groups))
+		}
+	}
+	return e.writeList(ctx, resources)
+}
+
+func (e *endpoint) getUser(ctx echo.Context) error {
+	usr, err := e.userDAO.Get(utils.GetNameParameter(ctx))
+	if err != nil {
+		return err
+	}
+	return e.writeUser(ctx, http.StatusOK, usr)
+}
+
+func (e *endpoint) createUser(ctx echo.Context) error {
+	body := &userResource{}
+	if err := decodeBody(ctx, body); err != nil {
+		return err
+	}
+	login, err := toLogin(body.UserName)
+	if err != nil {
+		return err
+	}
+	usr := &v1.User{
+		Kind:     v1.KindUser,
+		Metadata: v1.Metadata{Name: login},
+	}
+	replaceUserSpec(&usr.Spec, body)
+	usr.Metadata.CreateNow()
+	if createErr := e.userDAO.Create(usr); createErr != nil {
+		return createErr
+	}
+	e.refreshPermissions()
+	ctx.Response().Header().Set(echo.HeaderLocation, e.location(ctx, pathUsers, usr.Metadata.Name))
+	return e.writeUser(ctx, http.StatusCreated, usr)
+}
+
+func (e *endpoint) replaceUser(ctx echo.Context) error {
+	body := &userResource{}
+	if err := decodeBody(ctx, body); err != nil {
+		return err
+	}
+	usr, err := e.userDAO.Get(utils.GetNameParameter(ctx))
+	if err != nil {
+		return err
+	}
+	if err := checkUserName(usr, body.UserName); err != nil {
+		return err
+	}
+	replaceUserSpec(&usr.Spec, body)
+	return e.updateUser(ctx, usr)
+}
+
+func (e *endpoint) patchUser(ctx echo.Context) error {
+	body := &patchRequest{}
+	if err := decodeBody(ctx, body); err != nil {
+		return err
+	}
+	usr, err := e.userDAO.Get(utils.GetNameParameter(ctx))
+	if err != nil {
+		return err
+	}
+	for _, operation := range body.Operations {
+		if patchErr := patchUser(usr, operation); patchErr != nil {
+			return patchErr
+		}
+	}
+	return e.updateUser(ctx, usr)
+}
+
+func (e *endpoint) updateUser(ctx echo.Context, usr *v1.User) error {
+	usr.Metadata.Update(usr.Metadata)
+	if err := e.userDAO.Update(usr); err != nil {
+		return err
+	}
+	// Deactivating a user must remove the permissions immediately.
+	e.refreshPermissions()
+	return e.writeUser(ctx, http.StatusOK, usr)
+}
+
+// deleteUser removes the user and its memberships, so nothing remains of the user in Perses.
+// The memberships are removed first: if it fails, the user still exists and the deletion can be retried.
+func (e *endpoint) deleteUser(ctx echo.Context) error {
+	login := utils.GetNameParameter(ctx)
+	if _, err := e.userDAO.Get(login); err != nil {
+		return err
+	}
+	groups, err := e.groupDAO.List(&group.Query{})
+	if err != nil {
+		return err
+	}
+	for _, grp := range groups {
+		if !grp.Spec.Has(login) {
+			continue
+		}
+		grp.Spec.Members = slices.DeleteFunc(grp.Spec.Members, func(member string) bool { return member == login })
+		grp.Metadata.Update(grp.Metadata)
+		if updateErr := e.groupDAO.Update(grp); updateErr != nil {
+			return updateErr
+		}
+	}
+	if err := e.userDAO.Delete(login); err != nil {
+		return err
+	}
+	e.refreshPermissions()
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (e *endpoint) writeUser(ctx echo.Context, code int, usr *v1.User) error {
+	groups, err := e.groupDAO.List(&group.Query{})
+	if err != nil {
+		return err
+	}
+	return writeResponse(ctx, code, e.toUserResource(ctx, usr, groups))
+}
+
+// checkUserName verifies the userName is not changed as it is used as the name of the user in Perses.
+func checkUserName(usr *v1.User, userName string) error {
+	if len(userName) == 0 {
+		return nil
+	}
+	login, err := toLogin(userName)
+	if err != nil {
+		return err
+	}
+	if login != usr.Metadata.Name {
+		return newBadRequestError("mutability", "userName cannot be changed")
+	}
+	return nil
+}
+
+// replaceUserSpec sets the user spec according to the SCIM resource.
+// When the attribute active is not provided, the state of the user remains the same.
+func replaceUserSpec(spec *v1.UserSpec, res *userResource) {
+	spec.FirstName = ""
+	spec.LastName = ""
+	if res.Name != nil {
+		spec.FirstName = res.Name.GivenName
+		spec.LastName = res.Name.FamilyName
+	}
+	if res.Active != nil {
+		setActive(spec, bool(*res.Active))
+	}
+}
+
+// setActive suspends or resumes the user. The reason given by an admin to an already suspended user is kept.
+func setActive(spec *v1.UserSpec, active bool) {
+	if active {
+		spec.Resume()
+	} else if !spec.Disabled {
+		spec.Suspend(deactivatedReason)
+	}
+}
+
+func patchUser(usr *v1.User, operation patchOperation) error {
+	op := strings.ToLower(operation.Op)
+	if op != "add" && op != "replace" && op != "remove" {
+		return newBadRequestError("invalidSyntax", fmt.Sprintf("unsupported patch operation %q", operation.Op))
+	}
+	if len(operation.Path) > 0 {
+		return patchUserAttribute(usr, op, strings.ToLower(operation.Path), operation.Value)
+	}
+	if op == "remove" {
+		return newBadRequestError("noTarget", "path is required for the remove operation")
+	}
+	// Without a path, the value is an object containing the attributes to modify.
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+		return newBadRequestError("invalidValue", err.Error())
+	}
+	for attribute, value := range attributes {
+		if err := patchUserAttribute(usr, op, strings.ToLower(attribute), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchUserAttribute(usr *v1.User, op string, path string, value json.RawMessage) error {
+	remove := op == "remove"
+	switch path {
+	case "active":
+		active := boolean(true)
+		if !remove {
+			if err := json.Unmarshal(value, &active); err != nil {
+				return newBadRequestError("invalidValue", err.Error())
+			}
+		}
+		setActive(&usr.Spec, bool(active))
+	case "name":
+		if remove {
+			usr.Spec.FirstName = ""
+			usr.Spec.LastName = ""
+			return nil
+		}
+		n := name{}
+		if err := json.Unmarshal(value, &n); err != nil {
+			return newBadRequestError("invalidValue", err.Error())
+		}
+		// The sub-attributes not provided are left unchanged.
+		if len(n.GivenName) > 0 {
+			usr.Spec.FirstName = n.GivenName
+		}
+		if len(n.FamilyName) > 0 {
+			usr.Spec.LastName = n.FamilyName
+		}
+	case "name.givenname":
+		return unmarshalString(value, remove, &usr.Spec.FirstName)
+	case "name.familyname":
+		return unmarshalString(value, remove, &usr.Spec.LastName)
+	case "username":
+		var userName string
+		if err := unmarshalString(value, remove, &userName); err != nil {
+			return err
+		}
+		return checkUserName(usr, userName)
+	default:
+		// Identity providers are sending many attributes (emails, title, etc.) that are not stored by Perses.
+		logrus.Debugf("scim: ignoring the attribute %q of the user %q", path, usr.Metadata.Name)
+	}
+	return nil
+}
+
+func unmarshalString(value json.RawMessage, remove bool, result *string) error {
+	if remove {
+		*result = ""
+		return nil
+	}
+	if err := json.Unmarshal(value, result); err != nil {
+		return newBadRequestError("invalidValue", err.Error())
+	}
+	return nil
+}
@@ -34,9 +34,9 @@ type endpoint struct {
 	isDisable bool
 }
 
-func NewEndpoint(cfg config.DatasourceConfig, service datasource.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+func NewEndpoint(cfg config.DatasourceConfig, service datasource.Service, authz authorization.Authorization, readonly bool, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) route.Endpoint {
 	return &endpoint{
-		toolbox:   toolbox.New[*v1.Datasource, *v1.Datasource, *datasource.Query](service, authz, v1.KindDatasource, caseSensitive),
+		toolbox:   toolbox.New[*v1.Datasource, *v1.Datasource, *datasource.Query](service, authz, v1.KindDatasource, caseSensitive, provisioningPolicy),
 		readonly:  readonly,
 		isDisable: cfg.Project.Disable,
 	}
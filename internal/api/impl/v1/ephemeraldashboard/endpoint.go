@@ -24,6 +24,7 @@ import (
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/toolbox"
 	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 )
 
@@ -35,7 +36,7 @@ type endpoint struct {
 
 func NewEndpoint(service ephemeraldashboard.Service, authz authorization.Authorization, readonly bool, caseSensitive bool, isEnabled bool) route.Endpoint {
 	return &endpoint{
-		toolbox:   toolbox.New[*v1.EphemeralDashboard, *v1.EphemeralDashboard, *ephemeraldashboard.Query](service, authz, v1.KindEphemeralDashboard, caseSensitive),
+		toolbox:   toolbox.New[*v1.EphemeralDashboard, *v1.EphemeralDashboard, *ephemeraldashboard.Query](service, authz, v1.KindEphemeralDashboard, caseSensitive, config.ProvisioningEditPolicyAllow),
 		readonly:  readonly,
 		isEnabled: isEnabled,
 	}
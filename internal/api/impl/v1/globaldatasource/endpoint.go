@@ -34,9 +34,9 @@ type endpoint struct {
 	isDisable bool
 }
 
-func NewEndpoint(cfg config.DatasourceConfig, service globaldatasource.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+func NewEndpoint(cfg config.DatasourceConfig, service globaldatasource.Service, authz authorization.Authorization, readonly bool, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) route.Endpoint {
 	return &endpoint{
-		toolbox:   toolbox.New[*v1.GlobalDatasource, *v1.GlobalDatasource, *globaldatasource.Query](service, authz, v1.KindGlobalDatasource, caseSensitive),
+		toolbox:   toolbox.New[*v1.GlobalDatasource, *v1.GlobalDatasource, *globaldatasource.Query](service, authz, v1.KindGlobalDatasource, caseSensitive, provisioningPolicy),
 		readonly:  readonly,
 		isDisable: cfg.Global.Disable,
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/toolbox"
 	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 )
 
@@ -32,9 +33,9 @@ type endpoint struct {
 	readonly bool
 }
 
-func NewEndpoint(service globalsecret.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+func NewEndpoint(service globalsecret.Service, authz authorization.Authorization, readonly bool, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) route.Endpoint {
 	return &endpoint{
-		toolbox:  toolbox.New[*v1.GlobalSecret, *v1.PublicGlobalSecret, *globalsecret.Query](service, authz, v1.KindGlobalSecret, caseSensitive),
+		toolbox:  toolbox.New[*v1.GlobalSecret, *v1.PublicGlobalSecret, *globalsecret.Query](service, authz, v1.KindGlobalSecret, caseSensitive, provisioningPolicy),
 		readonly: readonly,
 	}
 }
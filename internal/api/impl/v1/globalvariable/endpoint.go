@@ -34,9 +34,9 @@ type endpoint struct {
 	isDisable bool
 }
 
-func NewEndpoint(cfg config.VariableConfig, service globalvariable.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+func NewEndpoint(cfg config.VariableConfig, service globalvariable.Service, authz authorization.Authorization, readonly bool, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) route.Endpoint {
 	return &endpoint{
-		toolbox:   toolbox.New[*v1.GlobalVariable, *v1.GlobalVariable, *globalvariable.Query](service, authz, v1.KindGlobalVariable, caseSensitive),
+		toolbox:   toolbox.New[*v1.GlobalVariable, *v1.GlobalVariable, *globalvariable.Query](service, authz, v1.KindGlobalVariable, caseSensitive, provisioningPolicy),
 		readonly:  readonly,
 		isDisable: cfg.Global.Disable,
 	}
@@ -14,26 +14,38 @@
 package plugin
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
 	apiinterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/plugin"
 	"github.com/perses/perses/internal/api/route"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	pluginModel "github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/perses/pkg/model/api/v1/role"
 	"github.com/sirupsen/logrus"
 )
 
+// maxArchiveSize is the maximum size of a plugin archive that can be uploaded.
+const maxArchiveSize = 100 << 20
+
 type endpoint struct {
-	svc       plugin.Plugin
-	enableDev bool
+	svc           plugin.Plugin
+	authz         authorization.Authorization
+	enableDev     bool
+	enableInstall bool
 }
 
-func NewEndpoint(svc plugin.Plugin, enableDev bool) route.Endpoint {
+// NewEndpoint creates the endpoint exposing the plugins.
+// enableInstall should be false when the server is in readonly mode, as installing a plugin modifies the plugin folder.
+func NewEndpoint(svc plugin.Plugin, authz authorization.Authorization, enableDev bool, enableInstall bool) route.Endpoint {
 	return &endpoint{
-		svc:       svc,
-		enableDev: enableDev,
+		svc:           svc,
+		authz:         authz,
+		enableDev:     enableDev,
+		enableInstall: enableInstall,
 	}
 }
 
@@ -46,6 +58,13 @@ func (e *endpoint) CollectRoutes(g *route.Group) {
 		devGroup.DELETE("", e.DeleteDevPlugin, true)
 		devGroup.POST("/refresh", e.RefreshDevPlugin, true)
 	}
+	if e.enableInstall {
+		group.POST("", e.Install, false)
+		group.DELETE("", e.Uninstall, false)
+		group.POST("/enable", e.Enable, false)
+		group.POST("/disable", e.Disable, false)
+		group.POST("/sync", e.SyncRequirements, false)
+	}
 }
 
 func (e *endpoint) List(ctx echo.Context) error {
@@ -88,3 +107,85 @@ func (e *endpoint) DeleteDevPlugin(ctx echo.Context) error {
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+func (e *endpoint) Install(ctx echo.Context) error {
+	if err := e.checkPermission(ctx, role.CreateAction); err != nil {
+		return err
+	}
+	archive := http.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxArchiveSize)
+	module, err := e.svc.Install(archive)
+	if err != nil {
+		logrus.WithError(err).Error("unable to install plugin")
+		return err
+	}
+	return ctx.JSON(http.StatusCreated, module)
+}
+
+func (e *endpoint) Uninstall(ctx echo.Context) error {
+	if err := e.checkPermission(ctx, role.DeleteAction); err != nil {
+		return err
+	}
+	var pluginMetadata pluginModel.ModuleMetadata
+	if err := ctx.Bind(&pluginMetadata); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	if err := e.svc.Uninstall(pluginMetadata); err != nil {
+		logrus.WithError(err).Errorf("unable to uninstall plugin %q with the version %q", pluginMetadata.Name, pluginMetadata.Version)
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (e *endpoint) Enable(ctx echo.Context) error {
+	if err := e.checkPermission(ctx, role.UpdateAction); err != nil {
+		return err
+	}
+	var pluginMetadata pluginModel.ModuleMetadata
+	if err := ctx.Bind(&pluginMetadata); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	if err := e.svc.Enable(pluginMetadata); err != nil {
+		logrus.WithError(err).Errorf("unable to enable plugin %q with the version %q", pluginMetadata.Name, pluginMetadata.Version)
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (e *endpoint) Disable(ctx echo.Context) error {
+	if err := e.checkPermission(ctx, role.UpdateAction); err != nil {
+		return err
+	}
+	var pluginMetadata pluginModel.ModuleMetadata
+	if err := ctx.Bind(&pluginMetadata); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	if err := e.svc.Disable(pluginMetadata); err != nil {
+		logrus.WithError(err).Errorf("unable to disable plugin %q with the version %q", pluginMetadata.Name, pluginMetadata.Version)
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (e *endpoint) SyncRequirements(ctx echo.Context) error {
+	if err := e.checkPermission(ctx, role.CreateAction); err != nil {
+		return err
+	}
+	installed, err := e.svc.SyncRequirements()
+	if err != nil {
+		logrus.WithError(err).Error("unable to install the plugins required")
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, installed)
+}
+
+// checkPermission verifies the user is an administrator, as the plugins are shared by every project.
+// Without authorization, nobody can be identified as an administrator, so the plugins cannot be managed at all.
+func (e *endpoint) checkPermission(ctx echo.Context, action role.Action) error {
+	if !e.authz.IsEnabled() {
+		return apiinterface.HandleForbiddenError("the plugins cannot be managed when the authorization is disabled")
+	}
+	if !e.authz.HasPermission(ctx, action, v1.WildcardProject, role.WildcardScope) {
+		return apiinterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' scope", action, role.WildcardScope))
+	}
+	return nil
+}
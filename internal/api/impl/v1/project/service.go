@@ -16,15 +16,18 @@ package project
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/brunoga/deep"
 	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/authorization"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/folder"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
@@ -37,15 +40,23 @@ import (
 
 type service struct {
 	project.Service
-	dao            project.DAO
-	folderDAO      folder.DAO
-	datasourceDAO  datasource.DAO
-	dashboardDAO   dashboard.DAO
-	roleDAO        role.DAO
-	roleBindingDAO rolebinding.DAO
-	secretDAO      secret.DAO
-	variableDAO    variable.DAO
-	authz          authorization.Authorization
+	dao                project.DAO
+	folderDAO          folder.DAO
+	datasourceDAO      datasource.DAO
+	dashboardDAO       dashboard.DAO
+	roleDAO            role.DAO
+	roleBindingDAO     rolebinding.DAO
+	secretDAO          secret.DAO
+	variableDAO        variable.DAO
+	projectTemplateDAO projecttemplate.DAO
+	folderService      folder.Service
+	datasourceService  datasource.Service
+	dashboardService   dashboard.Service
+	roleService        role.Service
+	roleBindingService rolebinding.Service
+	secretService      secret.Service
+	variableService    variable.Service
+	authz              authorization.Authorization
 }
 
 func NewService(dao project.DAO,
@@ -56,17 +67,33 @@ func NewService(dao project.DAO,
 	roleBindingDAO rolebinding.DAO,
 	secretDAO secret.DAO,
 	variableDAO variable.DAO,
+	projectTemplateDAO projecttemplate.DAO,
+	folderService folder.Service,
+	datasourceService datasource.Service,
+	dashboardService dashboard.Service,
+	roleService role.Service,
+	roleBindingService rolebinding.Service,
+	secretService secret.Service,
+	variableService variable.Service,
 	authz authorization.Authorization) project.Service {
 	return &service{
-		dao:            dao,
-		folderDAO:      folderDAO,
-		datasourceDAO:  datasourceDAO,
-		dashboardDAO:   dashboardDAO,
-		roleDAO:        roleDAO,
-		roleBindingDAO: roleBindingDAO,
-		secretDAO:      secretDAO,
-		variableDAO:    variableDAO,
-		authz:          authz,
+		dao:                dao,
+		folderDAO:          folderDAO,
+		datasourceDAO:      datasourceDAO,
+		dashboardDAO:       dashboardDAO,
+		roleDAO:            roleDAO,
+		roleBindingDAO:     roleBindingDAO,
+		secretDAO:          secretDAO,
+		variableDAO:        variableDAO,
+		projectTemplateDAO: projectTemplateDAO,
+		folderService:      folderService,
+		datasourceService:  datasourceService,
+		dashboardService:   dashboardService,
+		roleService:        roleService,
+		roleBindingService: roleBindingService,
+		secretService:      secretService,
+		variableService:    variableService,
+		authz:              authz,
 	}
 }
 
@@ -79,6 +106,19 @@ func (s *service) Create(ctx echo.Context, entity *v1.Project) (*v1.Project, err
 }
 
 func (s *service) create(ctx echo.Context, entity *v1.Project) (*v1.Project, error) {
+	// The template is rendered before creating anything, so an invalid template or missing parameter doesn't leave
+	// a half-created project behind.
+	var resources []api.Entity
+	if entity.Spec.Template != nil {
+		username, err := s.authz.GetUsername(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get username from context: %w", err)
+		}
+		entity.Spec.Template.Owner = username
+		if resources, err = s.renderTemplate(entity); err != nil {
+			return nil, err
+		}
+	}
 	// Update the time contains in the entity
 	entity.Metadata.CreateNow()
 	if err := s.dao.Create(entity); err != nil {
@@ -91,9 +131,139 @@ func (s *service) create(ctx echo.Context, entity *v1.Project) (*v1.Project, err
 			return nil, err
 		}
 	}
+	if _, err := s.applyResources(ctx, entity.Metadata.Name, resources); err != nil {
+		logrus.WithError(err).Errorf("unable to instantiate the template %q in the project %q, the project is removed", entity.Spec.Template.Name, entity.Metadata.Name)
+		if deleteErr := s.Delete(ctx, apiInterface.Parameters{Name: entity.Metadata.Name}); deleteErr != nil {
+			logrus.WithError(deleteErr).Errorf("unable to remove the project %q", entity.Metadata.Name)
+		}
+		return nil, err
+	}
 	return entity, nil
 }
 
+func (s *service) ApplyTemplate(ctx echo.Context, entity *v1.Project) error {
+	if entity.Spec.Template == nil {
+		return apiInterface.HandleBadRequestError(fmt.Sprintf("project %q is not created from a template", entity.Metadata.Name))
+	}
+	resources, err := s.renderTemplate(entity)
+	if err != nil {
+		return err
+	}
+	_, err = s.applyResources(ctx, entity.Metadata.Name, resources)
+	return err
+}
+
+// renderTemplate returns the resources of the ProjectTemplate referenced by the project, in the order they must be applied.
+// The template is rendered on behalf of its owner, not of the user running the request, so a sync doesn't change who owns the project.
+func (s *service) renderTemplate(entity *v1.Project) ([]api.Entity, error) {
+	templateName := entity.Spec.Template.Name
+	tmpl, err := s.projectTemplateDAO.Get(templateName)
+	if err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return nil, apiInterface.HandleBadRequestError(fmt.Sprintf("ProjectTemplate %q doesn't exist", templateName))
+		}
+		return nil, err
+	}
+	parameters, err := tmpl.ResolveParameters(entity.Spec.Template.Parameters)
+	if err != nil {
+		return nil, apiInterface.HandleBadRequestError(err.Error())
+	}
+	resources, err := tmpl.Render(v1.ProjectTemplateData{
+		Project:    entity.Metadata.Name,
+		Username:   entity.Spec.Template.Owner,
+		Parameters: parameters,
+	})
+	if err != nil {
+		return nil, apiInterface.HandleBadRequestError(err.Error())
+	}
+	return resources, nil
+}
+
+// applyResources creates the given resources, or updates them when they already exist.
+// If one of them cannot be applied, the resources already applied are restored to their previous state.
+// The returned function restores them as well, so the caller can undo the changes when a later step fails.
+func (s *service) applyResources(ctx echo.Context, projectName string, resources []api.Entity) (func(), error) {
+	nativeAuthz := s.authz.IsEnabled() && s.authz.IsNativeAuthz()
+	var restores []func() error
+	rollback := func() {
+		// The resources are restored in the reverse order, so a resource is restored before the ones it depends on.
+		for i := len(restores) - 1; i >= 0; i-- {
+			if err := restores[i](); err != nil {
+				logrus.WithError(err).Errorf("unable to restore a resource of the project %q", projectName)
+			}
+		}
+		if nativeAuthz {
+			if err := s.authz.RefreshPermissions(); err != nil {
+				logrus.WithError(err).Error("failed to refresh RBAC cache")
+			}
+		}
+	}
+	for _, resource := range resources {
+		kind := v1.Kind(resource.GetKind())
+		// Roles and role bindings are only meaningful for the native authorization.
+		if (kind == v1.KindRole || kind == v1.KindRoleBinding) && !nativeAuthz {
+			logrus.Debugf("%s %q of the project %q ignored as the native authorization is not enabled", kind, resource.GetMetadata().GetName(), projectName)
+			continue
+		}
+		var restore func() error
+		var err error
+		switch entity := resource.(type) {
+		case *v1.Secret:
+			restore, err = apply(ctx, s.secretService, s.secretDAO, projectName, entity)
+		case *v1.Datasource:
+			restore, err = apply(ctx, s.datasourceService, s.datasourceDAO, projectName, entity)
+		case *v1.Variable:
+			restore, err = apply(ctx, s.variableService, s.variableDAO, projectName, entity)
+		case *v1.Folder:
+			restore, err = apply(ctx, s.folderService, s.folderDAO, projectName, entity)
+		case *v1.Dashboard:
+			restore, err = apply(ctx, s.dashboardService, s.dashboardDAO, projectName, entity)
+		case *v1.Role:
+			restore, err = apply(ctx, s.roleService, s.roleDAO, projectName, entity)
+		case *v1.RoleBinding:
+			restore, err = apply(ctx, s.roleBindingService, s.roleBindingDAO, projectName, entity)
+		default:
+			err = fmt.Errorf("kind %q is not supported in a ProjectTemplate", kind)
+		}
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("unable to apply the %s %q: %w", kind, resource.GetMetadata().GetName(), err)
+		}
+		restores = append(restores, restore)
+	}
+	return rollback, nil
+}
+
+// resourceDAO is the part of the DAO of a project resource used to restore it.
+type resourceDAO[T api.Entity] interface {
+	Update(entity T) error
+	Delete(project string, name string) error
+	Get(project string, name string) (T, error)
+}
+
+// apply creates the entity, or updates it when it already exists.
+// It returns the function restoring the previous state: it deletes the entity created, or saves back the entity updated.
+func apply[T api.Entity, K api.Entity, V databaseModel.Query](ctx echo.Context, svc apiInterface.Service[T, K, V], dao resourceDAO[T], projectName string, entity T) (func() error, error) {
+	parameters := apiInterface.Parameters{
+		Project: projectName,
+		Name:    entity.GetMetadata().GetName(),
+	}
+	previous, err := dao.Get(projectName, parameters.Name)
+	if err != nil {
+		if !databaseModel.IsKeyNotFound(err) {
+			return nil, err
+		}
+		if _, err = svc.Create(ctx, entity); err != nil {
+			return nil, err
+		}
+		return func() error { return dao.Delete(projectName, parameters.Name) }, nil
+	}
+	if _, err = svc.Update(ctx, entity, parameters); err != nil {
+		return nil, err
+	}
+	return func() error { return dao.Update(previous) }, nil
+}
+
 // Create default roles and role bindings for the project
 func (s *service) createProjectRoleAndRoleBinding(ctx echo.Context, projectName string) error {
 	owner := utils.DefaultOwnerRole(projectName)
@@ -122,15 +292,15 @@ func (s *service) createProjectRoleAndRoleBinding(ctx echo.Context, projectName
 	return s.authz.RefreshPermissions()
 }
 
-func (s *service) Update(_ echo.Context, entity *v1.Project, parameters apiInterface.Parameters) (*v1.Project, error) {
+func (s *service) Update(ctx echo.Context, entity *v1.Project, parameters apiInterface.Parameters) (*v1.Project, error) {
 	copyEntity, err := deep.Copy(entity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy entity: %w", err)
 	}
-	return s.update(copyEntity, parameters)
+	return s.update(ctx, copyEntity, parameters)
 }
 
-func (s *service) update(entity *v1.Project, parameters apiInterface.Parameters) (*v1.Project, error) {
+func (s *service) update(ctx echo.Context, entity *v1.Project, parameters apiInterface.Parameters) (*v1.Project, error) {
 	if entity.Metadata.Name != parameters.Name {
 		logrus.Debugf("name in project %q and name from the http request: %q don't match", entity.Metadata.Name, parameters.Name)
 		return nil, apiInterface.HandleBadRequestError("metadata.name and the name in the http path request don't match")
@@ -140,9 +310,37 @@ func (s *service) update(entity *v1.Project, parameters apiInterface.Parameters)
 	if err != nil {
 		return nil, err
 	}
+	// When the project is moved to another template, or to other parameters, the template is instantiated again.
+	// Like for the creation, it's rendered first, so the project is not updated if the template cannot be instantiated.
+	// The owner is set by the server: it's kept from the previous version, or it's the current user when the project
+	// wasn't created from a template.
+	if entity.Spec.Template != nil {
+		if oldEntity.Spec.Template != nil {
+			entity.Spec.Template.Owner = oldEntity.Spec.Template.Owner
+		} else {
+			username, usernameErr := s.authz.GetUsername(ctx)
+			if usernameErr != nil {
+				return nil, fmt.Errorf("failed to get username from context: %w", usernameErr)
+			}
+			entity.Spec.Template.Owner = username
+		}
+	}
+	var resources []api.Entity
+	if entity.Spec.Template != nil && !reflect.DeepEqual(entity.Spec.Template, oldEntity.Spec.Template) {
+		if resources, err = s.renderTemplate(entity); err != nil {
+			return nil, err
+		}
+	}
+	// The resources are applied before saving the project, so the project doesn't reference a template that failed
+	// to be instantiated. If the project cannot be saved, the resources are restored.
+	rollback, err := s.applyResources(ctx, entity.Metadata.Name, resources)
+	if err != nil {
+		return nil, err
+	}
 	entity.Metadata.Update(oldEntity.Metadata)
 	if updateErr := s.dao.Update(entity); updateErr != nil {
 		logrus.WithError(updateErr).Errorf("unable to perform the update of the project %q, something wrong with the database", entity.Metadata.Name)
+		rollback()
 		return nil, updateErr
 	}
 	return entity, nil
@@ -0,0 +1,199 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
+	"github.com/perses/perses/internal/api/interface/v1/variable"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAuthorization struct {
+	authorization.Authorization
+}
+
+func (a *fakeAuthorization) IsEnabled() bool {
+	return false
+}
+
+func (a *fakeAuthorization) GetUsername(_ echo.Context) (string, error) {
+	return "jdoe", nil
+}
+
+type fakeProjectDAO struct {
+	project.DAO
+	projects  map[string]*v1.Project
+	updateErr error
+}
+
+func (d *fakeProjectDAO) Get(name string) (*v1.Project, error) {
+	entity, ok := d.projects[name]
+	if !ok {
+		return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return entity, nil
+}
+
+func (d *fakeProjectDAO) Update(entity *v1.Project) error {
+	if d.updateErr != nil {
+		return d.updateErr
+	}
+	d.projects[entity.Metadata.Name] = entity
+	return nil
+}
+
+type fakeProjectTemplateDAO struct {
+	projecttemplate.DAO
+	templates map[string]*v1.ProjectTemplate
+}
+
+func (d *fakeProjectTemplateDAO) Get(name string) (*v1.ProjectTemplate, error) {
+	entity, ok := d.templates[name]
+	if !ok {
+		return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return entity, nil
+}
+
+type fakeVariableDAO struct {
+	variable.DAO
+	variables map[string]*v1.Variable
+}
+
+func (d *fakeVariableDAO) Get(project string, name string) (*v1.Variable, error) {
+	entity, ok := d.variables[project+"/"+name]
+	if !ok {
+		return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return entity, nil
+}
+
+func (d *fakeVariableDAO) Update(entity *v1.Variable) error {
+	d.variables[entity.Metadata.Project+"/"+entity.Metadata.Name] = entity
+	return nil
+}
+
+func (d *fakeVariableDAO) Delete(project string, name string) error {
+	delete(d.variables, project+"/"+name)
+	return nil
+}
+
+// fakeVariableService rejects the variable named failingName.
+type fakeVariableService struct {
+	variable.Service
+	dao         *fakeVariableDAO
+	failingName string
+}
+
+func (s *fakeVariableService) Create(_ echo.Context, entity *v1.Variable) (*v1.Variable, error) {
+	if entity.Metadata.Name == s.failingName {
+		return nil, apiInterface.HandleBadRequestError("invalid variable")
+	}
+	return entity, s.dao.Update(entity)
+}
+
+func (s *fakeVariableService) Update(_ echo.Context, entity *v1.Variable, _ apiInterface.Parameters) (*v1.Variable, error) {
+	if entity.Metadata.Name == s.failingName {
+		return nil, apiInterface.HandleBadRequestError("invalid variable")
+	}
+	return entity, s.dao.Update(entity)
+}
+
+func textVariable(name string, value string) v1.ProjectTemplateResource {
+	return v1.ProjectTemplateResource{
+		Kind:     v1.KindVariable,
+		Metadata: v1.ProjectTemplateResourceMetadata{Name: name},
+		Spec: map[string]any{
+			"kind": "TextVariable",
+			"spec": map[string]any{"value": value},
+		},
+	}
+}
+
+func newTestService(oldValue string) (*service, *fakeProjectDAO, *fakeVariableDAO, *fakeVariableService) {
+	projectDAO := &fakeProjectDAO{projects: map[string]*v1.Project{
+		"perses": {
+			Kind:     v1.KindProject,
+			Metadata: v1.Metadata{Name: "perses"},
+			Spec:     v1.ProjectSpec{Template: &v1.ProjectTemplateReference{Name: "team", Owner: "jdoe"}},
+		},
+	}}
+	templateDAO := &fakeProjectTemplateDAO{templates: map[string]*v1.ProjectTemplate{
+		"team": {Kind: v1.KindProjectTemplate, Metadata: v1.Metadata{Name: "team"}},
+		"team-v2": {
+			Kind:     v1.KindProjectTemplate,
+			Metadata: v1.Metadata{Name: "team-v2"},
+			Spec: v1.ProjectTemplateSpec{Resources: []v1.ProjectTemplateResource{
+				textVariable("existing", "new"),
+				textVariable("created", "new"),
+				textVariable("failing", "new"),
+			}},
+		},
+	}}
+	variableDAO := &fakeVariableDAO{variables: map[string]*v1.Variable{}}
+	existing := &v1.Variable{Kind: v1.KindVariable, Metadata: *v1.NewProjectMetadata("perses", "existing")}
+	existing.Spec.Kind = "TextVariable"
+	existing.Spec.Spec = oldValue
+	variableDAO.variables["perses/existing"] = existing
+	variableService := &fakeVariableService{dao: variableDAO}
+	s := &service{
+		dao:                projectDAO,
+		projectTemplateDAO: templateDAO,
+		variableDAO:        variableDAO,
+		variableService:    variableService,
+		authz:              &fakeAuthorization{},
+	}
+	return s, projectDAO, variableDAO, variableService
+}
+
+func TestUpdateRollbackWhenResourceFails(t *testing.T) {
+	s, projectDAO, variableDAO, variableService := newTestService("old")
+	variableService.failingName = "failing"
+	entity := &v1.Project{Kind: v1.KindProject, Metadata: v1.Metadata{Name: "perses"}, Spec: v1.ProjectSpec{Template: &v1.ProjectTemplateReference{Name: "team-v2"}}}
+
+	_, err := s.update(nil, entity, apiInterface.Parameters{Name: "perses"})
+	assert.ErrorIs(t, err, apiInterface.BadRequestError)
+	// The resources applied before the failure are restored, and the project still references the previous template.
+	assert.Equal(t, "old", variableDAO.variables["perses/existing"].Spec.Spec)
+	assert.NotContains(t, variableDAO.variables, "perses/created")
+	assert.Equal(t, "team", projectDAO.projects["perses"].Spec.Template.Name)
+}
+
+func TestUpdateRollbackWhenProjectFails(t *testing.T) {
+	s, projectDAO, variableDAO, _ := newTestService("old")
+	projectDAO.updateErr = fmt.Errorf("database unavailable")
+	entity := &v1.Project{Kind: v1.KindProject, Metadata: v1.Metadata{Name: "perses"}, Spec: v1.ProjectSpec{Template: &v1.ProjectTemplateReference{Name: "team-v2"}}}
+
+	_, err := s.update(nil, entity, apiInterface.Parameters{Name: "perses"})
+	assert.Error(t, err)
+	assert.Equal(t, "old", variableDAO.variables["perses/existing"].Spec.Spec)
+	assert.NotContains(t, variableDAO.variables, "perses/created")
+	assert.NotContains(t, variableDAO.variables, "perses/failing")
+
+	projectDAO.updateErr = nil
+	_, err = s.update(nil, entity, apiInterface.Parameters{Name: "perses"})
+	assert.NoError(t, err)
+	assert.Contains(t, variableDAO.variables, "perses/created")
+	assert.Equal(t, "team-v2", projectDAO.projects["perses"].Spec.Template.Name)
+	assert.Equal(t, "jdoe", projectDAO.projects["perses"].Spec.Template.Owner)
+}
@@ -0,0 +1,99 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projecttemplate
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/toolbox"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+type endpoint struct {
+	toolbox       toolbox.Toolbox[*v1.ProjectTemplate, *projecttemplate.Query]
+	service       projecttemplate.Service
+	authz         authorization.Authorization
+	readonly      bool
+	caseSensitive bool
+}
+
+func NewEndpoint(service projecttemplate.Service, authz authorization.Authorization, readonly bool, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) route.Endpoint {
+	return &endpoint{
+		toolbox:       toolbox.New[*v1.ProjectTemplate, *v1.ProjectTemplate, *projecttemplate.Query](service, authz, v1.KindProjectTemplate, caseSensitive, provisioningPolicy),
+		service:       service,
+		authz:         authz,
+		readonly:      readonly,
+		caseSensitive: caseSensitive,
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	group := g.Group(fmt.Sprintf("/%s", utils.PathProjectTemplate))
+
+	if !e.readonly {
+		group.POST("", e.Create, false)
+		group.PUT(fmt.Sprintf("/:%s", utils.ParamName), e.Update, false)
+		group.DELETE(fmt.Sprintf("/:%s", utils.ParamName), e.Delete, false)
+		group.POST(fmt.Sprintf("/:%s/sync", utils.ParamName), e.Sync, false)
+	}
+	group.GET("", e.List, false)
+	group.GET(fmt.Sprintf("/:%s", utils.ParamName), e.Get, false)
+}
+
+func (e *endpoint) Create(ctx echo.Context) error {
+	entity := &v1.ProjectTemplate{}
+	return e.toolbox.Create(ctx, entity)
+}
+
+func (e *endpoint) Update(ctx echo.Context) error {
+	entity := &v1.ProjectTemplate{}
+	return e.toolbox.Update(ctx, entity)
+}
+
+func (e *endpoint) Delete(ctx echo.Context) error {
+	return e.toolbox.Delete(ctx)
+}
+
+func (e *endpoint) Get(ctx echo.Context) error {
+	return e.toolbox.Get(ctx)
+}
+
+func (e *endpoint) List(ctx echo.Context) error {
+	q := &projecttemplate.Query{}
+	return e.toolbox.List(ctx, q)
+}
+
+// Sync instantiates again the template in every project created from it.
+func (e *endpoint) Sync(ctx echo.Context) error {
+	if e.authz.IsEnabled() {
+		// The resources of the projects are written on behalf of the template, so the permission to update it is required.
+		if !e.authz.HasPermission(ctx, role.UpdateAction, v1.WildcardProject, role.ProjectTemplateScope) {
+			return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' kind", role.UpdateAction, role.ProjectTemplateScope))
+		}
+	}
+	result, err := e.service.Sync(ctx, toolbox.ExtractParameters(ctx, e.caseSensitive))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
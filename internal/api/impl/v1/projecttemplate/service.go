@@ -0,0 +1,136 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projecttemplate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/brunoga/deep"
+	"github.com/labstack/echo/v4"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+type service struct {
+	projecttemplate.Service
+	dao            projecttemplate.DAO
+	projectDAO     project.DAO
+	projectService project.Service
+}
+
+func NewService(dao projecttemplate.DAO, projectDAO project.DAO, projectService project.Service) projecttemplate.Service {
+	return &service{
+		dao:            dao,
+		projectDAO:     projectDAO,
+		projectService: projectService,
+	}
+}
+
+func (s *service) Create(_ echo.Context, entity *v1.ProjectTemplate) (*v1.ProjectTemplate, error) {
+	copyEntity, err := deep.Copy(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy entity: %w", err)
+	}
+	return s.create(copyEntity)
+}
+
+func (s *service) create(entity *v1.ProjectTemplate) (*v1.ProjectTemplate, error) {
+	// Update the time contains in the entity
+	entity.Metadata.CreateNow()
+	if err := s.dao.Create(entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (s *service) Update(_ echo.Context, entity *v1.ProjectTemplate, parameters apiInterface.Parameters) (*v1.ProjectTemplate, error) {
+	copyEntity, err := deep.Copy(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy entity: %w", err)
+	}
+	return s.update(copyEntity, parameters)
+}
+
+func (s *service) update(entity *v1.ProjectTemplate, parameters apiInterface.Parameters) (*v1.ProjectTemplate, error) {
+	if entity.Metadata.Name != parameters.Name {
+		logrus.Debugf("name in ProjectTemplate %q and name from the http request %q don't match", entity.Metadata.Name, parameters.Name)
+		return nil, apiInterface.HandleBadRequestError("metadata.name and the name in the http path request don't match")
+	}
+
+	// find the previous version of the ProjectTemplate
+	oldEntity, err := s.dao.Get(parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	entity.Metadata.Update(oldEntity.Metadata)
+	if updateErr := s.dao.Update(entity); updateErr != nil {
+		logrus.WithError(updateErr).Errorf("unable to perform the update of the ProjectTemplate %q, something wrong with the database", entity.Metadata.Name)
+		return nil, updateErr
+	}
+	return entity, nil
+}
+
+func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
+	return s.dao.Delete(parameters.Name)
+}
+
+func (s *service) Get(parameters apiInterface.Parameters) (*v1.ProjectTemplate, error) {
+	return s.dao.Get(parameters.Name)
+}
+
+func (s *service) List(q *projecttemplate.Query, _ apiInterface.Parameters) ([]*v1.ProjectTemplate, error) {
+	return s.dao.List(q)
+}
+
+func (s *service) RawList(q *projecttemplate.Query, _ apiInterface.Parameters) ([]json.RawMessage, error) {
+	return s.dao.RawList(q)
+}
+
+func (s *service) MetadataList(q *projecttemplate.Query, _ apiInterface.Parameters) ([]api.Entity, error) {
+	return s.dao.MetadataList(q)
+}
+
+func (s *service) RawMetadataList(q *projecttemplate.Query, _ apiInterface.Parameters) ([]json.RawMessage, error) {
+	return s.dao.RawMetadataList(q)
+}
+
+func (s *service) Sync(ctx echo.Context, parameters apiInterface.Parameters) (*api.ProjectTemplateSyncResult, error) {
+	// Ensure the template exists, so a typo in the name doesn't look like a template without any project.
+	if _, err := s.dao.Get(parameters.Name); err != nil {
+		return nil, err
+	}
+	projects, err := s.projectDAO.List(&project.Query{})
+	if err != nil {
+		return nil, err
+	}
+	result := &api.ProjectTemplateSyncResult{Synced: []string{}}
+	for _, prj := range projects {
+		if prj.Spec.Template == nil || prj.Spec.Template.Name != parameters.Name {
+			continue
+		}
+		// A project failing to sync (a required parameter added to the template, for example) must not block the others.
+		if applyErr := s.projectService.ApplyTemplate(ctx, prj); applyErr != nil {
+			logrus.WithError(applyErr).Errorf("unable to sync the project %q with the ProjectTemplate %q", prj.Metadata.Name, parameters.Name)
+			result.Failures = append(result.Failures, api.ProjectTemplateSyncFailure{Project: prj.Metadata.Name, Error: applyErr.Error()})
+			continue
+		}
+		result.Synced = append(result.Synced, prj.Metadata.Name)
+	}
+	return result, nil
+}
@@ -27,20 +27,25 @@ import (
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/toolbox"
 	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
 )
 
 type endpoint struct {
 	toolbox       toolbox.Toolbox[*v1.User, *user.Query]
+	service       user.Service
 	authz         authorization.Authorization
 	readonly      bool
 	disableSignUp bool
 	caseSensitive bool
 }
 
-func NewEndpoint(service user.Service, authz authorization.Authorization, disableSignUp bool, readonly bool, caseSensitive bool) route.Endpoint {
+func NewEndpoint(service user.Service, authz authorization.Authorization, disableSignUp bool, readonly bool, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) route.Endpoint {
 	return &endpoint{
-		toolbox:       toolbox.New[*v1.User, *v1.PublicUser, *user.Query](service, authz, v1.KindUser, caseSensitive),
+		toolbox:       toolbox.New[*v1.User, *v1.PublicUser, *user.Query](service, authz, v1.KindUser, caseSensitive, provisioningPolicy),
+		service:       service,
 		authz:         authz,
 		readonly:      readonly,
 		disableSignUp: disableSignUp,
@@ -59,6 +64,10 @@ func (e *endpoint) CollectRoutes(g *route.Group) {
 		}
 		generalUsersGroup.PUT(fmt.Sprintf("/:%s", utils.ParamName), e.Update, false)
 		generalUsersGroup.DELETE(fmt.Sprintf("/:%s", utils.ParamName), e.Delete, false)
+		generalUsersGroup.POST(fmt.Sprintf("/:%s/%s", utils.ParamName, utils.PathPasswordReset), e.ResetPassword, false)
+		generalUsersGroup.DELETE(fmt.Sprintf("/:%s/%s", utils.ParamName, utils.PathTOTP), e.ResetTOTP, false)
+		generalUsersGroup.POST(fmt.Sprintf("/:%s/%s", utils.ParamName, utils.PathSuspend), e.Suspend, false)
+		generalUsersGroup.POST(fmt.Sprintf("/:%s/%s", utils.ParamName, utils.PathResume), e.Resume, false)
 	}
 	generalUsersGroup.GET("", e.List, false)
 	generalUsersGroup.GET(fmt.Sprintf("/:%s", utils.ParamName), e.Get, false)
@@ -68,6 +77,11 @@ func (e *endpoint) CollectRoutes(g *route.Group) {
 	// It's used with /api/v1/user/... paths
 	currentUserGroup := g.Group(fmt.Sprintf("/%s", utils.PathCurrentUser))
 	currentUserGroup.GET(fmt.Sprintf("/%s", utils.PathWhoAmI), e.WhoAmI, false)
+	if !e.readonly {
+		currentUserGroup.POST(fmt.Sprintf("/%s", utils.PathTOTP), e.EnrollTOTP, false)
+		currentUserGroup.POST(fmt.Sprintf("/%s", utils.PathTOTPActivate), e.ActivateTOTP, false)
+		currentUserGroup.DELETE(fmt.Sprintf("/%s", utils.PathTOTP), e.DisableTOTP, false)
+	}
 }
 
 func (e *endpoint) Create(ctx echo.Context) error {
@@ -93,6 +107,127 @@ func (e *endpoint) List(ctx echo.Context) error {
 	return e.toolbox.List(ctx, q)
 }
 
+func (e *endpoint) ResetPassword(ctx echo.Context) error {
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.UpdateAction, v1.WildcardProject, role.UserScope) {
+		return apiinterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' kind", role.UpdateAction, role.UserScope))
+	}
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	result, err := e.service.ResetPassword(parameters)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// ResetTOTP lets an admin remove the two-factor authentication of a user who lost the authenticator app and the recovery codes.
+func (e *endpoint) ResetTOTP(ctx echo.Context) error {
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.UpdateAction, v1.WildcardProject, role.UserScope) {
+		return apiinterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' kind", role.UpdateAction, role.UserScope))
+	}
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	if err := e.service.DisableTOTP(parameters.Name); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (e *endpoint) Suspend(ctx echo.Context) error {
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.UpdateAction, v1.WildcardProject, role.UserScope) {
+		return apiinterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' kind", role.UpdateAction, role.UserScope))
+	}
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	if e.authz.IsEnabled() {
+		// Suspending yourself would lock you out, likely with nobody else able to resume you.
+		username, err := e.authz.GetUsername(ctx)
+		if err != nil {
+			return err
+		}
+		if username == parameters.Name {
+			return apiinterface.HandleBadRequestError("you cannot suspend yourself")
+		}
+	}
+	body := &api.UserSuspendRequest{}
+	if bindErr := ctx.Bind(body); bindErr != nil {
+		return apiinterface.HandleBadRequestError(bindErr.Error())
+	}
+	result, err := e.service.Suspend(parameters, body.Reason)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *endpoint) Resume(ctx echo.Context) error {
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.UpdateAction, v1.WildcardProject, role.UserScope) {
+		return apiinterface.HandleForbiddenError(fmt.Sprintf("missing '%s' global permission for '%s' kind", role.UpdateAction, role.UserScope))
+	}
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	result, err := e.service.Resume(parameters)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *endpoint) EnrollTOTP(ctx echo.Context) error {
+	username, err := e.getCurrentUsername(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := e.service.EnrollTOTP(username)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *endpoint) ActivateTOTP(ctx echo.Context) error {
+	username, err := e.getCurrentUsername(ctx)
+	if err != nil {
+		return err
+	}
+	body := &api.TOTPCode{}
+	if bindErr := ctx.Bind(body); bindErr != nil {
+		return apiinterface.HandleBadRequestError(bindErr.Error())
+	}
+	result, err := e.service.ActivateTOTP(username, body.Code)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// DisableTOTP lets the current user remove the two-factor authentication.
+// A valid code is required to make sure the request is not coming from a stolen session.
+func (e *endpoint) DisableTOTP(ctx echo.Context) error {
+	username, err := e.getCurrentUsername(ctx)
+	if err != nil {
+		return err
+	}
+	body := &api.TOTPCode{}
+	if bindErr := ctx.Bind(body); bindErr != nil {
+		return apiinterface.HandleBadRequestError(bindErr.Error())
+	}
+	if verifyErr := e.service.VerifyTOTP(username, body.Code); verifyErr != nil {
+		return verifyErr
+	}
+	if disableErr := e.service.DisableTOTP(username); disableErr != nil {
+		return disableErr
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (e *endpoint) getCurrentUsername(ctx echo.Context) (string, error) {
+	if !e.authz.IsEnabled() {
+		return "", apiinterface.HandleUnauthorizedError("authentication is required to manage the two-factor authentication")
+	}
+	username, err := e.authz.GetUsername(ctx)
+	if err != nil || len(username) == 0 {
+		return "", apiinterface.HandleUnauthorizedError("failed to retrieve username from context")
+	}
+	return username, nil
+}
+
 func (e *endpoint) WhoAmI(ctx echo.Context) error {
 	if !e.authz.IsEnabled() {
 		return apiinterface.HandleUnauthorizedError("authentication is required to retrieve user permissions")
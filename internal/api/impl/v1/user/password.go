@@ -0,0 +1,64 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"fmt"
+
+	"github.com/perses/perses/internal/api/crypto"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+// setPassword checks the password against the policy and the previous passwords of the user.
+// If the password is accepted, it is hashed and stored, and the previous one is pushed in the history.
+func setPassword(provider *v1.NativeProvider, password string, policy config.PasswordPolicy) error {
+	if err := policy.Check(password); err != nil {
+		return fmt.Errorf("%w: %s", apiInterface.BadRequestError, err)
+	}
+	// The history contains the previous passwords, the current one excluded.
+	// Therefore, to forbid the reuse of the last N passwords, we need to check the current one plus the N-1 previous ones.
+	var history []string
+	if len(provider.Password) > 0 {
+		history = append(history, provider.Password)
+	}
+	history = append(history, provider.PasswordHistory...)
+	if len(history) > policy.HistorySize {
+		history = history[:policy.HistorySize]
+	}
+	if crypto.MatchesAnyPassword(history, password) {
+		return fmt.Errorf("%w: password cannot be one of the last %d passwords", apiInterface.BadRequestError, policy.HistorySize)
+	}
+	hash, err := crypto.HashAndSalt([]byte(password))
+	if err != nil {
+		return err
+	}
+	if policy.HistorySize > 1 {
+		provider.PasswordHistory = history[:min(len(history), policy.HistorySize-1)]
+	} else {
+		provider.PasswordHistory = nil
+	}
+	provider.Password = string(hash)
+	return nil
+}
+
+// checkCredentials verifies the user is who it claims to be, either with the current password or with a valid reset token.
+// Once an admin has reset the password, only the reset token is accepted: the current password may be the one leaked.
+func checkCredentials(provider v1.NativeProvider, password string, resetToken string) bool {
+	if provider.ResetToken != nil {
+		return len(resetToken) > 0 && !provider.ResetToken.IsExpired() && crypto.CompareToken(provider.ResetToken.Hash, resetToken)
+	}
+	return len(resetToken) == 0 && len(provider.Password) > 0 && crypto.ComparePasswords(provider.Password, password)
+}
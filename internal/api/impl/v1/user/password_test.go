@@ -0,0 +1,134 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perses/perses/internal/api/authorization"
+	"github.com/perses/perses/internal/api/crypto"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/spec/go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPassword(t *testing.T) {
+	policy := config.PasswordPolicy{MinLength: 4, HistorySize: 3}
+	provider := &v1.NativeProvider{}
+
+	assert.ErrorIs(t, setPassword(provider, "abc", policy), apiInterface.BadRequestError)
+	assert.Empty(t, provider.Password)
+
+	for _, password := range []string{"pwd1", "pwd2", "pwd3"} {
+		assert.NoError(t, setPassword(provider, password, policy))
+	}
+	assert.True(t, crypto.ComparePasswords(provider.Password, "pwd3"))
+	assert.Len(t, provider.PasswordHistory, 2)
+
+	// The last 3 passwords cannot be reused
+	for _, password := range []string{"pwd1", "pwd2", "pwd3"} {
+		assert.ErrorIs(t, setPassword(provider, password, policy), apiInterface.BadRequestError)
+	}
+	assert.NoError(t, setPassword(provider, "pwd4", policy))
+	assert.Len(t, provider.PasswordHistory, 2)
+	// pwd1 is now out of the history
+	assert.NoError(t, setPassword(provider, "pwd1", policy))
+}
+
+func TestSetPasswordWithoutHistory(t *testing.T) {
+	provider := &v1.NativeProvider{}
+	assert.NoError(t, setPassword(provider, "pwd", config.PasswordPolicy{}))
+	assert.NoError(t, setPassword(provider, "pwd", config.PasswordPolicy{}))
+	assert.Empty(t, provider.PasswordHistory)
+}
+
+type fakeAuthorization struct {
+	authorization.Authorization
+}
+
+func (a *fakeAuthorization) RefreshPermissions() error {
+	return nil
+}
+
+func TestUpdateWithUnchangedPassword(t *testing.T) {
+	provider := &v1.NativeProvider{}
+	policy := config.PasswordPolicy{MinLength: 4, HistorySize: 3}
+	assert.NoError(t, setPassword(provider, "pwd1", policy))
+	dao := &fakeDAO{users: map[string]*v1.User{
+		"jdoe": {Metadata: v1.Metadata{Name: "jdoe"}, Spec: v1.UserSpec{NativeProvider: *provider}},
+	}}
+	s := &service{dao: dao, authz: &fakeAuthorization{}, native: config.NativeAuthnProvider{PasswordPolicy: policy}}
+	parameters := apiInterface.Parameters{Name: "jdoe"}
+
+	// Applying the user again with the same password, like the provisioning does, is not a password change.
+	_, err := s.update(&v1.User{Metadata: v1.Metadata{Name: "jdoe"}, Spec: v1.UserSpec{NativeProvider: v1.NativeProvider{Password: "pwd1"}}}, parameters)
+	assert.NoError(t, err)
+	assert.Equal(t, provider.Password, dao.users["jdoe"].Spec.NativeProvider.Password)
+	assert.Empty(t, dao.users["jdoe"].Spec.NativeProvider.PasswordHistory)
+
+	_, err = s.update(&v1.User{Metadata: v1.Metadata{Name: "jdoe"}, Spec: v1.UserSpec{NativeProvider: v1.NativeProvider{Password: "pwd2"}}}, parameters)
+	assert.NoError(t, err)
+	assert.True(t, crypto.ComparePasswords(dao.users["jdoe"].Spec.NativeProvider.Password, "pwd2"))
+	assert.Len(t, dao.users["jdoe"].Spec.NativeProvider.PasswordHistory, 1)
+}
+
+func TestCheckCredentials(t *testing.T) {
+	hash, err := crypto.HashAndSalt([]byte("password"))
+	assert.NoError(t, err)
+	token, tokenHash, err := crypto.GenerateToken()
+	assert.NoError(t, err)
+	provider := v1.NativeProvider{
+		Password:   string(hash),
+		ResetToken: &v1.PasswordResetToken{Hash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	// The password is not accepted anymore once it has been reset.
+	assert.False(t, checkCredentials(provider, "password", ""))
+	assert.True(t, checkCredentials(provider, "", token))
+	assert.False(t, checkCredentials(provider, "", "wrong"))
+
+	provider.ResetToken.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.False(t, checkCredentials(provider, "", token))
+	provider.ResetToken = nil
+	assert.False(t, checkCredentials(provider, "", token))
+	assert.True(t, checkCredentials(provider, "password", ""))
+	assert.False(t, checkCredentials(provider, "wrong", ""))
+}
+
+func TestChangePasswordAfterReset(t *testing.T) {
+	provider := &v1.NativeProvider{}
+	policy := config.PasswordPolicy{MinLength: 4}
+	assert.NoError(t, setPassword(provider, "pwd1", policy))
+	dao := &fakeDAO{users: map[string]*v1.User{
+		"jdoe": {Metadata: v1.Metadata{Name: "jdoe"}, Spec: v1.UserSpec{NativeProvider: *provider}},
+	}}
+	s := &service{dao: dao, authz: &fakeAuthorization{}, native: config.NativeAuthnProvider{PasswordPolicy: policy, ResetTokenTTL: common.Duration(time.Hour)}}
+
+	reset, err := s.ResetPassword(apiInterface.Parameters{Name: "jdoe"})
+	assert.NoError(t, err)
+	// The old password cannot be used anymore to change the password.
+	err = s.ChangePassword(&api.PasswordChangeRequest{Login: "jdoe", Password: "pwd1", NewPassword: "pwd2"})
+	assert.ErrorIs(t, err, errWrongCredentials)
+
+	assert.NoError(t, s.ChangePassword(&api.PasswordChangeRequest{Login: "jdoe", ResetToken: reset.Token, NewPassword: "pwd2"}))
+	assert.Nil(t, dao.users["jdoe"].Spec.NativeProvider.ResetToken)
+	assert.False(t, dao.users["jdoe"].Spec.NativeProvider.MustChangePassword)
+	// The token is single use, the new password is then the only way to prove the identity.
+	err = s.ChangePassword(&api.PasswordChangeRequest{Login: "jdoe", ResetToken: reset.Token, NewPassword: "pwd3"})
+	assert.ErrorIs(t, err, errWrongCredentials)
+	assert.NoError(t, s.ChangePassword(&api.PasswordChangeRequest{Login: "jdoe", Password: "pwd2", NewPassword: "pwd3"}))
+}
@@ -15,29 +15,39 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/brunoga/deep"
 	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/authorization"
 	"github.com/perses/perses/internal/api/crypto"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/user"
 	"github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
 )
 
+var errWrongCredentials = fmt.Errorf("%w: wrong login, password or reset token", apiInterface.BadRequestError)
+
 type service struct {
 	user.Service
-	dao   user.DAO
-	authz authorization.Authorization
+	dao    user.DAO
+	authz  authorization.Authorization
+	crypto crypto.Crypto
+	native config.NativeAuthnProvider
 }
 
-func NewService(dao user.DAO, authz authorization.Authorization) user.Service {
+func NewService(dao user.DAO, authz authorization.Authorization, crypto crypto.Crypto, native config.NativeAuthnProvider) user.Service {
 	return &service{
-		dao:   dao,
-		authz: authz,
+		dao:    dao,
+		authz:  authz,
+		crypto: crypto,
+		native: native,
 	}
 }
 
@@ -53,16 +63,16 @@ func (s *service) create(entity *v1.User) (*v1.PublicUser, error) {
 	// Update the time contains in the entity
 	entity.Metadata.CreateNow()
 	// check that the password is correctly filled
-	if len(entity.Spec.NativeProvider.Password) == 0 {
+	password := entity.Spec.NativeProvider.Password
+	if len(password) == 0 {
 		return nil, fmt.Errorf("%w: password cannot be empty", apiInterface.BadRequestError)
 	}
-	hash, err := crypto.HashAndSalt([]byte(entity.Spec.NativeProvider.Password))
-	if err != nil {
-		logrus.WithError(err).Errorf("unable to generate the hash for the password of the user %s", entity.Metadata.Name)
-		return nil, apiInterface.InternalError
+	// The history and the reset token are managed by the server only.
+	entity.Spec.NativeProvider = v1.NativeProvider{MustChangePassword: entity.Spec.NativeProvider.MustChangePassword}
+	if err := s.setPassword(entity, password); err != nil {
+		return nil, err
 	}
-	// save the hash in the password field
-	entity.Spec.NativeProvider.Password = string(hash)
+	setSuspension(&entity.Spec)
 	if createErr := s.dao.Create(entity); createErr != nil {
 		return nil, createErr
 	}
@@ -92,16 +102,23 @@ func (s *service) update(entity *v1.User, parameters apiInterface.Parameters) (*
 		return nil, err
 	}
 	entity.Metadata.Update(oldEntity.Metadata)
-	// in case the user updated his password, then we should hash it again, otherwise the old password should be kept
-	if len(entity.Spec.NativeProvider.Password) > 0 {
-		hash, hashErr := crypto.HashAndSalt([]byte(entity.Spec.NativeProvider.Password))
-		if hashErr != nil {
-			logrus.WithError(hashErr).Errorf("unable to generate the hash for the password of the user %q", entity.Metadata.Name)
-			return nil, hashErr
+	// The history and the reset token are managed by the server only, so we start from the old native provider.
+	newProvider := entity.Spec.NativeProvider
+	entity.Spec.NativeProvider = oldEntity.Spec.NativeProvider
+	// in case the user updated his password, then we should hash it again, otherwise the old password should be kept.
+	// Submitting the current password again (like the provisioning does at each run) is not a change, so it is neither
+	// checked against the policy nor against the history.
+	oldPassword := oldEntity.Spec.NativeProvider.Password
+	passwordChanged := len(newProvider.Password) > 0 && (len(oldPassword) == 0 || !crypto.MatchesAnyPassword([]string{oldPassword}, newProvider.Password))
+	if passwordChanged {
+		if err := s.setPassword(entity, newProvider.Password); err != nil {
+			return nil, err
 		}
-		entity.Spec.NativeProvider.Password = string(hash)
+		entity.Spec.NativeProvider.ResetToken = nil
+		entity.Spec.NativeProvider.MustChangePassword = newProvider.MustChangePassword
 	} else {
-		entity.Spec.NativeProvider.Password = oldEntity.Spec.NativeProvider.Password
+		// Without a new password, the obligation to change the password can be set but not removed.
+		entity.Spec.NativeProvider.MustChangePassword = oldEntity.Spec.NativeProvider.MustChangePassword || newProvider.MustChangePassword
 	}
 	// in case the user is updating the firstname / lastname, then it should be updated, otherwise the old one should be kept
 	if len(entity.Spec.FirstName) == 0 {
@@ -110,6 +127,11 @@ func (s *service) update(entity *v1.User, parameters apiInterface.Parameters) (*
 	if len(entity.Spec.LastName) == 0 {
 		entity.Spec.LastName = oldEntity.Spec.LastName
 	}
+	// The suspension can only be changed through the dedicated endpoints,
+	// so an update that leaves it out (like a new run of the provisioning) doesn't resume a suspended user.
+	entity.Spec.Disabled = oldEntity.Spec.Disabled
+	entity.Spec.DisabledReason = oldEntity.Spec.DisabledReason
+	entity.Spec.DisabledAt = oldEntity.Spec.DisabledAt
 	if updateErr := s.dao.Update(entity); updateErr != nil {
 		logrus.WithError(err).Errorf("unable to perform the update of the user %q", entity.Metadata.Name)
 		return nil, updateErr
@@ -121,6 +143,112 @@ func (s *service) update(entity *v1.User, parameters apiInterface.Parameters) (*
 	return v1.NewPublicUser(entity), nil
 }
 
+func (s *service) Suspend(parameters apiInterface.Parameters, reason string) (*v1.PublicUser, error) {
+	entity, err := s.dao.Get(parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	entity.Spec.Suspend(reason)
+	return s.saveSuspension(entity)
+}
+
+func (s *service) Resume(parameters apiInterface.Parameters) (*v1.PublicUser, error) {
+	entity, err := s.dao.Get(parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	entity.Spec.Resume()
+	return s.saveSuspension(entity)
+}
+
+func (s *service) saveSuspension(entity *v1.User) (*v1.PublicUser, error) {
+	entity.Metadata.Update(entity.Metadata)
+	if updateErr := s.dao.Update(entity); updateErr != nil {
+		logrus.WithError(updateErr).Errorf("unable to change the state of the user %q", entity.Metadata.Name)
+		return nil, updateErr
+	}
+	// The permissions of a disabled user are removed from the RBAC cache.
+	if err := s.authz.RefreshPermissions(); err != nil {
+		logrus.WithError(err).Error("failed to refresh RBAC cache")
+	}
+	return v1.NewPublicUser(entity), nil
+}
+
+// setSuspension makes sure the details of the suspension are consistent with the disabled flag of a new user.
+// The time of the suspension is managed by the server, so the one sent by the client is ignored.
+func setSuspension(spec *v1.UserSpec) {
+	disabled, reason := spec.Disabled, spec.DisabledReason
+	spec.Resume()
+	if disabled {
+		spec.Suspend(reason)
+	}
+}
+
+func (s *service) ResetPassword(parameters apiInterface.Parameters) (*api.PasswordResetResponse, error) {
+	entity, err := s.dao.Get(parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(entity.Spec.NativeProvider.Password) == 0 && len(entity.Spec.OauthProviders) > 0 {
+		return nil, fmt.Errorf("%w: the password of the user %q is managed by an external provider", apiInterface.BadRequestError, entity.Metadata.Name)
+	}
+	token, hash, err := crypto.GenerateToken()
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to generate the reset token for the user %q", entity.Metadata.Name)
+		return nil, apiInterface.InternalError
+	}
+	expiresAt := time.Now().Add(time.Duration(s.native.ResetTokenTTL)).UTC()
+	entity.Spec.NativeProvider.ResetToken = &v1.PasswordResetToken{
+		Hash:      hash,
+		ExpiresAt: expiresAt,
+	}
+	entity.Spec.NativeProvider.MustChangePassword = true
+	entity.Metadata.Update(entity.Metadata)
+	if updateErr := s.dao.Update(entity); updateErr != nil {
+		logrus.WithError(updateErr).Errorf("unable to save the reset token of the user %q", entity.Metadata.Name)
+		return nil, updateErr
+	}
+	return &api.PasswordResetResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *service) ChangePassword(request *api.PasswordChangeRequest) error {
+	entity, err := s.dao.Get(request.Login)
+	if err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return errWrongCredentials
+		}
+		return err
+	}
+	if !checkCredentials(entity.Spec.NativeProvider, request.Password, request.ResetToken) {
+		return errWrongCredentials
+	}
+	if err := s.setPassword(entity, request.NewPassword); err != nil {
+		return err
+	}
+	entity.Spec.NativeProvider.ResetToken = nil
+	entity.Spec.NativeProvider.MustChangePassword = false
+	entity.Metadata.Update(entity.Metadata)
+	if updateErr := s.dao.Update(entity); updateErr != nil {
+		logrus.WithError(updateErr).Errorf("unable to change the password of the user %q", entity.Metadata.Name)
+		return updateErr
+	}
+	return nil
+}
+
+func (s *service) setPassword(entity *v1.User, password string) error {
+	if err := setPassword(&entity.Spec.NativeProvider, password, s.native.PasswordPolicy); err != nil {
+		if !errors.Is(err, apiInterface.BadRequestError) {
+			logrus.WithError(err).Errorf("unable to generate the hash for the password of the user %q", entity.Metadata.Name)
+			return apiInterface.InternalError
+		}
+		return err
+	}
+	return nil
+}
+
 func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
 	err := s.dao.Delete(parameters.Name)
 	if err != nil {
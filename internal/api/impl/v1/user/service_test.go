@@ -0,0 +1,48 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"testing"
+
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateKeepsSuspension(t *testing.T) {
+	spec := v1.UserSpec{}
+	spec.Suspend("left the company")
+	dao := &fakeDAO{users: map[string]*v1.User{
+		"jdoe": {Metadata: v1.Metadata{Name: "jdoe"}, Spec: spec},
+	}}
+	s := &service{dao: dao, authz: &fakeAuthorization{}}
+	parameters := apiInterface.Parameters{Name: "jdoe"}
+
+	// Applying the user again without the suspension, like the provisioning does, doesn't resume the user.
+	_, err := s.update(&v1.User{Metadata: v1.Metadata{Name: "jdoe"}, Spec: v1.UserSpec{FirstName: "John"}}, parameters)
+	assert.NoError(t, err)
+	assert.True(t, dao.users["jdoe"].Spec.Disabled)
+	assert.Equal(t, "left the company", dao.users["jdoe"].Spec.DisabledReason)
+	assert.Equal(t, spec.DisabledAt, dao.users["jdoe"].Spec.DisabledAt)
+	assert.Equal(t, "John", dao.users["jdoe"].Spec.FirstName)
+
+	_, err = s.Resume(parameters)
+	assert.NoError(t, err)
+	// Likewise, an update cannot suspend the user.
+	_, err = s.update(&v1.User{Metadata: v1.Metadata{Name: "jdoe"}, Spec: v1.UserSpec{Disabled: true}}, parameters)
+	assert.NoError(t, err)
+	assert.False(t, dao.users["jdoe"].Spec.Disabled)
+	assert.Nil(t, dao.users["jdoe"].Spec.DisabledAt)
+}
@@ -0,0 +1,180 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/perses/perses/internal/api/crypto"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// totpIssuer is the name displayed by the authenticator apps next to the account.
+	totpIssuer = "Perses"
+	// recoveryCodesCount is the number of recovery codes generated when the two-factor authentication is enabled.
+	recoveryCodesCount = 10
+)
+
+var errWrongTOTPCode = fmt.Errorf("%w: wrong two-factor authentication code", apiInterface.BadRequestError)
+
+func (s *service) EnrollTOTP(username string) (*api.TOTPEnrollment, error) {
+	entity, err := s.getNativeUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if entity.Spec.NativeProvider.TOTP.IsEnabled() {
+		return nil, fmt.Errorf("%w: two-factor authentication is already enabled", apiInterface.ConflictError)
+	}
+	secret, err := crypto.GenerateTOTPSecret()
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to generate the TOTP secret of the user %q", username)
+		return nil, apiInterface.InternalError
+	}
+	encryptedSecret, err := s.crypto.EncryptString(secret)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to encrypt the TOTP secret of the user %q", username)
+		return nil, apiInterface.InternalError
+	}
+	entity.Spec.NativeProvider.TOTP = &v1.TOTP{Secret: encryptedSecret}
+	if err := s.save(entity); err != nil {
+		return nil, err
+	}
+	return &api.TOTPEnrollment{
+		Secret: secret,
+		URI:    crypto.TOTPURI(totpIssuer, entity.Metadata.Name, secret),
+	}, nil
+}
+
+func (s *service) PendingTOTPEnrollment(username string) (*api.TOTPEnrollment, error) {
+	entity, err := s.getNativeUser(username)
+	if err != nil {
+		return nil, err
+	}
+	totp := entity.Spec.NativeProvider.TOTP
+	if totp == nil || totp.Enabled {
+		return s.EnrollTOTP(username)
+	}
+	secret, err := s.crypto.DecryptString(totp.Secret)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to decrypt the TOTP secret of the user %q", username)
+		return nil, apiInterface.InternalError
+	}
+	return &api.TOTPEnrollment{
+		Secret: secret,
+		URI:    crypto.TOTPURI(totpIssuer, entity.Metadata.Name, secret),
+	}, nil
+}
+
+func (s *service) ActivateTOTP(username string, code string) (*api.TOTPRecoveryCodes, error) {
+	entity, err := s.getNativeUser(username)
+	if err != nil {
+		return nil, err
+	}
+	totp := entity.Spec.NativeProvider.TOTP
+	if totp == nil {
+		return nil, fmt.Errorf("%w: two-factor authentication enrollment has not been started", apiInterface.BadRequestError)
+	}
+	if totp.Enabled {
+		return nil, fmt.Errorf("%w: two-factor authentication is already enabled", apiInterface.ConflictError)
+	}
+	if err := s.checkTOTPCode(entity, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := crypto.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to generate the recovery codes of the user %q", username)
+		return nil, apiInterface.InternalError
+	}
+	totp.Enabled = true
+	totp.RecoveryCodes = hashes
+	if err := s.save(entity); err != nil {
+		return nil, err
+	}
+	return &api.TOTPRecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+func (s *service) VerifyTOTP(username string, code string) error {
+	entity, err := s.getNativeUser(username)
+	if err != nil {
+		return err
+	}
+	totp := entity.Spec.NativeProvider.TOTP
+	if !totp.IsEnabled() {
+		return fmt.Errorf("%w: two-factor authentication is not enabled", apiInterface.BadRequestError)
+	}
+	if i := crypto.MatchRecoveryCode(totp.RecoveryCodes, code); i >= 0 {
+		totp.RecoveryCodes = slices.Delete(totp.RecoveryCodes, i, i+1)
+		logrus.Infof("user %q used a recovery code, %d remaining", username, len(totp.RecoveryCodes))
+		return s.save(entity)
+	}
+	if err := s.checkTOTPCode(entity, code); err != nil {
+		return err
+	}
+	return s.save(entity)
+}
+
+func (s *service) DisableTOTP(username string) error {
+	entity, err := s.getNativeUser(username)
+	if err != nil {
+		return err
+	}
+	if entity.Spec.NativeProvider.TOTP == nil {
+		return nil
+	}
+	entity.Spec.NativeProvider.TOTP = nil
+	return s.save(entity)
+}
+
+// checkTOTPCode validates the code against the secret of the user and records the time step used,
+// so the same code cannot be used twice. The caller is responsible for saving the user.
+func (s *service) checkTOTPCode(entity *v1.User, code string) error {
+	totp := entity.Spec.NativeProvider.TOTP
+	secret, err := s.crypto.DecryptString(totp.Secret)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to decrypt the TOTP secret of the user %q", entity.Metadata.Name)
+		return apiInterface.InternalError
+	}
+	step, ok := crypto.ValidateTOTP(secret, code, time.Now(), totp.LastUsedStep)
+	if !ok {
+		return errWrongTOTPCode
+	}
+	totp.LastUsedStep = step
+	return nil
+}
+
+func (s *service) getNativeUser(username string) (*v1.User, error) {
+	entity, err := s.dao.Get(username)
+	if err != nil {
+		return nil, err
+	}
+	if len(entity.Spec.NativeProvider.Password) == 0 {
+		return nil, fmt.Errorf("%w: two-factor authentication is only available for native users", apiInterface.BadRequestError)
+	}
+	return entity, nil
+}
+
+func (s *service) save(entity *v1.User) error {
+	entity.Metadata.Update(entity.Metadata)
+	if err := s.dao.Update(entity); err != nil {
+		logrus.WithError(err).Errorf("unable to update the user %q", entity.Metadata.Name)
+		return err
+	}
+	return nil
+}
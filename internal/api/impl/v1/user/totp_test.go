@@ -0,0 +1,116 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/perses/perses/internal/api/crypto"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDAO struct {
+	user.DAO
+	users map[string]*v1.User
+}
+
+func (d *fakeDAO) Get(name string) (*v1.User, error) {
+	return d.users[name], nil
+}
+
+func (d *fakeDAO) Update(entity *v1.User) error {
+	d.users[entity.Metadata.Name] = entity
+	return nil
+}
+
+func newTOTPTestService(t *testing.T) (*service, *fakeDAO) {
+	cryptoService, _, err := crypto.New(config.Security{
+		EncryptionKey: secret.Hidden(hex.EncodeToString([]byte("=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc"))),
+	})
+	assert.NoError(t, err)
+	dao := &fakeDAO{users: map[string]*v1.User{
+		"jdoe": {
+			Metadata: v1.Metadata{Name: "jdoe"},
+			Spec:     v1.UserSpec{NativeProvider: v1.NativeProvider{Password: "hash"}},
+		},
+		"oauth": {
+			Metadata: v1.Metadata{Name: "oauth"},
+			Spec:     v1.UserSpec{OauthProviders: []v1.OAuthProvider{{Issuer: "github"}}},
+		},
+	}}
+	return &service{dao: dao, crypto: cryptoService}, dao
+}
+
+func TestTOTPEnrollment(t *testing.T) {
+	s, dao := newTOTPTestService(t)
+
+	enrollment, err := s.EnrollTOTP("jdoe")
+	assert.NoError(t, err)
+	totp := dao.users["jdoe"].Spec.NativeProvider.TOTP
+	assert.False(t, totp.IsEnabled())
+	assert.NotEqual(t, enrollment.Secret, totp.Secret, "the secret must be stored encrypted")
+
+	// The enrollment is confirmed only with a valid code
+	_, err = s.ActivateTOTP("jdoe", "000000")
+	assert.ErrorIs(t, err, apiInterface.BadRequestError)
+	code, err := crypto.GenerateTOTPCode(enrollment.Secret, time.Now())
+	assert.NoError(t, err)
+	recoveryCodes, err := s.ActivateTOTP("jdoe", code)
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes.RecoveryCodes, recoveryCodesCount)
+	assert.True(t, dao.users["jdoe"].Spec.NativeProvider.TOTP.IsEnabled())
+
+	// A new enrollment is not possible as long as the current one is enabled
+	_, err = s.EnrollTOTP("jdoe")
+	assert.ErrorIs(t, err, apiInterface.ConflictError)
+
+	// The code used for the activation cannot be used again
+	assert.ErrorIs(t, s.VerifyTOTP("jdoe", code), apiInterface.BadRequestError)
+
+	// A recovery code can be used once
+	assert.NoError(t, s.VerifyTOTP("jdoe", recoveryCodes.RecoveryCodes[0]))
+	assert.ErrorIs(t, s.VerifyTOTP("jdoe", recoveryCodes.RecoveryCodes[0]), apiInterface.BadRequestError)
+	assert.Len(t, dao.users["jdoe"].Spec.NativeProvider.TOTP.RecoveryCodes, recoveryCodesCount-1)
+
+	assert.NoError(t, s.DisableTOTP("jdoe"))
+	assert.Nil(t, dao.users["jdoe"].Spec.NativeProvider.TOTP)
+	assert.ErrorIs(t, s.VerifyTOTP("jdoe", code), apiInterface.BadRequestError)
+}
+
+func TestPendingTOTPEnrollment(t *testing.T) {
+	s, dao := newTOTPTestService(t)
+
+	enrollment, err := s.PendingTOTPEnrollment("jdoe")
+	assert.NoError(t, err)
+	storedSecret := dao.users["jdoe"].Spec.NativeProvider.TOTP.Secret
+
+	// The enrollment not confirmed yet is returned again, instead of generating a new secret
+	pending, err := s.PendingTOTPEnrollment("jdoe")
+	assert.NoError(t, err)
+	assert.Equal(t, enrollment, pending)
+	assert.Equal(t, storedSecret, dao.users["jdoe"].Spec.NativeProvider.TOTP.Secret)
+}
+
+func TestTOTPEnrollmentNonNativeUser(t *testing.T) {
+	s, _ := newTOTPTestService(t)
+	_, err := s.EnrollTOTP("oauth")
+	assert.ErrorIs(t, err, apiInterface.BadRequestError)
+}
@@ -34,9 +34,9 @@ type endpoint struct {
 	isDisable bool
 }
 
-func NewEndpoint(cfg config.VariableConfig, service variable.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+func NewEndpoint(cfg config.VariableConfig, service variable.Service, authz authorization.Authorization, readonly bool, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) route.Endpoint {
 	return &endpoint{
-		toolbox:   toolbox.New[*v1.Variable, *v1.Variable, *variable.Query](service, authz, v1.KindVariable, caseSensitive),
+		toolbox:   toolbox.New[*v1.Variable, *v1.Variable, *variable.Query](service, authz, v1.KindVariable, caseSensitive, provisioningPolicy),
 		readonly:  readonly,
 		isDisable: cfg.Project.Disable,
 	}
@@ -80,6 +80,10 @@ func (t *testRBAC) HasCreateProjectPermission(_ echo.Context, _ string) bool {
 	return t.allow
 }
 
+func (t *testRBAC) CanI(_ echo.Context, _ *api.CanIRequest) (*api.CanIResponse, error) {
+	return &api.CanIResponse{Allowed: t.allow}, nil
+}
+
 func (t *testRBAC) IsEnabled() bool {
 	return true
 }
@@ -39,6 +39,7 @@ var (
 	UnauthorizedError    = &PersesError{message: "unauthorized"}
 	ForbiddenError       = &PersesError{message: "forbidden access"}
 	UnsupportedMediaType = &PersesError{message: "unsupported media type"}
+	TooManyRequestsError = &PersesError{message: "too many requests"}
 )
 
 // HandleError is translating the given error to the echo.HTTPError
@@ -75,6 +76,9 @@ func HandleError(err error) error {
 	if errors.Is(err, UnsupportedMediaType) {
 		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
 	}
+	if errors.Is(err, TooManyRequestsError) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	}
 
 	var HTTPError *echo.HTTPError
 	if errors.As(err, &HTTPError) {
@@ -90,6 +94,10 @@ func HandleNotFoundError(msg string) error {
 	return handleErrorMsg(msg, NotFoundError)
 }
 
+func HandleConflictError(msg string) error {
+	return handleErrorMsg(msg, ConflictError)
+}
+
 func HandleBadRequestError(msg string) error {
 	return handleErrorMsg(msg, BadRequestError)
 }
@@ -102,6 +110,10 @@ func HandleForbiddenError(msg string) error {
 	return handleErrorMsg(msg, ForbiddenError)
 }
 
+func HandleTooManyRequestsError(msg string) error {
+	return handleErrorMsg(msg, TooManyRequestsError)
+}
+
 func handleErrorMsg(msg string, err *PersesError) error {
 	return fmt.Errorf("%w: %s", err, msg)
 }
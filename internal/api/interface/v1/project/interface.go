@@ -16,6 +16,7 @@ package project
 import (
 	"encoding/json"
 
+	"github.com/labstack/echo/v4"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/pkg/model/api"
@@ -55,4 +56,7 @@ type DAO interface {
 
 type Service interface {
 	apiInterface.Service[*v1.Project, *v1.Project, *Query]
+	// ApplyTemplate instantiates the ProjectTemplate referenced by the project.
+	// Resources already existing are updated.
+	ApplyTemplate(ctx echo.Context, entity *v1.Project) error
 }
@@ -54,4 +54,28 @@ type DAO interface {
 
 type Service interface {
 	apiInterface.Service[*v1.User, *v1.PublicUser, *Query]
+	// ResetPassword generates a one-time token the user can use to define a new password.
+	// Until the password is changed, the user is not able to log in anymore.
+	ResetPassword(parameters apiInterface.Parameters) (*api.PasswordResetResponse, error)
+	// ChangePassword changes the password of a native user once its identity has been verified,
+	// either with the current password or with a reset token.
+	ChangePassword(request *api.PasswordChangeRequest) error
+	// Suspend disables the user. A suspended user cannot log in anymore and loses all its permissions,
+	// while its role bindings and group memberships are kept.
+	Suspend(parameters apiInterface.Parameters, reason string) (*v1.PublicUser, error)
+	// Resume enables a suspended user.
+	Resume(parameters apiInterface.Parameters) (*v1.PublicUser, error)
+	// EnrollTOTP generates a new TOTP secret for the user. The two-factor authentication is only enabled
+	// once the user confirmed the enrollment with ActivateTOTP.
+	EnrollTOTP(username string) (*api.TOTPEnrollment, error)
+	// PendingTOTPEnrollment returns the enrollment started and not confirmed yet, or starts a new one with EnrollTOTP.
+	PendingTOTPEnrollment(username string) (*api.TOTPEnrollment, error)
+	// ActivateTOTP enables the two-factor authentication if the code matches the secret generated by EnrollTOTP.
+	// It returns the recovery codes of the user.
+	ActivateTOTP(username string, code string) (*api.TOTPRecoveryCodes, error)
+	// VerifyTOTP checks the code provided by a user who enabled the two-factor authentication.
+	// The code can be a TOTP code or a recovery code. In the latter case, the recovery code is consumed.
+	VerifyTOTP(username string, code string) error
+	// DisableTOTP removes the two-factor authentication of the user.
+	DisableTOTP(username string) error
 }
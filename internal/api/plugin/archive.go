@@ -19,15 +19,22 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/mholt/archives"
 	"github.com/perses/perses/internal/api/archive"
 	"github.com/sirupsen/logrus"
 )
 
+// maxExtractedSize is the maximum number of bytes extracted from a plugin archive.
+// The size of an uploaded archive is limited, but not the size of its content, so it protects against decompression bombs.
+const maxExtractedSize = 500 << 20
+
 type arch struct {
 	folders      []string
 	targetFolder string
+	// extracted is the number of bytes extracted from the current archive.
+	extracted int64
 }
 
 func (a *arch) unzipAll() error {
@@ -73,6 +80,7 @@ func (a *arch) unzip(folder string, archiveFileName string) error {
 		return nil
 	}
 	if ex, ok := format.(archives.Extractor); ok {
+		a.extracted = 0
 		if extractErr := ex.Extract(context.Background(), newStream, a.extractArchiveFileHandler(archiveName)); extractErr != nil {
 			return fmt.Errorf("unable to extract the archive file: %w", extractErr)
 		}
@@ -80,11 +88,44 @@ func (a *arch) unzip(folder string, archiveFileName string) error {
 	return nil
 }
 
+// ExtractArchive extracts the plugin archive in the given folder.
+func ExtractArchive(archivePath string, targetFolder string) error {
+	stream, err := os.Open(archivePath) //nolint: gosec
+	if err != nil {
+		return fmt.Errorf("unable to open archive file %q: %w", archivePath, err)
+	}
+	defer stream.Close() //nolint: errcheck
+	return extractStream(stream, filepath.Dir(targetFolder), filepath.Base(targetFolder))
+}
+
+// extractStream extracts the archive read from r in the folder targetFolder/folderName.
+// The format of the archive is identified from its content.
+func extractStream(r io.Reader, targetFolder string, folderName string) error {
+	format, stream, err := archives.Identify(context.Background(), "", r)
+	if err != nil {
+		return fmt.Errorf("unable to identify the type of the archive: %w", err)
+	}
+	extractor, ok := format.(archives.Extractor)
+	if !ok {
+		return fmt.Errorf("the archive format %q is not supported", format.Extension())
+	}
+	a := &arch{targetFolder: targetFolder}
+	if extractErr := extractor.Extract(context.Background(), stream, a.extractArchiveFileHandler(folderName)); extractErr != nil {
+		return fmt.Errorf("unable to extract the archive: %w", extractErr)
+	}
+	return nil
+}
+
 func (a *arch) extractArchiveFileHandler(archiveName string) archives.FileHandler {
 	return func(_ context.Context, f archives.FileInfo) error {
 		if f.IsDir() {
 			return nil
 		}
+		archiveFolder := filepath.Join(a.targetFolder, archiveName)
+		// Ensure a file of the archive cannot be written outside the folder of the archive, using a path like "../../file".
+		if !strings.HasPrefix(filepath.Join(archiveFolder, f.NameInArchive), archiveFolder+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid file path %q in the archive", f.NameInArchive)
+		}
 		currentDir, _ := filepath.Split(f.NameInArchive)
 		if mkdirErr := os.MkdirAll(filepath.Join(a.targetFolder, archiveName, currentDir), 0750); mkdirErr != nil {
 			return fmt.Errorf("unable to create directory %q: %w", currentDir, mkdirErr)
@@ -98,10 +139,15 @@ func (a *arch) extractArchiveFileHandler(archiveName string) archives.FileHandle
 				logrus.WithError(closeErr).Error("unable to close archive file stream")
 			}
 		}()
-		respBytes, err := io.ReadAll(stream)
+		remaining := maxExtractedSize - a.extracted
+		respBytes, err := io.ReadAll(io.LimitReader(stream, remaining+1))
 		if err != nil {
 			return fmt.Errorf("unable to read the file %q: %w", f.NameInArchive, err)
 		}
+		if int64(len(respBytes)) > remaining {
+			return fmt.Errorf("the content of the archive exceeds the maximum size of %d bytes", maxExtractedSize)
+		}
+		a.extracted += int64(len(respBytes))
 		if writeErr := os.WriteFile(filepath.Join(a.targetFolder, archiveName, f.NameInArchive), respBytes, 0644); writeErr != nil { // nolint: gosec
 			return fmt.Errorf("unable to write the file %q: %w", f.NameInArchive, writeErr)
 		}
@@ -64,6 +64,9 @@ func (p *pluginFile) LoadDevPlugin(plugins []v1.PluginInDevelopment) error {
 			if pluginMigrateLoadErr := p.mig.LoadDevPlugin(plg.AbsolutePath, pluginModule); pluginMigrateLoadErr != nil {
 				return apiinterface.HandleBadRequestError(fmt.Sprintf("failed to load plugin migration: %s", pluginMigrateLoadErr))
 			}
+			if pluginExportLoadErr := p.exp.LoadDevPlugin(plg.AbsolutePath, pluginModule); pluginExportLoadErr != nil {
+				return apiinterface.HandleBadRequestError(fmt.Sprintf("failed to load plugin export: %s", pluginExportLoadErr))
+			}
 		}
 		p.mutex.Lock()
 		p.devLoaded.Add(plg.Name, pluginModule.Metadata, pluginLoaded)
@@ -90,6 +93,9 @@ func (p *pluginFile) RefreshDevPlugin(metadata plugin.ModuleMetadata) error {
 	if err := p.mig.LoadDevPlugin(plg.DevEnvironment.AbsolutePath, plg.Module); err != nil {
 		return apiinterface.HandleBadRequestError(fmt.Sprintf("failed to refresh plugin migration schema: %s", err))
 	}
+	if err := p.exp.LoadDevPlugin(plg.DevEnvironment.AbsolutePath, plg.Module); err != nil {
+		return apiinterface.HandleBadRequestError(fmt.Sprintf("failed to refresh plugin export script: %s", err))
+	}
 	logrus.Infof("plugin %q has been refreshed in development mode", metadata.Name)
 	return nil
 }
@@ -103,6 +109,7 @@ func (p *pluginFile) UnLoadDevPlugin(metadata plugin.ModuleMetadata) error {
 	}
 	p.sch.UnloadDevPlugin(plg.Module)
 	p.mig.UnLoadDevPlugin(plg.Module)
+	p.exp.UnLoadDevPlugin(plg.Module)
 	p.devLoaded.Remove(metadata.Name, metadata)
 	p.mutex.Unlock()
 	logrus.Debugf("plugin %q has been unloaded from development mode", metadata.Name)
@@ -0,0 +1,521 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/dashboard"
+	"github.com/perses/spec/go/dashboard/variable"
+)
+
+const (
+	defaultDuration      = "1h"
+	grafanaNow           = "now"
+	grafanaLinkType      = "link"
+	grafanaSchemaVersion = 39
+	grafanaGridWidth     = 24
+	grafanaRowType       = "row"
+	grafanaTextType      = "text"
+	grafanaCustomType    = "custom"
+	grafanaTextboxType   = "textbox"
+	grafanaConstantType  = "constant"
+	// grafanaHideVariable is the value used by Grafana to hide both the label and the value of a variable.
+	grafanaHideVariable = 2
+)
+
+// grafanaDurationRegexp matches the durations Grafana understands in a relative time range, like now-6h.
+var grafanaDurationRegexp = regexp.MustCompile(`^[0-9]+[smhdwy]$`)
+
+// grafanaSort gives the Grafana sort option corresponding to the Perses one.
+var grafanaSort = map[variable.Sort]int{
+	variable.SortNone:                            0,
+	variable.SortAlphabeticalAsc:                 1,
+	variable.SortAlphabeticalDesc:                2,
+	variable.SortNumericalAsc:                    3,
+	variable.SortNumericalDesc:                   4,
+	variable.SortAlphabeticalCaseInsensitiveAsc:  5,
+	variable.SortAlphabeticalCaseInsensitiveDesc: 6,
+}
+
+// GrafanaDashboard is the Grafana dashboard resulting from the export of a Perses dashboard.
+// The panels and the variables are kept as generic objects, as most of their fields are provided by the export scripts of the plugins.
+type GrafanaDashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description,omitempty"`
+	Tags          []string          `json:"tags"`
+	Time          GrafanaTimeRange  `json:"time"`
+	Refresh       string            `json:"refresh,omitempty"`
+	Links         []GrafanaLink     `json:"links"`
+	Panels        []map[string]any  `json:"panels"`
+	Templating    GrafanaTemplating `json:"templating"`
+	SchemaVersion int               `json:"schemaVersion"`
+}
+
+type GrafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type GrafanaLink struct {
+	Title       string `json:"title"`
+	Type        string `json:"type,omitempty"`
+	URL         string `json:"url"`
+	Tooltip     string `json:"tooltip,omitempty"`
+	TargetBlank bool   `json:"targetBlank,omitempty"`
+}
+
+type GrafanaTemplating struct {
+	List []map[string]any `json:"list"`
+}
+
+type GrafanaGridPosition struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"w"`
+	Height int `json:"h"`
+}
+
+// warnings gathers the reasons why some parts of a Perses dashboard couldn't be exported.
+type warnings []string
+
+func (w *warnings) add(format string, args ...any) {
+	*w = append(*w, fmt.Sprintf(format, args...))
+}
+
+func (e *completeExport) exportDashboard(persesDashboard *v1.Dashboard) (*GrafanaDashboard, *modelAPI.MigrationReport, error) {
+	result := &GrafanaDashboard{
+		UID:           persesDashboard.Metadata.Name,
+		Title:         persesDashboard.Metadata.Name,
+		Tags:          []string{},
+		Time:          exportTimeRange(persesDashboard.Spec.Duration),
+		Refresh:       string(persesDashboard.Spec.RefreshInterval),
+		Links:         exportLinks(persesDashboard.Spec.Links, grafanaLinkType),
+		Panels:        []map[string]any{},
+		Templating:    GrafanaTemplating{List: []map[string]any{}},
+		SchemaVersion: grafanaSchemaVersion,
+	}
+	if display := persesDashboard.Spec.Display; display != nil {
+		if len(display.Name) > 0 {
+			result.Title = display.Name
+		}
+		result.Description = display.Description
+	}
+	for tag := range persesDashboard.Metadata.Tags {
+		result.Tags = append(result.Tags, tag)
+	}
+	sort.Strings(result.Tags)
+
+	report := modelAPI.NewMigrationReport()
+	var w warnings
+	if len(persesDashboard.Spec.Datasources) > 0 {
+		w.add("the datasources defined in the dashboard are not exported, they must be created in Grafana")
+	}
+	if len(persesDashboard.Spec.Annotations) > 0 {
+		w.add("the annotations of the dashboard are not exported")
+	}
+	result.Panels = e.exportLayouts(persesDashboard.Spec.Spec, report, &w)
+	result.Templating.List = e.exportVariables(persesDashboard.Spec.Variables, report)
+	report.Warnings = w
+	return result, report, nil
+}
+
+// exportTimeRange converts a Perses duration to a Grafana time range relative to now (like now-6h).
+func exportTimeRange(duration common.DurationString) GrafanaTimeRange {
+	from := string(duration)
+	if len(from) == 0 {
+		from = defaultDuration
+	}
+	if !grafanaDurationRegexp.MatchString(from) {
+		// Grafana understands only a single unit, so a duration like 1h30m is converted to 90m.
+		from = defaultDuration
+		if parsed, err := common.ParseDuration(string(duration)); err == nil {
+			from = singleUnitDuration(time.Duration(parsed))
+		}
+	}
+	return GrafanaTimeRange{
+		From: fmt.Sprintf("%s-%s", grafanaNow, from),
+		To:   grafanaNow,
+	}
+}
+
+func singleUnitDuration(d time.Duration) string {
+	for _, unit := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{name: "d", duration: 24 * time.Hour},
+		{name: "h", duration: time.Hour},
+		{name: "m", duration: time.Minute},
+	} {
+		if d%unit.duration == 0 {
+			return fmt.Sprintf("%d%s", d/unit.duration, unit.name)
+		}
+	}
+	return fmt.Sprintf("%ds", max(int64(d/time.Second), 1))
+}
+
+func exportLinks(links []dashboard.Link, linkType string) []GrafanaLink {
+	result := []GrafanaLink{}
+	for _, link := range links {
+		result = append(result, GrafanaLink{
+			Title:       link.Name,
+			Type:        linkType,
+			URL:         link.URL,
+			Tooltip:     link.Tooltip,
+			TargetBlank: link.TargetBlank,
+		})
+	}
+	return result
+}
+
+// exportLayouts converts the grid layouts to Grafana rows, followed by their panels.
+// The panels of a collapsed row are nested in the row, like Grafana does.
+func (e *completeExport) exportLayouts(spec dashboard.Spec, report *modelAPI.MigrationReport, w *warnings) []map[string]any {
+	result := []map[string]any{}
+	exported := make(map[string]bool)
+	id := 0
+	y := 0
+	for i, layout := range spec.Layouts {
+		grid, ok := layout.Spec.(*dashboard.GridLayoutSpec)
+		if !ok {
+			w.add("the layout %d of kind %q is not supported", i, layout.Kind)
+			continue
+		}
+		// A grid without a title at the beginning of the dashboard is rendered without header,
+		// like the panels placed before the first row in Grafana.
+		var row map[string]any
+		if i > 0 || grid.Display != nil {
+			id++
+			row = map[string]any{
+				"id":        id,
+				"type":      grafanaRowType,
+				"title":     "",
+				"collapsed": false,
+				"gridPos":   GrafanaGridPosition{X: 0, Y: y, Width: grafanaGridWidth, Height: 1},
+				"panels":    []map[string]any{},
+			}
+			if grid.Display != nil {
+				row["title"] = grid.Display.Title
+				row["collapsed"] = grid.Display.Collapse != nil && !grid.Display.Collapse.Open
+			}
+			if len(grid.RepeatVariable) > 0 {
+				row["repeat"] = grid.RepeatVariable
+			}
+			result = append(result, row)
+			y++
+		}
+		bottom := y
+		var panels []map[string]any
+		for _, item := range grid.Items {
+			key := panelKey(item.Content)
+			panel, found := spec.Panels[key]
+			if !found {
+				w.add("the layout %d references the panel %q that doesn't exist", i, key)
+				continue
+			}
+			id++
+			gridPosition := GrafanaGridPosition{X: item.X, Y: y + item.Y, Width: item.Width, Height: item.Height}
+			panels = append(panels, e.exportPanel(key, panel, id, gridPosition, report))
+			exported[key] = true
+			bottom = max(bottom, gridPosition.Y+gridPosition.Height)
+		}
+		if row != nil && row["collapsed"] == true {
+			row["panels"] = panels
+		} else {
+			result = append(result, panels...)
+			y = bottom
+		}
+	}
+	// Sorting the keys gives a stable report.
+	var keys []string
+	for key := range spec.Panels {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if exported[key] {
+			continue
+		}
+		report.Add(modelAPI.MigrationItem{
+			Kind:   modelAPI.MigrationItemKindPanel,
+			Name:   panelName(key, spec.Panels[key]),
+			Ref:    panelRef(key),
+			Plugin: spec.Panels[key].Spec.Plugin.Kind,
+			Status: modelAPI.MigrationStatusDropped,
+			Reason: "the panel is not displayed in any layout",
+		})
+	}
+	return result
+}
+
+func panelKey(ref *common.JSONRef) string {
+	if ref == nil {
+		return ""
+	}
+	// The path is set when the dashboard is decoded, otherwise we read the reference that should be like #/spec/panels/<key>.
+	if len(ref.Path) == 3 {
+		return ref.Path[2]
+	}
+	return strings.TrimPrefix(ref.Ref, "#/spec/panels/")
+}
+
+func panelRef(key string) string {
+	return fmt.Sprintf("#/spec/panels/%s", key)
+}
+
+func panelName(key string, panel *dashboard.Panel) string {
+	if panel.Spec.Display != nil && len(panel.Spec.Display.Name) > 0 {
+		return panel.Spec.Display.Name
+	}
+	return key
+}
+
+func (e *completeExport) exportPanel(key string, panel *dashboard.Panel, id int, gridPosition GrafanaGridPosition, report *modelAPI.MigrationReport) map[string]any {
+	item := modelAPI.MigrationItem{
+		Kind:   modelAPI.MigrationItemKindPanel,
+		Name:   panelName(key, panel),
+		Ref:    panelRef(key),
+		Plugin: panel.Spec.Plugin.Kind,
+	}
+	result, reason := e.executeScript(plugin.KindPanel, panel.Spec.Plugin)
+	if result == nil {
+		result = map[string]any{
+			"type": grafanaTextType,
+			"options": map[string]any{
+				"mode":    "markdown",
+				"content": fmt.Sprintf("**Export of the Perses panel %s to Grafana not supported !**", panel.Spec.Plugin.Kind),
+			},
+		}
+		item.Status = modelAPI.MigrationStatusFallback
+		item.Reason = reason
+	} else {
+		item.Status = modelAPI.MigrationStatusMigrated
+	}
+	item.GrafanaType, _ = result["type"].(string)
+	report.Add(item)
+
+	// The fields managed by Perses take precedence over the ones returned by the export script.
+	result["id"] = id
+	result["title"] = ""
+	result["gridPos"] = gridPosition
+	if panel.Spec.Display != nil {
+		result["title"] = panel.Spec.Display.Name
+		if len(panel.Spec.Display.Description) > 0 {
+			result["description"] = panel.Spec.Display.Description
+		}
+	}
+	if len(panel.Spec.Links) > 0 {
+		result["links"] = exportLinks(panel.Spec.Links, "")
+	}
+	if item.Status != modelAPI.MigrationStatusMigrated {
+		// The queries are not exported, as there is no panel to display them.
+		for i, query := range panel.Spec.Queries {
+			report.Add(modelAPI.MigrationItem{
+				Kind:   modelAPI.MigrationItemKindQuery,
+				Name:   grafanaRefID(i),
+				Ref:    fmt.Sprintf("%s/spec/queries/%d", item.Ref, i),
+				Plugin: query.Spec.Plugin.Kind,
+				Status: modelAPI.MigrationStatusDropped,
+				Reason: "the panel of the query has not been exported",
+			})
+		}
+		return result
+	}
+	if len(panel.Spec.Queries) > 0 {
+		result["targets"] = e.exportQueries(panel.Spec.Queries, item.Ref, report)
+	}
+	return result
+}
+
+func (e *completeExport) exportQueries(queries []dashboard.Query, ref string, report *modelAPI.MigrationReport) []map[string]any {
+	result := []map[string]any{}
+	for i, query := range queries {
+		item := modelAPI.MigrationItem{
+			Kind:   modelAPI.MigrationItemKindQuery,
+			Name:   grafanaRefID(i),
+			Ref:    fmt.Sprintf("%s/spec/queries/%d", ref, i),
+			Plugin: query.Spec.Plugin.Kind,
+			Status: modelAPI.MigrationStatusMigrated,
+		}
+		target, reason := e.executeScript(plugin.Kind(query.Kind), query.Spec.Plugin)
+		if target == nil {
+			item.Status = modelAPI.MigrationStatusDropped
+			item.Reason = reason
+			report.Add(item)
+			continue
+		}
+		target["refId"] = item.Name
+		item.GrafanaType = datasourceType(target)
+		report.Add(item)
+		result = append(result, target)
+	}
+	return result
+}
+
+// grafanaRefID gives the refId Grafana would give to the query at the given position: A, B, ..., Z, AA, AB, etc.
+func grafanaRefID(i int) string {
+	refID := string(rune('A' + i%26))
+	if i >= 26 {
+		refID = grafanaRefID(i/26-1) + refID
+	}
+	return refID
+}
+
+func datasourceType(target map[string]any) string {
+	if datasource, ok := target["datasource"].(map[string]any); ok {
+		datasourceType, _ := datasource["type"].(string)
+		return datasourceType
+	}
+	return ""
+}
+
+func (e *completeExport) exportVariables(variables []dashboard.Variable, report *modelAPI.MigrationReport) []map[string]any {
+	result := []map[string]any{}
+	for i, v := range variables {
+		item := modelAPI.MigrationItem{
+			Kind:   modelAPI.MigrationItemKindVariable,
+			Ref:    fmt.Sprintf("#/spec/variables/%d", i),
+			Status: modelAPI.MigrationStatusMigrated,
+		}
+		var grafanaVariable map[string]any
+		switch spec := v.Spec.(type) {
+		case *dashboard.ListVariableSpec:
+			item.Name = spec.Name
+			item.Plugin = spec.Plugin.Kind
+			grafanaVariable = e.exportListVariable(spec, &item)
+		case *dashboard.TextVariableSpec:
+			item.Name = spec.Name
+			item.Plugin = string(variable.KindText)
+			grafanaVariable = exportTextVariable(spec)
+		default:
+			item.Status = modelAPI.MigrationStatusDropped
+			item.Reason = fmt.Sprintf("the variable of kind %q is not supported", v.Kind)
+			report.Add(item)
+			continue
+		}
+		item.GrafanaType, _ = grafanaVariable["type"].(string)
+		report.Add(item)
+		result = append(result, grafanaVariable)
+	}
+	return result
+}
+
+func (e *completeExport) exportListVariable(spec *dashboard.ListVariableSpec, item *modelAPI.MigrationItem) map[string]any {
+	var values []string
+	if spec.DefaultValue != nil {
+		values = spec.DefaultValue.SliceValues
+		if len(spec.DefaultValue.SingleValue) > 0 {
+			values = []string{spec.DefaultValue.SingleValue}
+		}
+	}
+	result, reason := e.executeScript(plugin.KindVariable, spec.Plugin)
+	if result == nil {
+		// A custom variable with the default values keeps the dashboard usable.
+		result = map[string]any{
+			"type":  grafanaCustomType,
+			"query": strings.Join(values, ","),
+		}
+		item.Status = modelAPI.MigrationStatusFallback
+		item.Reason = reason
+	}
+	// The fields managed by Perses take precedence over the ones returned by the export script.
+	result["name"] = spec.Name
+	result["multi"] = spec.AllowMultiple
+	result["includeAll"] = spec.AllowAllValue
+	if len(spec.CustomAllValue) > 0 {
+		result["allValue"] = spec.CustomAllValue
+	}
+	if len(spec.CapturingRegexp) > 0 {
+		result["regex"] = spec.CapturingRegexp
+	}
+	if spec.Sort != nil {
+		result["sort"] = grafanaSort[*spec.Sort]
+	}
+	if len(values) > 0 {
+		result["current"] = map[string]any{
+			"text":  strings.Join(values, " + "),
+			"value": currentValue(values, spec.AllowMultiple),
+		}
+	}
+	exportVariableDisplay(spec.Display, result)
+	return result
+}
+
+func currentValue(values []string, allowMultiple bool) any {
+	if allowMultiple {
+		return values
+	}
+	return values[0]
+}
+
+func exportTextVariable(spec *dashboard.TextVariableSpec) map[string]any {
+	result := map[string]any{
+		"name":  spec.Name,
+		"type":  grafanaTextboxType,
+		"query": spec.Value,
+		"current": map[string]any{
+			"text":  spec.Value,
+			"value": spec.Value,
+		},
+	}
+	exportVariableDisplay(spec.Display, result)
+	if spec.Constant {
+		result["type"] = grafanaConstantType
+		result["hide"] = grafanaHideVariable
+	}
+	return result
+}
+
+func exportVariableDisplay(display *variable.Display, result map[string]any) {
+	if display == nil {
+		return
+	}
+	if len(display.Name) > 0 {
+		result["label"] = display.Name
+	}
+	if len(display.Description) > 0 {
+		result["description"] = display.Description
+	}
+	if display.Hidden {
+		result["hide"] = grafanaHideVariable
+	}
+}
+
+// executeScript executes the export script of the given plugin.
+// When the plugin couldn't be exported, it returns the reason instead.
+func (e *completeExport) executeScript(kind plugin.Kind, plg common.Plugin) (map[string]any, string) {
+	instance, ok := e.getScript(kind, plg.Kind)
+	if !ok {
+		return nil, fmt.Sprintf("no export script available for the plugin %q", plg.Kind)
+	}
+	result, err := executeCuelangScript(instance, plg)
+	if err != nil {
+		return nil, fmt.Sprintf("the export script of the plugin %s failed: %s", plg.Kind, err)
+	}
+	if result == nil {
+		return nil, fmt.Sprintf("the export script of the plugin %s didn't convert it", plg.Kind)
+	}
+	return result, ""
+}
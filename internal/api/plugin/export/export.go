@@ -0,0 +1,258 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/perses/perses/internal/api/plugin/schema"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/spec/go/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	exportFolder = "export"
+	exportFile   = "export.cue"
+	pluginDefID  = "#plugin"
+)
+
+var kindRegexp = regexp.MustCompile(`(?m)kind\s*:\s*"(\w+)"`)
+
+func LoadExportSchema(schemaPath string) (*build.Instance, error) {
+	return schema.LoadSchemaInstance(schemaPath, "export")
+}
+
+// Load is loading the export scripts of the given plugin module, with the kind of the plugin they are exporting.
+func Load(pluginPath string, moduleSpec plugin.ModuleSpec) ([]schema.LoadSchema, error) {
+	var schemas []schema.LoadSchema
+	err := filepath.WalkDir(filepath.Join(pluginPath, moduleSpec.SchemasPath), func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || d.Name() != exportFolder {
+			return nil
+		}
+		// At this point, we are in the "export" directory.
+		exportFilePath := filepath.Join(currentPath, exportFile)
+		data, readErr := os.ReadFile(exportFilePath) //nolint: gosec
+		if readErr != nil {
+			return readErr
+		}
+		// We are verifying if the package is a package export. Otherwise, we won't be able to use it.
+		if !strings.Contains(string(data), "package export") {
+			return fs.SkipDir
+		}
+		instance, schemaErr := LoadExportSchema(currentPath)
+		if schemaErr != nil {
+			return schemaErr
+		}
+		// Like for the migration scripts, the plugin exported by the script is found by reading the script,
+		// as a plugin module can contain multiple plugins.
+		pl := getPlugin(string(data), moduleSpec.Plugins)
+		if pl == nil {
+			return fmt.Errorf("unable to find the plugin kind associated to the export file %q", exportFilePath)
+		}
+		schemas = append(schemas, schema.LoadSchema{
+			Kind:     pl.Kind,
+			Name:     pl.Spec.Name,
+			Instance: instance,
+		})
+		return fs.SkipDir
+	})
+	return schemas, err
+}
+
+func getPlugin(script string, plugins []plugin.Plugin) *plugin.Plugin {
+	for _, group := range kindRegexp.FindAllStringSubmatch(script, -1) {
+		if len(group) < 2 {
+			continue
+		}
+		for _, plg := range plugins {
+			if plg.Spec.Name == group[1] {
+				return &plg
+			}
+		}
+	}
+	return nil
+}
+
+// executeCuelangScript executes a CUE export script against a Perses plugin.
+// It returns the fields to set in the Grafana object, or nil when the script didn't convert the plugin.
+func executeCuelangScript(cueScript *build.Instance, plg common.Plugin) (map[string]any, error) {
+	pluginData, err := plg.JSONMarshal()
+	if err != nil {
+		return nil, err
+	}
+	ctx := cuecontext.New()
+	pluginValue := ctx.CompileString(fmt.Sprintf("%s: _", pluginDefID))
+	pluginValue = pluginValue.FillPath(cue.ParsePath(pluginDefID), ctx.CompileBytes(pluginData))
+
+	finalVal := pluginValue.Unify(ctx.BuildInstance(cueScript))
+	if unifyErr := finalVal.Err(); unifyErr != nil {
+		logrus.WithError(unifyErr).Debugf("unable to export the plugin %q", plg.Kind)
+		return nil, unifyErr
+	}
+	if finalVal.IsNull() {
+		return nil, nil
+	}
+	data, err := finalVal.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if unmarshalErr := json.Unmarshal(data, &result); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+type Export interface {
+	Load(pluginPath string, module v1.PluginModule) error
+	Unload(module v1.PluginModule)
+	LoadDevPlugin(pluginPath string, module v1.PluginModule) error
+	UnLoadDevPlugin(module v1.PluginModule)
+	// Export converts a Perses dashboard to a Grafana dashboard. It also returns a report telling how each panel,
+	// query and variable has been exported, and why some parts of the Perses dashboard couldn't be exported.
+	Export(persesDashboard *v1.Dashboard) (*GrafanaDashboard, *modelAPI.MigrationReport, error)
+}
+
+func New() Export {
+	return &completeExport{
+		exp:    newExp(),
+		devExp: newExp(),
+	}
+}
+
+type completeExport struct {
+	exp    *exp
+	devExp *exp
+	mutex  sync.RWMutex
+}
+
+func (e *completeExport) Load(pluginPath string, module v1.PluginModule) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.exp.load(pluginPath, module)
+}
+
+func (e *completeExport) Unload(module v1.PluginModule) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, plg := range module.Spec.Plugins {
+		e.exp.remove(plg.Kind, plg.Spec.Name)
+	}
+}
+
+func (e *completeExport) LoadDevPlugin(pluginPath string, module v1.PluginModule) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.devExp.load(pluginPath, module)
+}
+
+func (e *completeExport) UnLoadDevPlugin(module v1.PluginModule) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, plg := range module.Spec.Plugins {
+		e.devExp.remove(plg.Kind, plg.Spec.Name)
+	}
+}
+
+func (e *completeExport) Export(persesDashboard *v1.Dashboard) (*GrafanaDashboard, *modelAPI.MigrationReport, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.exportDashboard(persesDashboard)
+}
+
+// getScript returns the export script of the plugin, looking first at the plugins loaded from the dev environment.
+func (e *completeExport) getScript(kind plugin.Kind, name string) (*build.Instance, bool) {
+	if instance, ok := e.devExp.get(kind, name); ok {
+		return instance, true
+	}
+	return e.exp.get(kind, name)
+}
+
+type exp struct {
+	// The key of each map is the name of the plugin (e.g., TimeSeriesChart, PrometheusTimeSeriesQuery).
+	// It implies we won't allow having two export scripts for the same plugin.
+	panels    map[string]*build.Instance
+	queries   map[string]*build.Instance
+	variables map[string]*build.Instance
+}
+
+func newExp() *exp {
+	return &exp{
+		panels:    make(map[string]*build.Instance),
+		queries:   make(map[string]*build.Instance),
+		variables: make(map[string]*build.Instance),
+	}
+}
+
+func (e *exp) load(pluginPath string, module v1.PluginModule) error {
+	schemas, err := Load(pluginPath, module.Spec)
+	if err != nil {
+		return err
+	}
+	for _, sch := range schemas {
+		scripts := e.scripts(sch.Kind)
+		if scripts == nil {
+			logrus.Infof("export script of the plugin %q ignored, as a plugin of kind %q cannot be exported to Grafana", sch.Name, sch.Kind)
+			continue
+		}
+		scripts[sch.Name] = sch.Instance
+	}
+	return nil
+}
+
+func (e *exp) scripts(kind plugin.Kind) map[string]*build.Instance {
+	switch {
+	case kind == plugin.KindPanel:
+		return e.panels
+	case kind == plugin.KindVariable:
+		return e.variables
+	case kind.IsQuery() || kind == plugin.KindQuery:
+		return e.queries
+	}
+	return nil
+}
+
+func (e *exp) get(kind plugin.Kind, name string) (*build.Instance, bool) {
+	scripts := e.scripts(kind)
+	if scripts == nil {
+		return nil, false
+	}
+	instance, ok := scripts[name]
+	return instance, ok
+}
+
+func (e *exp) remove(kind plugin.Kind, name string) {
+	if scripts := e.scripts(kind); scripts != nil {
+		delete(scripts, name)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// /!\ this file is not located under export/ in order to avoid an import cycle
+
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	testUtils "github.com/perses/perses/internal/test"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExp_Export(t *testing.T) {
+	pl := LoadTestPlugins()
+	persesDashboard := &modelV1.Dashboard{}
+	if err := json.Unmarshal([]byte(`{
+		"kind": "Dashboard",
+		"metadata": {"name": "test", "project": "perses", "tags": ["prod", "app"]},
+		"spec": {
+			"display": {"name": "Test", "description": "A dashboard to export"},
+			"duration": "1h30m",
+			"refreshInterval": "30s",
+			"links": [{"name": "Docs", "url": "https://perses.dev", "targetBlank": true}],
+			"datasources": {
+				"exotic": {"default": true, "plugin": {"kind": "ExoticTSDB", "spec": {"url": "http://localhost"}}}
+			},
+			"variables": [
+				{"kind": "ListVariable", "spec": {"name": "env", "display": {"name": "Environment"}, "allowAllValue": true, "allowMultiple": true, "defaultValue": ["dev", "prod"], "sort": "alphabetical-asc", "plugin": {"kind": "SomeVariable", "spec": {"values": ["dev", "prod"]}}}},
+				{"kind": "ListVariable", "spec": {"name": "job", "defaultValue": "api", "plugin": {"kind": "PrometheusLabelValuesVariable", "spec": {"labelName": "job"}}}},
+				{"kind": "TextVariable", "spec": {"name": "cluster", "value": "eu", "constant": true}}
+			],
+			"panels": {
+				"latency": {"kind": "Panel", "spec": {"display": {"name": "Latency", "description": "Latency of the API"}, "plugin": {"kind": "FooChart", "spec": {"field": "latency"}}, "queries": [
+					{"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "ExoticQuery", "spec": {"query": "latency"}}}},
+					{"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "PrometheusTimeSeriesQuery", "spec": {"query": "up"}}}}
+				]}},
+				"errors": {"kind": "Panel", "spec": {"display": {"name": "Errors"}, "plugin": {"kind": "BarChart", "spec": {"nested": {"field": "errors"}}}, "queries": [
+					{"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "ExoticQuery", "spec": {"query": "errors"}}}}
+				]}},
+				"saturation": {"kind": "Panel", "spec": {"display": {"name": "Saturation"}, "plugin": {"kind": "FooChart", "spec": {"field": "saturation"}}}},
+				"unused": {"kind": "Panel", "spec": {"display": {"name": "Unused"}, "plugin": {"kind": "FooChart", "spec": {"field": "unused"}}}}
+			},
+			"layouts": [
+				{"kind": "Grid", "spec": {"items": [
+					{"x": 0, "y": 0, "width": 12, "height": 8, "content": {"$ref": "#/spec/panels/latency"}},
+					{"x": 12, "y": 0, "width": 12, "height": 8, "content": {"$ref": "#/spec/panels/errors"}}
+				]}},
+				{"kind": "Grid", "spec": {"display": {"title": "Details", "collapse": {"open": false}}, "repeatVariable": "env", "items": [
+					{"x": 0, "y": 0, "width": 24, "height": 6, "content": {"$ref": "#/spec/panels/saturation"}}
+				]}}
+			]
+		}
+	}`), persesDashboard); err != nil {
+		t.Fatal(err)
+	}
+	grafanaDashboard, report, err := pl.Export().Export(persesDashboard)
+	if !assert.NoError(t, err) {
+		return
+	}
+	expectedDashboard := `{
+		"uid": "test",
+		"title": "Test",
+		"description": "A dashboard to export",
+		"tags": ["app", "prod"],
+		"time": {"from": "now-90m", "to": "now"},
+		"refresh": "30s",
+		"links": [{"title": "Docs", "type": "link", "url": "https://perses.dev", "targetBlank": true}],
+		"panels": [
+			{
+				"id": 1,
+				"type": "timeseries",
+				"title": "Latency",
+				"description": "Latency of the API",
+				"gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+				"options": {"legend": {"showLegend": true}},
+				"targets": [{"refId": "A", "datasource": {"type": "exotic-tsdb"}, "expr": "latency"}]
+			},
+			{
+				"id": 2,
+				"type": "text",
+				"title": "Errors",
+				"gridPos": {"x": 12, "y": 0, "w": 12, "h": 8},
+				"options": {"mode": "markdown", "content": "**Export of the Perses panel BarChart to Grafana not supported !**"}
+			},
+			{
+				"id": 3,
+				"type": "row",
+				"title": "Details",
+				"collapsed": true,
+				"repeat": "env",
+				"gridPos": {"x": 0, "y": 8, "w": 24, "h": 1},
+				"panels": [
+					{
+						"id": 4,
+						"type": "timeseries",
+						"title": "Saturation",
+						"gridPos": {"x": 0, "y": 9, "w": 24, "h": 6},
+						"options": {"legend": {"showLegend": true}}
+					}
+				]
+			}
+		],
+		"templating": {
+			"list": [
+				{"name": "env", "label": "Environment", "type": "custom", "query": "dev,prod", "multi": true, "includeAll": true, "sort": 1, "current": {"text": "dev + prod", "value": ["dev", "prod"]}},
+				{"name": "job", "type": "custom", "query": "api", "multi": false, "includeAll": false, "current": {"text": "api", "value": "api"}},
+				{"name": "cluster", "type": "constant", "query": "eu", "hide": 2, "current": {"text": "eu", "value": "eu"}}
+			]
+		},
+		"schemaVersion": 39
+	}`
+	assert.JSONEq(t, expectedDashboard, string(testUtils.JSONMarshalStrict(grafanaDashboard)))
+
+	expectedReport := &modelAPI.MigrationReport{
+		Summary: modelAPI.MigrationSummary{
+			Migrated: 5,
+			Fallback: 2,
+			Dropped:  3,
+			Score:    50,
+		},
+		Items: []modelAPI.MigrationItem{
+			{Kind: modelAPI.MigrationItemKindPanel, Name: "Latency", Ref: "#/spec/panels/latency", GrafanaType: "timeseries", Status: modelAPI.MigrationStatusMigrated, Plugin: "FooChart"},
+			{Kind: modelAPI.MigrationItemKindQuery, Name: "A", Ref: "#/spec/panels/latency/spec/queries/0", GrafanaType: "exotic-tsdb", Status: modelAPI.MigrationStatusMigrated, Plugin: "ExoticQuery"},
+			{Kind: modelAPI.MigrationItemKindQuery, Name: "B", Ref: "#/spec/panels/latency/spec/queries/1", Status: modelAPI.MigrationStatusDropped, Plugin: "PrometheusTimeSeriesQuery", Reason: `no export script available for the plugin "PrometheusTimeSeriesQuery"`},
+			{Kind: modelAPI.MigrationItemKindPanel, Name: "Errors", Ref: "#/spec/panels/errors", GrafanaType: "text", Status: modelAPI.MigrationStatusFallback, Plugin: "BarChart", Reason: `no export script available for the plugin "BarChart"`},
+			{Kind: modelAPI.MigrationItemKindQuery, Name: "A", Ref: "#/spec/panels/errors/spec/queries/0", Status: modelAPI.MigrationStatusDropped, Plugin: "ExoticQuery", Reason: "the panel of the query has not been exported"},
+			{Kind: modelAPI.MigrationItemKindPanel, Name: "Saturation", Ref: "#/spec/panels/saturation", GrafanaType: "timeseries", Status: modelAPI.MigrationStatusMigrated, Plugin: "FooChart"},
+			{Kind: modelAPI.MigrationItemKindPanel, Name: "Unused", Ref: "#/spec/panels/unused", Status: modelAPI.MigrationStatusDropped, Plugin: "FooChart", Reason: "the panel is not displayed in any layout"},
+			{Kind: modelAPI.MigrationItemKindVariable, Name: "env", Ref: "#/spec/variables/0", GrafanaType: "custom", Status: modelAPI.MigrationStatusMigrated, Plugin: "SomeVariable"},
+			{Kind: modelAPI.MigrationItemKindVariable, Name: "job", Ref: "#/spec/variables/1", GrafanaType: "custom", Status: modelAPI.MigrationStatusFallback, Plugin: "PrometheusLabelValuesVariable", Reason: `no export script available for the plugin "PrometheusLabelValuesVariable"`},
+			{Kind: modelAPI.MigrationItemKindVariable, Name: "cluster", Ref: "#/spec/variables/2", GrafanaType: "constant", Status: modelAPI.MigrationStatusMigrated, Plugin: "TextVariable"},
+		},
+		Warnings: []string{"the datasources defined in the dashboard are not exported, they must be created in Grafana"},
+	}
+	assert.Equal(t, expectedReport, report)
+}
@@ -0,0 +1,287 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/plugin/export"
+	"github.com/perses/perses/internal/api/plugin/migrate"
+	"github.com/perses/perses/internal/api/plugin/schema"
+	"github.com/perses/perses/internal/cli/file"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// disabledFileName is the name of the file created in the folder of a plugin module when it is disabled.
+	// As long as this file exists, the plugin module is not loaded, even after a restart.
+	disabledFileName = ".disabled"
+	// installFolderName is the name of the folder where an uploaded archive is extracted before being validated.
+	installFolderName = "plugin"
+)
+
+// pluginNameRegexp is the format accepted for the name of an installed plugin.
+// The name is used to build the folder of the plugin, so it must not contain a path separator or start with a dot.
+var pluginNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// isInFolder returns true if path is a sub-path of folder.
+func isInFolder(folder string, path string) bool {
+	return strings.HasPrefix(filepath.Clean(path), filepath.Clean(folder)+string(os.PathSeparator))
+}
+
+func isDisabled(pluginPath string) bool {
+	exist, err := file.Exists(filepath.Join(pluginPath, disabledFileName))
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to check if the plugin in %q is disabled", pluginPath)
+		return false
+	}
+	return exist
+}
+
+// Install extracts the given plugin archive, validates it and loads it without requiring a restart.
+// The archive is validated in a temporary folder and is moved in the plugin folder only when the plugin module is valid.
+func (p *pluginFile) Install(r io.Reader) (*v1.PluginModule, error) {
+	tmpFolder, err := os.MkdirTemp(p.path, ".install-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the temporary folder to extract the plugin archive: %w", err)
+	}
+	defer func() {
+		if removeErr := os.RemoveAll(tmpFolder); removeErr != nil {
+			logrus.WithError(removeErr).Errorf("unable to remove the temporary folder %q", tmpFolder)
+		}
+	}()
+	if extractErr := extractStream(r, tmpFolder, installFolderName); extractErr != nil {
+		return nil, apiinterface.HandleBadRequestError(extractErr.Error())
+	}
+	tmpPluginPath := filepath.Join(tmpFolder, installFolderName)
+	module, validErr := p.validateArchive(tmpPluginPath)
+	if validErr != nil {
+		return nil, validErr
+	}
+
+	p.mutex.Lock()
+	if _, exist := p.loaded.Get(module.Metadata.Name, module.Metadata); exist {
+		p.mutex.Unlock()
+		return nil, apiinterface.HandleConflictError(fmt.Sprintf("plugin %q in version %q is already installed", module.Metadata.Name, module.Metadata.Version))
+	}
+	pluginPath := filepath.Join(p.path, fmt.Sprintf("%s-%s", module.Metadata.Name, module.Metadata.Version))
+	if !isInFolder(p.path, pluginPath) {
+		p.mutex.Unlock()
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("invalid plugin name %q", module.Metadata.Name))
+	}
+	if exist, existErr := file.Exists(pluginPath); existErr != nil || exist {
+		p.mutex.Unlock()
+		return nil, apiinterface.HandleConflictError(fmt.Sprintf("the folder %q already exists", filepath.Base(pluginPath)))
+	}
+	if renameErr := os.Rename(tmpPluginPath, pluginPath); renameErr != nil {
+		p.mutex.Unlock()
+		return nil, fmt.Errorf("unable to move the plugin %q in the plugin folder: %w", module.Metadata.Name, renameErr)
+	}
+	if _, loadErr := loadScripts(pluginPath, *module, p.sch, p.mig, p.exp); loadErr != nil {
+		p.unloadScripts(*module)
+		if removeErr := os.RemoveAll(pluginPath); removeErr != nil {
+			logrus.WithError(removeErr).Errorf("unable to remove the folder %q", pluginPath)
+		}
+		p.mutex.Unlock()
+		return nil, fmt.Errorf("unable to load the plugin %q: %w", module.Metadata.Name, loadErr)
+	}
+	p.loaded.Add(module.Metadata.Name, module.Metadata, &Loaded{
+		Module:    *module,
+		LocalPath: pluginPath,
+	})
+	p.mutex.Unlock()
+	logrus.Infof("plugin %q in version %q has been installed", module.Metadata.Name, module.Metadata.Version)
+	return module, p.storeLoadedList()
+}
+
+// validateArchive checks the plugin extracted in the given folder is a valid plugin module.
+// The schemas, the migration and the export scripts are loaded in dedicated services, so an invalid plugin never reaches the services in use.
+func (p *pluginFile) validateArchive(pluginPath string) (*v1.PluginModule, error) {
+	if validErr := IsRequiredFileExists(pluginPath, pluginPath, pluginPath); validErr != nil {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("the archive is not a valid plugin: %s", validErr))
+	}
+	manifest, readErr := ReadManifest(pluginPath)
+	if readErr != nil {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("unable to read the plugin manifest: %s", readErr))
+	}
+	if !pluginNameRegexp.MatchString(manifest.Name) {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("invalid plugin name %q, it must match %s", manifest.Name, pluginNameRegexp.String()))
+	}
+	version := manifest.Metadata.BuildInfo.Version
+	if !strings.HasPrefix(version, "v") {
+		version = fmt.Sprintf("v%s", version)
+	}
+	if !semver.IsValid(version) {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("plugin %q does not follow the semver convention for its version %q", manifest.Name, manifest.Metadata.BuildInfo.Version))
+	}
+	npmPackageData, readErr := ReadPackage(pluginPath)
+	if readErr != nil {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("unable to read the plugin package.json: %s", readErr))
+	}
+	signatureStatus, signatureErr := p.verifier.verify(manifest.Name, pluginPath)
+	if signatureErr != nil {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("invalid plugin signature: %s", signatureErr))
+	}
+	module := &v1.PluginModule{
+		Kind: v1.PluginModuleKind,
+		Metadata: plugin.ModuleMetadata{
+			Name:    manifest.Name,
+			Version: manifest.Metadata.BuildInfo.Version,
+		},
+		Spec: npmPackageData.Perses,
+		Status: &plugin.ModuleStatus{
+			IsLoaded:  true,
+			Signature: signatureStatus,
+		},
+	}
+	if msg, loadErr := loadScripts(pluginPath, *module, schema.New(), migrate.New(), export.New()); loadErr != nil {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("%s: %s", msg, loadErr))
+	}
+	return module, nil
+}
+
+// Uninstall unloads the plugin module and removes its folder.
+// Note that a plugin module extracted from an archive in the archive paths is extracted again at the next restart.
+func (p *pluginFile) Uninstall(metadata plugin.ModuleMetadata) error {
+	p.mutex.Lock()
+	loaded, ok := p.loaded.Get(metadata.Name, metadata)
+	if !ok {
+		p.mutex.Unlock()
+		return apiinterface.HandleNotFoundError(fmt.Sprintf("plugin %q not found", metadata.Name))
+	}
+	if !isInFolder(p.path, loaded.LocalPath) {
+		p.mutex.Unlock()
+		return apiinterface.HandleBadRequestError(fmt.Sprintf("plugin %q is not in the plugin folder and cannot be uninstalled", metadata.Name))
+	}
+	p.loaded.Remove(metadata.Name, loaded.Module.Metadata)
+	p.unloadScripts(loaded.Module)
+	p.mutex.Unlock()
+	if removeErr := os.RemoveAll(loaded.LocalPath); removeErr != nil {
+		return fmt.Errorf("unable to remove the folder of the plugin %q: %w", metadata.Name, removeErr)
+	}
+	logrus.Infof("plugin %q in version %q has been uninstalled", loaded.Module.Metadata.Name, loaded.Module.Metadata.Version)
+	return p.storeLoadedList()
+}
+
+// Enable loads again a plugin module previously disabled.
+func (p *pluginFile) Enable(metadata plugin.ModuleMetadata) error {
+	p.mutex.Lock()
+	loaded, ok := p.loaded.Get(metadata.Name, metadata)
+	if !ok {
+		p.mutex.Unlock()
+		return apiinterface.HandleNotFoundError(fmt.Sprintf("plugin %q not found", metadata.Name))
+	}
+	if !loaded.Module.Status.Disabled {
+		p.mutex.Unlock()
+		return nil
+	}
+	if removeErr := os.Remove(filepath.Join(loaded.LocalPath, disabledFileName)); removeErr != nil && !os.IsNotExist(removeErr) {
+		p.mutex.Unlock()
+		return fmt.Errorf("unable to enable the plugin %q: %w", metadata.Name, removeErr)
+	}
+	// The content of the plugin may have changed while it was disabled, so the signature is verified again.
+	signatureStatus, loadErr := p.verifier.verify(metadata.Name, loaded.LocalPath)
+	status := &plugin.ModuleStatus{IsLoaded: true, Signature: signatureStatus}
+	msg := "invalid plugin signature"
+	if loadErr == nil {
+		msg, loadErr = loadScripts(loaded.LocalPath, loaded.Module, p.sch, p.mig, p.exp)
+	}
+	if loadErr != nil {
+		logrus.WithError(loadErr).Error(msg)
+		status = &plugin.ModuleStatus{IsLoaded: false, Signature: signatureStatus, Error: msg}
+	}
+	p.updateStatus(loaded, status)
+	p.mutex.Unlock()
+	if storeErr := p.storeLoadedList(); storeErr != nil {
+		return storeErr
+	}
+	if loadErr != nil {
+		return apiinterface.HandleBadRequestError(fmt.Sprintf("%s: %s", msg, loadErr))
+	}
+	logrus.Infof("plugin %q in version %q has been enabled", loaded.Module.Metadata.Name, loaded.Module.Metadata.Version)
+	return nil
+}
+
+// Disable unloads the plugin module without removing its folder.
+// The plugin module remains disabled after a restart until it is enabled again.
+func (p *pluginFile) Disable(metadata plugin.ModuleMetadata) error {
+	p.mutex.Lock()
+	loaded, ok := p.loaded.Get(metadata.Name, metadata)
+	if !ok {
+		p.mutex.Unlock()
+		return apiinterface.HandleNotFoundError(fmt.Sprintf("plugin %q not found", metadata.Name))
+	}
+	if loaded.Module.Status.Disabled {
+		p.mutex.Unlock()
+		return nil
+	}
+	if writeErr := os.WriteFile(filepath.Join(loaded.LocalPath, disabledFileName), []byte{}, 0644); writeErr != nil { // nolint: gosec
+		p.mutex.Unlock()
+		return fmt.Errorf("unable to disable the plugin %q: %w", metadata.Name, writeErr)
+	}
+	p.unloadScripts(loaded.Module)
+	p.updateStatus(loaded, &plugin.ModuleStatus{IsLoaded: false, Disabled: true, Signature: loaded.Module.Status.Signature})
+	p.mutex.Unlock()
+	logrus.Infof("plugin %q in version %q has been disabled", loaded.Module.Metadata.Name, loaded.Module.Metadata.Version)
+	return p.storeLoadedList()
+}
+
+// updateStatus replaces the loaded plugin module by a copy holding the new status,
+// so the readers that already got the previous instance are not affected.
+// The mutex must be held by the caller.
+func (p *pluginFile) updateStatus(loaded *Loaded, status *plugin.ModuleStatus) {
+	updated := *loaded
+	updated.Module.Status = status
+	p.loaded.Add(updated.Module.Metadata.Name, updated.Module.Metadata, &updated)
+}
+
+// unloadScripts removes the schemas, the migration and the export scripts of the plugin module from the services.
+// The mutex must be held by the caller.
+func (p *pluginFile) unloadScripts(module v1.PluginModule) {
+	p.sch.Unload(module)
+	p.mig.Unload(module)
+	p.exp.Unload(module)
+	// The migration and export scripts are not versioned.
+	// If another version of the same plugin module is still loaded, its scripts need to be loaded again.
+	var latest *Loaded
+	for _, versions := range p.loaded {
+		for version, loaded := range versions {
+			if version == plugin.LatestVersion || loaded.Module.Metadata.Name != module.Metadata.Name ||
+				loaded.Module.Metadata.Version == module.Metadata.Version || !loaded.Module.Status.IsLoaded {
+				continue
+			}
+			if latest == nil || semver.Compare("v"+strings.TrimPrefix(latest.Module.Metadata.Version, "v"), "v"+strings.TrimPrefix(version, "v")) < 0 {
+				latest = loaded
+			}
+		}
+	}
+	if latest == nil || !IsSchemaRequired(latest.Module.Spec) {
+		return
+	}
+	if err := p.mig.Load(latest.LocalPath, latest.Module); err != nil {
+		logrus.WithError(err).Errorf("unable to load again the migration scripts of the plugin %q", latest.Module.Metadata.Name)
+	}
+	if err := p.exp.Load(latest.LocalPath, latest.Module); err != nil {
+		logrus.WithError(err).Errorf("unable to load again the export scripts of the plugin %q", latest.Module.Metadata.Name)
+	}
+}
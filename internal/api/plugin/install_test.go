@@ -0,0 +1,212 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mholt/archives"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestArchive(t *testing.T, files []archives.FileInfo) *bytes.Buffer {
+	format := archives.CompressedArchive{
+		Compression: archives.Gz{},
+		Archival:    archives.Tar{},
+	}
+	buf := &bytes.Buffer{}
+	if err := format.Archive(context.Background(), buf, files); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func buildTestPluginArchive(t *testing.T, pluginName string) *bytes.Buffer {
+	// the trailing separator puts the content of the plugin folder at the root of the archive, like `percli plugin build` does.
+	files, err := archives.FilesFromDisk(context.Background(), nil, map[string]string{
+		filepath.Join("migrate", testDataFolder, "plugins", pluginName) + string(os.PathSeparator): "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buildTestArchive(t, files)
+}
+
+func buildTestFileArchive(t *testing.T, nameInArchive string) *bytes.Buffer {
+	files, err := archives.FilesFromDisk(context.Background(), nil, map[string]string{
+		filepath.Join("migrate", testDataFolder, "plugins", "SomeVariable", PackageJSONFile): PackageJSONFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[0].NameInArchive = nameInArchive
+	return buildTestArchive(t, files)
+}
+
+// buildTestRenamedPluginArchive builds the archive of the plugin SomeVariable, with a different name in its manifest.
+func buildTestRenamedPluginArchive(t *testing.T, name string) *bytes.Buffer {
+	pluginPath := t.TempDir()
+	if err := os.CopyFS(pluginPath, os.DirFS(filepath.Join("migrate", testDataFolder, "plugins", "SomeVariable"))); err != nil {
+		t.Fatal(err)
+	}
+	manifestPath := filepath.Join(pluginPath, ManifestFileName)
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := make(map[string]any)
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	manifest["name"] = name
+	if data, err = json.Marshal(manifest); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(manifestPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	files, err := archives.FilesFromDisk(context.Background(), nil, map[string]string{
+		pluginPath + string(os.PathSeparator): "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buildTestArchive(t, files)
+}
+
+func TestInstall(t *testing.T) {
+	pluginPath := t.TempDir()
+	svc := New(config.Plugin{Path: pluginPath})
+	assert.NoError(t, svc.Load())
+	metadata := plugin.ModuleMetadata{Name: "SomeVariable", Version: "0.10.0"}
+
+	module, err := svc.Install(buildTestPluginArchive(t, "SomeVariable"))
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, module.Metadata)
+	assert.True(t, module.Status.IsLoaded)
+	loaded, ok := svc.GetLoadedPlugin("SomeVariable", "0.10.0", "")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(pluginPath, "SomeVariable-0.10.0"), loaded.LocalPath)
+	assert.DirExists(t, loaded.LocalPath)
+	list, err := svc.List()
+	assert.NoError(t, err)
+	assert.Contains(t, string(list), `"SomeVariable"`)
+
+	// installing the same version twice is a conflict
+	_, err = svc.Install(buildTestPluginArchive(t, "SomeVariable"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already installed")
+
+	assert.NoError(t, svc.Disable(metadata))
+	loaded, _ = svc.GetLoadedPlugin("SomeVariable", "0.10.0", "")
+	assert.False(t, loaded.Module.Status.IsLoaded)
+	assert.True(t, loaded.Module.Status.Disabled)
+	assert.FileExists(t, filepath.Join(loaded.LocalPath, disabledFileName))
+
+	// the plugin stays disabled after a restart
+	restarted := New(config.Plugin{Path: pluginPath})
+	assert.NoError(t, restarted.Load())
+	loaded, ok = restarted.GetLoadedPlugin("SomeVariable", "0.10.0", "")
+	assert.True(t, ok)
+	assert.True(t, loaded.Module.Status.Disabled)
+
+	assert.NoError(t, svc.Enable(metadata))
+	loaded, _ = svc.GetLoadedPlugin("SomeVariable", "0.10.0", "")
+	assert.True(t, loaded.Module.Status.IsLoaded)
+	assert.False(t, loaded.Module.Status.Disabled)
+	assert.NoFileExists(t, filepath.Join(loaded.LocalPath, disabledFileName))
+
+	assert.NoError(t, svc.Uninstall(metadata))
+	_, ok = svc.GetLoadedPlugin("SomeVariable", "0.10.0", "")
+	assert.False(t, ok)
+	assert.NoDirExists(t, filepath.Join(pluginPath, "SomeVariable-0.10.0"))
+	assert.Error(t, svc.Uninstall(metadata))
+}
+
+func TestInstallInvalidArchive(t *testing.T) {
+	testSuite := []struct {
+		title         string
+		archive       io.Reader
+		expectedError string
+	}{
+		{
+			title:         "not an archive",
+			archive:       strings.NewReader("this is not an archive"),
+			expectedError: "unable to identify the type of the archive",
+		},
+		{
+			title:         "archive without manifest",
+			archive:       buildTestFileArchive(t, PackageJSONFile),
+			expectedError: "the archive is not a valid plugin",
+		},
+		{
+			title:         "file outside the archive folder",
+			archive:       buildTestFileArchive(t, "../../package.json"),
+			expectedError: "invalid file path",
+		},
+		{
+			title:         "plugin name with a path",
+			archive:       buildTestRenamedPluginArchive(t, "../../SomeVariable"),
+			expectedError: "invalid plugin name",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			pluginPath := t.TempDir()
+			svc := New(config.Plugin{Path: pluginPath})
+			_, err := svc.Install(test.archive)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedError)
+			entries, _ := os.ReadDir(pluginPath)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestInstallUnsignedPlugin(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key.pub")
+	assert.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}), 0600))
+
+	svc := New(config.Plugin{
+		Path: t.TempDir(),
+		Signature: config.PluginSignature{
+			Policy:      config.PluginSignaturePolicyEnforce,
+			TrustedKeys: []string{keyPath},
+		},
+	})
+	_, err = svc.Install(buildTestPluginArchive(t, "SomeVariable"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid plugin signature: the plugin is not signed")
+}
@@ -0,0 +1,94 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cuelang.org/go/cue/build"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/spec/go/common"
+	"github.com/sirupsen/logrus"
+)
+
+func (m *completeMigration) migrateAnnotations(grafanaDashboard *SimplifiedDashboard, report *modelAPI.MigrationReport) []v1.Annotation {
+	var result []v1.Annotation
+	for _, a := range grafanaDashboard.Annotations.List {
+		if a.BuiltIn == 1 {
+			// The built-in annotation displays the alerts and the annotations stored in Grafana, which have no equivalent in Perses.
+			continue
+		}
+		_, datasourceType := describeTarget(a.RawMessage)
+		item := modelAPI.MigrationItem{
+			Kind:        modelAPI.MigrationItemKindAnnotation,
+			Name:        a.Name,
+			Ref:         fmt.Sprintf("#/spec/annotations/%d", len(result)),
+			GrafanaType: datasourceType,
+			Status:      modelAPI.MigrationStatusMigrated,
+		}
+		// We try first to execute the migration script from the dev migration instance.
+		annotationPlugin := migrateAnnotation(m.devMig.annotations, a.RawMessage)
+		if annotationPlugin == nil {
+			// If the migration failed, we tried again with the prod migration instance.
+			annotationPlugin = migrateAnnotation(m.mig.annotations, a.RawMessage)
+		}
+		if annotationPlugin == nil {
+			// There is no placeholder for an annotation, as it would display nothing.
+			// So the reference is the list of annotations the annotation would have been part of.
+			item.Ref = "#/spec/annotations"
+			item.Status = modelAPI.MigrationStatusDropped
+			item.Reason = "no migration script could convert the annotation"
+			report.Add(item)
+			continue
+		}
+		item.Plugin = annotationPlugin.Kind
+		report.Add(item)
+		result = append(result, v1.Annotation{
+			Kind: string(plugin.KindAnnotation),
+			Spec: v1.AnnotationSpec{
+				Display: &v1.AnnotationDisplay{
+					Name:   a.Name,
+					Hidden: a.Hide,
+				},
+				Enabled: a.Enable,
+				Color:   a.IconColor,
+				Plugin:  *annotationPlugin,
+			},
+		})
+	}
+	return result
+}
+
+func migrateAnnotation(annotations map[string]*build.Instance, grafanaAnnotation json.RawMessage) *common.Plugin {
+	// Like for the queries, Grafana doesn't give the type of annotation.
+	// So every migration script is executed, and the first one returning a non-empty plugin is kept.
+	for _, annotationInstance := range annotations {
+		annotationPlugin, annotationMigrationIsEmpty, err := ExecuteAnnotationScript(annotationInstance, grafanaAnnotation)
+		if err != nil {
+			logrus.WithError(err).Debug("failed to execute annotation migration script")
+			continue
+		}
+		if !annotationMigrationIsEmpty {
+			return annotationPlugin
+		}
+	}
+	return nil
+}
+
+func ExecuteAnnotationScript(cueScript *build.Instance, grafanaAnnotationData []byte) (*common.Plugin, bool, error) {
+	return executeCuelangScript(cueScript, grafanaAnnotationData, annotationDefID, "annotation")
+}
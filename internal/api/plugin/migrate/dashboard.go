@@ -0,0 +1,127 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/dashboard"
+)
+
+const (
+	defaultDuration = "1h"
+	grafanaNow      = "now"
+	grafanaLinkType = "link"
+)
+
+// grafanaDefaultRefreshIntervals are the refresh intervals proposed by Grafana when the dashboard doesn't define its own.
+// The older versions of Grafana used to write them in the dashboards, without the shortest intervals.
+var grafanaDefaultRefreshIntervals = [][]string{
+	{"5s", "10s", "30s", "1m", "5m", "15m", "30m", "1h", "2h", "1d"},
+	{"30s", "1m", "5m", "15m", "30m", "1h", "2h", "1d"},
+}
+
+// warnings gathers the reasons why some parts of a Grafana dashboard couldn't be migrated.
+type warnings []string
+
+func (w *warnings) add(format string, args ...any) {
+	*w = append(*w, fmt.Sprintf(format, args...))
+}
+
+// migrateSettings migrates the settings of the Grafana dashboard that are not related to the panels or the variables:
+// the time range, the refresh interval, the links and the description.
+func migrateSettings(grafanaDashboard *SimplifiedDashboard, spec *dashboard.Spec, w *warnings) {
+	spec.Display.Description = grafanaDashboard.Description
+	spec.Duration = migrateTimeRange(grafanaDashboard.Time, w)
+	spec.RefreshInterval = migrateRefresh(grafanaDashboard.Refresh, w)
+	if grafanaDashboard.Timepicker != nil && !isDefaultRefreshIntervals(grafanaDashboard.Timepicker.RefreshIntervals) {
+		w.add("the custom refresh intervals %s are not supported, the default ones are used", strings.Join(grafanaDashboard.Timepicker.RefreshIntervals, ", "))
+	}
+	spec.Links = migrateDashboardLinks(grafanaDashboard.Links, w)
+}
+
+func isDefaultRefreshIntervals(intervals []string) bool {
+	if len(intervals) == 0 {
+		return true
+	}
+	for _, defaultIntervals := range grafanaDefaultRefreshIntervals {
+		if slices.Equal(intervals, defaultIntervals) {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateTimeRange converts a Grafana time range relative to now (like now-6h) to a Perses duration.
+func migrateTimeRange(timeRange *GrafanaTimeRange, w *warnings) common.DurationString {
+	if timeRange == nil || len(timeRange.From) == 0 {
+		return defaultDuration
+	}
+	if len(timeRange.To) > 0 && timeRange.To != grafanaNow {
+		w.add("the time range from %q to %q is not supported, only the time ranges ending now can be migrated. The default duration %s is used", timeRange.From, timeRange.To, defaultDuration)
+		return defaultDuration
+	}
+	duration, isRelative := strings.CutPrefix(timeRange.From, grafanaNow+"-")
+	if isRelative {
+		if _, err := common.ParseDuration(duration); err == nil {
+			return common.DurationString(duration)
+		}
+	}
+	w.add("the time range starting at %q is not supported. The default duration %s is used", timeRange.From, defaultDuration)
+	return defaultDuration
+}
+
+func migrateRefresh(refresh json.RawMessage, w *warnings) common.DurationString {
+	if len(refresh) == 0 {
+		return ""
+	}
+	var interval string
+	if err := json.Unmarshal(refresh, &interval); err != nil {
+		// Grafana uses false when the auto-refresh is disabled.
+		return ""
+	}
+	if len(interval) == 0 {
+		return ""
+	}
+	if _, err := common.ParseDuration(interval); err != nil {
+		w.add("the refresh interval %q is not supported", interval)
+		return ""
+	}
+	return common.DurationString(interval)
+}
+
+func migrateDashboardLinks(grafanaLinks []GrafanaDashboardLink, w *warnings) []dashboard.Link {
+	var result []dashboard.Link
+	for _, link := range grafanaLinks {
+		if link.Type != grafanaLinkType {
+			w.add("the link %q listing the dashboards with the tags [%s] is not supported", link.Title, strings.Join(link.Tags, ", "))
+			continue
+		}
+		if link.IncludeVars || link.KeepTime {
+			w.add("the link %q cannot include the current variables or time range, it's migrated as a simple link", link.Title)
+		}
+		result = append(result, dashboard.Link{
+			Name:            link.Title,
+			URL:             link.URL,
+			Tooltip:         link.Tooltip,
+			TargetBlank:     link.TargetBlank,
+			RenderVariables: hasGrafanaVariables(link.URL),
+		})
+	}
+	return result
+}
@@ -0,0 +1,377 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue/build"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/dashboard"
+	"github.com/perses/spec/go/datasource"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	datasourceDefID = "#grafanaDatasource"
+	// secretPlaceholder is used for the sensitive values Grafana doesn't return through its API.
+	secretPlaceholder = "<to be replaced>"
+	maxNameLength     = 75
+)
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// GrafanaDatasource is a Grafana datasource, as returned by the API /api/datasources or as written in the provisioning files.
+type GrafanaDatasource struct {
+	UID           string         `json:"uid"`
+	Name          string         `json:"name"`
+	Type          string         `json:"type"`
+	URL           string         `json:"url"`
+	IsDefault     bool           `json:"isDefault"`
+	BasicAuth     bool           `json:"basicAuth"`
+	BasicAuthUser string         `json:"basicAuthUser"`
+	JSONData      map[string]any `json:"jsonData"`
+	// SecureJSONData contains the sensitive values. It's only available in the provisioning files.
+	SecureJSONData map[string]string `json:"secureJsonData"`
+	// SecureJSONFields tells which sensitive values are set. It's only available through the API.
+	SecureJSONFields map[string]bool `json:"secureJsonFields"`
+	json.RawMessage
+}
+
+// Custom unmarshal to both store fields and keep raw data (used by the CUE-based migration logic).
+func (d *GrafanaDatasource) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	tmp := GrafanaDatasource{}
+	fields := map[string]any{
+		"uid":              &tmp.UID,
+		"name":             &tmp.Name,
+		"type":             &tmp.Type,
+		"url":              &tmp.URL,
+		"isDefault":        &tmp.IsDefault,
+		"basicAuth":        &tmp.BasicAuth,
+		"basicAuthUser":    &tmp.BasicAuthUser,
+		"jsonData":         &tmp.JSONData,
+		"secureJsonData":   &tmp.SecureJSONData,
+		"secureJsonFields": &tmp.SecureJSONFields,
+	}
+	for key, field := range fields {
+		value, ok := raw[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			return fmt.Errorf("invalid field %q in the Grafana datasource: %w", key, err)
+		}
+	}
+	// The sensitive values are not given to the migration scripts.
+	delete(raw, "secureJsonData")
+	var err error
+	tmp.RawMessage, err = json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	*d = tmp
+	return nil
+}
+
+// persesName returns the name of the Perses datasource corresponding to the Grafana datasource.
+func (d *GrafanaDatasource) persesName() string {
+	for _, name := range []string{d.Name, d.UID} {
+		result := strings.Trim(invalidNameCharacters.ReplaceAllString(name, "-"), "-")
+		if len(result) > maxNameLength {
+			result = result[:maxNameLength]
+		}
+		if len(result) > 0 {
+			return result
+		}
+	}
+	return ""
+}
+
+func (d *GrafanaDatasource) jsonDataString(key string) string {
+	value, _ := d.JSONData[key].(string)
+	return value
+}
+
+func (d *GrafanaDatasource) jsonDataBool(key string) bool {
+	value, _ := d.JSONData[key].(bool)
+	return value
+}
+
+// secureValue returns the sensitive value associated with the key, and whether it's set.
+// When the value is set but Grafana didn't return it, a placeholder is returned instead.
+func (d *GrafanaDatasource) secureValue(key string) (string, bool) {
+	if value, ok := d.SecureJSONData[key]; ok && len(value) > 0 {
+		return value, true
+	}
+	if d.SecureJSONFields[key] {
+		return secretPlaceholder, true
+	}
+	return "", false
+}
+
+// secretSpec extracts the sensitive part of the HTTP settings of the Grafana datasource.
+// It returns nil when the datasource doesn't use any authentication or TLS settings.
+func (d *GrafanaDatasource) secretSpec() *v1.SecretSpec {
+	spec := &v1.SecretSpec{}
+	if d.BasicAuth {
+		password, _ := d.secureValue("basicAuthPassword")
+		spec.BasicAuth = &secret.BasicAuth{Username: d.BasicAuthUser, Password: password}
+	} else {
+		// Grafana supports any number of custom headers, but only the Authorization one has an equivalent in Perses.
+		for i := 1; len(d.jsonDataString(fmt.Sprintf("httpHeaderName%d", i))) > 0; i++ {
+			if !strings.EqualFold(d.jsonDataString(fmt.Sprintf("httpHeaderName%d", i)), "Authorization") {
+				continue
+			}
+			value, ok := d.secureValue(fmt.Sprintf("httpHeaderValue%d", i))
+			if !ok {
+				break
+			}
+			authType, credentials, found := strings.Cut(value, " ")
+			if !found || value == secretPlaceholder {
+				authType, credentials = "Bearer", value
+			}
+			spec.Authorization = &secret.Authorization{Type: authType, Credentials: credentials}
+			break
+		}
+	}
+	tlsConfig := &secret.TLSConfig{
+		InsecureSkipVerify: d.jsonDataBool("tlsSkipVerify"),
+		ServerName:         d.jsonDataString("serverName"),
+	}
+	if d.jsonDataBool("tlsAuthWithCACert") {
+		tlsConfig.CA, _ = d.secureValue("tlsCACert")
+	}
+	if d.jsonDataBool("tlsAuth") {
+		tlsConfig.Cert, _ = d.secureValue("tlsClientCert")
+		tlsConfig.Key, _ = d.secureValue("tlsClientKey")
+	}
+	if *tlsConfig != (secret.TLSConfig{}) {
+		spec.TLSConfig = tlsConfig
+	}
+	if spec.BasicAuth == nil && spec.Authorization == nil && spec.TLSConfig == nil {
+		return nil
+	}
+	return spec
+}
+
+// DatasourceMigration is the result of the migration of Grafana datasources.
+type DatasourceMigration struct {
+	// Secrets contains the sensitive settings extracted from the Grafana datasources.
+	// They must be created before the datasources.
+	Secrets []modelAPI.Entity
+	// Datasources contains the Perses datasources. They are global datasources when no project is given.
+	Datasources []modelAPI.Entity
+	// References maps the UID and the name of each Grafana datasource to the name of the Perses datasource.
+	// It's meant to be given to RewriteDatasourceReferences.
+	References map[string]string
+	// Warnings lists what couldn't be migrated and requires a manual action.
+	Warnings []string
+}
+
+// DatasourceReferences returns the name of the Perses datasource corresponding to each Grafana datasource,
+// indexed by the UID and the name of the Grafana datasource.
+func DatasourceReferences(grafanaDatasources []GrafanaDatasource) map[string]string {
+	result := make(map[string]string)
+	for _, ds := range grafanaDatasources {
+		name := ds.persesName()
+		if len(name) == 0 {
+			continue
+		}
+		if len(ds.UID) > 0 {
+			result[ds.UID] = name
+		}
+		if len(ds.Name) > 0 {
+			result[ds.Name] = name
+		}
+	}
+	return result
+}
+
+// RewriteDatasourceReferences replaces in the queries and the variables of the dashboard the references to the
+// Grafana datasources by the names of the Perses datasources.
+func RewriteDatasourceReferences(dash *v1.Dashboard, references map[string]string) {
+	if len(references) == 0 {
+		return
+	}
+	for _, panel := range dash.Spec.Panels {
+		if panel == nil {
+			continue
+		}
+		for _, query := range panel.Spec.Queries {
+			rewriteDatasourceReference(query.Spec.Plugin.Spec, references)
+		}
+	}
+	for _, variable := range dash.Spec.Variables {
+		if spec, ok := variable.Spec.(*dashboard.ListVariableSpec); ok {
+			rewriteDatasourceReference(spec.Plugin.Spec, references)
+		}
+	}
+}
+
+// rewriteDatasourceReference looks recursively for the datasource selectors ({"datasource": {"kind": ..., "name": ...}})
+// in the spec of a plugin.
+func rewriteDatasourceReference(value any, references map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		if selector, ok := v["datasource"].(map[string]any); ok {
+			if name, isString := selector["name"].(string); isString {
+				if newName, found := references[name]; found {
+					selector["name"] = newName
+				}
+			}
+		}
+		for _, child := range v {
+			rewriteDatasourceReference(child, references)
+		}
+	case []any:
+		for _, child := range v {
+			rewriteDatasourceReference(child, references)
+		}
+	}
+}
+
+func (m *completeMigration) MigrateDatasources(grafanaDatasources []GrafanaDatasource, project string) (*DatasourceMigration, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	result := &DatasourceMigration{
+		References: DatasourceReferences(grafanaDatasources),
+	}
+	names := make(map[string]string)
+	for _, grafanaDatasource := range grafanaDatasources {
+		name := grafanaDatasource.persesName()
+		if len(name) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("the Grafana datasource of type %q has neither a name nor a UID", grafanaDatasource.Type))
+			continue
+		}
+		if previous, exists := names[name]; exists {
+			return nil, fmt.Errorf("the Grafana datasources %q and %q would both be migrated to the Perses datasource %q", previous, grafanaDatasource.Name, name)
+		}
+		names[name] = grafanaDatasource.Name
+		plg, isEmpty := migrateDatasourcePlugin(m.devMig.datasources, grafanaDatasource)
+		if isEmpty {
+			plg, isEmpty = migrateDatasourcePlugin(m.mig.datasources, grafanaDatasource)
+		}
+		if isEmpty {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("no migration script found for the Grafana datasource %q of type %q", grafanaDatasource.Name, grafanaDatasource.Type))
+			continue
+		}
+		spec := datasource.Spec{
+			Display: &common.Display{Name: grafanaDatasource.Name},
+			Default: grafanaDatasource.IsDefault,
+			Plugin:  *plg,
+		}
+		if secretSpec := grafanaDatasource.secretSpec(); secretSpec != nil {
+			secretName := fmt.Sprintf("%s-secret", name)
+			if !setProxySecret(&spec.Plugin, secretName) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("the Perses datasource %q doesn't use an HTTP proxy, the secret %q must be referenced manually", name, secretName))
+			}
+			if hasPlaceholder(secretSpec) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Grafana doesn't expose the sensitive values of the datasource %q, they must be set in the secret %q", grafanaDatasource.Name, secretName))
+			}
+			result.Secrets = append(result.Secrets, newSecret(project, secretName, *secretSpec))
+		}
+		result.Datasources = append(result.Datasources, newDatasource(project, name, spec))
+	}
+	sort.Strings(result.Warnings)
+	return result, nil
+}
+
+func newDatasource(project string, name string, spec datasource.Spec) modelAPI.Entity {
+	if len(project) == 0 {
+		return &v1.GlobalDatasource{
+			Kind:     v1.KindGlobalDatasource,
+			Metadata: *v1.NewMetadata(name),
+			Spec:     spec,
+		}
+	}
+	return &v1.Datasource{
+		Kind:     v1.KindDatasource,
+		Metadata: *v1.NewProjectMetadata(project, name),
+		Spec:     spec,
+	}
+}
+
+func newSecret(project string, name string, spec v1.SecretSpec) modelAPI.Entity {
+	if len(project) == 0 {
+		return &v1.GlobalSecret{
+			Kind:     v1.KindGlobalSecret,
+			Metadata: *v1.NewMetadata(name),
+			Spec:     spec,
+		}
+	}
+	return &v1.Secret{
+		Kind:     v1.KindSecret,
+		Metadata: *v1.NewProjectMetadata(project, name),
+		Spec:     spec,
+	}
+}
+
+// setProxySecret references the secret in the HTTP proxy of the datasource plugin, when there is one.
+func setProxySecret(plg *common.Plugin, secretName string) bool {
+	spec, ok := plg.Spec.(map[string]any)
+	if !ok {
+		return false
+	}
+	proxy, ok := spec["proxy"].(map[string]any)
+	if !ok {
+		return false
+	}
+	proxySpec, ok := proxy["spec"].(map[string]any)
+	if !ok {
+		return false
+	}
+	proxySpec["secret"] = secretName
+	return true
+}
+
+func hasPlaceholder(spec *v1.SecretSpec) bool {
+	if spec.BasicAuth != nil && spec.BasicAuth.Password == secretPlaceholder {
+		return true
+	}
+	if spec.Authorization != nil && spec.Authorization.Credentials == secretPlaceholder {
+		return true
+	}
+	return spec.TLSConfig != nil && (spec.TLSConfig.CA == secretPlaceholder || spec.TLSConfig.Cert == secretPlaceholder || spec.TLSConfig.Key == secretPlaceholder)
+}
+
+func migrateDatasourcePlugin(datasourceInstances map[string]*build.Instance, grafanaDatasource GrafanaDatasource) (*common.Plugin, bool) {
+	// Like for the variables, the Grafana type is not enough to know which script to use, as different Grafana plugins
+	// can be migrated to the same Perses plugin. So we execute every script and keep the first non-empty result.
+	for _, instance := range datasourceInstances {
+		plg, isEmpty, err := ExecuteDatasourceScript(instance, grafanaDatasource.RawMessage)
+		if err != nil {
+			logrus.WithError(err).Debug("failed to execute datasource migration script")
+			continue
+		}
+		if !isEmpty {
+			return plg, false
+		}
+	}
+	return nil, true
+}
+
+func ExecuteDatasourceScript(cueScript *build.Instance, grafanaDatasourceData []byte) (*common.Plugin, bool, error) {
+	return executeCuelangScript(cueScript, grafanaDatasourceData, datasourceDefID, "datasource")
+}
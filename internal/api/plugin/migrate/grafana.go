@@ -45,6 +45,13 @@ type Panel struct {
 	GridPosition GridPosition      `json:"gridPos"`
 	Targets      []json.RawMessage `json:"targets"`
 	Links        []GrafanaLink     `json:"links"`
+	// LibraryPanel is set when the panel is a library panel. Once the library panel is resolved (see ResolveLibraryPanels),
+	// the other fields come from its definition.
+	LibraryPanel *GrafanaLibraryPanelRef `json:"libraryPanel,omitempty"`
+	// Repeat is the name of the variable used to repeat the panel, or the row, for each of its values.
+	Repeat string `json:"repeat,omitempty"`
+	// RepeatPanelID is set on the copies of a repeated panel, that older versions of Grafana used to save with the dashboard.
+	RepeatPanelID int `json:"repeatPanelId,omitempty"`
 	json.RawMessage
 }
 
@@ -88,6 +95,17 @@ func (p *Panel) UnmarshalJSON(data []byte) error {
 			return err
 		}
 	}
+	if libraryPanel, ok := tmp[grafanaLibraryPanelField]; ok {
+		if err := json.Unmarshal(libraryPanel, &panel.LibraryPanel); err != nil {
+			return err
+		}
+	}
+	if repeat, ok := tmp["repeat"]; ok {
+		_ = json.Unmarshal(repeat, &panel.Repeat)
+	}
+	if repeatPanelID, ok := tmp["repeatPanelId"]; ok {
+		_ = json.Unmarshal(repeatPanelID, &panel.RepeatPanelID)
+	}
 	var err error
 	panel.RawMessage, err = json.Marshal(tmp)
 	if err != nil {
@@ -176,14 +194,85 @@ func (v *TemplateVar) getDefaultValue() *variable.DefaultValue {
 	return v.Current.Value
 }
 
+type GrafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type GrafanaTimePicker struct {
+	RefreshIntervals []string `json:"refresh_intervals"`
+}
+
+// GrafanaDashboardLink is a link displayed at the top of a Grafana dashboard.
+type GrafanaDashboardLink struct {
+	Title string `json:"title"`
+	// Type is either "link" for a URL, or "dashboards" for a list of dashboards found by tags.
+	Type        string   `json:"type"`
+	URL         string   `json:"url"`
+	Tooltip     string   `json:"tooltip"`
+	TargetBlank bool     `json:"targetBlank"`
+	Tags        []string `json:"tags"`
+	IncludeVars bool     `json:"includeVars"`
+	KeepTime    bool     `json:"keepTime"`
+}
+
+type GrafanaAnnotation struct {
+	Name      string `json:"name"`
+	Enable    bool   `json:"enable"`
+	Hide      bool   `json:"hide"`
+	IconColor string `json:"iconColor,omitempty"`
+	// BuiltIn is 1 for the annotations provided by Grafana itself, like the alerts or the annotations saved in Grafana.
+	BuiltIn int `json:"builtIn,omitempty"`
+	json.RawMessage
+}
+
+func (a *GrafanaAnnotation) UnmarshalJSON(data []byte) error {
+	var tmp map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	grafanaAnnotation := GrafanaAnnotation{}
+	if name, ok := tmp["name"]; ok {
+		_ = json.Unmarshal(name, &grafanaAnnotation.Name)
+	}
+	if enable, ok := tmp["enable"]; ok {
+		_ = json.Unmarshal(enable, &grafanaAnnotation.Enable)
+	}
+	if hide, ok := tmp["hide"]; ok {
+		_ = json.Unmarshal(hide, &grafanaAnnotation.Hide)
+	}
+	if iconColor, ok := tmp["iconColor"]; ok {
+		_ = json.Unmarshal(iconColor, &grafanaAnnotation.IconColor)
+	}
+	if builtIn, ok := tmp["builtIn"]; ok {
+		_ = json.Unmarshal(builtIn, &grafanaAnnotation.BuiltIn)
+	}
+	var err error
+	grafanaAnnotation.RawMessage, err = json.Marshal(tmp)
+	if err != nil {
+		return err
+	}
+	*a = grafanaAnnotation
+	return nil
+}
+
 type SimplifiedDashboard struct {
-	UID        string   `json:"uid,omitempty"`
-	Title      string   `json:"title"`
-	Tags       []string `json:"tags"`
-	Panels     []Panel  `json:"panels"`
+	UID         string            `json:"uid,omitempty"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags"`
+	Time        *GrafanaTimeRange `json:"time,omitempty"`
+	// Refresh is either a duration like "30s", or false when the auto-refresh is disabled.
+	Refresh    json.RawMessage        `json:"refresh,omitempty"`
+	Timepicker *GrafanaTimePicker     `json:"timepicker,omitempty"`
+	Links      []GrafanaDashboardLink `json:"links,omitempty"`
+	Panels     []Panel                `json:"panels"`
 	Templating struct {
 		List []TemplateVar `json:"list"`
 	} `json:"templating"`
+	Annotations struct {
+		List []GrafanaAnnotation `json:"list"`
+	} `json:"annotations"`
 }
 
 func (d *SimplifiedDashboard) UnmarshalJSON(data []byte) error {
@@ -192,11 +281,25 @@ func (d *SimplifiedDashboard) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, (*plain)(tmp)); err != nil {
 		return err
 	}
+	tmp.Panels = removeRepeatedCopies(tmp.Panels)
 	tmp.rearrangeGrafanaPanelsWithinExpandedRows()
 	*d = *tmp
 	return nil
 }
 
+// removeRepeatedCopies removes the copies of the repeated panels and rows, as the repetition is done by Perses.
+func removeRepeatedCopies(panels []Panel) []Panel {
+	var result []Panel
+	for _, panel := range panels {
+		if panel.RepeatPanelID != 0 {
+			continue
+		}
+		panel.Panels = removeRepeatedCopies(panel.Panels)
+		result = append(result, panel)
+	}
+	return result
+}
+
 // This function addresses an issue we have with Grafana datamodel when it comes to migrating dashboards to Perses: When
 // a row is expanded in Grafana, its children panels are moved up in the main panels list, thus become siblings of the row.
 // When it comes to Perses migration, we need to recompose the parent->children relationships.
@@ -0,0 +1,130 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	grafanaPanelsField       = "panels"
+	grafanaLibraryPanelField = "libraryPanel"
+	// grafanaElementsField is where Grafana puts the library panels used by a dashboard exported for sharing externally.
+	grafanaElementsField = "__elements"
+)
+
+// GrafanaLibraryPanelRef is the reference to a library panel, used by a dashboard panel instead of its own definition.
+type GrafanaLibraryPanelRef struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+// GrafanaLibraryPanel is a library panel, as returned by the Grafana API or embedded in an exported dashboard.
+type GrafanaLibraryPanel struct {
+	UID   string          `json:"uid"`
+	Name  string          `json:"name"`
+	Model json.RawMessage `json:"model"`
+}
+
+// ResolveLibraryPanels replaces the references to library panels in the Grafana dashboard with the definition of the
+// library panels. The library panels are looked for in the given list and in the library panels embedded in the dashboard.
+// The references that cannot be resolved are left untouched.
+func ResolveLibraryPanels(grafanaDashboard []byte, libraryPanels []GrafanaLibraryPanel) ([]byte, error) {
+	var dash map[string]json.RawMessage
+	if err := json.Unmarshal(grafanaDashboard, &dash); err != nil {
+		return nil, err
+	}
+	rawPanels, ok := dash[grafanaPanelsField]
+	if !ok {
+		return grafanaDashboard, nil
+	}
+	library := make(map[string]GrafanaLibraryPanel)
+	if elements, hasElements := dash[grafanaElementsField]; hasElements {
+		var embedded map[string]GrafanaLibraryPanel
+		if err := json.Unmarshal(elements, &embedded); err != nil {
+			return nil, fmt.Errorf("unable to read the library panels embedded in the dashboard: %w", err)
+		}
+		for uid, libraryPanel := range embedded {
+			if len(libraryPanel.UID) == 0 {
+				libraryPanel.UID = uid
+			}
+			library[libraryPanel.UID] = libraryPanel
+		}
+	}
+	for _, libraryPanel := range libraryPanels {
+		library[libraryPanel.UID] = libraryPanel
+	}
+	if len(library) == 0 {
+		return grafanaDashboard, nil
+	}
+	resolvedPanels, isResolved, err := resolveLibraryPanels(rawPanels, library)
+	if err != nil || !isResolved {
+		return grafanaDashboard, err
+	}
+	dash[grafanaPanelsField] = resolvedPanels
+	return json.Marshal(dash)
+}
+
+// resolveLibraryPanels resolves the library panels of the given list of panels, including the ones nested in a row.
+// It returns false when the list doesn't contain any library panel that could be resolved.
+func resolveLibraryPanels(rawPanels json.RawMessage, library map[string]GrafanaLibraryPanel) (json.RawMessage, bool, error) {
+	var panels []map[string]json.RawMessage
+	if err := json.Unmarshal(rawPanels, &panels); err != nil {
+		return nil, false, err
+	}
+	isResolved := false
+	for i, panel := range panels {
+		if innerPanels, ok := panel[grafanaPanelsField]; ok {
+			resolvedPanels, isInnerResolved, err := resolveLibraryPanels(innerPanels, library)
+			if err != nil {
+				return nil, false, err
+			}
+			if isInnerResolved {
+				panel[grafanaPanelsField] = resolvedPanels
+				isResolved = true
+			}
+			continue
+		}
+		rawRef, ok := panel[grafanaLibraryPanelField]
+		if !ok {
+			continue
+		}
+		ref := &GrafanaLibraryPanelRef{}
+		if err := json.Unmarshal(rawRef, ref); err != nil {
+			return nil, false, fmt.Errorf("invalid reference to a library panel: %w", err)
+		}
+		libraryPanel, ok := library[ref.UID]
+		if !ok || len(libraryPanel.Model) == 0 {
+			continue
+		}
+		var model map[string]json.RawMessage
+		if err := json.Unmarshal(libraryPanel.Model, &model); err != nil {
+			return nil, false, fmt.Errorf("invalid library panel %q: %w", ref.UID, err)
+		}
+		// The library panel gives the definition of the panel, while the dashboard gives its position.
+		for _, field := range []string{"gridPos", "id", grafanaLibraryPanelField} {
+			if value, exists := panel[field]; exists {
+				model[field] = value
+			}
+		}
+		panels[i] = model
+		isResolved = true
+	}
+	if !isResolved {
+		return rawPanels, false, nil
+	}
+	data, err := json.Marshal(panels)
+	return data, true, err
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/perses/common/set"
 	apiinterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/plugin/schema"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/plugin"
 	"github.com/perses/spec/go/common"
@@ -41,6 +42,7 @@ const (
 	grafanaType     = "#grafanaType"
 	migrationFolder = "migrate"
 	varDefID        = "#grafanaVar"
+	annotationDefID = "#grafanaAnnotation"
 )
 
 var kindRegexp = regexp.MustCompile(`(?m)kind\s*:\s*"(\w+)"`)
@@ -126,12 +128,18 @@ func GetPluginKind(migrateFile string) (plugin.Kind, error) {
 	if err != nil {
 		return "", err
 	}
+	if strings.Contains(string(data), datasourceDefID) {
+		return plugin.KindDatasource, nil
+	}
 	if strings.Contains(string(data), grafanaType) {
 		return plugin.KindPanel, nil
 	}
 	if strings.Contains(string(data), varDefID) {
 		return plugin.KindVariable, nil
 	}
+	if strings.Contains(string(data), annotationDefID) {
+		return plugin.KindAnnotation, nil
+	}
 	return plugin.KindQuery, nil
 }
 
@@ -189,22 +197,32 @@ func convertToPlugin(migrateValue cue.Value) (*common.Plugin, bool, error) {
 
 type Migration interface {
 	Load(pluginPath string, module v1.PluginModule) error
+	Unload(module v1.PluginModule)
 	LoadDevPlugin(pluginPath string, module v1.PluginModule) error
 	UnLoadDevPlugin(module v1.PluginModule)
-	Migrate(grafanaDashboard *SimplifiedDashboard, useDefaultDatasource bool) (*v1.Dashboard, error)
+	// Migrate converts a Grafana dashboard to a Perses dashboard. It also returns a report telling how each panel,
+	// query, variable and annotation has been migrated, and why some parts of the Grafana dashboard couldn't be migrated.
+	Migrate(grafanaDashboard *SimplifiedDashboard, useDefaultDatasource bool) (*v1.Dashboard, *modelAPI.MigrationReport, error)
+	// MigrateDatasources converts the Grafana datasources to Perses datasources, in the given project or as global
+	// datasources when the project is empty.
+	MigrateDatasources(grafanaDatasources []GrafanaDatasource, project string) (*DatasourceMigration, error)
 }
 
 func New() Migration {
 	return &completeMigration{
 		mig: &mig{
-			panels:    make(map[string]*panelInstance),
-			variables: make(map[string]*build.Instance),
-			queries:   make(map[string]*queryInstance),
+			panels:      make(map[string]*panelInstance),
+			variables:   make(map[string]*build.Instance),
+			queries:     make(map[string]*queryInstance),
+			datasources: make(map[string]*build.Instance),
+			annotations: make(map[string]*build.Instance),
 		},
 		devMig: &mig{
-			panels:    make(map[string]*panelInstance),
-			variables: make(map[string]*build.Instance),
-			queries:   make(map[string]*queryInstance),
+			panels:      make(map[string]*panelInstance),
+			variables:   make(map[string]*build.Instance),
+			queries:     make(map[string]*queryInstance),
+			datasources: make(map[string]*build.Instance),
+			annotations: make(map[string]*build.Instance),
 		},
 	}
 }
@@ -222,6 +240,14 @@ func (m *completeMigration) Load(pluginPath string, module v1.PluginModule) erro
 	return m.mig.load(pluginPath, module)
 }
 
+func (m *completeMigration) Unload(module v1.PluginModule) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, plg := range module.Spec.Plugins {
+		m.mig.remove(plg.Kind, plg.Spec.Name)
+	}
+}
+
 func (m *completeMigration) LoadDevPlugin(pluginPath string, module v1.PluginModule) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -236,7 +262,7 @@ func (m *completeMigration) UnLoadDevPlugin(module v1.PluginModule) {
 	}
 }
 
-func (m *completeMigration) Migrate(grafanaDashboard *SimplifiedDashboard, useDefaultDatasource bool) (*v1.Dashboard, error) {
+func (m *completeMigration) Migrate(grafanaDashboard *SimplifiedDashboard, useDefaultDatasource bool) (*v1.Dashboard, *modelAPI.MigrationReport, error) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 	result := &v1.Dashboard{
@@ -247,25 +273,31 @@ func (m *completeMigration) Migrate(grafanaDashboard *SimplifiedDashboard, useDe
 				Tags: set.New(grafanaDashboard.Tags...),
 			},
 		},
-		Spec: dashboard.Spec{
-			Display: &common.Display{
-				Name: grafanaDashboard.Title,
+		Spec: v1.AnnotatedDashboardSpec{
+			Spec: dashboard.Spec{
+				Display: &common.Display{
+					Name: grafanaDashboard.Title,
+				},
 			},
-			Duration: "1h",
 		},
 	}
+	report := modelAPI.NewMigrationReport()
+	var w warnings
+	migrateSettings(grafanaDashboard, &result.Spec.Spec, &w)
 
-	panels, err := m.migratePanels(grafanaDashboard, useDefaultDatasource)
+	panels, err := m.migratePanels(grafanaDashboard, useDefaultDatasource, report)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	result.Spec.Panels = panels
-	result.Spec.Variables = m.migrateVariables(grafanaDashboard)
-	result.Spec.Layouts = m.migrateGrid(grafanaDashboard)
-	return result, nil
+	result.Spec.Variables = m.migrateVariables(grafanaDashboard, report)
+	result.Spec.Layouts = m.migrateGrid(grafanaDashboard, &w)
+	result.Spec.Annotations = m.migrateAnnotations(grafanaDashboard, report)
+	report.Warnings = w
+	return result, report, nil
 }
 
-func (m *completeMigration) migrateGrid(grafanaDashboard *SimplifiedDashboard) []dashboard.Layout {
+func (m *completeMigration) migrateGrid(grafanaDashboard *SimplifiedDashboard, w *warnings) []dashboard.Layout {
 	var result []dashboard.Layout
 	// This is not allowed in Perses to have "orphan" panels (a.k.a panels that don't belong to a group).
 	// Thus we have to create a first grid to gather the eventual orphans found at the beginning of the
@@ -284,6 +316,7 @@ func (m *completeMigration) migrateGrid(grafanaDashboard *SimplifiedDashboard) [
 
 	for i, panel := range grafanaDashboard.Panels {
 		if panel.Type != grafanaPanelRowType {
+			warnUnsupportedRepeat(panel, w)
 			orphansGridSpec.Items = append(orphansGridSpec.Items, dashboard.GridItem{
 				Width:  panel.GridPosition.Width,
 				Height: panel.GridPosition.Height,
@@ -307,7 +340,16 @@ func (m *completeMigration) migrateGrid(grafanaDashboard *SimplifiedDashboard) [
 				Kind: dashboard.KindGridLayout,
 				Spec: gridSpec,
 			}
+			gridSpec.RepeatVariable = panel.Repeat
+			if len(gridSpec.RepeatVariable) == 0 {
+				// Perses cannot repeat a panel, but it can repeat a group of panels.
+				// So when every panel of the row is repeated by the same variable, the row is repeated instead.
+				gridSpec.RepeatVariable = commonRepeatVariable(panel.Panels)
+			}
 			for j, innerPanel := range panel.Panels {
+				if innerPanel.Repeat != gridSpec.RepeatVariable {
+					warnUnsupportedRepeat(innerPanel, w)
+				}
 				gridSpec.Items = append(gridSpec.Items, dashboard.GridItem{
 					Width:  innerPanel.GridPosition.Width,
 					Height: innerPanel.GridPosition.Height,
@@ -331,6 +373,26 @@ func (m *completeMigration) migrateGrid(grafanaDashboard *SimplifiedDashboard) [
 	return result
 }
 
+// commonRepeatVariable returns the variable repeating all the given panels, or an empty string if there is none.
+func commonRepeatVariable(panels []Panel) string {
+	if len(panels) == 0 {
+		return ""
+	}
+	repeat := panels[0].Repeat
+	for _, panel := range panels[1:] {
+		if panel.Repeat != repeat {
+			return ""
+		}
+	}
+	return repeat
+}
+
+func warnUnsupportedRepeat(panel Panel, w *warnings) {
+	if len(panel.Repeat) > 0 {
+		w.add("the panel %q is repeated for each value of the variable %q, which is only supported for all the panels of a row. It's displayed once", panel.Title, panel.Repeat)
+	}
+}
+
 type queryInstance struct {
 	instance *build.Instance
 	kind     plugin.Kind
@@ -353,6 +415,12 @@ type mig struct {
 	// queries is a map that implies we won't allow having two migration scripts for the same query type.
 	// The key is the query instance kind (e.g., PrometheusTimeSeriesQuery).
 	queries map[string]*queryInstance
+	// datasources is a map that implies we won't allow having two migration scripts for the same datasource type.
+	// The key is the datasource plugin kind (e.g., PrometheusDatasource).
+	datasources map[string]*build.Instance
+	// annotations is a map that implies we won't allow having two migration scripts for the same annotation type.
+	// The key is the annotation plugin kind (e.g., PrometheusAnnotation).
+	annotations map[string]*build.Instance
 }
 
 func (m *mig) load(pluginPath string, module v1.PluginModule) error {
@@ -368,6 +436,10 @@ func (m *mig) load(pluginPath string, module v1.PluginModule) error {
 			m.loadVariable(sch.Name, sch.Instance, module)
 		case plugin.KindPanel:
 			m.loadPanel(sch.Name, sch.Instance, module)
+		case plugin.KindDatasource:
+			m.loadDatasource(sch.Name, sch.Instance, module)
+		case plugin.KindAnnotation:
+			m.loadAnnotation(sch.Name, sch.Instance, module)
 		}
 	}
 	return nil
@@ -479,6 +551,48 @@ func (m *mig) loadQuery(schemaPath string, instance *build.Instance, module v1.P
 	logrus.Infof("unable to recognize the query kind from the migrate script %q", schemaPath)
 }
 
+func (m *mig) loadDatasource(schemaPath string, instance *build.Instance, module v1.PluginModule) {
+	// Like for the variables, the datasource plugin kind is used to ensure we have a single migration script per datasource kind.
+	data, err := os.ReadFile(filepath.Join(schemaPath, "migrate.cue")) //nolint: gosec
+	if err != nil {
+		logrus.WithError(err).Warnf("unable to read migrate script from %q", schemaPath)
+	}
+	for _, group := range kindRegexp.FindAllStringSubmatch(string(data), -1) {
+		if len(group) < 2 {
+			continue
+		}
+		kind := group[1]
+		for _, plg := range module.Spec.Plugins {
+			if plg.Kind == plugin.KindDatasource && plg.Spec.Name == kind {
+				m.datasources[kind] = instance
+				return
+			}
+		}
+	}
+	logrus.Infof("unable to recognize the datasource kind from the migrate script %q", schemaPath)
+}
+
+func (m *mig) loadAnnotation(schemaPath string, instance *build.Instance, module v1.PluginModule) {
+	// Like for the datasources, the annotation plugin kind is used to ensure we have a single migration script per annotation kind.
+	data, err := os.ReadFile(filepath.Join(schemaPath, "migrate.cue")) //nolint: gosec
+	if err != nil {
+		logrus.WithError(err).Warnf("unable to read migrate script from %q", schemaPath)
+	}
+	for _, group := range kindRegexp.FindAllStringSubmatch(string(data), -1) {
+		if len(group) < 2 {
+			continue
+		}
+		kind := group[1]
+		for _, plg := range module.Spec.Plugins {
+			if plg.Kind == plugin.KindAnnotation && plg.Spec.Name == kind {
+				m.annotations[kind] = instance
+				return
+			}
+		}
+	}
+	logrus.Infof("unable to recognize the annotation kind from the migrate script %q", schemaPath)
+}
+
 func (m *mig) remove(kind plugin.Kind, name string) {
 	if kind.IsQuery() {
 		delete(m.queries, name)
@@ -496,8 +610,12 @@ func (m *mig) remove(kind plugin.Kind, name string) {
 			delete(m.panels, name)
 		case plugin.KindVariable:
 			delete(m.variables, name)
-		case plugin.KindDatasource, plugin.KindExplore:
-		// No migration script for datasource or explorer, so nothing to remove
+		case plugin.KindDatasource:
+			delete(m.datasources, name)
+		case plugin.KindAnnotation:
+			delete(m.annotations, name)
+		case plugin.KindExplore:
+		// No migration script for explorer, so nothing to remove
 		default:
 			logrus.Warnf("unable to remove migration script for %q: kind %q not supported", name, kind)
 		}
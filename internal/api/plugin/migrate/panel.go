@@ -19,6 +19,7 @@ import (
 	"regexp"
 
 	"cuelang.org/go/cue/build"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/v1/plugin"
 	"github.com/perses/spec/go/common"
 	"github.com/perses/spec/go/dashboard"
@@ -67,29 +68,50 @@ func convertGrafanaLinksToPerses(grafanaLinks []GrafanaLink) []dashboard.Link {
 	return persesLinks
 }
 
-func (m *completeMigration) migratePanels(grafanaDashboard *SimplifiedDashboard, useDefaultDatasource bool) (map[string]*dashboard.Panel, error) {
+func panelRef(key string) string {
+	return fmt.Sprintf("#/spec/panels/%s", key)
+}
+
+// describeTarget extracts from a Grafana query the information useful for the migration report:
+// its refId and the type of its datasource when the datasource is not given as a simple name.
+func describeTarget(target json.RawMessage) (string, string) {
+	var tmp struct {
+		RefID      string          `json:"refId"`
+		Datasource json.RawMessage `json:"datasource"`
+	}
+	_ = json.Unmarshal(target, &tmp)
+	var datasource struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(tmp.Datasource, &datasource)
+	return tmp.RefID, datasource.Type
+}
+
+func (m *completeMigration) migratePanels(grafanaDashboard *SimplifiedDashboard, useDefaultDatasource bool, report *modelAPI.MigrationReport) (map[string]*dashboard.Panel, error) {
 	panels := make(map[string]*dashboard.Panel)
 	for i, p := range grafanaDashboard.Panels {
 		if p.Type == grafanaPanelRowType {
 			for j, innerPanel := range p.Panels {
-				panel, err := m.migratePanel(innerPanel, useDefaultDatasource)
+				key := fmt.Sprintf("%d_%d", i, j)
+				panel, err := m.migratePanel(innerPanel, key, useDefaultDatasource, report)
 				if err != nil {
 					return nil, err
 				}
-				panels[fmt.Sprintf("%d_%d", i, j)] = panel
+				panels[key] = panel
 			}
 		} else {
-			panel, err := m.migratePanel(p, useDefaultDatasource)
+			key := fmt.Sprintf("%d", i)
+			panel, err := m.migratePanel(p, key, useDefaultDatasource, report)
 			if err != nil {
 				return nil, err
 			}
-			panels[fmt.Sprintf("%d", i)] = panel
+			panels[key] = panel
 		}
 	}
 	return panels, nil
 }
 
-func (m *completeMigration) migratePanel(grafanaPanel Panel, useDefaultDatasource bool) (*dashboard.Panel, error) {
+func (m *completeMigration) migratePanel(grafanaPanel Panel, key string, useDefaultDatasource bool, report *modelAPI.MigrationReport) (*dashboard.Panel, error) {
 	result := &dashboard.Panel{
 		Kind: string(plugin.KindPanel),
 		Spec: dashboard.PanelSpec{
@@ -102,6 +124,12 @@ func (m *completeMigration) migratePanel(grafanaPanel Panel, useDefaultDatasourc
 	if len(grafanaPanel.Title) > 0 {
 		result.Spec.Display.Name = grafanaPanel.Title
 	}
+	item := modelAPI.MigrationItem{
+		Kind:        modelAPI.MigrationItemKindPanel,
+		Name:        result.Spec.Display.Name,
+		Ref:         panelRef(key),
+		GrafanaType: grafanaPanel.Type,
+	}
 	// first try to load the migration script from the dev migration instance.
 	migrateScriptInstance, ok := m.devMig.panels[grafanaPanel.Type]
 	if !ok {
@@ -109,6 +137,25 @@ func (m *completeMigration) migratePanel(grafanaPanel Panel, useDefaultDatasourc
 		migrateScriptInstance, ok = m.mig.panels[grafanaPanel.Type]
 		if !ok {
 			result.Spec.Plugin = defaultPanelPlugin
+			item.Status = modelAPI.MigrationStatusFallback
+			item.Plugin = defaultPanelPlugin.Kind
+			item.Reason = fmt.Sprintf("no migration script available for the Grafana panel type %q", grafanaPanel.Type)
+			if grafanaPanel.LibraryPanel != nil && len(grafanaPanel.Type) == 0 {
+				item.Reason = fmt.Sprintf("the library panel %q (%s) has not been provided", grafanaPanel.LibraryPanel.Name, grafanaPanel.LibraryPanel.UID)
+			}
+			report.Add(item)
+			// The queries are not migrated, as there is no panel to display them.
+			for _, target := range grafanaPanel.Targets {
+				refID, datasourceType := describeTarget(target)
+				report.Add(modelAPI.MigrationItem{
+					Kind:        modelAPI.MigrationItemKindQuery,
+					Name:        refID,
+					Ref:         item.Ref,
+					GrafanaType: datasourceType,
+					Status:      modelAPI.MigrationStatusDropped,
+					Reason:      "the panel of the query has not been migrated",
+				})
+			}
 			return result, nil
 		}
 	}
@@ -118,10 +165,15 @@ func (m *completeMigration) migratePanel(grafanaPanel Panel, useDefaultDatasourc
 	}
 	if panelMigrationIsEmpty {
 		result.Spec.Plugin = defaultPanelPlugin
+		item.Status = modelAPI.MigrationStatusFallback
+		item.Reason = fmt.Sprintf("the migration script of the plugin %s didn't convert the panel", migrateScriptInstance.name)
 	} else {
 		result.Spec.Plugin = *panelPlugin
+		item.Status = modelAPI.MigrationStatusMigrated
 	}
-	m.migrateQueries(grafanaPanel.Targets, result)
+	item.Plugin = result.Spec.Plugin.Kind
+	report.Add(item)
+	m.migrateQueries(grafanaPanel.Targets, result, item.Ref, report)
 	result.Spec.Links = convertGrafanaLinksToPerses(grafanaPanel.Links)
 
 	// Apply datasource cleaning if the flag is set
@@ -140,9 +192,17 @@ func (m *completeMigration) migratePanel(grafanaPanel Panel, useDefaultDatasourc
 	return result, nil
 }
 
-func (m *completeMigration) migrateQueries(targets []json.RawMessage, result *dashboard.Panel) {
+func (m *completeMigration) migrateQueries(targets []json.RawMessage, result *dashboard.Panel, ref string, report *modelAPI.MigrationReport) {
 	// As Grafana does not provide a type of their queries, we can only execute every query migration script hoping there is only one that matches the target.
 	for _, target := range targets {
+		refID, datasourceType := describeTarget(target)
+		item := modelAPI.MigrationItem{
+			Kind:        modelAPI.MigrationItemKindQuery,
+			Name:        refID,
+			Ref:         fmt.Sprintf("%s/spec/queries/%d", ref, len(result.Spec.Queries)),
+			GrafanaType: datasourceType,
+			Status:      modelAPI.MigrationStatusMigrated,
+		}
 		// We try first to execute the migration script from the dev migration instance.
 		isQueryMigrationEmpty := migrateQuery(m.devMig.queries, target, result)
 		if isQueryMigrationEmpty {
@@ -155,8 +215,12 @@ func (m *completeMigration) migrateQueries(targets []json.RawMessage, result *da
 						Plugin: defaultQueryPlugin,
 					},
 				})
+				item.Status = modelAPI.MigrationStatusFallback
+				item.Reason = "no migration script could convert the query"
 			}
 		}
+		item.Plugin = result.Spec.Queries[len(result.Spec.Queries)-1].Spec.Plugin.Kind
+		report.Add(item)
 	}
 }
 
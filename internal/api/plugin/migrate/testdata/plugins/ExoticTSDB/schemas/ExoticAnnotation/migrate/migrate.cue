@@ -0,0 +1,13 @@
+package migrate
+
+#grafanaAnnotation: _
+
+if (*#grafanaAnnotation.datasource.type | null) == "exotic-tsdb" {
+	kind: "ExoticAnnotation"
+	spec: {
+		query: #grafanaAnnotation.expr
+		if #grafanaAnnotation.titleFormat != _|_ {
+			titleFormat: #grafanaAnnotation.titleFormat
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package export
+
+#plugin: {
+	kind: "ExoticQuery"
+	spec: {
+		query: string
+		...
+	}
+}
+
+datasource: type: "exotic-tsdb"
+expr: #plugin.spec.query
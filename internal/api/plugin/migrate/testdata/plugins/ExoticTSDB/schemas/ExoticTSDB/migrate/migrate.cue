@@ -0,0 +1,10 @@
+package migrate
+
+#grafanaDatasource: _
+
+if #grafanaDatasource.type == "exotic-tsdb" {
+	kind: "ExoticTSDB"
+	spec: {
+		url: #grafanaDatasource.url
+	}
+}
@@ -0,0 +1,9 @@
+package export
+
+#plugin: {
+	kind: "FooChart"
+	spec: _
+}
+
+type: "timeseries"
+options: legend: showLegend: true
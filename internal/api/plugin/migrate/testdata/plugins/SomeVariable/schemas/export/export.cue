@@ -0,0 +1,11 @@
+package export
+
+import "strings"
+
+#plugin: {
+	kind: "SomeVariable"
+	spec: values: [...string]
+}
+
+type:  "custom"
+query: strings.Join(#plugin.spec.values, ",")
@@ -15,8 +15,10 @@ package migrate
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"cuelang.org/go/cue/build"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/spec/go/common"
 	"github.com/perses/spec/go/dashboard"
 	"github.com/perses/spec/go/dashboard/variable"
@@ -75,24 +77,44 @@ func grafanaMappingSort(sort *int) *variable.Sort {
 	return &mappingSort[i]
 }
 
-func (m *completeMigration) migrateVariables(grafanaDashboard *SimplifiedDashboard) []dashboard.Variable {
+func (m *completeMigration) migrateVariables(grafanaDashboard *SimplifiedDashboard, report *modelAPI.MigrationReport) []dashboard.Variable {
 	var result []dashboard.Variable
-	for _, v := range grafanaDashboard.Templating.List {
+	for i, v := range grafanaDashboard.Templating.List {
+		item := modelAPI.MigrationItem{
+			Kind:        modelAPI.MigrationItemKindVariable,
+			Name:        v.Name,
+			Ref:         fmt.Sprintf("#/spec/variables/%d", i),
+			GrafanaType: v.Type,
+			Status:      modelAPI.MigrationStatusMigrated,
+		}
 		if v.Type == "constant" || v.Type == "textbox" {
 			persesStaticVariable := migrateTextVariable(v)
 			if persesStaticVariable == nil {
 				result = append(result, buildDefaultVariable(v))
+				item.Status = modelAPI.MigrationStatusFallback
+				item.Plugin = defaultVariablePlugin.Kind
+				item.Reason = "unable to read the value of the variable"
 			} else {
 				result = append(result, *persesStaticVariable)
+				item.Plugin = string(persesStaticVariable.Kind)
 			}
 		} else {
-			result = append(result, m.migrateListVariable(v))
+			persesListVariable, isMigrated := m.migrateListVariable(v)
+			result = append(result, persesListVariable)
+			item.Plugin = persesListVariable.Spec.(*dashboard.ListVariableSpec).Plugin.Kind
+			if !isMigrated {
+				item.Status = modelAPI.MigrationStatusFallback
+				item.Reason = fmt.Sprintf("no migration script could convert the variable of type %q", v.Type)
+			}
 		}
+		report.Add(item)
 	}
 	return result
 }
 
-func (m *completeMigration) migrateListVariable(v TemplateVar) dashboard.Variable {
+// migrateListVariable returns the Perses list variable, and false when the placeholder has been used
+// because no migration script could convert the Grafana variable.
+func (m *completeMigration) migrateListVariable(v TemplateVar) (dashboard.Variable, bool) {
 	result := dashboard.Variable{
 		Kind: variable.KindList,
 	}
@@ -120,11 +142,11 @@ func (m *completeMigration) migrateListVariable(v TemplateVar) dashboard.Variabl
 	if isQueryMigrationEmpty {
 		isQueryMigrationEmpty = migrateListVar(m.mig.variables, v, spec)
 		if isQueryMigrationEmpty {
-			return buildDefaultVariable(v)
+			return buildDefaultVariable(v), false
 		}
 	}
 	result.Spec = spec
-	return result
+	return result, true
 }
 
 func migrateListVar(varInstances map[string]*build.Instance, v TemplateVar, specResult *dashboard.ListVariableSpec) bool {
@@ -23,7 +23,14 @@ import (
 	"github.com/perses/common/set"
 	"github.com/perses/perses/internal/api/plugin/migrate"
 	testUtils "github.com/perses/perses/internal/test"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/dashboard"
+	"github.com/perses/spec/go/dashboard/variable"
+	"github.com/perses/spec/go/datasource"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -81,7 +88,7 @@ func TestMig_Migrate(t *testing.T) {
 			if unmarshallErr := json.Unmarshal(input, grafanaDashboard); unmarshallErr != nil {
 				t.Fatal(unmarshallErr)
 			}
-			persesDashboard, err := pl.Migration().Migrate(grafanaDashboard, false)
+			persesDashboard, _, err := pl.Migration().Migrate(grafanaDashboard, false)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -99,11 +106,295 @@ func TestMig_MigrateTags(t *testing.T) {
 		Tags:  []string{"ops", "prod", "ops"},
 	}
 
-	persesDashboard, err := pl.Migration().Migrate(grafanaDashboard, false)
+	persesDashboard, _, err := pl.Migration().Migrate(grafanaDashboard, false)
 	assert.NoError(t, err)
 	assert.Equal(t, set.New("ops", "prod"), persesDashboard.Metadata.Tags)
 }
 
+func TestMig_MigrateSettings(t *testing.T) {
+	pl := LoadTestPlugins()
+	testSuite := []struct {
+		title            string
+		grafanaDashboard string
+		expectedSpec     dashboard.Spec
+		expectedWarnings []string
+	}{
+		{
+			title:            "no settings",
+			grafanaDashboard: `{"uid": "test", "title": "Test"}`,
+			expectedSpec: dashboard.Spec{
+				Display:  &common.Display{Name: "Test"},
+				Duration: "1h",
+			},
+		},
+		{
+			title: "supported settings",
+			grafanaDashboard: `{
+				"uid": "test",
+				"title": "Test",
+				"description": "A test dashboard",
+				"time": {"from": "now-7d", "to": "now"},
+				"refresh": "30s",
+				"timepicker": {"refresh_intervals": ["5s", "10s", "30s", "1m", "5m", "15m", "30m", "1h", "2h", "1d"]},
+				"links": [{"title": "Runbook", "type": "link", "url": "https://runbooks.example.com/${service}", "tooltip": "How to fix it", "targetBlank": true}]
+			}`,
+			expectedSpec: dashboard.Spec{
+				Display:         &common.Display{Name: "Test", Description: "A test dashboard"},
+				Duration:        "7d",
+				RefreshInterval: "30s",
+				Links: []dashboard.Link{
+					{Name: "Runbook", URL: "https://runbooks.example.com/${service}", Tooltip: "How to fix it", TargetBlank: true, RenderVariables: true},
+				},
+			},
+		},
+		{
+			title: "disabled refresh",
+			grafanaDashboard: `{
+				"uid": "test",
+				"title": "Test",
+				"time": {"from": "now-30m", "to": "now"},
+				"refresh": false
+			}`,
+			expectedSpec: dashboard.Spec{
+				Display:  &common.Display{Name: "Test"},
+				Duration: "30m",
+			},
+		},
+		{
+			title: "unsupported settings",
+			grafanaDashboard: `{
+				"uid": "test",
+				"title": "Test",
+				"time": {"from": "now/d", "to": "now/d"},
+				"refresh": "1M",
+				"timepicker": {"refresh_intervals": ["1m", "10m"]},
+				"links": [
+					{"title": "Related", "type": "dashboards", "tags": ["team-a", "prod"]},
+					{"title": "Home", "type": "link", "url": "/d/home", "includeVars": true}
+				]
+			}`,
+			expectedSpec: dashboard.Spec{
+				Display:  &common.Display{Name: "Test"},
+				Duration: "1h",
+				Links: []dashboard.Link{
+					{Name: "Home", URL: "/d/home"},
+				},
+			},
+			expectedWarnings: []string{
+				`the time range from "now/d" to "now/d" is not supported, only the time ranges ending now can be migrated. The default duration 1h is used`,
+				`the refresh interval "1M" is not supported`,
+				`the custom refresh intervals 1m, 10m are not supported, the default ones are used`,
+				`the link "Related" listing the dashboards with the tags [team-a, prod] is not supported`,
+				`the link "Home" cannot include the current variables or time range, it's migrated as a simple link`,
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			grafanaDashboard := &migrate.SimplifiedDashboard{}
+			if err := json.Unmarshal([]byte(test.grafanaDashboard), grafanaDashboard); err != nil {
+				t.Fatal(err)
+			}
+			persesDashboard, report, err := pl.Migration().Migrate(grafanaDashboard, false)
+			if !assert.NoError(t, err) {
+				return
+			}
+			// Only the settings are checked here, the panels and the variables are covered by the other tests.
+			persesDashboard.Spec.Panels = nil
+			persesDashboard.Spec.Variables = nil
+			persesDashboard.Spec.Layouts = nil
+			assert.Equal(t, test.expectedSpec, persesDashboard.Spec.Spec)
+			assert.Equal(t, test.expectedWarnings, report.Warnings)
+		})
+	}
+}
+
+func TestMig_MigrateReport(t *testing.T) {
+	pl := LoadTestPlugins()
+	grafanaDashboard := &migrate.SimplifiedDashboard{}
+	if err := json.Unmarshal([]byte(`{
+		"uid": "test",
+		"title": "Test",
+		"panels": [
+			{
+				"type": "timeseries",
+				"title": "Latency",
+				"targets": [
+					{"refId": "A", "datasource": {"type": "exotic-tsdb", "uid": "exotic"}, "expr": "latency"},
+					{"refId": "B", "datasource": {"type": "prometheus", "uid": "prom"}, "expr": "up"}
+				]
+			},
+			{
+				"type": "row",
+				"title": "Details",
+				"panels": [
+					{
+						"type": "gauge",
+						"title": "Saturation",
+						"targets": [{"refId": "A", "datasource": {"type": "exotic-tsdb", "uid": "exotic"}, "expr": "saturation"}]
+					}
+				]
+			}
+		],
+		"templating": {
+			"list": [
+				{"name": "env", "type": "custom", "query": "dev,prod"},
+				{"name": "job", "type": "query", "query": "label_values(job)"},
+				{"name": "filter", "type": "textbox", "query": "foo"}
+			]
+		}
+	}`), grafanaDashboard); err != nil {
+		t.Fatal(err)
+	}
+	_, report, err := pl.Migration().Migrate(grafanaDashboard, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	expected := &modelAPI.MigrationReport{
+		Summary: modelAPI.MigrationSummary{
+			Migrated: 4,
+			Fallback: 3,
+			Dropped:  1,
+			Score:    50,
+		},
+		Items: []modelAPI.MigrationItem{
+			{Kind: modelAPI.MigrationItemKindPanel, Name: "Latency", Ref: "#/spec/panels/0", GrafanaType: "timeseries", Status: modelAPI.MigrationStatusMigrated, Plugin: "FooChart"},
+			{Kind: modelAPI.MigrationItemKindQuery, Name: "A", Ref: "#/spec/panels/0/spec/queries/0", GrafanaType: "exotic-tsdb", Status: modelAPI.MigrationStatusMigrated, Plugin: "ExoticQuery"},
+			{Kind: modelAPI.MigrationItemKindQuery, Name: "B", Ref: "#/spec/panels/0/spec/queries/1", GrafanaType: "prometheus", Status: modelAPI.MigrationStatusFallback, Plugin: "PrometheusTimeSeriesQuery", Reason: "no migration script could convert the query"},
+			{Kind: modelAPI.MigrationItemKindPanel, Name: "Saturation", Ref: "#/spec/panels/1_0", GrafanaType: "gauge", Status: modelAPI.MigrationStatusFallback, Plugin: "Markdown", Reason: `no migration script available for the Grafana panel type "gauge"`},
+			{Kind: modelAPI.MigrationItemKindQuery, Name: "A", Ref: "#/spec/panels/1_0", GrafanaType: "exotic-tsdb", Status: modelAPI.MigrationStatusDropped, Reason: "the panel of the query has not been migrated"},
+			{Kind: modelAPI.MigrationItemKindVariable, Name: "env", Ref: "#/spec/variables/0", GrafanaType: "custom", Status: modelAPI.MigrationStatusMigrated, Plugin: "SomeVariable"},
+			{Kind: modelAPI.MigrationItemKindVariable, Name: "job", Ref: "#/spec/variables/1", GrafanaType: "query", Status: modelAPI.MigrationStatusFallback, Plugin: "StaticListVariable", Reason: `no migration script could convert the variable of type "query"`},
+			{Kind: modelAPI.MigrationItemKindVariable, Name: "filter", Ref: "#/spec/variables/2", GrafanaType: "textbox", Status: modelAPI.MigrationStatusMigrated, Plugin: "TextVariable"},
+		},
+	}
+	assert.Equal(t, expected, report)
+}
+
+func TestMig_MigrateAnnotations(t *testing.T) {
+	pl := LoadTestPlugins()
+	grafanaDashboard := &migrate.SimplifiedDashboard{}
+	if err := json.Unmarshal([]byte(`{
+		"uid": "test",
+		"title": "Test",
+		"annotations": {
+			"list": [
+				{"builtIn": 1, "datasource": {"type": "grafana", "uid": "-- Grafana --"}, "enable": true, "hide": true, "iconColor": "rgba(0, 211, 255, 1)", "name": "Annotations & Alerts", "type": "dashboard"},
+				{"datasource": {"type": "exotic-tsdb", "uid": "exotic"}, "enable": true, "iconColor": "red", "name": "Deployments", "expr": "deployments", "titleFormat": "{{version}}"},
+				{"datasource": {"type": "loki", "uid": "loki"}, "enable": false, "hide": true, "iconColor": "yellow", "name": "Incidents", "expr": "{app=\"incident\"}"}
+			]
+		}
+	}`), grafanaDashboard); err != nil {
+		t.Fatal(err)
+	}
+	persesDashboard, report, err := pl.Migration().Migrate(grafanaDashboard, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	expectedAnnotations := []modelV1.Annotation{
+		{
+			Kind: "Annotation",
+			Spec: modelV1.AnnotationSpec{
+				Display: &modelV1.AnnotationDisplay{Name: "Deployments"},
+				Enabled: true,
+				Color:   "red",
+				Plugin: common.Plugin{
+					Kind: "ExoticAnnotation",
+					Spec: map[string]any{
+						"query":       "deployments",
+						"titleFormat": "{{version}}",
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expectedAnnotations, persesDashboard.Spec.Annotations)
+	expectedItems := []modelAPI.MigrationItem{
+		{Kind: modelAPI.MigrationItemKindAnnotation, Name: "Deployments", Ref: "#/spec/annotations/0", GrafanaType: "exotic-tsdb", Status: modelAPI.MigrationStatusMigrated, Plugin: "ExoticAnnotation"},
+		{Kind: modelAPI.MigrationItemKindAnnotation, Name: "Incidents", Ref: "#/spec/annotations", GrafanaType: "loki", Status: modelAPI.MigrationStatusDropped, Reason: "no migration script could convert the annotation"},
+	}
+	assert.Equal(t, expectedItems, report.Items)
+}
+
+func TestResolveLibraryPanels(t *testing.T) {
+	libraryPanels := []migrate.GrafanaLibraryPanel{
+		{UID: "cpu", Name: "CPU", Model: json.RawMessage(`{"type": "timeseries", "title": "CPU usage", "gridPos": {"h": 1, "w": 1, "x": 0, "y": 0}, "targets": [{"expr": "cpu"}]}`)},
+	}
+	grafanaDashboard := `{
+		"title": "Test",
+		"__elements": {
+			"memory": {"name": "Memory", "uid": "memory", "kind": 1, "model": {"type": "stat", "title": "Memory usage"}}
+		},
+		"panels": [
+			{"id": 1, "title": "CPU", "gridPos": {"h": 8, "w": 12, "x": 0, "y": 0}, "libraryPanel": {"uid": "cpu", "name": "CPU"}},
+			{"type": "row", "title": "Details", "collapsed": true, "panels": [
+				{"id": 2, "title": "Memory", "gridPos": {"h": 8, "w": 12, "x": 12, "y": 1}, "libraryPanel": {"uid": "memory", "name": "Memory"}},
+				{"id": 3, "title": "Disk", "gridPos": {"h": 8, "w": 12, "x": 0, "y": 9}, "libraryPanel": {"uid": "disk", "name": "Disk"}}
+			]}
+		]
+	}`
+	resolved, err := migrate.ResolveLibraryPanels([]byte(grafanaDashboard), libraryPanels)
+	if !assert.NoError(t, err) {
+		return
+	}
+	dash := &migrate.SimplifiedDashboard{}
+	if err := json.Unmarshal(resolved, dash); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, dash.Panels, 2) || !assert.Len(t, dash.Panels[1].Panels, 2) {
+		return
+	}
+	cpu := dash.Panels[0]
+	assert.Equal(t, "timeseries", cpu.Type)
+	assert.Equal(t, "CPU usage", cpu.Title)
+	assert.Equal(t, migrate.GridPosition{Height: 8, Width: 12}, cpu.GridPosition)
+	assert.Len(t, cpu.Targets, 1)
+	assert.Equal(t, &migrate.GrafanaLibraryPanelRef{UID: "cpu", Name: "CPU"}, cpu.LibraryPanel)
+	memory := dash.Panels[1].Panels[0]
+	assert.Equal(t, "stat", memory.Type)
+	assert.Equal(t, "Memory usage", memory.Title)
+	assert.Equal(t, migrate.GridPosition{Height: 8, Width: 12, X: 12, Y: 1}, memory.GridPosition)
+	// The library panel "disk" is unknown, so the panel is left untouched.
+	disk := dash.Panels[1].Panels[1]
+	assert.Empty(t, disk.Type)
+	assert.Equal(t, "Disk", disk.Title)
+}
+
+func TestMig_MigrateRepeats(t *testing.T) {
+	pl := LoadTestPlugins()
+	grafanaDashboard := &migrate.SimplifiedDashboard{}
+	if err := json.Unmarshal([]byte(`{
+		"uid": "test",
+		"title": "Test",
+		"panels": [
+			{"type": "stat", "title": "Requests of $service", "repeat": "service", "gridPos": {"h": 4, "w": 6, "x": 0, "y": 0}},
+			{"type": "stat", "title": "Requests of $service", "repeatPanelId": 1, "gridPos": {"h": 4, "w": 6, "x": 6, "y": 0}},
+			{"type": "row", "title": "Environment $env", "repeat": "env", "collapsed": false, "gridPos": {"h": 1, "w": 24, "x": 0, "y": 4}},
+			{"type": "stat", "title": "Errors", "gridPos": {"h": 4, "w": 6, "x": 0, "y": 5}},
+			{"type": "row", "title": "Pods", "collapsed": true, "gridPos": {"h": 1, "w": 24, "x": 0, "y": 9}, "panels": [
+				{"type": "timeseries", "title": "CPU of $pod", "repeat": "pod", "gridPos": {"h": 4, "w": 12, "x": 0, "y": 10}},
+				{"type": "timeseries", "title": "Memory of $pod", "repeat": "pod", "gridPos": {"h": 4, "w": 12, "x": 12, "y": 10}}
+			]}
+		]
+	}`), grafanaDashboard); err != nil {
+		t.Fatal(err)
+	}
+	persesDashboard, report, err := pl.Migration().Migrate(grafanaDashboard, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// The copy of the repeated panel is not migrated.
+	assert.Len(t, persesDashboard.Spec.Panels, 4)
+	var repeatVariables []string
+	for _, layout := range persesDashboard.Spec.Layouts {
+		repeatVariables = append(repeatVariables, layout.Spec.(*dashboard.GridLayoutSpec).RepeatVariable)
+	}
+	assert.Equal(t, []string{"", "env", "pod"}, repeatVariables)
+	assert.Equal(t, []string{
+		`the panel "Requests of $service" is repeated for each value of the variable "service", which is only supported for all the panels of a row. It's displayed once`,
+	}, report.Warnings)
+}
+
 func TestLinkConversionLogic(t *testing.T) {
 	testSuite := []struct {
 		name                    string
@@ -171,3 +462,135 @@ func TestLinkConversionLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestMig_MigrateDatasources(t *testing.T) {
+	var grafanaDatasources []migrate.GrafanaDatasource
+	data := []byte(`[
+		{
+			"uid": "P1809F7CD0C75ACF3",
+			"name": "Exotic TSDB (prod)",
+			"type": "exotic-tsdb",
+			"url": "http://exotic.prod:9090",
+			"isDefault": true,
+			"basicAuth": true,
+			"basicAuthUser": "admin",
+			"secureJsonData": {"basicAuthPassword": "s3cr3t"}
+		},
+		{
+			"uid": "exotic-dev",
+			"name": "ExoticDev",
+			"type": "exotic-tsdb",
+			"url": "http://exotic.dev:9090",
+			"jsonData": {"httpHeaderName1": "Authorization", "tlsSkipVerify": true},
+			"secureJsonFields": {"httpHeaderValue1": true}
+		},
+		{
+			"uid": "loki",
+			"name": "Loki",
+			"type": "loki",
+			"url": "http://loki:3100"
+		}
+	]`)
+	if err := json.Unmarshal(data, &grafanaDatasources); err != nil {
+		t.Fatal(err)
+	}
+	pl := LoadTestPlugins()
+
+	result, err := pl.Migration().MigrateDatasources(grafanaDatasources, "perses")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]string{
+		"P1809F7CD0C75ACF3":  "Exotic-TSDB-prod",
+		"Exotic TSDB (prod)": "Exotic-TSDB-prod",
+		"exotic-dev":         "ExoticDev",
+		"ExoticDev":          "ExoticDev",
+		"loki":               "Loki",
+		"Loki":               "Loki",
+	}, result.References)
+	assert.Equal(t, []modelAPI.Entity{
+		&modelV1.Datasource{
+			Kind:     modelV1.KindDatasource,
+			Metadata: *modelV1.NewProjectMetadata("perses", "Exotic-TSDB-prod"),
+			Spec: datasource.Spec{
+				Display: &common.Display{Name: "Exotic TSDB (prod)"},
+				Default: true,
+				Plugin:  common.Plugin{Kind: "ExoticTSDB", Spec: map[string]any{"url": "http://exotic.prod:9090"}},
+			},
+		},
+		&modelV1.Datasource{
+			Kind:     modelV1.KindDatasource,
+			Metadata: *modelV1.NewProjectMetadata("perses", "ExoticDev"),
+			Spec: datasource.Spec{
+				Display: &common.Display{Name: "ExoticDev"},
+				Plugin:  common.Plugin{Kind: "ExoticTSDB", Spec: map[string]any{"url": "http://exotic.dev:9090"}},
+			},
+		},
+	}, result.Datasources)
+	assert.Equal(t, []modelAPI.Entity{
+		&modelV1.Secret{
+			Kind:     modelV1.KindSecret,
+			Metadata: *modelV1.NewProjectMetadata("perses", "Exotic-TSDB-prod-secret"),
+			Spec:     modelV1.SecretSpec{BasicAuth: &secret.BasicAuth{Username: "admin", Password: "s3cr3t"}},
+		},
+		&modelV1.Secret{
+			Kind:     modelV1.KindSecret,
+			Metadata: *modelV1.NewProjectMetadata("perses", "ExoticDev-secret"),
+			Spec: modelV1.SecretSpec{
+				Authorization: &secret.Authorization{Type: "Bearer", Credentials: "<to be replaced>"},
+				TLSConfig:     &secret.TLSConfig{InsecureSkipVerify: true},
+			},
+		},
+	}, result.Secrets)
+	assert.Equal(t, []string{
+		`Grafana doesn't expose the sensitive values of the datasource "ExoticDev", they must be set in the secret "ExoticDev-secret"`,
+		`no migration script found for the Grafana datasource "Loki" of type "loki"`,
+		`the Perses datasource "Exotic-TSDB-prod" doesn't use an HTTP proxy, the secret "Exotic-TSDB-prod-secret" must be referenced manually`,
+		`the Perses datasource "ExoticDev" doesn't use an HTTP proxy, the secret "ExoticDev-secret" must be referenced manually`,
+	}, result.Warnings)
+
+	// Without project, global resources are created.
+	globalResult, err := pl.Migration().MigrateDatasources(grafanaDatasources[:1], "")
+	if assert.NoError(t, err) && assert.Len(t, globalResult.Datasources, 1) && assert.Len(t, globalResult.Secrets, 1) {
+		assert.IsType(t, &modelV1.GlobalDatasource{}, globalResult.Datasources[0])
+		assert.IsType(t, &modelV1.GlobalSecret{}, globalResult.Secrets[0])
+	}
+}
+
+func TestRewriteDatasourceReferences(t *testing.T) {
+	dash := &modelV1.Dashboard{
+		Spec: modelV1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
+			Panels: map[string]*dashboard.Panel{
+				"0": {
+					Spec: dashboard.PanelSpec{
+						Queries: []dashboard.Query{
+							{Spec: dashboard.QuerySpec{Plugin: common.Plugin{Kind: "ExoticQuery", Spec: map[string]any{
+								"datasource": map[string]any{"kind": "ExoticTSDB", "name": "P1809F7CD0C75ACF3"},
+							}}}},
+							{Spec: dashboard.QuerySpec{Plugin: common.Plugin{Kind: "ExoticQuery", Spec: map[string]any{
+								"datasource": map[string]any{"kind": "ExoticTSDB", "name": "unknown"},
+							}}}},
+						},
+					},
+				},
+			},
+			Variables: []dashboard.Variable{
+				{
+					Kind: variable.KindList,
+					Spec: &dashboard.ListVariableSpec{ListSpec: variable.ListSpec{Plugin: common.Plugin{Kind: "SomeVariable", Spec: map[string]any{
+						"datasource": map[string]any{"kind": "ExoticTSDB", "name": "Exotic TSDB (prod)"},
+					}}}},
+				},
+			},
+		}},
+	}
+	migrate.RewriteDatasourceReferences(dash, map[string]string{
+		"P1809F7CD0C75ACF3":  "Exotic-TSDB-prod",
+		"Exotic TSDB (prod)": "Exotic-TSDB-prod",
+	})
+	queries := dash.Spec.Panels["0"].Spec.Queries
+	assert.Equal(t, "Exotic-TSDB-prod", queries[0].Spec.Plugin.Spec.(map[string]any)["datasource"].(map[string]any)["name"])
+	assert.Equal(t, "unknown", queries[1].Spec.Plugin.Spec.(map[string]any)["datasource"].(map[string]any)["name"])
+	variableSpec := dash.Spec.Variables[0].Spec.(*dashboard.ListVariableSpec)
+	assert.Equal(t, "Exotic-TSDB-prod", variableSpec.Plugin.Spec.(map[string]any)["datasource"].(map[string]any)["name"])
+}
@@ -16,12 +16,16 @@ package plugin
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/perses/perses/internal/api/plugin/export"
 	"github.com/perses/perses/internal/api/plugin/migrate"
+	"github.com/perses/perses/internal/api/plugin/registry"
 	"github.com/perses/perses/internal/api/plugin/schema"
 	"github.com/perses/perses/internal/api/plugin/tree"
 	"github.com/perses/perses/internal/cli/file"
@@ -49,11 +53,19 @@ type Plugin interface {
 	LoadDevPlugin(plugins []v1.PluginInDevelopment) error
 	RefreshDevPlugin(metadata plugin.ModuleMetadata) error
 	UnLoadDevPlugin(metadata plugin.ModuleMetadata) error
+	// Install extracts, validates and loads the plugin archive given, without requiring a restart.
+	Install(archive io.Reader) (*v1.PluginModule, error)
+	Uninstall(metadata plugin.ModuleMetadata) error
+	Enable(metadata plugin.ModuleMetadata) error
+	Disable(metadata plugin.ModuleMetadata) error
+	// SyncRequirements downloads the plugins required in the configuration from their registry and installs the missing ones.
+	SyncRequirements() ([]v1.PluginModule, error)
 	List() ([]byte, error)
 	UnzipArchives() error
 	GetLoadedPlugin(name, version, registry string) (*Loaded, bool)
 	Schema() schema.Schema
 	Migration() migrate.Migration
+	Export() export.Export
 }
 
 // StrictLoad is a helper function that loads the plugin from the default path.
@@ -83,10 +95,14 @@ func New(cfg config.Plugin) Plugin {
 			folders:      cfg.ArchivePaths,
 			targetFolder: cfg.Path,
 		},
-		sch:       schema.New(),
-		mig:       migrate.New(),
-		loaded:    make(tree.Tree[*Loaded]),
-		devLoaded: make(tree.Tree[*Loaded]),
+		sch:          schema.New(),
+		mig:          migrate.New(),
+		exp:          export.New(),
+		verifier:     newSignatureVerifier(cfg.Signature),
+		requirements: cfg.Requirements,
+		registry:     registry.New(cfg.RegistryCachePath, time.Duration(cfg.RegistryTimeout)),
+		loaded:       make(tree.Tree[*Loaded]),
+		devLoaded:    make(tree.Tree[*Loaded]),
 	}
 }
 
@@ -105,6 +121,15 @@ type pluginFile struct {
 	// mig is the service used to load and provide the migration schema of the plugin.
 	// This service is used when migrating the plugin from Grafana to Perses.
 	mig migrate.Migration
+	// exp is the service used to load and provide the export scripts of the plugin.
+	// This service is used when exporting a Perses dashboard to Grafana.
+	exp export.Export
+	// verifier checks the signature of the plugins before loading them.
+	verifier *signatureVerifier
+	// requirements is the list of plugins to download from a registry.
+	requirements []config.PluginRequirement
+	// registry is the client used to download the plugins listed in the requirements.
+	registry *registry.Client
 	// mutex will protect the loaded map.
 	mutex sync.RWMutex
 }
@@ -142,6 +167,10 @@ func (p *pluginFile) Migration() migrate.Migration {
 	return p.mig
 }
 
+func (p *pluginFile) Export() export.Export {
+	return p.exp
+}
+
 func (p *pluginFile) UnzipArchives() error {
 	return p.archibal.unzipAll()
 }
@@ -220,23 +249,48 @@ func (p *pluginFile) loadSinglePlugin(file os.DirEntry, pluginPath string) *v1.P
 	}
 	pluginModule.Spec = npmPackageData.Perses
 
-	if IsSchemaRequired(pluginModule.Spec) {
-		if pluginSchemaLoadErr := p.sch.Load(pluginPath, *pluginModule); pluginSchemaLoadErr != nil {
-			pluginStatus.IsLoaded = false
-			pluginStatus.Error = "unable to load plugin schema"
-			logrus.WithError(pluginSchemaLoadErr).Error(pluginStatus.Error)
-			return pluginModule
-		}
-		if pluginMigrateLoadErr := p.mig.Load(pluginPath, *pluginModule); pluginMigrateLoadErr != nil {
-			pluginStatus.IsLoaded = false
-			pluginStatus.Error = "unable to load plugin migration"
-			logrus.WithError(pluginMigrateLoadErr).Error(pluginStatus.Error)
-			return pluginModule
-		}
+	if isDisabled(pluginPath) {
+		logrus.Infof("plugin %q is disabled and is not loaded", manifest.Name)
+		pluginStatus.IsLoaded = false
+		pluginStatus.Disabled = true
+		return pluginModule
+	}
+
+	signatureStatus, signatureErr := p.verifier.verify(manifest.Name, pluginPath)
+	pluginStatus.Signature = signatureStatus
+	if signatureErr != nil {
+		pluginStatus.IsLoaded = false
+		pluginStatus.Error = "invalid plugin signature"
+		logrus.WithError(signatureErr).Errorf("plugin %q is not loaded", manifest.Name)
+		return pluginModule
+	}
+
+	if msg, loadErr := loadScripts(pluginPath, *pluginModule, p.sch, p.mig, p.exp); loadErr != nil {
+		pluginStatus.IsLoaded = false
+		pluginStatus.Error = msg
+		logrus.WithError(loadErr).Error(pluginStatus.Error)
 	}
 	return pluginModule
 }
 
+// loadScripts loads the schemas, the migration and the export scripts of the plugin module in the given services.
+// In case of error, it also returns the message to use as the status of the plugin module.
+func loadScripts(pluginPath string, module v1.PluginModule, sch schema.Schema, mig migrate.Migration, exp export.Export) (string, error) {
+	if !IsSchemaRequired(module.Spec) {
+		return "", nil
+	}
+	if err := sch.Load(pluginPath, module); err != nil {
+		return "unable to load plugin schema", err
+	}
+	if err := mig.Load(pluginPath, module); err != nil {
+		return "unable to load plugin migration", err
+	}
+	if err := exp.Load(pluginPath, module); err != nil {
+		return "unable to load plugin export", err
+	}
+	return "", nil
+}
+
 func (p *pluginFile) storeLoadedList() error {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
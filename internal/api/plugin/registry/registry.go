@@ -0,0 +1,212 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry downloads the plugin archives from a plugin registry.
+//
+// A registry is a simple HTTP server. For every plugin module, it exposes an index at `<registry>/<plugin name>/index.json`
+// listing the available releases. Each release gives the URL of the archive, absolute or relative to the index,
+// and the sha256 checksum of the archive.
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/perses/perses/internal/api/archive"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/sirupsen/logrus"
+)
+
+const IndexFileName = "index.json"
+
+// Release is a version of a plugin module available in the registry.
+type Release struct {
+	Version string `json:"version"`
+	// URL is the URL of the archive. It can be relative to the URL of the index.
+	URL string `json:"url"`
+	// SHA256 is the hex encoded sha256 checksum of the archive.
+	SHA256 string `json:"sha256"`
+}
+
+// Index is the list of the releases of a plugin module available in the registry.
+type Index struct {
+	Name     string    `json:"name"`
+	Releases []Release `json:"releases"`
+}
+
+type Client struct {
+	httpClient  *http.Client
+	cacheFolder string
+}
+
+// New creates a client downloading the archives in the given cache folder.
+// The timeout applies to every request sent to the registry, so a registry that doesn't answer cannot block the caller.
+func New(cacheFolder string, timeout time.Duration) *Client {
+	return &Client{
+		httpClient:  &http.Client{Timeout: timeout},
+		cacheFolder: cacheFolder,
+	}
+}
+
+// Fetch resolves the highest release of the plugin matching the version range, and downloads its archive if it is not
+// already in the cache. It returns the release and the path to the archive.
+func (c *Client) Fetch(registry *common.URL, name string, versionRange string) (*Release, string, error) {
+	indexURL := common.NewURL(registry, name, IndexFileName)
+	index, err := c.getIndex(indexURL)
+	if err != nil {
+		return nil, "", err
+	}
+	release, err := Resolve(index, versionRange)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to resolve the version of the plugin %q: %w", name, err)
+	}
+	archiveURL, err := indexURL.Parse(release.URL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid archive URL %q for the plugin %q: %w", release.URL, name, err)
+	}
+	archivePath, err := c.download(name, release, archiveURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return release, archivePath, nil
+}
+
+// Resolve returns the highest release of the index matching the version range.
+// An empty range matches any stable version.
+func Resolve(index *Index, versionRange string) (*Release, error) {
+	if len(versionRange) == 0 {
+		versionRange = "*"
+	}
+	constraint, err := semver.NewConstraint(versionRange)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version range %q: %w", versionRange, err)
+	}
+	var result *Release
+	var resultVersion *semver.Version
+	for i, release := range index.Releases {
+		version, parseErr := semver.NewVersion(release.Version)
+		if parseErr != nil {
+			logrus.WithError(parseErr).Warnf("invalid version %q of the plugin %q in the registry, it is ignored", release.Version, index.Name)
+			continue
+		}
+		if !constraint.Check(version) {
+			continue
+		}
+		if resultVersion == nil || version.GreaterThan(resultVersion) {
+			result = &index.Releases[i]
+			resultVersion = version
+		}
+	}
+	if result == nil {
+		return nil, fmt.Errorf("no release matches the version range %q", versionRange)
+	}
+	return result, nil
+}
+
+func (c *Client) getIndex(indexURL *common.URL) (*Index, error) {
+	body, err := c.get(indexURL.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the index %q: %w", indexURL.String(), err)
+	}
+	defer body.Close() //nolint:errcheck
+	index := &Index{}
+	if decodeErr := json.NewDecoder(body).Decode(index); decodeErr != nil {
+		return nil, fmt.Errorf("unable to decode the index %q: %w", indexURL.String(), decodeErr)
+	}
+	return index, nil
+}
+
+// download stores the archive of the release in the cache folder and returns its path.
+// The archive is downloaded only if the cache doesn't already contain it with the expected checksum.
+func (c *Client) download(name string, release *Release, archiveURL *url.URL) (string, error) {
+	archiveFileName := path.Base(archiveURL.Path)
+	if !archive.IsArchiveFile(archiveFileName) {
+		return "", fmt.Errorf("the archive %q of the plugin %q is not a supported archive", archiveFileName, name)
+	}
+	expectedChecksum := strings.ToLower(release.SHA256)
+	if len(expectedChecksum) == 0 {
+		return "", fmt.Errorf("the release %q of the plugin %q doesn't provide any checksum", release.Version, name)
+	}
+	folder := filepath.Join(c.cacheFolder, name)
+	archivePath := filepath.Join(folder, archiveFileName)
+	if checksum, err := fileChecksum(archivePath); err == nil && checksum == expectedChecksum {
+		logrus.Debugf("archive %q of the plugin %q found in the cache", archiveFileName, name)
+		return archivePath, nil
+	}
+	if err := os.MkdirAll(folder, 0750); err != nil {
+		return "", fmt.Errorf("unable to create the cache folder %q: %w", folder, err)
+	}
+	body, err := c.get(archiveURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to download the archive %q: %w", archiveURL.String(), err)
+	}
+	defer body.Close() //nolint:errcheck
+	// The archive is written in a temporary file, so an interrupted download or an invalid archive never reaches the cache.
+	tmpFile, err := os.CreateTemp(folder, ".download-")
+	if err != nil {
+		return "", fmt.Errorf("unable to create the temporary file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name()) //nolint:errcheck
+	h := sha256.New()
+	_, copyErr := io.Copy(io.MultiWriter(tmpFile, h), body)
+	if closeErr := tmpFile.Close(); copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		return "", fmt.Errorf("unable to download the archive %q: %w", archiveURL.String(), copyErr)
+	}
+	if checksum := hex.EncodeToString(h.Sum(nil)); checksum != expectedChecksum {
+		return "", fmt.Errorf("checksum mismatch for the archive %q: expected %q, got %q", archiveURL.String(), expectedChecksum, checksum)
+	}
+	if err := os.Rename(tmpFile.Name(), archivePath); err != nil {
+		return "", fmt.Errorf("unable to store the archive %q in the cache: %w", archiveFileName, err)
+	}
+	logrus.Infof("archive %q of the plugin %q downloaded", archiveFileName, name)
+	return archivePath, nil
+}
+
+func (c *Client) get(u *url.URL) (io.ReadCloser, error) {
+	resp, err := c.httpClient.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close() //nolint:errcheck,gosec
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func fileChecksum(filePath string) (string, error) {
+	f, err := os.Open(filePath) //nolint:gosec
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
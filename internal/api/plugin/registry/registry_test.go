@@ -0,0 +1,197 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRegistry is an HTTP stand-in for a plugin registry.
+type testRegistry struct {
+	server    *httptest.Server
+	downloads atomic.Int32
+}
+
+func newTestRegistry(t *testing.T, archives map[string]string, releases []Release) *testRegistry {
+	r := &testRegistry{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/registry/Prometheus/index.json", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Index{Name: "Prometheus", Releases: releases})
+	})
+	for name, content := range archives {
+		mux.HandleFunc("/registry/Prometheus/"+name, func(w http.ResponseWriter, _ *http.Request) {
+			r.downloads.Add(1)
+			_, _ = w.Write([]byte(content))
+		})
+	}
+	r.server = httptest.NewServer(mux)
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *testRegistry) url() *common.URL {
+	return common.MustParseURL(r.server.URL + "/registry")
+}
+
+func checksum(content string) string {
+	h := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
+func TestResolve(t *testing.T) {
+	index := &Index{
+		Name: "Prometheus",
+		Releases: []Release{
+			{Version: "0.5.0"},
+			{Version: "0.5.2"},
+			{Version: "v0.6.1"},
+			{Version: "0.7.0-rc.0"},
+			{Version: "not-a-version"},
+		},
+	}
+	testSuite := []struct {
+		versionRange    string
+		expectedVersion string
+		expectedError   bool
+	}{
+		{versionRange: "", expectedVersion: "v0.6.1"},
+		{versionRange: "^0.5.0", expectedVersion: "0.5.2"},
+		{versionRange: "~0.5.0", expectedVersion: "0.5.2"},
+		{versionRange: ">= 0.5.0, < 0.6.0", expectedVersion: "0.5.2"},
+		{versionRange: "0.5.0", expectedVersion: "0.5.0"},
+		{versionRange: ">= 0.7.0-0", expectedVersion: "0.7.0-rc.0"},
+		{versionRange: "^1.0.0", expectedError: true},
+		{versionRange: "not a range", expectedError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.versionRange, func(t *testing.T) {
+			release, err := Resolve(index, test.versionRange)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedVersion, release.Version)
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	registry := newTestRegistry(t,
+		map[string]string{
+			"Prometheus-0.5.0.tar.gz": "archive 0.5.0",
+			"Prometheus-0.6.0.tar.gz": "archive 0.6.0",
+		},
+		[]Release{
+			{Version: "0.5.0", URL: "Prometheus-0.5.0.tar.gz", SHA256: checksum("archive 0.5.0")},
+			{Version: "0.6.0", URL: "Prometheus-0.6.0.tar.gz", SHA256: checksum("archive 0.6.0")},
+		},
+	)
+	cacheFolder := t.TempDir()
+	client := New(cacheFolder, time.Minute)
+
+	release, archivePath, err := client.Fetch(registry.url(), "Prometheus", "^0.5.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.5.0", release.Version)
+	assert.Equal(t, filepath.Join(cacheFolder, "Prometheus", "Prometheus-0.5.0.tar.gz"), archivePath)
+	content, err := os.ReadFile(archivePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "archive 0.5.0", string(content))
+	assert.Equal(t, int32(1), registry.downloads.Load())
+
+	// The archive is now in the cache, it is not downloaded again.
+	_, _, err = client.Fetch(registry.url(), "Prometheus", "^0.5.0")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), registry.downloads.Load())
+
+	release, _, err = client.Fetch(registry.url(), "Prometheus", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.6.0", release.Version)
+	assert.Equal(t, int32(2), registry.downloads.Load())
+}
+
+func TestFetchErrors(t *testing.T) {
+	registry := newTestRegistry(t,
+		map[string]string{
+			"Prometheus-0.5.0.tar.gz": "archive 0.5.0",
+			"Prometheus-0.6.0.zip":    "archive 0.6.0",
+			"Prometheus-0.7.0.exe":    "archive 0.7.0",
+		},
+		[]Release{
+			{Version: "0.5.0", URL: "Prometheus-0.5.0.tar.gz", SHA256: checksum("tampered archive")},
+			{Version: "0.6.0", URL: "Prometheus-0.6.0.zip"},
+			{Version: "0.7.0", URL: "Prometheus-0.7.0.exe", SHA256: checksum("archive 0.7.0")},
+			{Version: "0.8.0", URL: "Prometheus-0.8.0.tar.gz", SHA256: checksum("archive 0.8.0")},
+		},
+	)
+	testSuite := []struct {
+		title         string
+		name          string
+		versionRange  string
+		expectedError string
+	}{
+		{
+			title:         "unknown plugin",
+			name:          "Unknown",
+			expectedError: "unexpected status code 404",
+		},
+		{
+			title:         "checksum mismatch",
+			name:          "Prometheus",
+			versionRange:  "0.5.0",
+			expectedError: "checksum mismatch",
+		},
+		{
+			title:         "missing checksum",
+			name:          "Prometheus",
+			versionRange:  "0.6.0",
+			expectedError: "doesn't provide any checksum",
+		},
+		{
+			title:         "not an archive",
+			name:          "Prometheus",
+			versionRange:  "0.7.0",
+			expectedError: "is not a supported archive",
+		},
+		{
+			title:         "archive not found",
+			name:          "Prometheus",
+			versionRange:  "0.8.0",
+			expectedError: "unexpected status code 404",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			cacheFolder := t.TempDir()
+			_, _, err := New(cacheFolder, time.Minute).Fetch(registry.url(), test.name, test.versionRange)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedError)
+			// Nothing is stored in the cache when the download fails.
+			files, _ := filepath.Glob(filepath.Join(cacheFolder, "*", "*"))
+			assert.Empty(t, files)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// SyncRequirements downloads the plugin modules required in the configuration and installs the ones not loaded yet.
+// A requirement that cannot be satisfied doesn't prevent the other ones from being installed.
+// It returns the plugin modules installed.
+func (p *pluginFile) SyncRequirements() ([]v1.PluginModule, error) {
+	installed := make([]v1.PluginModule, 0)
+	var errs []error
+	for _, requirement := range p.requirements {
+		release, archivePath, err := p.registry.Fetch(&requirement.Registry, requirement.Name, requirement.Version)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to fetch the plugin %q: %w", requirement.Name, err))
+			continue
+		}
+		// The version of a loaded plugin module is the one of its manifest, which doesn't have the "v" prefix.
+		version := strings.TrimPrefix(release.Version, "v")
+		if _, ok := p.GetLoadedPlugin(requirement.Name, version, ""); ok {
+			logrus.Debugf("plugin %q in version %q is already installed", requirement.Name, version)
+			continue
+		}
+		module, err := p.installArchiveFile(archivePath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to install the plugin %q in version %q: %w", requirement.Name, version, err))
+			continue
+		}
+		installed = append(installed, *module)
+	}
+	return installed, errors.Join(errs...)
+}
+
+func (p *pluginFile) installArchiveFile(archivePath string) (*v1.PluginModule, error) {
+	stream, err := os.Open(archivePath) //nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("unable to open archive file %q: %w", archivePath, err)
+	}
+	defer stream.Close() //nolint: errcheck
+	return p.Install(stream)
+}
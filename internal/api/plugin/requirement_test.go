@@ -0,0 +1,69 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/perses/perses/internal/api/plugin/registry"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncRequirements(t *testing.T) {
+	archive := buildTestPluginArchive(t, "SomeVariable").Bytes()
+	checksum := sha256.Sum256(archive)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/SomeVariable/index.json", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(registry.Index{
+			Name: "SomeVariable",
+			Releases: []registry.Release{
+				{Version: "0.10.0", URL: "SomeVariable-0.10.0.tar.gz", SHA256: hex.EncodeToString(checksum[:])},
+			},
+		})
+	})
+	mux.HandleFunc("/SomeVariable/SomeVariable-0.10.0.tar.gz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := New(config.Plugin{
+		Path:              t.TempDir(),
+		RegistryCachePath: t.TempDir(),
+		Requirements: []config.PluginRequirement{
+			{Name: "SomeVariable", Version: "^0.10.0", Registry: *common.MustParseURL(server.URL)},
+			{Name: "Unknown", Registry: *common.MustParseURL(server.URL)},
+		},
+	})
+	assert.NoError(t, svc.Load())
+
+	installed, err := svc.SyncRequirements()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unable to fetch the plugin "Unknown"`)
+	assert.Len(t, installed, 1)
+	assert.Equal(t, "SomeVariable", installed[0].Metadata.Name)
+	_, ok := svc.GetLoadedPlugin("SomeVariable", "0.10.0", "")
+	assert.True(t, ok)
+
+	// The plugin is already installed, nothing is installed again.
+	installed, _ = svc.SyncRequirements()
+	assert.Empty(t, installed)
+}
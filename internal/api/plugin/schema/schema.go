@@ -46,7 +46,8 @@ func Load(pluginPath string, moduleSpec plugin.ModuleSpec) ([]LoadSchema, error)
 			return err
 		}
 		if d.IsDir() {
-			if d.Name() == "migrate" {
+			// The migration and export scripts are loaded by their own services.
+			if d.Name() == "migrate" || d.Name() == "export" {
 				return fs.SkipDir
 			}
 			return nil
@@ -101,6 +102,7 @@ func getPlugin(plugins []plugin.Plugin, kind string) *plugin.Plugin {
 
 type Schema interface {
 	Load(pluginPath string, module v1.PluginModule) error
+	Unload(module v1.PluginModule)
 	LoadDevPlugin(pluginPath string, module v1.PluginModule) error
 	UnloadDevPlugin(module v1.PluginModule)
 	ValidateDatasource(plugin common.Plugin, dtsName string) error
@@ -109,6 +111,7 @@ type Schema interface {
 	ValidateGlobalVariable(v v1.VariableSpec) error
 	ValidateDashboardVariables([]dashboard.Variable) error
 	ValidateVariable(plugin common.Plugin, varName string) error
+	ValidateAnnotations(annotations []v1.Annotation) error
 	GetDatasourceSchema(pluginName string) (*build.Instance, error)
 }
 
@@ -132,6 +135,14 @@ func (s *completeSchema) Load(pluginPath string, module v1.PluginModule) error {
 	return s.sch.load(pluginPath, module)
 }
 
+func (s *completeSchema) Unload(module v1.PluginModule) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, p := range module.Spec.Plugins {
+		s.sch.remove(p.Kind, p.Spec.Name, module.Metadata)
+	}
+}
+
 func (s *completeSchema) LoadDevPlugin(pluginPath string, module v1.PluginModule) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -243,6 +254,33 @@ func (s *completeSchema) ValidateVariable(plugin common.Plugin, varName string)
 	return s.sch.validateVariable(plugin, varName)
 }
 
+// ValidateAnnotations verify a list of annotations defined in a dashboard.
+// The annotations are matched against the known list of CUE definitions (schemas).
+// If no schema matches for at least 1 annotation, the validation fails.
+func (s *completeSchema) ValidateAnnotations(annotations []v1.Annotation) error {
+	var errs []error
+	for _, annotation := range annotations {
+		annotationName := annotation.Spec.Display.Name
+		logrus.Tracef("Annotation to validate: %s", annotationName)
+		if err := s.validateAnnotation(annotation.Spec.Plugin, annotationName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		logrus.Debug("All annotations are valid")
+	}
+	return errors.Join(errs...)
+}
+
+func (s *completeSchema) validateAnnotation(plugin common.Plugin, annotationName string) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if _, ok := s.devSch.annotations.GetWithPluginMetadata(plugin.Kind, plugin.Metadata); ok {
+		return s.devSch.validateAnnotation(plugin, annotationName)
+	}
+	return s.sch.validateAnnotation(plugin, annotationName)
+}
+
 func (s *completeSchema) GetDatasourceSchema(pluginName string) (*build.Instance, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -271,6 +309,7 @@ type sch struct {
 	queries     tree.Tree[*build.Instance]
 	variables   tree.Tree[*build.Instance]
 	panels      tree.Tree[*build.Instance]
+	annotations tree.Tree[*build.Instance]
 }
 
 func newSch() *sch {
@@ -279,6 +318,7 @@ func newSch() *sch {
 		queries:     make(tree.Tree[*build.Instance]),
 		variables:   make(tree.Tree[*build.Instance]),
 		panels:      make(tree.Tree[*build.Instance]),
+		annotations: make(tree.Tree[*build.Instance]),
 	}
 }
 
@@ -298,6 +338,8 @@ func (s *sch) load(pluginPath string, module v1.PluginModule) error {
 				s.variables.Add(schema.Name, module.Metadata, schema.Instance)
 			case plugin.KindPanel:
 				s.panels.Add(schema.Name, module.Metadata, schema.Instance)
+			case plugin.KindAnnotation:
+				s.annotations.Add(schema.Name, module.Metadata, schema.Instance)
 			default:
 				return fmt.Errorf("unknown kind %s", schema.Kind)
 			}
@@ -317,6 +359,8 @@ func (s *sch) remove(kind plugin.Kind, name string, moduleMetadata plugin.Module
 			s.variables.Remove(name, moduleMetadata)
 		case plugin.KindPanel:
 			s.panels.Remove(name, moduleMetadata)
+		case plugin.KindAnnotation:
+			s.annotations.Remove(name, moduleMetadata)
 		}
 	}
 }
@@ -353,6 +397,14 @@ func (s *sch) validateVariable(plugin common.Plugin, variableName string) error
 	return validatePlugin(plugin, instance, "variable", variableName)
 }
 
+func (s *sch) validateAnnotation(plugin common.Plugin, annotationName string) error {
+	if len(s.annotations) == 0 {
+		return fmt.Errorf("annotation schemas are not loaded")
+	}
+	instance, _ := s.annotations.GetWithPluginMetadata(plugin.Kind, plugin.Metadata)
+	return validatePlugin(plugin, instance, "annotation", annotationName)
+}
+
 func (s *sch) getDatasourceSchema(datasourceName string, metadata *common.PluginMetadata) (*build.Instance, error) {
 	if len(s.datasources) == 0 {
 		return nil, fmt.Errorf("datasource schemas are not loaded")
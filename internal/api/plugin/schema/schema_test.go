@@ -195,7 +195,7 @@ func TestValidatePanels(t *testing.T) {
 			dashboard: &v1.Dashboard{
 				Kind:     v1.KindDashboard,
 				Metadata: metadata,
-				Spec: dashboard.Spec{
+				Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Duration:  "6h",
 					Variables: nil,
 					Panels: map[string]*dashboard.Panel{
@@ -219,7 +219,7 @@ func TestValidatePanels(t *testing.T) {
 						},
 					},
 					Layouts: []dashboard.Layout{},
-				},
+				}},
 			},
 			expectedErrorStr: "",
 		},
@@ -228,7 +228,7 @@ func TestValidatePanels(t *testing.T) {
 			dashboard: &v1.Dashboard{
 				Kind:     v1.KindDashboard,
 				Metadata: metadata,
-				Spec: dashboard.Spec{
+				Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Duration:  "6h",
 					Variables: nil,
 					Panels: map[string]*dashboard.Panel{
@@ -240,7 +240,7 @@ func TestValidatePanels(t *testing.T) {
 						},
 					},
 					Layouts: []dashboard.Layout{},
-				},
+				}},
 			},
 			expectedErrorStr: "schema not found for plugin UnknownChart",
 		},
@@ -249,7 +249,7 @@ func TestValidatePanels(t *testing.T) {
 			dashboard: &v1.Dashboard{
 				Kind:     v1.KindDashboard,
 				Metadata: metadata,
-				Spec: dashboard.Spec{
+				Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Duration:  "6h",
 					Variables: nil,
 					Panels: map[string]*dashboard.Panel{
@@ -261,7 +261,7 @@ func TestValidatePanels(t *testing.T) {
 						},
 					},
 					Layouts: []dashboard.Layout{},
-				},
+				}},
 			},
 			expectedErrorStr: "schema not found for plugin UnknownGraphQuery",
 		},
@@ -270,7 +270,7 @@ func TestValidatePanels(t *testing.T) {
 			dashboard: &v1.Dashboard{
 				Kind:     v1.KindDashboard,
 				Metadata: metadata,
-				Spec: dashboard.Spec{
+				Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Duration:  "6h",
 					Variables: nil,
 					Panels: map[string]*dashboard.Panel{
@@ -282,7 +282,7 @@ func TestValidatePanels(t *testing.T) {
 						},
 					},
 					Layouts: []dashboard.Layout{},
-				},
+				}},
 			},
 			expectedErrorStr: "invalid query n°1: spec.aaaaaa: field not allowed",
 		},
@@ -291,7 +291,7 @@ func TestValidatePanels(t *testing.T) {
 			dashboard: &v1.Dashboard{
 				Kind:     v1.KindDashboard,
 				Metadata: metadata,
-				Spec: dashboard.Spec{
+				Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Duration:  "6h",
 					Variables: nil,
 					Panels: map[string]*dashboard.Panel{
@@ -303,7 +303,7 @@ func TestValidatePanels(t *testing.T) {
 						},
 					},
 					Layouts: []dashboard.Layout{},
-				},
+				}},
 			},
 			expectedErrorStr: "invalid query n°1: spec.datasource.kind: conflicting values \"CustomDatasource\" and \"SQLDatasource\"",
 		},
@@ -348,7 +348,7 @@ func TestValidateDashboardVariables(t *testing.T) {
 			dashboard: &v1.Dashboard{
 				Kind:     v1.KindDashboard,
 				Metadata: metadata,
-				Spec: dashboard.Spec{
+				Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Duration: "6h",
 					Variables: []dashboard.Variable{
 						{
@@ -386,7 +386,7 @@ func TestValidateDashboardVariables(t *testing.T) {
 					},
 					Panels:  map[string]*dashboard.Panel{},
 					Layouts: []dashboard.Layout{},
-				},
+				}},
 			},
 			expectedErrorStr: "",
 		},
@@ -395,7 +395,7 @@ func TestValidateDashboardVariables(t *testing.T) {
 			dashboard: &v1.Dashboard{
 				Kind:     v1.KindDashboard,
 				Metadata: metadata,
-				Spec: dashboard.Spec{
+				Spec: v1.AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Duration: "6h",
 					Variables: []dashboard.Variable{
 						{
@@ -417,7 +417,7 @@ func TestValidateDashboardVariables(t *testing.T) {
 					},
 					Panels:  map[string]*dashboard.Panel{},
 					Layouts: []dashboard.Layout{},
-				},
+				}},
 			},
 			expectedErrorStr: "schema not found for plugin UnknownVariable",
 		},
@@ -434,6 +434,71 @@ func TestValidateDashboardVariables(t *testing.T) {
 	}
 }
 
+func TestValidateAnnotations(t *testing.T) {
+	s := New()
+	loadPlugin("testdata/schemas/annotations", []plugin.ModuleSpec{
+		{
+			SchemasPath: "deployment",
+			Plugins: []plugin.Plugin{
+				{
+					Kind: plugin.KindAnnotation,
+					Spec: plugin.Spec{
+						Name: "DeploymentAnnotation",
+					},
+				},
+			},
+		},
+	}, s, t)
+
+	newAnnotation := func(plg common.Plugin) v1.Annotation {
+		return v1.Annotation{
+			Kind: string(plugin.KindAnnotation),
+			Spec: v1.AnnotationSpec{
+				Display: &v1.AnnotationDisplay{Name: "Deployments"},
+				Enabled: true,
+				Plugin:  plg,
+			},
+		}
+	}
+
+	testSuite := []struct {
+		title            string
+		annotations      []v1.Annotation
+		expectedErrorStr string
+	}{
+		{
+			title: "valid annotation",
+			annotations: []v1.Annotation{
+				newAnnotation(common.Plugin{Kind: "DeploymentAnnotation", Spec: map[string]any{"query": "deployments"}}),
+			},
+		},
+		{
+			title: "annotation with an unwanted field",
+			annotations: []v1.Annotation{
+				newAnnotation(common.Plugin{Kind: "DeploymentAnnotation", Spec: map[string]any{"query": "deployments", "color": "red"}}),
+			},
+			expectedErrorStr: "invalid annotation Deployments",
+		},
+		{
+			title: "annotation of an unknown schema type",
+			annotations: []v1.Annotation{
+				newAnnotation(common.Plugin{Kind: "IncidentAnnotation", Spec: map[string]any{"query": "incidents"}}),
+			},
+			expectedErrorStr: "schema not found for plugin IncidentAnnotation",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			err := s.ValidateAnnotations(test.annotations)
+			if test.expectedErrorStr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErrorStr)
+			}
+		})
+	}
+}
+
 func TestSch_load_SuccessAndMissingPlugin(t *testing.T) {
 	// Successful load case
 	s := newSch()
@@ -0,0 +1,192 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signature computes the digest of a plugin module and verifies its ed25519 signature.
+//
+// The signature is detached from the content it signs: it is stored in the file FileName at the root of the plugin,
+// and covers the digest of every other file of the plugin. The same digest is computed from the files of the archive
+// when building the plugin and from the plugin folder once the archive has been extracted.
+package signature
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mholt/archives"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+)
+
+const (
+	// FileName is the name of the file containing the signature at the root of the plugin.
+	FileName = "perses-plugin.sig"
+	// digestPrefix is the prefix of the digest stored in the signature file. It gives the algorithm used.
+	digestPrefix = "sha256:"
+)
+
+// File is the content of the signature file.
+type File struct {
+	// Digest is the digest of the plugin content, prefixed by the algorithm used.
+	Digest string `json:"digest"`
+	// Signature is the ed25519 signature of the digest.
+	Signature []byte `json:"signature"`
+}
+
+// DigestFiles computes the digest of the given archive files.
+// The directories and the signature file are ignored. Every file is hashed, then the digest is the hash of the sorted
+// list of the file hashes with their path, so it does not depend on the order of the files in the archive.
+func DigestFiles(files []archives.FileInfo) ([]byte, error) {
+	sorted := make([]archives.FileInfo, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() || f.NameInArchive == FileName {
+			continue
+		}
+		sorted = append(sorted, f)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].NameInArchive < sorted[j].NameInArchive
+	})
+	h := sha256.New()
+	for _, f := range sorted {
+		fileHash, err := hashFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fmt.Fprintf(h, "%x  %s\n", fileHash, f.NameInArchive); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// DigestFolder computes the digest of the plugin stored in the given folder.
+func DigestFolder(folder string) ([]byte, error) {
+	// The trailing separator puts the content of the folder at the root, like it is in the archive.
+	files, err := archives.FilesFromDisk(context.Background(), nil, map[string]string{
+		strings.TrimSuffix(folder, string(os.PathSeparator)) + string(os.PathSeparator): "",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the files of the plugin: %w", err)
+	}
+	return DigestFiles(files)
+}
+
+func hashFile(f archives.FileInfo) ([]byte, error) {
+	stream, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the file %q: %w", f.NameInArchive, err)
+	}
+	defer stream.Close() //nolint:errcheck
+	h := sha256.New()
+	if _, err := io.Copy(h, stream); err != nil {
+		return nil, fmt.Errorf("unable to read the file %q: %w", f.NameInArchive, err)
+	}
+	return h.Sum(nil), nil
+}
+
+// Sign signs the digest with the private key and returns the content of the signature file.
+func Sign(digest []byte, key ed25519.PrivateKey) ([]byte, error) {
+	return json.Marshal(File{
+		Digest:    digestPrefix + hex.EncodeToString(digest),
+		Signature: ed25519.Sign(key, digest),
+	})
+}
+
+// Verify checks the plugin stored in the given folder is signed by one of the trusted keys.
+// It returns the signature status of the plugin, and an error explaining why the plugin is not verified.
+func Verify(folder string, trustedKeys []ed25519.PublicKey) (plugin.SignatureStatus, error) {
+	data, err := os.ReadFile(filepath.Join(folder, FileName)) //nolint: gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return plugin.SignatureStatusUnsigned, errors.New("the plugin is not signed")
+		}
+		return plugin.SignatureStatusInvalid, fmt.Errorf("unable to read the signature file: %w", err)
+	}
+	var sig File
+	if unmarshalErr := json.Unmarshal(data, &sig); unmarshalErr != nil {
+		return plugin.SignatureStatusInvalid, fmt.Errorf("unable to decode the signature file: %w", unmarshalErr)
+	}
+	if !strings.HasPrefix(sig.Digest, digestPrefix) {
+		return plugin.SignatureStatusInvalid, fmt.Errorf("unsupported digest %q, only sha256 is supported", sig.Digest)
+	}
+	digest, err := DigestFolder(folder)
+	if err != nil {
+		return plugin.SignatureStatusInvalid, err
+	}
+	if hex.EncodeToString(digest) != strings.TrimPrefix(sig.Digest, digestPrefix) {
+		return plugin.SignatureStatusInvalid, errors.New("the content of the plugin does not match the signed digest")
+	}
+	for _, key := range trustedKeys {
+		if ed25519.Verify(key, digest, sig.Signature) {
+			return plugin.SignatureStatusVerified, nil
+		}
+	}
+	return plugin.SignatureStatusInvalid, errors.New("the plugin is not signed by any of the trusted keys")
+}
+
+// ReadPublicKey reads a PEM encoded ed25519 public key, as generated by `openssl pkey -pubout`.
+func ReadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the public key %q: %w", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the public key %q is not an ed25519 key", path)
+	}
+	return publicKey, nil
+}
+
+// ReadPrivateKey reads a PEM encoded ed25519 private key, as generated by `openssl genpkey -algorithm ed25519`.
+func ReadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the private key %q: %w", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key %q is not an ed25519 key", path)
+	}
+	return privateKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path) //nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the key %q: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("the key %q is not PEM encoded", path)
+	}
+	return block, nil
+}
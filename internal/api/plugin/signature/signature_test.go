@@ -0,0 +1,178 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mholt/archives"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestPlugin(t *testing.T) string {
+	folder := t.TempDir()
+	files := map[string]string{
+		"package.json":          `{"name": "test"}`,
+		"mf-manifest.json":      `{"name": "Test"}`,
+		"schemas/test/test.cue": "package model",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(folder, name)), 0750))
+		assert.NoError(t, os.WriteFile(filepath.Join(folder, name), []byte(content), 0600))
+	}
+	return folder
+}
+
+func signTestPlugin(t *testing.T, folder string, key ed25519.PrivateKey) {
+	digest, err := DigestFolder(folder)
+	assert.NoError(t, err)
+	data, err := Sign(digest, key)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, FileName), data, 0600))
+}
+
+func generateKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return publicKey, privateKey
+}
+
+func TestDigestFiles(t *testing.T) {
+	folder := writeTestPlugin(t)
+	folderDigest, err := DigestFolder(folder)
+	assert.NoError(t, err)
+
+	// The archive is built from several folders when building the plugin. The digest must be the same as the one of the extracted folder.
+	files, err := archives.FilesFromDisk(context.Background(), nil, map[string]string{
+		filepath.Join(folder, "schemas"):          "schemas",
+		filepath.Join(folder, "mf-manifest.json"): "mf-manifest.json",
+		filepath.Join(folder, "package.json"):     "package.json",
+	})
+	assert.NoError(t, err)
+	archiveDigest, err := DigestFiles(files)
+	assert.NoError(t, err)
+	assert.Equal(t, folderDigest, archiveDigest)
+
+	// The signature file is not part of the digest.
+	_, privateKey := generateKey(t)
+	signTestPlugin(t, folder, privateKey)
+	signedDigest, err := DigestFolder(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, folderDigest, signedDigest)
+}
+
+func TestVerify(t *testing.T) {
+	publicKey, privateKey := generateKey(t)
+	otherPublicKey, otherPrivateKey := generateKey(t)
+	testSuite := []struct {
+		title          string
+		prepare        func(t *testing.T, folder string)
+		expectedStatus plugin.SignatureStatus
+		expectedError  string
+	}{
+		{
+			title: "signed by a trusted key",
+			prepare: func(t *testing.T, folder string) {
+				signTestPlugin(t, folder, privateKey)
+			},
+			expectedStatus: plugin.SignatureStatusVerified,
+		},
+		{
+			title: "signed by the second trusted key",
+			prepare: func(t *testing.T, folder string) {
+				signTestPlugin(t, folder, otherPrivateKey)
+			},
+			expectedStatus: plugin.SignatureStatusVerified,
+		},
+		{
+			title:          "not signed",
+			prepare:        func(_ *testing.T, _ string) {},
+			expectedStatus: plugin.SignatureStatusUnsigned,
+			expectedError:  "the plugin is not signed",
+		},
+		{
+			title: "signed by an unknown key",
+			prepare: func(t *testing.T, folder string) {
+				_, unknownKey := generateKey(t)
+				signTestPlugin(t, folder, unknownKey)
+			},
+			expectedStatus: plugin.SignatureStatusInvalid,
+			expectedError:  "the plugin is not signed by any of the trusted keys",
+		},
+		{
+			title: "file modified after the signature",
+			prepare: func(t *testing.T, folder string) {
+				signTestPlugin(t, folder, privateKey)
+				assert.NoError(t, os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{"name": "malicious"}`), 0600))
+			},
+			expectedStatus: plugin.SignatureStatusInvalid,
+			expectedError:  "the content of the plugin does not match the signed digest",
+		},
+		{
+			title: "file added after the signature",
+			prepare: func(t *testing.T, folder string) {
+				signTestPlugin(t, folder, privateKey)
+				assert.NoError(t, os.WriteFile(filepath.Join(folder, "malicious.js"), []byte("alert()"), 0600))
+			},
+			expectedStatus: plugin.SignatureStatusInvalid,
+			expectedError:  "the content of the plugin does not match the signed digest",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			folder := writeTestPlugin(t)
+			test.prepare(t, folder)
+			status, err := Verify(folder, []ed25519.PublicKey{publicKey, otherPublicKey})
+			assert.Equal(t, test.expectedStatus, status)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	publicKey, privateKey := generateKey(t)
+	folder := t.TempDir()
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.NoError(t, err)
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+	publicKeyPath := filepath.Join(folder, "key.pub")
+	privateKeyPath := filepath.Join(folder, "key.pem")
+	assert.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}), 0600))
+	assert.NoError(t, os.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyBytes}), 0600))
+
+	readPublicKey, err := ReadPublicKey(publicKeyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, readPublicKey)
+	readPrivateKey, err := ReadPrivateKey(privateKeyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, readPrivateKey)
+
+	_, err = ReadPublicKey(privateKeyPath)
+	assert.Error(t, err)
+}
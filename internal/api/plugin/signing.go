@@ -0,0 +1,59 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/ed25519"
+
+	"github.com/perses/perses/internal/api/plugin/signature"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/sirupsen/logrus"
+)
+
+type signatureVerifier struct {
+	policy      config.PluginSignaturePolicy
+	trustedKeys []ed25519.PublicKey
+}
+
+func newSignatureVerifier(cfg config.PluginSignature) *signatureVerifier {
+	verifier := &signatureVerifier{policy: cfg.Policy}
+	for _, keyPath := range cfg.TrustedKeys {
+		key, err := signature.ReadPublicKey(keyPath)
+		if err != nil {
+			// With the enforce policy, the plugins signed by this key won't be loaded.
+			logrus.WithError(err).Errorf("unable to read the trusted key %q, it is ignored", keyPath)
+			continue
+		}
+		verifier.trustedKeys = append(verifier.trustedKeys, key)
+	}
+	return verifier
+}
+
+// verify checks the signature of the plugin stored in pluginPath and returns its signature status.
+// An error is returned only when the plugin must not be loaded according to the policy.
+func (v *signatureVerifier) verify(pluginName string, pluginPath string) (plugin.SignatureStatus, error) {
+	if len(v.policy) == 0 || v.policy == config.PluginSignaturePolicyOff {
+		return "", nil
+	}
+	status, err := signature.Verify(pluginPath, v.trustedKeys)
+	if err == nil {
+		return status, nil
+	}
+	if v.policy == config.PluginSignaturePolicyWarn {
+		logrus.WithError(err).Warnf("the signature of the plugin %q cannot be verified, the plugin is loaded anyway", pluginName)
+		return status, nil
+	}
+	return status, err
+}
@@ -61,7 +61,7 @@ func IsRequiredFileExists(frontendFolder string, schemaFolder string, distFolder
 // IsSchemaRequired check if any plugins described in the module require a schema
 func IsSchemaRequired(moduleSpec plugin.ModuleSpec) bool {
 	for _, plg := range moduleSpec.Plugins {
-		if plg.Kind == plugin.KindDatasource || plg.Kind == plugin.KindPanel || plg.Kind == plugin.KindVariable || plg.Kind.IsQuery() {
+		if plg.Kind == plugin.KindDatasource || plg.Kind == plugin.KindPanel || plg.Kind == plugin.KindVariable || plg.Kind.IsQuery() || plg.Kind == plugin.KindAnnotation {
 			return true
 		}
 	}
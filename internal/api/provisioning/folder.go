@@ -0,0 +1,82 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioning
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/perses/perses/internal/cli/file"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// folderSync is the task provisioning the resources described in the local folders.
+type folderSync struct {
+	async.SimpleTask
+	*applier
+	folders []string
+	now     func() time.Time
+	mutex   sync.RWMutex
+	status  modelAPI.ProvisioningRunStatus
+}
+
+func (f *folderSync) Execute(_ context.Context, _ context.CancelFunc) error {
+	r := newRun(f.now())
+	for _, dir := range f.folders {
+		files, err := file.ListEntityFiles(dir)
+		if err != nil {
+			logrus.WithError(err).Warningf("unable to read the provisioning folder %q", dir)
+			r.fail(dir, err)
+			continue
+		}
+		for _, path := range files {
+			data, readErr := os.ReadFile(path) //nolint: gosec
+			if readErr != nil {
+				logrus.WithError(readErr).Warningf("unable to read the file %q", path)
+				r.fail(path, readErr)
+				continue
+			}
+			loaded, loadErr := loadFile(modelV1.ProvisioningSource{File: path}, data)
+			if loadErr != nil {
+				logrus.WithError(loadErr).Warningf("unable to load every entity from the file %q", path)
+				r.fail(path, loadErr)
+				continue
+			}
+			f.applyFile(r, loaded)
+		}
+	}
+	if f.prune {
+		f.pruneResources(r, "")
+	}
+	status := r.end()
+	f.mutex.Lock()
+	f.status = status
+	f.mutex.Unlock()
+	return nil
+}
+
+func (f *folderSync) Status() modelAPI.ProvisioningRunStatus {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.status
+}
+
+func (f *folderSync) String() string {
+	return "provisioning service"
+}
@@ -0,0 +1,205 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package git reads the resources to provision from a git repository.
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+// File is a file of the repository that may contain some resources.
+type File struct {
+	// Path is relative to the root of the repository.
+	Path string
+	Data []byte
+}
+
+// Repository is a local bare copy of the remote repository.
+type Repository struct {
+	conf config.GitProvisioning
+	repo *gogit.Repository
+}
+
+// Open opens the local copy of the repository stored in dir, or initializes it if it doesn't exist.
+func Open(conf config.GitProvisioning, dir string) (*Repository, error) {
+	repo, err := gogit.PlainOpen(dir)
+	if errors.Is(err, gogit.ErrRepositoryNotExists) {
+		if mkdirErr := os.MkdirAll(dir, 0750); mkdirErr != nil {
+			return nil, mkdirErr
+		}
+		repo, err = gogit.PlainInit(dir, true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the local copy of the repository %q: %w", conf.Name, err)
+	}
+	if _, remoteErr := repo.Remote(gogit.DefaultRemoteName); errors.Is(remoteErr, gogit.ErrRemoteNotFound) {
+		_, remoteErr = repo.CreateRemote(&gitConfig.RemoteConfig{Name: gogit.DefaultRemoteName, URLs: []string{conf.URL}})
+		if remoteErr != nil {
+			return nil, remoteErr
+		}
+	} else if remoteErr != nil {
+		return nil, remoteErr
+	}
+	return &Repository{conf: conf, repo: repo}, nil
+}
+
+// localRef is where the followed branch or tag is stored in the local copy.
+func (r *Repository) localRef() plumbing.ReferenceName {
+	if len(r.conf.Tag) > 0 {
+		return plumbing.NewTagReferenceName(r.conf.Tag)
+	}
+	return plumbing.NewRemoteReferenceName(gogit.DefaultRemoteName, r.conf.Branch)
+}
+
+func (r *Repository) refSpec() gitConfig.RefSpec {
+	remoteRef := plumbing.NewBranchReferenceName(r.conf.Branch)
+	if len(r.conf.Tag) > 0 {
+		remoteRef = plumbing.NewTagReferenceName(r.conf.Tag)
+	}
+	return gitConfig.RefSpec(fmt.Sprintf("+%s:%s", remoteRef, r.localRef()))
+}
+
+// Fetch downloads the latest changes of the followed branch or tag and returns the commit it points to.
+// secret is optional and provides the credentials and the TLS config used to reach the remote repository.
+func (r *Repository) Fetch(ctx context.Context, secret *modelV1.SecretSpec) (string, error) {
+	opts := &gogit.FetchOptions{
+		RemoteName: gogit.DefaultRemoteName,
+		RemoteURL:  r.conf.URL,
+		RefSpecs:   []gitConfig.RefSpec{r.refSpec()},
+		Tags:       gogit.NoTags,
+		Force:      true,
+	}
+	if err := applySecret(opts, secret); err != nil {
+		return "", err
+	}
+	if err := r.repo.FetchContext(ctx, opts); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return "", fmt.Errorf("unable to fetch %q from the repository %q: %w", r.refSpec(), r.conf.Name, err)
+	}
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(r.localRef()))
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %q in the repository %q: %w", r.localRef(), r.conf.Name, err)
+	}
+	return hash.String(), nil
+}
+
+// Files returns every JSON or YAML file found in the configured path at the given commit.
+// As the files are read from the git objects, they are all coming from the same commit.
+func (r *Repository) Files(commit string) ([]File, error) {
+	c, err := r.repo.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return nil, err
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.conf.Path) > 0 {
+		tree, err = tree.Tree(r.conf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the path %q at the commit %s: %w", r.conf.Path, commit, err)
+		}
+	}
+	var files []File
+	err = tree.Files().ForEach(func(f *object.File) error {
+		ext := path.Ext(f.Name)
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		reader, readerErr := f.Reader()
+		if readerErr != nil {
+			return readerErr
+		}
+		defer reader.Close() //nolint: errcheck
+		data, readErr := io.ReadAll(reader)
+		if readErr != nil {
+			return readErr
+		}
+		files = append(files, File{Path: path.Join(r.conf.Path, f.Name), Data: data})
+		return nil
+	})
+	return files, err
+}
+
+func applySecret(opts *gogit.FetchOptions, secret *modelV1.SecretSpec) error {
+	if secret == nil {
+		return nil
+	}
+	auth, err := buildAuth(secret)
+	if err != nil {
+		return err
+	}
+	opts.Auth = auth
+	if tlsConfig := secret.TLSConfig; tlsConfig != nil {
+		opts.InsecureSkipTLS = tlsConfig.InsecureSkipVerify
+		if opts.CABundle, err = readValueOrFile(tlsConfig.CA, tlsConfig.CAFile); err != nil {
+			return err
+		}
+		if opts.ClientCert, err = readValueOrFile(tlsConfig.Cert, tlsConfig.CertFile); err != nil {
+			return err
+		}
+		if opts.ClientKey, err = readValueOrFile(tlsConfig.Key, tlsConfig.KeyFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildAuth(secret *modelV1.SecretSpec) (transport.AuthMethod, error) {
+	if secret.BasicAuth != nil {
+		password, err := secret.BasicAuth.GetPassword()
+		if err != nil {
+			return nil, err
+		}
+		return &http.BasicAuth{Username: secret.BasicAuth.Username, Password: password}, nil
+	}
+	if secret.Authorization != nil {
+		credentials, err := secret.Authorization.GetCredentials()
+		if err != nil {
+			return nil, err
+		}
+		if len(secret.Authorization.Type) > 0 && !strings.EqualFold(secret.Authorization.Type, "bearer") {
+			return nil, fmt.Errorf("authorization type %q is not supported to reach a git repository, only bearer is", secret.Authorization.Type)
+		}
+		return &http.TokenAuth{Token: credentials}, nil
+	}
+	if secret.OAuth != nil {
+		return nil, errors.New("oauth is not supported to reach a git repository")
+	}
+	return nil, nil
+}
+
+func readValueOrFile(value string, file string) ([]byte, error) {
+	if len(file) > 0 {
+		return os.ReadFile(file) //nolint: gosec
+	}
+	if len(value) > 0 {
+		return []byte(value), nil
+	}
+	return nil, nil
+}
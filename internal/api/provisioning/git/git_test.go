@@ -0,0 +1,134 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRemote creates a repository acting as the remote one.
+func newRemote(t *testing.T) (string, *gogit.Repository) {
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, repo
+}
+
+func commit(t *testing.T, dir string, repo *gogit.Repository, files map[string]string) string {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wt.AddGlob("."); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("update", &gogit.CommitOptions{Author: &object.Signature{Name: "perses", Email: "perses@example.com", When: time.Now()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+func currentBranch(t *testing.T, repo *gogit.Repository) string {
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head.Name().Short()
+}
+
+func TestRepository_FetchAndFiles(t *testing.T) {
+	remoteDir, remote := newRemote(t)
+	first := commit(t, remoteDir, remote, map[string]string{
+		"dashboards/a.yaml": "kind: Project\nmetadata:\n  name: a\nspec: {}\n",
+		"README.md":         "not a resource",
+	})
+	conf := config.GitProvisioning{Name: "test", URL: remoteDir, Branch: currentBranch(t, remote)}
+	repo, err := Open(conf, filepath.Join(t.TempDir(), "local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sha, err := repo.Fetch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, first, sha)
+	files, err := repo.Files(sha)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "dashboards/a.yaml", files[0].Path)
+	}
+
+	second := commit(t, remoteDir, remote, map[string]string{"dashboards/b.json": `{"kind": "Project", "metadata": {"name": "b"}, "spec": {}}`})
+	sha, err = repo.Fetch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, second, sha)
+	files, err = repo.Files(sha)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// The previous commit is still readable as it is.
+	files, err = repo.Files(first)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestRepository_PathAndTag(t *testing.T) {
+	remoteDir, remote := newRemote(t)
+	tagged := commit(t, remoteDir, remote, map[string]string{
+		"perses/a.yaml": "kind: Project\nmetadata:\n  name: a\nspec: {}\n",
+		"other/b.yaml":  "kind: Project\nmetadata:\n  name: b\nspec: {}\n",
+	})
+	head, err := remote.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = remote.CreateTag("v1.0.0", head.Hash(), &gogit.CreateTagOptions{Message: "v1.0.0", Tagger: &object.Signature{Name: "perses", When: time.Now()}}); err != nil {
+		t.Fatal(err)
+	}
+	commit(t, remoteDir, remote, map[string]string{"perses/c.yaml": "kind: Project\nmetadata:\n  name: c\nspec: {}\n"})
+
+	conf := config.GitProvisioning{Name: "test", URL: remoteDir, Tag: "v1.0.0", Path: "perses"}
+	repo, err := Open(conf, filepath.Join(t.TempDir(), "local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha, err := repo.Fetch(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tagged, sha)
+	files, err := repo.Files(sha)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "perses/a.yaml", files[0].Path)
+	}
+}
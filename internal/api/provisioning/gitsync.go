@@ -0,0 +1,172 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/perses/perses/internal/api/crypto"
+	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
+	"github.com/perses/perses/internal/api/provisioning/git"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// gitSync is the task provisioning the resources described in a git repository.
+// Every file of a commit is loaded before anything is applied: if a single file cannot be loaded, nothing is applied,
+// and the resources stay at the state of the previous commit. The resources are then applied one by one, and the
+// commit is only recorded when all of them have been applied, so a commit partially applied is retried at the next run.
+type gitSync struct {
+	async.SimpleTask
+	*applier
+	conf    config.GitProvisioning
+	repo    *git.Repository
+	secrets globalsecret.DAO
+	crypto  crypto.Crypto
+	now     func() time.Time
+	// syncMutex serializes the runs triggered by the ticker and by the webhook.
+	syncMutex sync.Mutex
+	// queued is true when a run triggered by a webhook is waiting to be executed.
+	queued atomic.Bool
+	mutex  sync.RWMutex
+	status modelAPI.GitProvisioningStatus
+}
+
+func (g *gitSync) Execute(ctx context.Context, _ context.CancelFunc) error {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+	g.sync(ctx)
+	return nil
+}
+
+// trigger runs a synchronization in the background.
+// Triggers received while a run is already waiting are merged into it.
+func (g *gitSync) trigger() {
+	if !g.queued.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		g.syncMutex.Lock()
+		defer g.syncMutex.Unlock()
+		g.queued.Store(false)
+		g.sync(context.Background())
+	}()
+}
+
+func (g *gitSync) sync(ctx context.Context) {
+	r := newRun(g.now())
+	commit := g.Status().Commit
+	defer func() {
+		status := modelAPI.GitProvisioningStatus{
+			Name:                  g.conf.Name,
+			URL:                   g.conf.URL,
+			Ref:                   g.ref(),
+			Commit:                commit,
+			ProvisioningRunStatus: r.end(),
+		}
+		g.mutex.Lock()
+		g.status = status
+		g.mutex.Unlock()
+	}()
+
+	secret, err := g.getSecret()
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to get the secret of the git repository %q", g.conf.Name)
+		r.fail(g.conf.URL, err)
+		return
+	}
+	sha, err := g.repo.Fetch(ctx, secret)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to fetch the git repository %q", g.conf.Name)
+		r.fail(g.conf.URL, err)
+		return
+	}
+	files, err := g.repo.Files(sha)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to read the commit %s of the git repository %q", sha, g.conf.Name)
+		r.fail(g.conf.URL, err)
+		return
+	}
+	// Load every file before applying anything, so a broken commit doesn't leave the resources half-updated.
+	var loaded []*loadedFile
+	for _, f := range files {
+		lf, loadErr := loadFile(modelV1.ProvisioningSource{File: f.Path, Repository: g.conf.Name, Commit: sha}, f.Data)
+		if loadErr != nil {
+			r.fail(f.Path, loadErr)
+			continue
+		}
+		loaded = append(loaded, lf)
+	}
+	if len(r.failures) > 0 {
+		logrus.Errorf("the commit %s of the git repository %q contains invalid files, it won't be applied", sha, g.conf.Name)
+		return
+	}
+	for _, lf := range loaded {
+		g.applyFile(r, lf)
+	}
+	if g.prune {
+		g.pruneResources(r, g.conf.Name)
+	}
+	if len(r.failures) > 0 {
+		logrus.Errorf("the commit %s of the git repository %q has not been fully applied", sha, g.conf.Name)
+		return
+	}
+	commit = sha
+}
+
+func (g *gitSync) getSecret() (*modelV1.SecretSpec, error) {
+	if len(g.conf.Secret) == 0 {
+		return nil, nil
+	}
+	secret, err := g.secrets.Get(g.conf.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the global secret %q: %w", g.conf.Secret, err)
+	}
+	spec := secret.Spec
+	if decryptErr := g.crypto.Decrypt(&spec); decryptErr != nil {
+		return nil, decryptErr
+	}
+	return &spec, nil
+}
+
+func (g *gitSync) ref() string {
+	if len(g.conf.Tag) > 0 {
+		return "tags/" + g.conf.Tag
+	}
+	return "heads/" + g.conf.Branch
+}
+
+func (g *gitSync) Status() modelAPI.GitProvisioningStatus {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	status := g.status
+	if len(status.Name) == 0 {
+		// no run yet
+		status.Name = g.conf.Name
+		status.URL = g.conf.URL
+		status.Ref = g.ref()
+	}
+	return status
+}
+
+func (g *gitSync) String() string {
+	return fmt.Sprintf("git provisioning %q", g.conf.Name)
+}
@@ -0,0 +1,208 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/perses/perses/internal/api/provisioning/git"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRemote is a bare repository, fed by pushing the commits done in a local working copy.
+type testRemote struct {
+	t      *testing.T
+	url    string
+	dir    string
+	branch string
+	repo   *gogit.Repository
+}
+
+func newTestRemote(t *testing.T) *testRemote {
+	url := t.TempDir()
+	if _, err := gogit.PlainInit(url, true); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.CreateRemote(&gitConfig.RemoteConfig{Name: "origin", URLs: []string{url}}); err != nil {
+		t.Fatal(err)
+	}
+	return &testRemote{t: t, url: url, dir: dir, repo: repo}
+}
+
+// push commits the given files (an empty content removes the file) and pushes the commit to the bare repository.
+func (r *testRemote) push(files map[string]string) string {
+	t := r.t
+	for name, content := range files {
+		path := filepath.Join(r.dir, name)
+		if len(content) == 0 {
+			if err := os.Remove(path); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wt.AddWithOptions(&gogit.AddOptions{All: true}); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("update", &gogit.CommitOptions{Author: &object.Signature{Name: "perses", Email: "perses@example.com", When: time.Now()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.repo.Push(&gogit.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatal(err)
+	}
+	head, err := r.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.branch = head.Name().Short()
+	return hash.String()
+}
+
+func projectContent(name string) string {
+	return "kind: Project\nmetadata:\n  name: " + name + "\nspec: {}\n"
+}
+
+func newTestGitSync(t *testing.T, remote *testRemote, prune bool) (*gitSync, *fakeService) {
+	a, fake := newTestApplier(prune)
+	conf := config.GitProvisioning{Name: "infra", URL: remote.url, Branch: remote.branch, Path: "perses"}
+	repo, err := git.Open(conf, filepath.Join(t.TempDir(), "local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &gitSync{applier: a, conf: conf, repo: repo, now: time.Now}, fake
+}
+
+func TestGitSync_Commit(t *testing.T) {
+	remote := newTestRemote(t)
+	first := remote.push(map[string]string{
+		"perses/a.yaml": projectContent("a"),
+		"perses/b.yaml": projectContent("b"),
+		"other/c.yaml":  projectContent("c"),
+	})
+	g, fake := newTestGitSync(t, remote, true)
+	assert.NoError(t, g.Execute(context.Background(), nil))
+
+	status := g.Status()
+	assert.Empty(t, status.Failures)
+	assert.Equal(t, first, status.Commit)
+	assert.Equal(t, 2, status.Files)
+	assert.NotContains(t, fake.entities, "c")
+	source := fake.entities["a"].Metadata.Provisioning
+	if assert.NotNil(t, source) {
+		assert.Equal(t, "perses/a.yaml", source.File)
+		assert.Equal(t, "infra", source.Repository)
+		assert.Equal(t, first, source.Commit)
+	}
+
+	second := remote.push(map[string]string{
+		"perses/a.yaml": "kind: Project\nmetadata:\n  name: a\nspec:\n  display:\n    name: A\n",
+		"perses/b.yaml": "",
+	})
+	assert.NoError(t, g.Execute(context.Background(), nil))
+	assert.Equal(t, second, g.Status().Commit)
+	assert.Equal(t, second, fake.entities["a"].Metadata.Provisioning.Commit)
+	assert.NotContains(t, fake.entities, "b")
+	assert.Contains(t, fake.entities, "manual")
+}
+
+func TestGitSync_Atomic(t *testing.T) {
+	remote := newTestRemote(t)
+	first := remote.push(map[string]string{
+		"perses/a.yaml": projectContent("a"),
+	})
+	g, fake := newTestGitSync(t, remote, true)
+	assert.NoError(t, g.Execute(context.Background(), nil))
+
+	// The new commit adds a project but breaks another file: nothing must be applied.
+	remote.push(map[string]string{
+		"perses/a.yaml": "kind: Project\nmetadata: [",
+		"perses/b.yaml": projectContent("b"),
+	})
+	assert.NoError(t, g.Execute(context.Background(), nil))
+	status := g.Status()
+	assert.Equal(t, first, status.Commit)
+	if assert.Len(t, status.Failures, 1) {
+		assert.Equal(t, "perses/a.yaml", status.Failures[0].File)
+	}
+	assert.Contains(t, fake.entities, "a")
+	assert.NotContains(t, fake.entities, "b")
+}
+
+func TestGitSync_ApplyFailure(t *testing.T) {
+	remote := newTestRemote(t)
+	first := remote.push(map[string]string{
+		"perses/a.yaml": projectContent("a"),
+	})
+	g, fake := newTestGitSync(t, remote, true)
+	assert.NoError(t, g.Execute(context.Background(), nil))
+
+	// The commit is valid, but a resource cannot be applied: the commit must not be recorded.
+	second := remote.push(map[string]string{
+		"perses/b.yaml": projectContent("b"),
+	})
+	fake.createErr = errors.New("database unavailable")
+	assert.NoError(t, g.Execute(context.Background(), nil))
+	status := g.Status()
+	assert.Equal(t, first, status.Commit)
+	if assert.Len(t, status.Failures, 1) {
+		assert.Equal(t, "perses/b.yaml", status.Failures[0].File)
+	}
+
+	// The commit is applied again at the next run
+	fake.createErr = nil
+	assert.NoError(t, g.Execute(context.Background(), nil))
+	assert.Equal(t, second, g.Status().Commit)
+	assert.Contains(t, fake.entities, "b")
+}
+
+func TestGitSync_FetchFailure(t *testing.T) {
+	remote := newTestRemote(t)
+	remote.push(map[string]string{"perses/a.yaml": projectContent("a")})
+	g, fake := newTestGitSync(t, remote, true)
+	assert.NoError(t, g.Execute(context.Background(), nil))
+	assert.NoError(t, os.RemoveAll(remote.url))
+
+	// The resources must not be pruned when the repository cannot be fetched.
+	assert.NoError(t, g.Execute(context.Background(), nil))
+	status := g.Status()
+	if assert.Len(t, status.Failures, 1) {
+		assert.Equal(t, remote.url, status.Failures[0].File)
+	}
+	assert.Contains(t, fake.entities, "a")
+}
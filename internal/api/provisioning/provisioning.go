@@ -14,211 +14,271 @@
 package provisioning
 
 import (
-	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/perses/common/async"
+	"github.com/perses/common/async/taskhelper"
+	"github.com/perses/common/set"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	"github.com/perses/perses/internal/api/dependency"
 	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
+	"github.com/perses/perses/internal/api/provisioning/git"
 	"github.com/perses/perses/internal/cli/file"
 	"github.com/perses/perses/internal/cli/resource"
 	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
 )
 
-type insertFunc func() (modelAPI.Entity, error)
+// Provisioning applies periodically the resources described in the provisioning folders and git repositories.
+type Provisioning interface {
+	// Tasks returns the tasks synchronizing periodically every source.
+	Tasks() []taskhelper.Helper
+	// Status returns the result of the last run of every source.
+	Status() modelAPI.ProvisioningStatus
+	// SyncGit triggers in the background the synchronization of the given git repository.
+	// It returns false if the repository is not known.
+	SyncGit(name string) bool
+}
 
-func New(serviceManager dependency.ServiceManager, folders []string, caseSensitive bool) async.SimpleTask {
-	return &provisioning{
-		serviceManager: serviceManager,
-		folders:        folders,
-		caseSensitive:  caseSensitive,
+func New(serviceManager dependency.ServiceManager, globalSecretDAO globalsecret.DAO, conf config.ProvisioningConfig, caseSensitive bool) (Provisioning, error) {
+	a := &applier{
+		services:      buildServices(serviceManager),
+		caseSensitive: caseSensitive,
+		prune:         conf.Prune,
+	}
+	m := &manager{}
+	if len(conf.Folders) > 0 {
+		m.folders = &folderSync{applier: a, folders: conf.Folders, now: time.Now}
+		helper, err := taskhelper.NewTick(m.folders, time.Duration(conf.Interval))
+		if err != nil {
+			return nil, err
+		}
+		m.tasks = append(m.tasks, helper)
+	}
+	for _, g := range conf.Git {
+		repo, err := git.Open(g, filepath.Join(os.TempDir(), "perses", "provisioning", g.Name))
+		if err != nil {
+			return nil, err
+		}
+		gs := &gitSync{
+			applier: a,
+			conf:    g,
+			repo:    repo,
+			secrets: globalSecretDAO,
+			crypto:  serviceManager.GetCrypto(),
+			now:     time.Now,
+		}
+		helper, err := taskhelper.NewTick(gs, time.Duration(g.Interval))
+		if err != nil {
+			return nil, err
+		}
+		m.git = append(m.git, gs)
+		m.tasks = append(m.tasks, helper)
 	}
+	return m, nil
 }
 
-type provisioning struct {
-	async.SimpleTask
-	serviceManager dependency.ServiceManager
-	folders        []string
-	caseSensitive  bool
+type manager struct {
+	folders *folderSync
+	git     []*gitSync
+	tasks   []taskhelper.Helper
 }
 
-func (p *provisioning) Execute(_ context.Context, _ context.CancelFunc) error {
-	if len(p.folders) == 0 {
-		return nil
+func (m *manager) Tasks() []taskhelper.Helper {
+	return m.tasks
+}
+
+func (m *manager) Status() modelAPI.ProvisioningStatus {
+	var status modelAPI.ProvisioningStatus
+	if m.folders != nil {
+		status.ProvisioningRunStatus = m.folders.Status()
 	}
-	var entities []modelAPI.Entity
-	for _, dir := range p.folders {
-		objects, errors := file.UnmarshalEntitiesFromDirectory(dir)
-		for _, err := range errors {
-			logrus.WithError(err).Warningf("unable to load every entity from the folder %q", dir)
+	for _, g := range m.git {
+		status.Git = append(status.Git, g.Status())
+	}
+	return status
+}
+
+func (m *manager) SyncGit(name string) bool {
+	for _, g := range m.git {
+		if g.conf.Name == name {
+			g.trigger()
+			return true
 		}
+	}
+	return false
+}
+
+// run holds the state of a single provisioning run.
+type run struct {
+	status modelAPI.ProvisioningRunStatus
+	// failures contains the errors per file (or per folder when the folder itself cannot be read).
+	failures map[string][]string
+	// provisioned contains the key of every resource found in the files.
+	provisioned set.Set[string]
+}
+
+func newRun(now time.Time) *run {
+	now = now.UTC()
+	return &run{
+		status:      modelAPI.ProvisioningRunStatus{LastSync: &now},
+		failures:    make(map[string][]string),
+		provisioned: set.New[string](),
+	}
+}
+
+func (r *run) fail(path string, err error) {
+	r.failures[path] = append(r.failures[path], err.Error())
+}
 
-		if len(objects) > 0 {
-			entities = append(entities, objects...)
+// isUnreadable returns true when the file, or the folder containing it, couldn't be read during the run.
+// In this case, we can't say whether the resources coming from this file still exist.
+func (r *run) isUnreadable(path string) bool {
+	for failedPath := range r.failures {
+		if path == failedPath || strings.HasPrefix(path, failedPath+string(filepath.Separator)) {
+			return true
 		}
+	}
+	return false
+}
 
+// end builds the final status of the run.
+func (r *run) end() modelAPI.ProvisioningRunStatus {
+	for path, errs := range r.failures {
+		r.status.Failures = append(r.status.Failures, modelAPI.ProvisioningFailure{File: path, Errors: errs})
 	}
-	p.applyEntity(entities)
-	return nil
+	sort.Slice(r.status.Failures, func(i, j int) bool {
+		return r.status.Failures[i].File < r.status.Failures[j].File
+	})
+	return r.status
 }
 
-func (p *provisioning) String() string {
-	return "provisioning service"
+func key(kind modelV1.Kind, project string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, project, name)
 }
 
-func (p *provisioning) applyEntity(entities []modelAPI.Entity) {
+// loadedFile contains the resources described in a file, with the source to record on each of them.
+type loadedFile struct {
+	source   modelV1.ProvisioningSource
+	entities []modelAPI.Entity
+	// hashes contains the hash of each resource, so editing a resource doesn't update the other ones of the same file.
+	hashes []string
+}
+
+func loadFile(source modelV1.ProvisioningSource, data []byte) (*loadedFile, error) {
+	entities, err := file.UnmarshalEntitiesFromData(source.File, data)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(entities))
 	for _, entity := range entities {
-		entity.GetMetadata().Flatten(p.caseSensitive)
-		kind := modelV1.Kind(entity.GetKind())
-		name := entity.GetMetadata().GetName()
-		project := resource.GetProject(entity.GetMetadata(), "")
-		param := apiInterface.Parameters{
-			Name:    name,
-			Project: project,
-		}
-		createFun, updateFunc, svcErr := p.getService(entity, param)
-		if svcErr != nil {
-			logrus.WithError(svcErr).Warningf("unable to retrieve the service associated to %q", kind)
-			continue
+		raw, marshalErr := json.Marshal(entity)
+		if marshalErr != nil {
+			return nil, marshalErr
 		}
+		sum := sha256.Sum256(raw)
+		hashes = append(hashes, hex.EncodeToString(sum[:]))
+	}
+	return &loadedFile{source: source, entities: entities, hashes: hashes}, nil
+}
 
-		// the document doesn't exist, so we have to create it.
-		_, createErr := createFun()
+// applier creates or updates the resources loaded from a source, and prunes the ones that disappeared.
+type applier struct {
+	services      map[modelV1.Kind]kindService
+	caseSensitive bool
+	prune         bool
+}
 
-		if createErr == nil {
-			continue
+func (a *applier) applyFile(r *run, f *loadedFile) {
+	r.status.Files++
+	for i, entity := range f.entities {
+		source := f.source
+		source.Hash = f.hashes[i]
+		if err := a.applyEntity(r, source, entity); err != nil {
+			logrus.WithError(err).Errorf("unable to provision the %s %q from the file %q", entity.GetKind(), entity.GetMetadata().GetName(), f.source.File)
+			r.fail(f.source.File, err)
 		}
+	}
+}
 
-		if !databaseModel.IsKeyConflict(createErr) {
-			logrus.WithError(createErr).Errorf("unable to create the %q %q", kind, name)
-			continue
-		}
+func (a *applier) applyEntity(r *run, source modelV1.ProvisioningSource, entity modelAPI.Entity) error {
+	entity.GetMetadata().Flatten(a.caseSensitive)
+	kind := modelV1.Kind(entity.GetKind())
+	name := entity.GetMetadata().GetName()
+	project := resource.GetProject(entity.GetMetadata(), "")
+	svc, ok := a.services[kind]
+	if !ok {
+		return fmt.Errorf("resource %q not supported by the provisioning service", kind)
+	}
+	r.provisioned.Add(key(kind, project, name))
+	param := apiInterface.Parameters{
+		Name:    name,
+		Project: project,
+	}
+	modelV1.SetProvisioningSource(entity.GetMetadata(), &source)
 
-		if _, updateError := updateFunc(); updateError != nil {
-			logrus.WithError(updateError).Errorf("unable to update the %q %q", kind, name)
+	existing, getErr := svc.get(param)
+	if getErr != nil {
+		if !databaseModel.IsKeyNotFound(getErr) {
+			return getErr
 		}
+		// the document doesn't exist, so we have to create it.
+		return svc.create(entity)
+	}
+
+	current := modelV1.GetProvisioningSource(existing.GetMetadata())
+	if current != nil && current.Modified {
+		logrus.Warningf("the %s %q has been modified through the API, it is restored to the content of the file %q", kind, name, source.File)
+		r.status.Drift = append(r.status.Drift, modelAPI.ProvisionedResource{Kind: string(kind), Project: project, Name: name, File: source.File})
+	} else if current != nil && current.File == source.File && current.Hash == source.Hash && current.Repository == source.Repository {
+		// Nothing changed since the last run.
+		// For a git repository, the resource keeps the commit that brought its current content.
+		return nil
 	}
+	return svc.update(entity, param)
 }
 
-func (p *provisioning) getService(object modelAPI.Entity, parameters apiInterface.Parameters) (createFunc insertFunc, updateFunc insertFunc, err error) {
-	switch entity := object.(type) {
-	case *modelV1.Dashboard:
-		svc := p.serviceManager.GetDashboard()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.Datasource:
-		svc := p.serviceManager.GetDatasource()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.Folder:
-		svc := p.serviceManager.GetFolder()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.GlobalDatasource:
-		svc := p.serviceManager.GetGlobalDatasource()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.GlobalRole:
-		svc := p.serviceManager.GetGlobalRole()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.GlobalRoleBinding:
-		svc := p.serviceManager.GetGlobalRoleBinding()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.GlobalSecret:
-		svc := p.serviceManager.GetGlobalSecret()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.GlobalVariable:
-		svc := p.serviceManager.GetGlobalVariable()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.Project:
-		svc := p.serviceManager.GetProject()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.Role:
-		svc := p.serviceManager.GetRole()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.RoleBinding:
-		svc := p.serviceManager.GetRoleBinding()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.Secret:
-		svc := p.serviceManager.GetSecret()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.User:
-		svc := p.serviceManager.GetUser()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	case *modelV1.Variable:
-		svc := p.serviceManager.GetVariable()
-		return func() (modelAPI.Entity, error) {
-				return svc.Create(nil, entity)
-			},
-			func() (modelAPI.Entity, error) {
-				return svc.Update(nil, entity, parameters)
-			}, nil
-	// We don't support the provisioning of the following resources: EphemeralDashboard
-	default:
-		return nil, nil, fmt.Errorf("resource %q not supported by the provisioning service", entity.GetKind())
+// pruneResources deletes the resources provisioned from the given repository (empty for the local folders)
+// that have not been found during the run.
+func (a *applier) pruneResources(r *run, repository string) {
+	for _, kind := range pruneOrder {
+		svc, ok := a.services[kind]
+		if !ok {
+			continue
+		}
+		entities, err := svc.list()
+		if err != nil {
+			logrus.WithError(err).Errorf("unable to list the %s to prune", kind)
+			continue
+		}
+		for _, entity := range entities {
+			source := modelV1.GetProvisioningSource(entity.GetMetadata())
+			if source == nil || source.Repository != repository {
+				// Resource not managed by this provisioning source.
+				continue
+			}
+			name := entity.GetMetadata().GetName()
+			project := resource.GetProject(entity.GetMetadata(), "")
+			if r.provisioned.Contains(key(kind, project, name)) || r.isUnreadable(source.File) {
+				continue
+			}
+			if deleteErr := svc.delete(apiInterface.Parameters{Name: name, Project: project}); deleteErr != nil && !databaseModel.IsKeyNotFound(deleteErr) {
+				logrus.WithError(deleteErr).Errorf("unable to prune the %s %q", kind, name)
+				r.fail(source.File, deleteErr)
+				continue
+			}
+			logrus.Infof("%s %q pruned as the file %q doesn't describe it anymore", kind, name, source.File)
+			r.status.Pruned = append(r.status.Pruned, modelAPI.ProvisionedResource{Kind: string(kind), Project: project, Name: name, File: source.File})
+		}
 	}
 }
@@ -0,0 +1,203 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioning
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/project"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeService struct {
+	project.Service
+	entities map[string]*modelV1.Project
+	updates  int
+	// createErr is returned by Create when set.
+	createErr error
+}
+
+func (s *fakeService) Create(_ echo.Context, entity *modelV1.Project) (*modelV1.Project, error) {
+	if s.createErr != nil {
+		return nil, s.createErr
+	}
+	s.entities[entity.Metadata.Name] = entity
+	return entity, nil
+}
+
+func (s *fakeService) Update(_ echo.Context, entity *modelV1.Project, _ apiInterface.Parameters) (*modelV1.Project, error) {
+	s.updates++
+	s.entities[entity.Metadata.Name] = entity
+	return entity, nil
+}
+
+func (s *fakeService) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
+	delete(s.entities, parameters.Name)
+	return nil
+}
+
+func (s *fakeService) Get(parameters apiInterface.Parameters) (*modelV1.Project, error) {
+	entity, ok := s.entities[parameters.Name]
+	if !ok {
+		return nil, &databaseModel.Error{Key: parameters.Name, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return entity, nil
+}
+
+func (s *fakeService) MetadataList(_ *project.Query, _ apiInterface.Parameters) ([]modelAPI.Entity, error) {
+	var result []modelAPI.Entity
+	for _, entity := range s.entities {
+		result = append(result, entity)
+	}
+	return result, nil
+}
+
+func writeProject(t *testing.T, dir string, name string) string {
+	path := filepath.Join(dir, name+".yaml")
+	content := "kind: Project\nmetadata:\n  name: " + name + "\nspec: {}\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestApplier(prune bool) (*applier, *fakeService) {
+	fake := &fakeService{entities: map[string]*modelV1.Project{
+		// A project created by hand must never be pruned.
+		"manual": {Kind: modelV1.KindProject, Metadata: modelV1.Metadata{Name: "manual"}},
+	}}
+	a := &applier{
+		services: map[modelV1.Kind]kindService{
+			modelV1.KindProject: newService[*modelV1.Project, *modelV1.Project, *project.Query](fake, func() *project.Query { return &project.Query{} }),
+		},
+		prune:         prune,
+		caseSensitive: true,
+	}
+	return a, fake
+}
+
+func newTestProvisioning(t *testing.T, prune bool) (*folderSync, *fakeService, string) {
+	dir := t.TempDir()
+	a, fake := newTestApplier(prune)
+	return &folderSync{applier: a, folders: []string{dir}, now: time.Now}, fake, dir
+}
+
+func TestExecute_SetSource(t *testing.T) {
+	p, fake, dir := newTestProvisioning(t, false)
+	path := writeProject(t, dir, "perses")
+	assert.NoError(t, p.Execute(context.Background(), nil))
+
+	source := fake.entities["perses"].Metadata.Provisioning
+	if assert.NotNil(t, source) {
+		assert.Equal(t, path, source.File)
+		assert.Len(t, source.Hash, 64)
+	}
+	assert.Equal(t, 1, p.Status().Files)
+	assert.NotNil(t, p.Status().LastSync)
+}
+
+func TestExecute_SkipUnchanged(t *testing.T) {
+	p, fake, dir := newTestProvisioning(t, false)
+	writeProject(t, dir, "perses")
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	assert.Equal(t, 0, fake.updates)
+}
+
+func TestExecute_SkipUnchangedInSameFile(t *testing.T) {
+	p, fake, dir := newTestProvisioning(t, false)
+	path := filepath.Join(dir, "projects.yaml")
+	content := "- kind: Project\n  metadata:\n    name: perses\n  spec: {}\n- kind: Project\n  metadata:\n    name: team\n  spec: {}\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	assert.NotEqual(t, fake.entities["perses"].Metadata.Provisioning.Hash, fake.entities["team"].Metadata.Provisioning.Hash)
+
+	// Only the resource edited in the file is updated.
+	content = "- kind: Project\n  metadata:\n    name: perses\n  spec: {}\n- kind: Project\n  metadata:\n    name: team\n  spec:\n    display:\n      name: Team\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	assert.Equal(t, 1, fake.updates)
+	assert.Equal(t, "Team", fake.entities["team"].Spec.Display.Name)
+}
+
+func TestExecute_Drift(t *testing.T) {
+	p, fake, dir := newTestProvisioning(t, false)
+	writeProject(t, dir, "perses")
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	// Simulate an edit done through the API.
+	fake.entities["perses"].Metadata.Provisioning.Modified = true
+
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	assert.Equal(t, 1, fake.updates)
+	assert.False(t, fake.entities["perses"].Metadata.Provisioning.Modified)
+	assert.Equal(t, []modelAPI.ProvisionedResource{{Kind: string(modelV1.KindProject), Name: "perses", File: filepath.Join(dir, "perses.yaml")}}, p.Status().Drift)
+}
+
+func TestExecute_Failure(t *testing.T) {
+	p, fake, dir := newTestProvisioning(t, true)
+	path := writeProject(t, dir, "perses")
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	// A broken file must be reported and the resources it contains must not be pruned.
+	if err := os.WriteFile(path, []byte("kind: Project\nmetadata: ["), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, p.Execute(context.Background(), nil))
+	failures := p.Status().Failures
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, path, failures[0].File)
+	}
+	assert.Contains(t, fake.entities, "perses")
+}
+
+func TestExecute_Prune(t *testing.T) {
+	testSuites := []struct {
+		title  string
+		prune  bool
+		exists bool
+	}{
+		{
+			title:  "prune disabled",
+			prune:  false,
+			exists: true,
+		},
+		{
+			title:  "prune enabled",
+			prune:  true,
+			exists: false,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			p, fake, dir := newTestProvisioning(t, test.prune)
+			path := writeProject(t, dir, "perses")
+			assert.NoError(t, p.Execute(context.Background(), nil))
+			assert.NoError(t, os.Remove(path))
+
+			assert.NoError(t, p.Execute(context.Background(), nil))
+			_, exists := fake.entities["perses"]
+			assert.Equal(t, test.exists, exists)
+			assert.Contains(t, fake.entities, "manual")
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provisioning
+
+import (
+	"fmt"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/dependency"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/folder"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	"github.com/perses/perses/internal/api/interface/v1/globalrole"
+	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
+	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
+	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/group"
+	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/projecttemplate"
+	"github.com/perses/perses/internal/api/interface/v1/role"
+	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
+	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/interface/v1/variable"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+// kindService is the subset of the service of a kind used by the provisioning.
+// It hides the generic types so the services of every kind can be stored together.
+type kindService interface {
+	create(entity modelAPI.Entity) error
+	update(entity modelAPI.Entity, parameters apiInterface.Parameters) error
+	delete(parameters apiInterface.Parameters) error
+	get(parameters apiInterface.Parameters) (modelAPI.Entity, error)
+	list() ([]modelAPI.Entity, error)
+}
+
+type genericService[T modelAPI.Entity, K modelAPI.Entity, V databaseModel.Query] struct {
+	svc   apiInterface.Service[T, K, V]
+	query func() V
+}
+
+func newService[T modelAPI.Entity, K modelAPI.Entity, V databaseModel.Query](svc apiInterface.Service[T, K, V], query func() V) kindService {
+	return &genericService[T, K, V]{svc: svc, query: query}
+}
+
+func (s *genericService[T, K, V]) create(entity modelAPI.Entity) error {
+	e, ok := entity.(T)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for the kind %q", entity, entity.GetKind())
+	}
+	_, err := s.svc.Create(nil, e)
+	return err
+}
+
+func (s *genericService[T, K, V]) update(entity modelAPI.Entity, parameters apiInterface.Parameters) error {
+	e, ok := entity.(T)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for the kind %q", entity, entity.GetKind())
+	}
+	_, err := s.svc.Update(nil, e, parameters)
+	return err
+}
+
+func (s *genericService[T, K, V]) delete(parameters apiInterface.Parameters) error {
+	return s.svc.Delete(nil, parameters)
+}
+
+func (s *genericService[T, K, V]) get(parameters apiInterface.Parameters) (modelAPI.Entity, error) {
+	return s.svc.Get(parameters)
+}
+
+func (s *genericService[T, K, V]) list() ([]modelAPI.Entity, error) {
+	return s.svc.MetadataList(s.query(), apiInterface.Parameters{})
+}
+
+// pruneOrder is the order used to delete the resources whose file has been removed.
+// Projects are deleted last as deleting a project deletes everything it contains, followed by the templates they may
+// reference.
+var pruneOrder = []modelV1.Kind{
+	modelV1.KindDashboard,
+	modelV1.KindDatasource,
+	modelV1.KindFolder,
+	modelV1.KindRoleBinding,
+	modelV1.KindRole,
+	modelV1.KindSecret,
+	modelV1.KindVariable,
+	modelV1.KindGlobalDatasource,
+	modelV1.KindGlobalRoleBinding,
+	modelV1.KindGlobalRole,
+	modelV1.KindGlobalSecret,
+	modelV1.KindGlobalVariable,
+	modelV1.KindGroup,
+	modelV1.KindUser,
+	modelV1.KindProject,
+	modelV1.KindProjectTemplate,
+}
+
+// We don't support the provisioning of the following resources: EphemeralDashboard
+func buildServices(serviceManager dependency.ServiceManager) map[modelV1.Kind]kindService {
+	return map[modelV1.Kind]kindService{
+		modelV1.KindDashboard: newService[*modelV1.Dashboard, *modelV1.Dashboard, *dashboard.Query](
+			serviceManager.GetDashboard(), func() *dashboard.Query { return &dashboard.Query{} }),
+		modelV1.KindDatasource: newService[*modelV1.Datasource, *modelV1.Datasource, *datasource.Query](
+			serviceManager.GetDatasource(), func() *datasource.Query { return &datasource.Query{} }),
+		modelV1.KindFolder: newService[*modelV1.Folder, *modelV1.Folder, *folder.Query](
+			serviceManager.GetFolder(), func() *folder.Query { return &folder.Query{} }),
+		modelV1.KindGlobalDatasource: newService[*modelV1.GlobalDatasource, *modelV1.GlobalDatasource, *globaldatasource.Query](
+			serviceManager.GetGlobalDatasource(), func() *globaldatasource.Query { return &globaldatasource.Query{} }),
+		modelV1.KindGlobalRole: newService[*modelV1.GlobalRole, *modelV1.GlobalRole, *globalrole.Query](
+			serviceManager.GetGlobalRole(), func() *globalrole.Query { return &globalrole.Query{} }),
+		modelV1.KindGlobalRoleBinding: newService[*modelV1.GlobalRoleBinding, *modelV1.GlobalRoleBinding, *globalrolebinding.Query](
+			serviceManager.GetGlobalRoleBinding(), func() *globalrolebinding.Query { return &globalrolebinding.Query{} }),
+		modelV1.KindGlobalSecret: newService[*modelV1.GlobalSecret, *modelV1.PublicGlobalSecret, *globalsecret.Query](
+			serviceManager.GetGlobalSecret(), func() *globalsecret.Query { return &globalsecret.Query{} }),
+		modelV1.KindGlobalVariable: newService[*modelV1.GlobalVariable, *modelV1.GlobalVariable, *globalvariable.Query](
+			serviceManager.GetGlobalVariable(), func() *globalvariable.Query { return &globalvariable.Query{} }),
+		modelV1.KindGroup: newService[*modelV1.Group, *modelV1.Group, *group.Query](
+			serviceManager.GetGroup(), func() *group.Query { return &group.Query{} }),
+		modelV1.KindProject: newService[*modelV1.Project, *modelV1.Project, *project.Query](
+			serviceManager.GetProject(), func() *project.Query { return &project.Query{} }),
+		modelV1.KindProjectTemplate: newService[*modelV1.ProjectTemplate, *modelV1.ProjectTemplate, *projecttemplate.Query](
+			serviceManager.GetProjectTemplate(), func() *projecttemplate.Query { return &projecttemplate.Query{} }),
+		modelV1.KindRole: newService[*modelV1.Role, *modelV1.Role, *role.Query](
+			serviceManager.GetRole(), func() *role.Query { return &role.Query{} }),
+		modelV1.KindRoleBinding: newService[*modelV1.RoleBinding, *modelV1.RoleBinding, *rolebinding.Query](
+			serviceManager.GetRoleBinding(), func() *rolebinding.Query { return &rolebinding.Query{} }),
+		modelV1.KindSecret: newService[*modelV1.Secret, *modelV1.PublicSecret, *secret.Query](
+			serviceManager.GetSecret(), func() *secret.Query { return &secret.Query{} }),
+		modelV1.KindUser: newService[*modelV1.User, *modelV1.PublicUser, *user.Query](
+			serviceManager.GetUser(), func() *user.Query { return &user.Query{} }),
+		modelV1.KindVariable: newService[*modelV1.Variable, *modelV1.Variable, *variable.Query](
+			serviceManager.GetVariable(), func() *variable.Query { return &variable.Query{} }),
+	}
+}
@@ -99,6 +99,16 @@ func (g *Group) GET(path string, h echo.HandlerFunc, isAnonymous bool, middlewar
 	})
 }
 
+func (g *Group) PATCH(path string, h echo.HandlerFunc, isAnonymous bool, middleware ...echo.MiddlewareFunc) {
+	g.Routes = append(g.Routes, &Route{
+		Method:      http.MethodPatch,
+		Path:        path,
+		Handler:     h,
+		IsAnonymous: isAnonymous,
+		Middlewares: middleware,
+	})
+}
+
 func (g *Group) DELETE(path string, h echo.HandlerFunc, isAnonymous bool, middleware ...echo.MiddlewareFunc) {
 	g.Routes = append(g.Routes, &Route{
 		Method:      http.MethodDelete,
@@ -24,6 +24,7 @@ import (
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/role"
 	"github.com/sirupsen/logrus"
@@ -60,21 +61,23 @@ type Toolbox[T api.Entity, K databaseModel.Query] interface {
 	List(ctx echo.Context, q K) error
 }
 
-func New[T api.Entity, K api.Entity, V databaseModel.Query](service apiInterface.Service[T, K, V], authz authorization.Authorization, kind v1.Kind, caseSensitive bool) Toolbox[T, V] {
+func New[T api.Entity, K api.Entity, V databaseModel.Query](service apiInterface.Service[T, K, V], authz authorization.Authorization, kind v1.Kind, caseSensitive bool, provisioningPolicy config.ProvisioningEditPolicy) Toolbox[T, V] {
 	return &toolbox[T, K, V]{
-		service:       service,
-		authz:         authz,
-		kind:          kind,
-		caseSensitive: caseSensitive,
+		service:            service,
+		authz:              authz,
+		kind:               kind,
+		caseSensitive:      caseSensitive,
+		provisioningPolicy: provisioningPolicy,
 	}
 }
 
 type toolbox[T api.Entity, K api.Entity, V databaseModel.Query] struct {
 	Toolbox[T, V]
-	service       apiInterface.Service[T, K, V]
-	authz         authorization.Authorization
-	kind          v1.Kind
-	caseSensitive bool
+	service            apiInterface.Service[T, K, V]
+	authz              authorization.Authorization
+	kind               v1.Kind
+	caseSensitive      bool
+	provisioningPolicy config.ProvisioningEditPolicy
 }
 
 // checkPermissionList will verify only the permission for the List method. As you can see, scope is hardcoded.
@@ -144,6 +147,44 @@ func (t *toolbox[T, K, V]) checkPermission(ctx echo.Context, entity api.Entity,
 	return nil
 }
 
+// checkProvisioning applies the provisioning edit policy when the resource targeted by an update (entity is not nil)
+// or a deletion (entity is nil) has been created by the provisioning.
+// On update, the provisioning source is kept and flagged as modified so the drift is visible in the provisioning status.
+func (t *toolbox[T, K, V]) checkProvisioning(ctx echo.Context, entity api.Entity, parameters apiInterface.Parameters) error {
+	if entity != nil {
+		// Only the provisioning can flag a resource as provisioned.
+		v1.SetProvisioningSource(entity.GetMetadata(), nil)
+	} else if t.provisioningPolicy == config.ProvisioningEditPolicyAllow {
+		return nil
+	}
+	old, err := t.service.Get(parameters)
+	if err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			// Let the service return the proper error.
+			return nil
+		}
+		return err
+	}
+	source := v1.GetProvisioningSource(old.GetMetadata())
+	if source == nil {
+		return nil
+	}
+	switch t.provisioningPolicy {
+	case config.ProvisioningEditPolicyReject:
+		return apiInterface.HandleConflictError(fmt.Sprintf("%s %q is provisioned from the file %q and cannot be modified through the API", t.kind, parameters.Name, source.File))
+	case config.ProvisioningEditPolicyWarn:
+		msg := fmt.Sprintf("%s %s is provisioned from the file %s, the changes will be reverted by the next provisioning", t.kind, parameters.Name, source.File)
+		logrus.Warning(msg)
+		ctx.Response().Header().Add("Warning", fmt.Sprintf("299 - %q", msg))
+	}
+	if entity != nil {
+		modified := *source
+		modified.Modified = true
+		v1.SetProvisioningSource(entity.GetMetadata(), &modified)
+	}
+	return nil
+}
+
 func (t *toolbox[T, K, V]) Create(ctx echo.Context, entity T) error {
 	if err := t.bind(ctx, entity); err != nil {
 		return err
@@ -152,6 +193,8 @@ func (t *toolbox[T, K, V]) Create(ctx echo.Context, entity T) error {
 	if err := t.checkPermission(ctx, entity, parameters, role.CreateAction); err != nil {
 		return err
 	}
+	// Only the provisioning can flag a resource as provisioned.
+	v1.SetProvisioningSource(entity.GetMetadata(), nil)
 	newEntity, err := t.service.Create(ctx, entity)
 	if err != nil {
 		return err
@@ -167,6 +210,9 @@ func (t *toolbox[T, K, V]) Update(ctx echo.Context, entity T) error {
 	if err := t.checkPermission(ctx, entity, parameters, role.UpdateAction); err != nil {
 		return err
 	}
+	if err := t.checkProvisioning(ctx, entity, parameters); err != nil {
+		return err
+	}
 	newEntity, err := t.service.Update(ctx, entity, parameters)
 	if err != nil {
 		return err
@@ -179,6 +225,9 @@ func (t *toolbox[T, K, V]) Delete(ctx echo.Context) error {
 	if err := t.checkPermission(ctx, nil, parameters, role.DeleteAction); err != nil {
 		return err
 	}
+	if err := t.checkProvisioning(ctx, nil, parameters); err != nil {
+		return err
+	}
 	if err := t.service.Delete(ctx, parameters); err != nil {
 		return err
 	}
@@ -32,11 +32,19 @@ const (
 	PathRefresh            = "refresh"
 	PathDeviceCode         = "device/code"
 	PathToken              = "token"
+	PathPassword           = "password"
+	PathPasswordReset      = "password/reset"
+	PathTOTP               = "totp"
+	PathTOTPActivate       = "totp/activate"
+	PathSuspend            = "suspend"
+	PathResume             = "resume"
 	AuthnKindNative        = "native"
 	AuthnKindOIDC          = "oidc"
 	AuthnKindOAuth         = "oauth"
 	AuthnKindKubernetes    = "kubernetes"
+	AuthnKindHeader        = "header"
 	APIV1Prefix            = "/api/v1"
+	SCIMV2Prefix           = "/scim/v2"
 	PathDashboard          = "dashboards"
 	PathDatasource         = "datasources"
 	PathEphemeralDashboard = "ephemeraldashboards"
@@ -46,7 +54,9 @@ const (
 	PathGlobalRoleBinding  = "globalrolebindings"
 	PathGlobalSecret       = "globalsecrets"
 	PathGlobalVariable     = "globalvariables"
+	PathGroup              = "groups"
 	PathProject            = "projects"
+	PathProjectTemplate    = "projecttemplates"
 	PathRole               = "roles"
 	PathRoleBinding        = "rolebindings"
 	PathSecret             = "secrets"
@@ -57,6 +67,7 @@ const (
 	PathView               = "view"
 	PathWhoAmI             = "whoami"
 	ContextKeyAnonymous    = "anonymous"
+	ContextKeyUser         = "user"
 )
 
 const MetricNamespace = "perses"
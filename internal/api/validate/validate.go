@@ -31,7 +31,7 @@ import (
 // For example, in PromQL, the function `label_replace` uses the syntax "$1", "$2" for the placeholders.
 var variableNameRegexp = regexp.MustCompile(`^\w*?[^0-9]\w*$`)
 
-func DashboardSpec(spec dashboard.Spec, sch schema.Schema) error {
+func DashboardSpec(spec modelV1.AnnotatedDashboardSpec, sch schema.Schema) error {
 	if _, err := utils.BuildVariableOrder(spec.Variables, nil, nil); err != nil {
 		return err
 	}
@@ -39,7 +39,7 @@ func DashboardSpec(spec dashboard.Spec, sch schema.Schema) error {
 
 }
 
-func DashboardSpecWithVars(spec dashboard.Spec, sch schema.Schema, projectVariables []*modelV1.Variable, globalVariables []*modelV1.GlobalVariable) error {
+func DashboardSpecWithVars(spec modelV1.AnnotatedDashboardSpec, sch schema.Schema, projectVariables []*modelV1.Variable, globalVariables []*modelV1.GlobalVariable) error {
 	if _, err := utils.BuildVariableOrder(spec.Variables, projectVariables, globalVariables); err != nil {
 		return err
 	}
@@ -115,7 +115,7 @@ func validateDatasourcePlugin(plugin common.Plugin, name string, sch schema.Sche
 	return sch.ValidateDatasource(plugin, name)
 }
 
-func validateDashboardSpec(spec dashboard.Spec, sch schema.Schema) error {
+func validateDashboardSpec(spec modelV1.AnnotatedDashboardSpec, sch schema.Schema) error {
 	if err := validateVariableNames(spec.Variables); err != nil {
 		return err
 	}
@@ -127,6 +127,9 @@ func validateDashboardSpec(spec dashboard.Spec, sch schema.Schema) error {
 		if err := sch.ValidatePanels(spec.Panels); err != nil {
 			return err
 		}
+		if err := sch.ValidateAnnotations(spec.Annotations); err != nil {
+			return err
+		}
 	}
 	if len(spec.Datasources) > 0 {
 		defaultDts := make(map[string]bool)
@@ -0,0 +1,194 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cani
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/client/api"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/spf13/cobra"
+)
+
+var columnHeader = []string{
+	"BINDING",
+	"ROLE",
+	"SUBJECT",
+	"RESOURCE",
+	"ALLOWED",
+	"REASON",
+}
+
+type option struct {
+	persesCMD.Option
+	opt.OutputOption
+	writer         io.Writer
+	errWriter      io.Writer
+	user           string
+	groups         []string
+	serviceAccount string
+	project        string
+	request        *modelAPI.CanIRequest
+	apiClient      api.ClientInterface
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("you have to specify the action and the scope to check")
+	}
+	action, err := role.GetAction(args[0])
+	if err != nil {
+		return err
+	}
+	scope, err := role.GetScope(args[1])
+	if err != nil {
+		return err
+	}
+	if len(o.Output) > 0 {
+		if outputErr := o.OutputOption.Complete(); outputErr != nil {
+			return outputErr
+		}
+	}
+	username := o.user
+	if len(o.serviceAccount) > 0 {
+		namespace, name, found := strings.Cut(o.serviceAccount, "/")
+		if !found || len(namespace) == 0 || len(name) == 0 {
+			return fmt.Errorf("the service account must be written as <namespace>/<name>")
+		}
+		username = fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+	}
+	project := o.project
+	// Like the other commands, the permission is checked in the current project unless another one is given.
+	if len(project) == 0 && !role.IsGlobalScope(*scope) {
+		project = config.Global.Project
+	}
+	o.request = &modelAPI.CanIRequest{
+		User:    username,
+		Groups:  o.groups,
+		Action:  *action,
+		Scope:   *scope,
+		Project: project,
+	}
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+func (o *option) Validate() error {
+	if len(o.user) > 0 && len(o.serviceAccount) > 0 {
+		return fmt.Errorf("--as and --service-account are mutually exclusive")
+	}
+	if len(o.request.User) == 0 && len(o.request.Groups) == 0 {
+		return fmt.Errorf("you have to specify the subject to check with --as, --as-group or --service-account")
+	}
+	return nil
+}
+
+func (o *option) Execute() error {
+	result, err := o.apiClient.CanI(o.request)
+	if err != nil {
+		return err
+	}
+	if len(o.Output) > 0 {
+		return output.Handle(o.writer, o.Output, result)
+	}
+	answer := "no"
+	if result.Allowed {
+		answer = "yes"
+	}
+	if outputErr := output.HandleString(o.writer, fmt.Sprintf("%s - %s", answer, result.Reason)); outputErr != nil {
+		return outputErr
+	}
+	if len(result.Evaluations) == 0 {
+		return nil
+	}
+	var matrix [][]string
+	for _, evaluation := range result.Evaluations {
+		matrix = append(matrix, []string{
+			formatBinding(evaluation),
+			formatKindName(evaluation.RoleKind, evaluation.Role, ""),
+			evaluation.Subject,
+			evaluation.Resource,
+			fmt.Sprintf("%t", evaluation.Allowed),
+			evaluation.Reason,
+		})
+	}
+	return output.HandlerTable(o.writer, columnHeader, matrix)
+}
+
+func formatBinding(evaluation modelAPI.CanIEvaluation) string {
+	if len(evaluation.BindingKind) == 0 && len(evaluation.Resource) == 0 {
+		return "guest permissions"
+	}
+	return formatKindName(evaluation.BindingKind, evaluation.Binding, evaluation.Project)
+}
+
+func formatKindName(kind string, name string, project string) string {
+	if len(kind) == 0 {
+		return ""
+	}
+	if len(project) > 0 && project != modelV1.WildcardProject {
+		return fmt.Sprintf("%s %s/%s", kind, project, name)
+	}
+	return fmt.Sprintf("%s %s", kind, name)
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "can-i ACTION SCOPE",
+		Short: "Check if a user, a group or a service account can perform an action",
+		Long: `Check if a user, a group or a service account can perform an action on a scope, in a project or globally.
+The result explains which role bindings and roles granted, or failed to grant, the permission.
+This command requires the permission to read the role bindings and the global role bindings.`,
+		Example: `
+# Check if the user 'john' can create a dashboard in the project 'my-project'
+percli auth can-i create Dashboard --as john --project my-project
+
+# Check if the members of the group 'sre' can update the global datasources
+percli auth can-i update GlobalDatasource --as-group sre
+
+# Check if a Kubernetes service account can read the dashboards of its namespace
+percli auth can-i read Dashboard --service-account monitoring/grafana-sync --project monitoring
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&o.user, "as", o.user, "The name of the user to check.")
+	cmd.Flags().StringSliceVar(&o.groups, "as-group", o.groups, "The group to check, can be repeated. Combined with --as, the groups are added to the ones of the user.")
+	cmd.Flags().StringVar(&o.serviceAccount, "service-account", o.serviceAccount, "The Kubernetes service account to check, written as <namespace>/<name>.")
+	cmd.Flags().StringVarP(&o.project, "project", "p", o.project, "The project in which the permission is checked. If not set, the current project is used, or the permission is checked globally if there is none.")
+	opt.AddOutputFlags(cmd, &o.OutputOption)
+	return cmd
+}
@@ -0,0 +1,75 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cani
+
+import (
+	"testing"
+
+	"github.com/perses/perses/internal/cli/config"
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	fakeapi "github.com/perses/perses/pkg/client/fake/api"
+)
+
+func TestCanICMD(t *testing.T) {
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "missing scope",
+			Args:            []string{"read"},
+			IsErrorExpected: true,
+			ExpectedMessage: "you have to specify the action and the scope to check",
+		},
+		{
+			Title:           "missing subject",
+			Args:            []string{"read", "Dashboard"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: "you have to specify the subject to check with --as, --as-group or --service-account",
+		},
+		{
+			Title:           "invalid service account",
+			Args:            []string{"read", "Dashboard", "--service-account", "perses"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: "the service account must be written as <namespace>/<name>",
+		},
+		{
+			Title:           "user and service account",
+			Args:            []string{"read", "Dashboard", "--as", "john", "--service-account", "monitoring/perses"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: "--as and --service-account are mutually exclusive",
+		},
+		{
+			Title:           "allowed",
+			Args:            []string{"read", "Dashboard", "--as", "john"},
+			APIClient:       fakeapi.New(),
+			Config:          config.Config{Project: "perses"},
+			IsErrorExpected: false,
+			ExpectedMessage: `yes - allowed by GlobalRoleBinding "viewer" of GlobalRole "viewer" to User john
+         BINDING          │       ROLE        │  SUBJECT  │ RESOURCE │ ALLOWED │                REASON                 
+──────────────────────────┼───────────────────┼───────────┼──────────┼─────────┼───────────────────────────────────────
+ GlobalRoleBinding viewer │ GlobalRole viewer │ User john │          │ true    │ the role grants "read" on "Dashboard" 
+`,
+		},
+		{
+			Title:           "denied in json",
+			Args:            []string{"create", "Dashboard", "--as", "john", "-o", "json"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: false,
+			ExpectedMessage: `{"allowed":false,"reason":"none of the roles bound to the subject grant the permission","evaluations":[{"bindingKind":"GlobalRoleBinding","binding":"viewer","roleKind":"GlobalRole","role":"viewer","subject":"User john","allowed":false,"reason":"the role doesn't grant \"create\" on \"Dashboard\""}]}
+`,
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
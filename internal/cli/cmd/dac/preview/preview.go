@@ -53,7 +53,7 @@ func newEphemeralDashboard(project string, name string, ttl commonSpec.Duration,
 		},
 		Spec: modelV1.EphemeralDashboardSpec{
 			EphemeralDashboardSpecBase: modelV1.EphemeralDashboardSpecBase{TTL: ttl},
-			Spec:                       dashboard.Spec,
+			AnnotatedDashboardSpec:     dashboard.Spec,
 		},
 	}
 }
@@ -0,0 +1,217 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/internal/api/plugin/export"
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/internal/cli/file"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/client/api"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	apiConfig "github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	persesCMD.Option
+	opt.FileOption
+	opt.ProjectOption
+	opt.OutputOption
+	writer        io.Writer
+	errWriter     io.Writer
+	dashboardName string
+	pluginPath    string
+	online        bool
+	reportPath    string
+	exp           export.Export
+	apiClient     api.ClientInterface
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("only the name of the dashboard can be given as an argument")
+	}
+	if len(args) == 1 {
+		o.dashboardName = args[0]
+	}
+	// The Grafana dashboards are JSON documents, so it's the default output.
+	if len(o.Output) == 0 {
+		o.Output = output.JSONOutput
+	}
+	if outputErr := o.OutputOption.Complete(); outputErr != nil {
+		return outputErr
+	}
+	if len(o.pluginPath) > 0 {
+		pl := plugin.New(apiConfig.Plugin{
+			Path: o.pluginPath,
+		})
+		if err := pl.Load(); err != nil {
+			return err
+		}
+		o.exp = pl.Export()
+	}
+	if o.online || len(o.dashboardName) > 0 {
+		apiClient, err := config.Global.GetAPIClient()
+		if err != nil {
+			return err
+		}
+		o.apiClient = apiClient
+	}
+	if len(o.dashboardName) > 0 {
+		return o.ProjectOption.Complete()
+	}
+	return nil
+}
+
+func (o *option) Validate() error {
+	if len(o.dashboardName) == 0 && len(o.File) == 0 {
+		return fmt.Errorf("the dashboard to export must be given either by its name or with the flag --file")
+	}
+	if len(o.dashboardName) > 0 && len(o.File) > 0 {
+		return fmt.Errorf("the dashboard to export cannot be given both by its name and with the flag --file")
+	}
+	if len(o.File) > 0 {
+		if err := o.FileOption.Validate(); err != nil {
+			return err
+		}
+	}
+	if !o.online && o.exp == nil {
+		return fmt.Errorf("offline export requires --plugin.path to be specified, or use --online for server-side export")
+	}
+	return nil
+}
+
+func (o *option) Execute() error {
+	dashboard, err := o.getDashboard()
+	if err != nil {
+		return err
+	}
+	var grafanaDashboard json.RawMessage
+	var report *modelAPI.MigrationReport
+	if o.online {
+		grafanaDashboard, report, err = o.onlineExecution(dashboard)
+	} else {
+		grafanaDashboard, report, err = o.offlineExecution(dashboard)
+	}
+	if err != nil {
+		return err
+	}
+	for _, warning := range report.Warnings {
+		if outputErr := output.HandleString(o.errWriter, fmt.Sprintf("warning: %s", warning)); outputErr != nil {
+			return outputErr
+		}
+	}
+	for _, item := range report.Items {
+		if item.Status == modelAPI.MigrationStatusMigrated {
+			continue
+		}
+		if outputErr := output.HandleString(o.errWriter, fmt.Sprintf("warning: the %s %q (%s) is %s: %s", item.Kind, item.Name, item.Ref, item.Status, item.Reason)); outputErr != nil {
+			return outputErr
+		}
+	}
+	if len(o.reportPath) > 0 {
+		if reportErr := o.writeReport(report); reportErr != nil {
+			return fmt.Errorf("unable to write the report: %w", reportErr)
+		}
+	}
+	// The dashboard is decoded to print it in the requested format.
+	var result any
+	if unmarshalErr := json.Unmarshal(grafanaDashboard, &result); unmarshalErr != nil {
+		return unmarshalErr
+	}
+	return output.Handle(o.writer, o.Output, result)
+}
+
+func (o *option) getDashboard() (*modelV1.Dashboard, error) {
+	if len(o.dashboardName) > 0 {
+		return o.apiClient.V1().Dashboard(o.Project).Get(o.dashboardName)
+	}
+	dashboard := &modelV1.Dashboard{}
+	if err := file.Unmarshal(o.File, dashboard); err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
+
+func (o *option) writeReport(report *modelAPI.MigrationReport) error {
+	f, err := os.Create(o.reportPath) //nolint: gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint: errcheck
+	return output.Handle(f, o.Output, report)
+}
+
+func (o *option) onlineExecution(dashboard *modelV1.Dashboard) (json.RawMessage, *modelAPI.MigrationReport, error) {
+	result, err := o.apiClient.ExportGrafana(dashboard)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Dashboard, result.Report, nil
+}
+
+func (o *option) offlineExecution(dashboard *modelV1.Dashboard) (json.RawMessage, *modelAPI.MigrationReport, error) {
+	grafanaDashboard, report, err := o.exp.Export(dashboard)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(grafanaDashboard)
+	return data, report, err
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "grafana [DASHBOARD_NAME]",
+		Short: "Export a Perses dashboard to the Grafana format",
+		Example: `
+# Export a dashboard of the current project using the plugins of the Perses server
+percli export grafana my-dashboard --online
+
+# Export a dashboard from a file using local plugins, and write the export report
+percli export grafana -f ./dashboard.yaml --plugin.path ./plugins --report report.json
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	opt.AddFileFlags(cmd, &o.FileOption)
+	opt.AddProjectFlags(cmd, &o.ProjectOption)
+	opt.AddOutputFlags(cmd, &o.OutputOption)
+	cmd.Flags().StringVar(&o.pluginPath, "plugin.path", "", "Path to the Perses plugins.")
+	cmd.Flags().BoolVar(&o.online, "online", false, "When enabled, it can request the API to use it to perform the export")
+	cmd.Flags().StringVar(&o.reportPath, "report", "", "Path to the file where the export report is written. The report tells how each panel, query and variable has been exported.")
+	// When "online" flag is used, the CLI will call the endpoint /export/grafana that will then use the export scripts from the server.
+	// So no need to use / load the plugins with the CLI.
+	cmd.MarkFlagsMutuallyExclusive("plugin.path", "online")
+	return cmd
+}
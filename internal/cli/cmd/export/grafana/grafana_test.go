@@ -0,0 +1,56 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"path/filepath"
+	"testing"
+
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/perses/perses/internal/test"
+)
+
+// reuse the test plugins from the API
+var pluginsFolder = filepath.Join(test.GetRepositoryPath(), "internal", "api", "plugin", "migrate", "testdata", "plugins")
+
+func TestExportGrafanaCMD(t *testing.T) {
+	pathToDashboard := filepath.Join("testdata", "dashboard.yaml")
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "no dashboard",
+			Args:            []string{},
+			IsErrorExpected: true,
+			ExpectedMessage: "the dashboard to export must be given either by its name or with the flag --file",
+		},
+		{
+			Title:           "too many args",
+			Args:            []string{"first", "second"},
+			IsErrorExpected: true,
+			ExpectedMessage: "only the name of the dashboard can be given as an argument",
+		},
+		{
+			Title:           "offline export without plugin path",
+			Args:            []string{"-f", pathToDashboard},
+			IsErrorExpected: true,
+			ExpectedMessage: "offline export requires --plugin.path to be specified, or use --online for server-side export",
+		},
+		{
+			Title:           "offline export",
+			Args:            []string{"-f", pathToDashboard, "--plugin.path", pluginsFolder},
+			IsErrorExpected: false,
+			ExpectedMessage: `{"links":[],"panels":[{"gridPos":{"h":8,"w":24,"x":0,"y":0},"id":1,"options":{"legend":{"showLegend":true}},"targets":[{"datasource":{"type":"exotic-tsdb"},"expr":"cpu","refId":"A"}],"title":"CPU","type":"timeseries"}],"schemaVersion":39,"tags":[],"templating":{"list":[]},"time":{"from":"now-6h","to":"now"},"title":"demo","uid":"demo"}` + "\n",
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
@@ -56,6 +56,7 @@ type option struct {
 	isNativeAuthnSelected bool
 	username              string
 	password              string
+	totpCode              string
 	clientID              string
 	clientSecret          string
 	externalAuthnKind     externalAuthnKind
@@ -206,6 +207,7 @@ func (o *option) newLoginOption() (loginOption, error) {
 			writer:    o.writer,
 			username:  o.username,
 			password:  o.password,
+			totpCode:  o.totpCode,
 			apiClient: o.apiClient,
 		}, nil
 	}
@@ -365,6 +367,7 @@ percli login https://demo.perses.dev --provider <slug_id> --client-id <client_id
 	cmd.Flags().BoolVar(&o.insecureTLS, "insecure-skip-tls-verify", o.insecureTLS, "If true the server's certificate will not be checked for validity. This will make your HTTPS connections insecure.")
 	cmd.Flags().StringVarP(&o.username, "username", "u", "", "Username used for the authentication.")
 	cmd.Flags().StringVarP(&o.password, "password", "p", "", "Password used for the authentication.")
+	cmd.Flags().StringVar(&o.totpCode, "totp", "", "TOTP code (or recovery code) used when the two-factor authentication is enabled.")
 	cmd.Flags().StringVar(&o.clientID, "client-id", "", "Client ID used for robotic access when using external authentication provider.")
 	cmd.Flags().StringVar(&o.clientSecret, "client-secret", "", "Client Secret used for robotic access when using external authentication provider.")
 	cmd.Flags().StringVar(&o.accessToken, "token", "", "Bearer token for authentication to the API server")
@@ -14,11 +14,16 @@
 package login
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/perses/perses/internal/cli/output"
 	"github.com/perses/perses/pkg/client/api"
+	"github.com/perses/perses/pkg/client/api/auth"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	"golang.org/x/oauth2"
 )
 
@@ -26,11 +31,51 @@ type nativeLogin struct {
 	writer    io.Writer
 	username  string
 	password  string
+	totpCode  string
 	apiClient api.ClientInterface
 }
 
 func (l *nativeLogin) Login() (*oauth2.Token, error) {
-	return l.apiClient.Auth().Login(l.username, l.password)
+	token, err := l.apiClient.Auth().Login(l.username, l.password)
+	var twoFactorErr *auth.TwoFactorRequiredError
+	if errors.As(err, &twoFactorErr) {
+		return l.loginTwoFactor(twoFactorErr.Challenge)
+	}
+	return token, err
+}
+
+// loginTwoFactor completes the login with a TOTP code. When the server requires the two-factor authentication and
+// the user hasn't enrolled an authenticator app yet, the secret to add in the app is printed first.
+func (l *nativeLogin) loginTwoFactor(challenge *modelAPI.TwoFactorChallenge) (*oauth2.Token, error) {
+	if challenge.Enrollment != nil {
+		msg := fmt.Sprintf("Two-factor authentication is required. Add the following secret to your authenticator app:\n\n  %s\n\nor use the URI: %s", challenge.Enrollment.Secret, challenge.Enrollment.URI)
+		if err := output.HandleString(l.writer, msg); err != nil {
+			return nil, err
+		}
+	}
+	if len(l.totpCode) == 0 {
+		input := huh.NewInput().Title("Authentication code").Value(&l.totpCode)
+		if err := input.Run(); err != nil {
+			return nil, err
+		}
+		if err := output.HandleString(l.writer, input.View()); err != nil {
+			return nil, err
+		}
+	}
+	response, err := l.apiClient.Auth().LoginTwoFactor(&modelAPI.TwoFactorLoginRequest{
+		TwoFactorToken: challenge.TwoFactorToken,
+		Code:           l.totpCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.RecoveryCodes) > 0 {
+		msg := fmt.Sprintf("Two-factor authentication is now enabled. Store the following recovery codes in a safe place, each of them can be used once if you lose access to your authenticator app:\n\n  %s\n", strings.Join(response.RecoveryCodes, "\n  "))
+		if err := output.HandleString(l.writer, msg); err != nil {
+			return nil, err
+		}
+	}
+	return &response.Token, nil
 }
 
 func (l *nativeLogin) SetMissingInput() error {
@@ -0,0 +1,155 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/internal/api/plugin/migrate"
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/file"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	apiConfig "github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ReadGrafanaDatasources reads the Grafana datasources from a file. The file can be a Grafana provisioning file,
+// the response of the Grafana API /api/datasources, or a single datasource. Both JSON and YAML are supported.
+func ReadGrafanaDatasources(filePath string) ([]migrate.GrafanaDatasource, error) {
+	var content any
+	if err := file.Unmarshal(filePath, &content); err != nil {
+		return nil, err
+	}
+	if provisioning, ok := content.(map[string]any); ok {
+		if datasources, isProvisioning := provisioning["datasources"]; isProvisioning {
+			content = datasources
+		} else {
+			content = []any{provisioning}
+		}
+	}
+	if _, ok := content.([]any); !ok {
+		return nil, fmt.Errorf("no Grafana datasource found in the file %q", filePath)
+	}
+	// The datasources are converted back to JSON, as it's what the migration scripts expect.
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	var result []migrate.GrafanaDatasource
+	if unmarshalErr := json.Unmarshal(data, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("unable to read the Grafana datasources from the file %q: %w", filePath, unmarshalErr)
+	}
+	return result, nil
+}
+
+type option struct {
+	persesCMD.Option
+	opt.FileOption
+	opt.OutputOption
+	writer     io.Writer
+	errWriter  io.Writer
+	project    string
+	pluginPath string
+	mig        migrate.Migration
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'migrate datasource'")
+	}
+	if outputErr := o.OutputOption.Complete(); outputErr != nil {
+		return outputErr
+	}
+	pl := plugin.New(apiConfig.Plugin{
+		Path: o.pluginPath,
+	})
+	if err := pl.Load(); err != nil {
+		return err
+	}
+	o.mig = pl.Migration()
+	return nil
+}
+
+func (o *option) Validate() error {
+	return nil
+}
+
+func (o *option) Execute() error {
+	grafanaDatasources, err := ReadGrafanaDatasources(o.File)
+	if err != nil {
+		return err
+	}
+	result, err := o.mig.MigrateDatasources(grafanaDatasources, o.project)
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		if outputErr := output.HandleString(o.errWriter, fmt.Sprintf("warning: %s", warning)); outputErr != nil {
+			return outputErr
+		}
+	}
+	// The secrets come first, so the output can be given as it is to 'percli apply'.
+	entities := make([]modelAPI.Entity, 0, len(result.Secrets)+len(result.Datasources))
+	entities = append(entities, result.Secrets...)
+	entities = append(entities, result.Datasources...)
+	return output.Handle(o.writer, o.Output, entities)
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "datasource -f [GRAFANA_DATASOURCES_FILE]",
+		Short: "migrate Grafana datasources to the Perses format",
+		Long: `Migrate Grafana datasources to Perses datasources, using the migration scripts provided by the plugins.
+
+The file can be a Grafana provisioning file, the response of the Grafana API /api/datasources, or a single datasource.
+The credentials and the TLS settings of the datasources are extracted into secrets. As Grafana doesn't return them through
+its API, the sensitive values must then be set manually in the secrets.
+
+Without --project, global datasources and global secrets are created.
+
+To make the migrated dashboards use these datasources, give the same file to 'percli migrate' with --grafana-datasources.
+`,
+		Example: `
+# Migrate the datasources of a Grafana provisioning file in the project "my-project"
+percli migrate datasource -f ./provisioning/datasources/datasources.yaml --project my-project --plugin.path ./plugins
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	opt.AddFileFlags(cmd, &o.FileOption)
+	opt.AddOutputFlags(cmd, &o.OutputOption)
+	opt.MarkFileFlagAsMandatory(cmd)
+	cmd.Flags().StringVar(&o.project, "project", "", "The project of the migrated datasources. If not set, global datasources are created.")
+	cmd.Flags().StringVar(&o.pluginPath, "plugin.path", "", "Path to the Perses plugins.")
+	if err := cmd.MarkFlagRequired("plugin.path"); err != nil {
+		logrus.Panic(err)
+	}
+	return cmd
+}
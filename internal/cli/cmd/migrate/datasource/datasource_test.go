@@ -0,0 +1,85 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"path/filepath"
+	"testing"
+
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/perses/perses/internal/test"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/datasource"
+	"github.com/stretchr/testify/assert"
+)
+
+// reuse the test data from the API
+var testDataFolder = filepath.Join(test.GetRepositoryPath(), "internal", "api", "plugin", "migrate", "testdata")
+
+func TestMigrateDatasourceCMD(t *testing.T) {
+	pathToProvisioningFile := filepath.Join(testDataFolder, "datasources", "grafana_provisioning.yaml")
+	pluginPath := filepath.Join(testDataFolder, "plugins")
+	expectedEntities := []modelAPI.Entity{
+		&modelV1.Secret{
+			Kind:     modelV1.KindSecret,
+			Metadata: *modelV1.NewProjectMetadata("perses", "Exotic-TSDB-prod-secret"),
+			Spec:     modelV1.SecretSpec{BasicAuth: &secret.BasicAuth{Username: "admin", Password: "s3cr3t"}},
+		},
+		&modelV1.Datasource{
+			Kind:     modelV1.KindDatasource,
+			Metadata: *modelV1.NewProjectMetadata("perses", "Exotic-TSDB-prod"),
+			Spec: datasource.Spec{
+				Display: &common.Display{Name: "Exotic TSDB (prod)"},
+				Default: true,
+				Plugin:  common.Plugin{Kind: "ExoticTSDB", Spec: map[string]any{"url": "http://exotic.prod:9090"}},
+			},
+		},
+	}
+	expectedWarnings := `warning: no migration script found for the Grafana datasource "Loki" of type "loki"
+warning: the Perses datasource "Exotic-TSDB-prod" doesn't use an HTTP proxy, the secret "Exotic-TSDB-prod-secret" must be referenced manually
+`
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "empty args",
+			Args:            []string{"--plugin.path", pluginPath},
+			IsErrorExpected: true,
+			ExpectedMessage: `required flag(s) "file" not set`,
+		},
+		{
+			Title:           "use args",
+			Args:            []string{"whatever", "-f", pathToProvisioningFile, "--plugin.path", pluginPath},
+			IsErrorExpected: true,
+			ExpectedMessage: "no args are supported by the command 'migrate datasource'",
+		},
+		{
+			Title:           "migrate a provisioning file",
+			Args:            []string{"-f", pathToProvisioningFile, "--project", "perses", "--plugin.path", pluginPath},
+			IsErrorExpected: false,
+			ExpectedMessage: expectedWarnings + string(test.YAMLMarshalStrict(expectedEntities)) + "\n",
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
+
+func TestReadGrafanaDatasources(t *testing.T) {
+	grafanaDatasources, err := ReadGrafanaDatasources(filepath.Join(testDataFolder, "datasources", "grafana_provisioning.yaml"))
+	if assert.NoError(t, err) && assert.Len(t, grafanaDatasources, 2) {
+		assert.Equal(t, "P1809F7CD0C75ACF3", grafanaDatasources[0].UID)
+		assert.Equal(t, map[string]string{"basicAuthPassword": "s3cr3t"}, grafanaDatasources[0].SecureJSONData)
+		assert.Equal(t, "loki", grafanaDatasources[1].Type)
+	}
+}
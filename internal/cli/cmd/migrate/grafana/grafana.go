@@ -0,0 +1,507 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/internal/api/plugin/migrate"
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/config"
+	grafanaClient "github.com/perses/perses/internal/cli/grafana"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/internal/cli/service"
+	"github.com/perses/perses/pkg/client/api"
+	"github.com/perses/perses/pkg/client/perseshttp"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	apiConfig "github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const tokenEnvVariable = "GRAFANA_TOKEN"
+
+// migration is the result of the migration of a single Grafana dashboard.
+type migration struct {
+	report    *dashboardReport
+	dashboard *modelV1.Dashboard
+}
+
+type option struct {
+	persesCMD.Option
+	opt.OutputOption
+	writer               io.Writer
+	errWriter            io.Writer
+	grafanaURL           string
+	token                string
+	rawRules             []string
+	rules                []*rule
+	project              string
+	parallelism          int
+	reportPath           string
+	minScore             float64
+	outputDir            string
+	apply                bool
+	pluginPath           string
+	online               bool
+	useDefaultDatasource bool
+	migrateDatasources   bool
+	datasourceReferences map[string]string
+	libraryPanels        []migrate.GrafanaLibraryPanel
+	grafana              grafanaClient.Client
+	mig                  migrate.Migration
+	apiClient            api.ClientInterface
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'migrate grafana'")
+	}
+	if outputErr := o.OutputOption.Complete(); outputErr != nil {
+		return outputErr
+	}
+	for _, rawRule := range o.rawRules {
+		r, err := parseRule(rawRule)
+		if err != nil {
+			return err
+		}
+		o.rules = append(o.rules, r)
+	}
+	if len(o.token) == 0 {
+		o.token = os.Getenv(tokenEnvVariable)
+	}
+	grafana, err := grafanaClient.NewClient(o.grafanaURL, o.token)
+	if err != nil {
+		return err
+	}
+	o.grafana = grafana
+	if len(o.pluginPath) > 0 {
+		pl := plugin.New(apiConfig.Plugin{
+			Path: o.pluginPath,
+		})
+		if loadErr := pl.Load(); loadErr != nil {
+			return loadErr
+		}
+		o.mig = pl.Migration()
+	}
+	if o.online || o.apply {
+		apiClient, clientErr := config.Global.GetAPIClient()
+		if clientErr != nil {
+			return clientErr
+		}
+		o.apiClient = apiClient
+	}
+	return nil
+}
+
+func (o *option) Validate() error {
+	if !o.online && o.mig == nil {
+		return fmt.Errorf("offline migration requires --plugin.path to be specified, or use --online for server-side migration")
+	}
+	if len(o.outputDir) == 0 && !o.apply {
+		return fmt.Errorf("nowhere to put the migrated dashboards, use --output-dir and/or --apply")
+	}
+	if o.migrateDatasources && o.mig == nil {
+		return fmt.Errorf("the migration of the datasources requires --plugin.path to be specified")
+	}
+	if o.parallelism <= 0 {
+		return fmt.Errorf("--parallelism must be greater than 0")
+	}
+	if o.minScore < 0 || o.minScore > 100 {
+		return fmt.Errorf("--min-score must be between 0 and 100")
+	}
+	return nil
+}
+
+func (o *option) Execute() error {
+	var datasources *migrate.DatasourceMigration
+	if o.migrateDatasources {
+		var err error
+		if datasources, err = o.migrateGrafanaDatasources(); err != nil {
+			return err
+		}
+		o.datasourceReferences = datasources.References
+	}
+	if err := o.getLibraryPanels(); err != nil {
+		return err
+	}
+	hits, err := o.grafana.SearchDashboards()
+	if err != nil {
+		return fmt.Errorf("unable to list the Grafana dashboards: %w", err)
+	}
+	migrations := make([]*migration, len(hits))
+	for i, hit := range hits {
+		grafanaFolder := hit.Folder()
+		dest := resolveDestination(o.rules, o.project, grafanaFolder)
+		migrations[i] = &migration{
+			report: &dashboardReport{
+				UID:           hit.UID,
+				Title:         hit.Title,
+				GrafanaFolder: grafanaFolder,
+				Project:       dest.project,
+				Folder:        dest.folder,
+			},
+		}
+	}
+	sort.SliceStable(migrations, func(i, j int) bool {
+		if migrations[i].report.Project != migrations[j].report.Project {
+			return migrations[i].report.Project < migrations[j].report.Project
+		}
+		return migrations[i].report.UID < migrations[j].report.UID
+	})
+
+	o.forEach(migrations, o.migrateDashboard)
+	projects := buildProjects(migrations)
+	if len(o.outputDir) > 0 {
+		if writeErr := o.writeDatasources(datasources); writeErr != nil {
+			return writeErr
+		}
+		if writeErr := o.writeProjects(projects); writeErr != nil {
+			return writeErr
+		}
+	}
+	if o.apply {
+		if applyErr := o.applyDatasources(datasources); applyErr != nil {
+			return applyErr
+		}
+		if applyErr := o.applyProjects(projects); applyErr != nil {
+			return applyErr
+		}
+	}
+
+	reports := make([]*dashboardReport, 0, len(migrations))
+	for _, m := range migrations {
+		reports = append(reports, m.report)
+	}
+	r := newReport(reports)
+	if datasources != nil {
+		r.DatasourceWarnings = datasources.Warnings
+	}
+	if len(o.reportPath) > 0 {
+		if reportErr := r.write(o.reportPath, o.Output); reportErr != nil {
+			return fmt.Errorf("unable to write the report: %w", reportErr)
+		}
+	}
+	if printErr := r.print(o.writer); printErr != nil {
+		return printErr
+	}
+	if r.Failed > 0 {
+		return fmt.Errorf("%d dashboards failed to be migrated", r.Failed)
+	}
+	if belowMinScore := r.countBelow(o.minScore); belowMinScore > 0 {
+		return fmt.Errorf("%d dashboards have a migration score below %g", belowMinScore, o.minScore)
+	}
+	return nil
+}
+
+// getLibraryPanels retrieves the library panels, so they can be resolved in the dashboards using them.
+func (o *option) getLibraryPanels() error {
+	rawLibraryPanels, err := o.grafana.ListLibraryPanels()
+	if err != nil {
+		return fmt.Errorf("unable to list the Grafana library panels: %w", err)
+	}
+	o.libraryPanels = make([]migrate.GrafanaLibraryPanel, len(rawLibraryPanels))
+	for i, rawLibraryPanel := range rawLibraryPanels {
+		if unmarshalErr := json.Unmarshal(rawLibraryPanel, &o.libraryPanels[i]); unmarshalErr != nil {
+			return fmt.Errorf("unable to read the Grafana library panels: %w", unmarshalErr)
+		}
+	}
+	return nil
+}
+
+// forEach runs the given function on every migration, using at most o.parallelism goroutines.
+func (o *option) forEach(migrations []*migration, f func(m *migration)) {
+	queue := make(chan *migration)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < o.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for m := range queue {
+				f(m)
+			}
+		}()
+	}
+	for _, m := range migrations {
+		queue <- m
+	}
+	close(queue)
+	wg.Wait()
+}
+
+func (o *option) migrateDashboard(m *migration) {
+	if len(m.report.Project) == 0 {
+		m.report.fail(fmt.Errorf("unable to build a project name from the Grafana folder %q, use --rule or --project", m.report.GrafanaFolder))
+		return
+	}
+	grafanaDashboard, err := o.grafana.GetDashboard(m.report.UID)
+	if err != nil {
+		m.report.fail(err)
+		return
+	}
+	grafanaDashboard, err = migrate.ResolveLibraryPanels(grafanaDashboard, o.libraryPanels)
+	if err != nil {
+		m.report.fail(err)
+		return
+	}
+	var persesDashboard *modelV1.Dashboard
+	var migrationReport *modelAPI.MigrationReport
+	if o.online {
+		persesDashboard, migrationReport, err = o.apiClient.MigrateWithReport(&modelAPI.Migrate{
+			GrafanaDashboard:     grafanaDashboard,
+			UseDefaultDatasource: o.useDefaultDatasource,
+			DatasourceReferences: o.datasourceReferences,
+		})
+	} else {
+		dash := &migrate.SimplifiedDashboard{}
+		if err = json.Unmarshal(grafanaDashboard, dash); err == nil {
+			persesDashboard, migrationReport, err = o.mig.Migrate(dash, o.useDefaultDatasource)
+		}
+		if err == nil {
+			migrate.RewriteDatasourceReferences(persesDashboard, o.datasourceReferences)
+		}
+	}
+	if err != nil {
+		m.report.fail(err)
+		return
+	}
+	persesDashboard.Metadata.Name = m.report.UID
+	persesDashboard.Metadata.Project = m.report.Project
+	m.dashboard = persesDashboard
+	m.report.inspect(migrationReport)
+}
+
+// migrateGrafanaDatasources migrates the Grafana datasources to global datasources, as they can be used by dashboards
+// spread across several projects.
+func (o *option) migrateGrafanaDatasources() (*migrate.DatasourceMigration, error) {
+	data, err := o.grafana.ListDatasources()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the Grafana datasources: %w", err)
+	}
+	var grafanaDatasources []migrate.GrafanaDatasource
+	if unmarshalErr := json.Unmarshal(data, &grafanaDatasources); unmarshalErr != nil {
+		return nil, fmt.Errorf("unable to read the Grafana datasources: %w", unmarshalErr)
+	}
+	return o.mig.MigrateDatasources(grafanaDatasources, "")
+}
+
+// project gathers everything created in a Perses project by the migration.
+type project struct {
+	name       string
+	migrations []*migration
+	folders    []*modelV1.Folder
+}
+
+// buildProjects groups the migrated dashboards by project and creates the folders referencing them.
+// The migrations are expected to be sorted by project.
+func buildProjects(migrations []*migration) []*project {
+	var result []*project
+	var current *project
+	folders := map[string]*modelV1.Folder{}
+	for _, m := range migrations {
+		if m.dashboard == nil {
+			continue
+		}
+		if current == nil || current.name != m.report.Project {
+			current = &project{name: m.report.Project}
+			result = append(result, current)
+			folders = map[string]*modelV1.Folder{}
+		}
+		current.migrations = append(current.migrations, m)
+		if len(m.report.Folder) == 0 {
+			continue
+		}
+		folder, ok := folders[m.report.Folder]
+		if !ok {
+			folder = &modelV1.Folder{
+				Kind:     modelV1.KindFolder,
+				Metadata: *modelV1.NewProjectMetadata(current.name, m.report.Folder),
+			}
+			folders[m.report.Folder] = folder
+			current.folders = append(current.folders, folder)
+		}
+		folder.Spec = append(folder.Spec, modelV1.FolderSpec{Kind: modelV1.KindDashboard, Name: m.report.UID})
+	}
+	return result
+}
+
+// writeProjects writes one file per project. Each file contains the project followed by its dashboards and its folders,
+// so it can be given as it is to 'percli apply'.
+func (o *option) writeProjects(projects []*project) error {
+	if err := os.MkdirAll(o.outputDir, 0750); err != nil {
+		return err
+	}
+	for _, p := range projects {
+		entities := []modelAPI.Entity{&modelV1.Project{Kind: modelV1.KindProject, Metadata: *modelV1.NewMetadata(p.name)}}
+		for _, m := range p.migrations {
+			entities = append(entities, m.dashboard)
+		}
+		for _, folder := range p.folders {
+			entities = append(entities, folder)
+		}
+		if err := o.writeFile(filepath.Join(o.outputDir, fmt.Sprintf("%s.%s", p.name, o.Output)), entities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDatasources writes the global secrets and datasources in a single file, in the order they must be applied.
+func (o *option) writeDatasources(datasources *migrate.DatasourceMigration) error {
+	if datasources == nil {
+		return nil
+	}
+	if err := os.MkdirAll(o.outputDir, 0750); err != nil {
+		return err
+	}
+	entities := append(append([]modelAPI.Entity{}, datasources.Secrets...), datasources.Datasources...)
+	return o.writeFile(filepath.Join(o.outputDir, fmt.Sprintf("datasources.%s", o.Output)), entities)
+}
+
+func (o *option) writeFile(path string, entities []modelAPI.Entity) error {
+	f, err := os.Create(path) //nolint: gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint: errcheck
+	return output.Handle(f, o.Output, entities)
+}
+
+func (o *option) applyDatasources(datasources *migrate.DatasourceMigration) error {
+	if datasources == nil {
+		return nil
+	}
+	secretService, err := service.New(modelV1.KindGlobalSecret, "", o.apiClient)
+	if err != nil {
+		return err
+	}
+	for _, entity := range datasources.Secrets {
+		if upsertErr := service.Upsert(secretService, entity); upsertErr != nil {
+			return fmt.Errorf("unable to apply the global secret %q: %w", entity.GetMetadata().GetName(), upsertErr)
+		}
+	}
+	datasourceService, err := service.New(modelV1.KindGlobalDatasource, "", o.apiClient)
+	if err != nil {
+		return err
+	}
+	for _, entity := range datasources.Datasources {
+		if upsertErr := service.Upsert(datasourceService, entity); upsertErr != nil {
+			return fmt.Errorf("unable to apply the global datasource %q: %w", entity.GetMetadata().GetName(), upsertErr)
+		}
+	}
+	return nil
+}
+
+// applyProjects creates the projects that don't exist yet, then creates or updates the dashboards and the folders.
+// A dashboard that cannot be applied is reported as failed, while any other error stops the migration.
+func (o *option) applyProjects(projects []*project) error {
+	projectService, err := service.New(modelV1.KindProject, "", o.apiClient)
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		entity := &modelV1.Project{Kind: modelV1.KindProject, Metadata: *modelV1.NewMetadata(p.name)}
+		if _, createErr := projectService.CreateResource(entity); createErr != nil && !errors.Is(createErr, perseshttp.ConflictError) {
+			return fmt.Errorf("unable to create the project %q: %w", p.name, createErr)
+		}
+		dashboardService, svcErr := service.New(modelV1.KindDashboard, p.name, o.apiClient)
+		if svcErr != nil {
+			return svcErr
+		}
+		o.forEach(p.migrations, func(m *migration) {
+			if upsertErr := service.Upsert(dashboardService, m.dashboard); upsertErr != nil {
+				m.report.fail(fmt.Errorf("unable to apply the dashboard: %w", upsertErr))
+			}
+		})
+		folderService, svcErr := service.New(modelV1.KindFolder, p.name, o.apiClient)
+		if svcErr != nil {
+			return svcErr
+		}
+		for _, folder := range p.folders {
+			if upsertErr := service.Upsert(folderService, folder); upsertErr != nil {
+				return fmt.Errorf("unable to apply the folder %q in the project %q: %w", folder.Metadata.Name, p.name, upsertErr)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "grafana --url [GRAFANA_URL]",
+		Short: "migrate every dashboard of a Grafana instance to Perses",
+		Long: `Migrate every dashboard of a Grafana instance to Perses, using the Grafana HTTP API.
+
+The dashboards are put in the Perses project matching their Grafana folder according to the rules given with --rule.
+A rule has the syntax <regexp>=<project>[/<folder>]: the regexp must match the whole title of the Grafana folder,
+and the project and the folder can reference the captured groups with $1, $2, etc. The first matching rule wins.
+When no rule matches, the dashboard goes to the project set with --project, or to a project named after its Grafana folder.
+
+The migrated resources are written in --output-dir, one file per project that can be given to 'percli apply',
+and/or created directly in Perses with --apply. A summary of the dashboards that failed or have been partially migrated
+is printed at the end, and the full report can be written in a file with --report. With --min-score, the command fails
+when the score of a dashboard, i.e. the percentage of its panels, queries and variables migrated, is lower.
+
+The Grafana token can also be provided with the environment variable GRAFANA_TOKEN.
+`,
+		Example: `
+# Migrate all dashboards in files, one project per Grafana folder
+percli migrate grafana --url https://grafana.example.com --token $TOKEN --plugin.path ./plugins --output-dir ./migrated
+
+# Migrate all dashboards directly in Perses, mapping the folders "Team A - *" to the project "team-a"
+percli migrate grafana --url https://grafana.example.com --online --apply --rule 'Team A - (.+)=team-a/$1' --report report.yaml
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	opt.AddOutputFlags(cmd, &o.OutputOption)
+	cmd.Flags().StringVar(&o.grafanaURL, "url", "", "URL of the Grafana instance.")
+	cmd.Flags().StringVar(&o.token, "token", "", "Token used to authenticate against the Grafana API. Defaults to the environment variable GRAFANA_TOKEN.")
+	cmd.Flags().StringArrayVar(&o.rawRules, "rule", o.rawRules, "Rule mapping Grafana folders to Perses projects. Syntax supported is <regexp>=<project>[/<folder>].")
+	cmd.Flags().StringVar(&o.project, "project", "", "The project receiving the dashboards that don't match any rule. If not set, a project is created for each Grafana folder.")
+	cmd.Flags().IntVar(&o.parallelism, "parallelism", 10, "Number of dashboards migrated in parallel.")
+	cmd.Flags().StringVar(&o.reportPath, "report", "", "Path to the file where the migration report is written.")
+	cmd.Flags().Float64Var(&o.minScore, "min-score", 0, "Minimum migration score of each dashboard, between 0 and 100.")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "Directory where the migrated resources are written.")
+	cmd.Flags().BoolVar(&o.apply, "apply", false, "When enabled, the migrated resources are created or updated in Perses.")
+	cmd.Flags().StringVar(&o.pluginPath, "plugin.path", "", "Path to the Perses plugins.")
+	cmd.Flags().BoolVar(&o.online, "online", false, "When enabled, it can request the API to use it to perform the migration")
+	cmd.Flags().BoolVar(&o.useDefaultDatasource, "use-default-datasource", false, "When enabled, the default Perses datasource will be used for all panels. This will remove any reference to a specific datasource in the migrated dashboard.")
+	cmd.Flags().BoolVar(&o.migrateDatasources, "datasources", false, "When enabled, the Grafana datasources are migrated to global datasources, and the dashboards are updated to use them. It requires --plugin.path.")
+	cmd.MarkFlagsMutuallyExclusive("plugin.path", "online")
+	if err := cmd.MarkFlagRequired("url"); err != nil {
+		logrus.Panic(err)
+	}
+	return cmd
+}
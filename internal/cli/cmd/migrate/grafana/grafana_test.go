@@ -0,0 +1,213 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perses/perses/internal/cli/file"
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/perses/perses/internal/test"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/datasource"
+	"github.com/stretchr/testify/assert"
+)
+
+// reuse the test data from the API
+var testDataFolder = filepath.Join(test.GetRepositoryPath(), "internal", "api", "plugin", "migrate", "testdata")
+
+// newGrafanaServer starts a stand-in of the Grafana API serving a datasource, no library panel and two dashboards:
+// "random" in the folder "Team A - Infra", and "missing" at the root that cannot be retrieved.
+func newGrafanaServer(t *testing.T) *httptest.Server {
+	grafanaDashboard := test.ReadFile(filepath.Join(testDataFolder, "dashboards", "basic_grafana_dashboard.json"))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/search", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"uid": "random", "title": "Basic dashboard", "folderUid": "f1", "folderTitle": "Team A - Infra"},
+			{"uid": "missing", "title": "Missing dashboard"}
+		]`))
+	})
+	mux.HandleFunc("/api/datasources", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"uid": "exotic", "name": "Exotic", "type": "exotic-tsdb", "url": "http://exotic:9090"}]`))
+	})
+	mux.HandleFunc("/api/library-elements", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"result": {"totalCount": 0, "elements": []}}`))
+	})
+	mux.HandleFunc("/api/dashboards/uid/random", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"dashboard": `))
+		_, _ = w.Write(grafanaDashboard)
+		_, _ = w.Write([]byte(`}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMigrateGrafanaCMD(t *testing.T) {
+	server := newGrafanaServer(t)
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "empty args",
+			Args:            []string{},
+			IsErrorExpected: true,
+			ExpectedMessage: `required flag(s) "url" not set`,
+		},
+		{
+			Title:           "use args",
+			Args:            []string{"whatever", "--url", server.URL},
+			IsErrorExpected: true,
+			ExpectedMessage: "no args are supported by the command 'migrate grafana'",
+		},
+		{
+			Title:           "invalid rule",
+			Args:            []string{"--url", server.URL, "--rule", "Team A"},
+			IsErrorExpected: true,
+			ExpectedMessage: `invalid rule "Team A", the syntax is <regexp>=<project>[/<folder>]`,
+		},
+		{
+			Title:           "offline migration without plugin path",
+			Args:            []string{"--url", server.URL, "--output-dir", t.TempDir()},
+			IsErrorExpected: true,
+			ExpectedMessage: "offline migration requires --plugin.path to be specified, or use --online for server-side migration",
+		},
+		{
+			Title:           "datasources migration without plugin path",
+			Args:            []string{"--url", server.URL, "--online", "--datasources", "--output-dir", t.TempDir()},
+			IsErrorExpected: true,
+			ExpectedMessage: "the migration of the datasources requires --plugin.path to be specified",
+		},
+		{
+			Title:           "no output",
+			Args:            []string{"--url", server.URL, "--plugin.path", filepath.Join(testDataFolder, "plugins")},
+			IsErrorExpected: true,
+			ExpectedMessage: "nowhere to put the migrated dashboards, use --output-dir and/or --apply",
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
+
+func TestMigrateGrafanaToDirectory(t *testing.T) {
+	server := newGrafanaServer(t)
+	outputDir := t.TempDir()
+	reportPath := filepath.Join(t.TempDir(), "report.yaml")
+	var expectedDashboard *modelV1.Dashboard
+	test.JSONUnmarshalFromFile(filepath.Join(testDataFolder, "dashboards", "basic_perses_dashboard.json"), &expectedDashboard)
+
+	cmd := NewCMD()
+	buffer := bytes.NewBufferString("")
+	cmd.SetOut(buffer)
+	cmd.SetArgs([]string{
+		"--url", server.URL,
+		"--plugin.path", filepath.Join(testDataFolder, "plugins"),
+		"--rule", "Team A - (.+)=team-a/$1",
+		"--output-dir", outputDir,
+		"--report", reportPath,
+		"--parallelism", "2",
+		"--datasources",
+	})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "1 dashboards failed to be migrated")
+	assert.Contains(t, buffer.String(), "2 dashboards: ")
+
+	entities, unmarshalErr := file.UnmarshalEntities(filepath.Join(outputDir, "team-a.yaml"), "")
+	if !assert.NoError(t, unmarshalErr) || !assert.Len(t, entities, 3) {
+		return
+	}
+	assert.Equal(t, &modelV1.Project{Kind: modelV1.KindProject, Metadata: *modelV1.NewMetadata("team-a")}, entities[0])
+	dashboard := entities[1].(*modelV1.Dashboard)
+	assert.Equal(t, "random", dashboard.Metadata.Name)
+	assert.Equal(t, "team-a", dashboard.Metadata.Project)
+	assert.Equal(t, expectedDashboard.Spec, dashboard.Spec)
+	assert.Equal(t, []modelAPI.Entity{&modelV1.Folder{
+		Kind:     modelV1.KindFolder,
+		Metadata: *modelV1.NewProjectMetadata("team-a", "infra"),
+		Spec:     []modelV1.FolderSpec{{Kind: modelV1.KindDashboard, Name: "random"}},
+	}}, entities[2:])
+	datasources, unmarshalErr := file.UnmarshalEntities(filepath.Join(outputDir, "datasources.yaml"), "")
+	if assert.NoError(t, unmarshalErr) && assert.Len(t, datasources, 1) {
+		assert.Equal(t, &modelV1.GlobalDatasource{
+			Kind:     modelV1.KindGlobalDatasource,
+			Metadata: *modelV1.NewMetadata("Exotic"),
+			Spec: datasource.Spec{
+				Display: &common.Display{Name: "Exotic"},
+				Plugin:  common.Plugin{Kind: "ExoticTSDB", Spec: map[string]any{"url": "http://exotic:9090"}},
+			},
+		}, datasources[0])
+	}
+	// The dashboard that couldn't be retrieved doesn't produce any file.
+	_, statErr := os.Stat(filepath.Join(outputDir, "general.yaml"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	var r report
+	test.YAMLUnmarshalFromFile(reportPath, &r)
+	assert.Equal(t, 2, r.Total)
+	assert.Equal(t, 1, r.Migrated+r.Partial)
+	assert.Equal(t, 1, r.Failed)
+	assert.Equal(t, "missing", r.Dashboards[0].UID)
+	assert.Equal(t, "general", r.Dashboards[0].Project)
+	assert.Equal(t, failedStatus, r.Dashboards[0].Status)
+	assert.Equal(t, "random", r.Dashboards[1].UID)
+	assert.Equal(t, "infra", r.Dashboards[1].Folder)
+}
+
+func TestResolveDestination(t *testing.T) {
+	rules := make([]*rule, 0, 2)
+	for _, raw := range []string{"Team (A|B) - (.+)=team-$1/$2", "Infra.*=infrastructure"} {
+		r, err := parseRule(raw)
+		if !assert.NoError(t, err) {
+			return
+		}
+		rules = append(rules, r)
+	}
+	testSuite := []struct {
+		title          string
+		defaultProject string
+		grafanaFolder  string
+		expected       destination
+	}{
+		{
+			title:         "rule with a folder and captured groups",
+			grafanaFolder: "Team B - Databases & Queues",
+			expected:      destination{project: "team-b", folder: "databases-queues"},
+		},
+		{
+			title:         "rule without folder",
+			grafanaFolder: "Infrastructure",
+			expected:      destination{project: "infrastructure"},
+		},
+		{
+			title:         "the regexp must match the whole title",
+			grafanaFolder: "Old Team A - Legacy",
+			expected:      destination{project: "old-team-a-legacy"},
+		},
+		{
+			title:          "default project",
+			defaultProject: "migrated",
+			grafanaFolder:  "Old Team A - Legacy",
+			expected:       destination{project: "migrated"},
+		},
+	}
+	for _, tt := range testSuite {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveDestination(rules, tt.defaultProject, tt.grafanaFolder))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/perses/perses/internal/cli/output"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+)
+
+type status string
+
+const (
+	migratedStatus status = "migrated"
+	// partialStatus is used when the dashboard has been migrated, but it contains panels or queries that are not supported
+	// by the migration and have been replaced by a placeholder.
+	partialStatus status = "partial"
+	failedStatus  status = "failed"
+)
+
+type dashboardReport struct {
+	UID           string `json:"uid" yaml:"uid"`
+	Title         string `json:"title" yaml:"title"`
+	GrafanaFolder string `json:"grafanaFolder" yaml:"grafanaFolder"`
+	Project       string `json:"project" yaml:"project"`
+	Folder        string `json:"folder,omitempty" yaml:"folder,omitempty"`
+	Status        status `json:"status" yaml:"status"`
+	Error         string `json:"error,omitempty" yaml:"error,omitempty"`
+	// Score is the percentage of the panels, queries and variables that have been migrated.
+	Score float64 `json:"score" yaml:"score"`
+	// Issues lists the panels, queries and variables that have not been migrated.
+	Issues []modelAPI.MigrationItem `json:"issues,omitempty" yaml:"issues,omitempty"`
+	// Warnings lists the settings of the dashboard that couldn't be migrated.
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+func (r *dashboardReport) fail(err error) {
+	r.Status = failedStatus
+	r.Error = err.Error()
+}
+
+// inspect keeps from the migration report what has not been migrated and sets the status accordingly.
+func (r *dashboardReport) inspect(migrationReport *modelAPI.MigrationReport) {
+	r.Score = migrationReport.Summary.Score
+	for _, item := range migrationReport.Items {
+		if item.Status != modelAPI.MigrationStatusMigrated {
+			r.Issues = append(r.Issues, item)
+		}
+	}
+	r.Warnings = migrationReport.Warnings
+	r.Status = migratedStatus
+	if len(r.Issues) > 0 || len(r.Warnings) > 0 {
+		r.Status = partialStatus
+	}
+}
+
+func (r *dashboardReport) details() string {
+	if r.Status == failedStatus {
+		return r.Error
+	}
+	issues := make(map[modelAPI.MigrationItemKind][]string)
+	for _, item := range r.Issues {
+		issues[item.Kind] = append(issues[item.Kind], item.Name)
+	}
+	details := []string{fmt.Sprintf("score: %g", r.Score)}
+	for _, kind := range []modelAPI.MigrationItemKind{modelAPI.MigrationItemKindPanel, modelAPI.MigrationItemKindQuery, modelAPI.MigrationItemKindVariable, modelAPI.MigrationItemKindAnnotation} {
+		if names, ok := issues[kind]; ok {
+			sort.Strings(names)
+			details = append(details, fmt.Sprintf("unsupported %ss: %s", kind, strings.Join(names, ", ")))
+		}
+	}
+	details = append(details, r.Warnings...)
+	return strings.Join(details, "; ")
+}
+
+type report struct {
+	Total      int                `json:"total" yaml:"total"`
+	Migrated   int                `json:"migrated" yaml:"migrated"`
+	Partial    int                `json:"partial" yaml:"partial"`
+	Failed     int                `json:"failed" yaml:"failed"`
+	Dashboards []*dashboardReport `json:"dashboards" yaml:"dashboards"`
+	// DatasourceWarnings lists what couldn't be migrated from the Grafana datasources.
+	DatasourceWarnings []string `json:"datasourceWarnings,omitempty" yaml:"datasourceWarnings,omitempty"`
+}
+
+func newReport(dashboards []*dashboardReport) *report {
+	r := &report{
+		Total:      len(dashboards),
+		Dashboards: dashboards,
+	}
+	for _, dash := range dashboards {
+		switch dash.Status {
+		case migratedStatus:
+			r.Migrated++
+		case partialStatus:
+			r.Partial++
+		case failedStatus:
+			r.Failed++
+		}
+	}
+	return r
+}
+
+// countBelow returns the number of migrated dashboards whose score is lower than the given one.
+func (r *report) countBelow(score float64) int {
+	count := 0
+	for _, dash := range r.Dashboards {
+		if dash.Status != failedStatus && dash.Score < score {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *report) write(path string, format string) error {
+	f, err := os.Create(path) //nolint: gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint: errcheck
+	return output.Handle(f, format, r)
+}
+
+// print displays the dashboards that have not been fully migrated, followed by a summary.
+func (r *report) print(writer io.Writer) error {
+	var data [][]string
+	for _, dash := range r.Dashboards {
+		if dash.Status == migratedStatus {
+			continue
+		}
+		data = append(data, []string{dash.UID, dash.GrafanaFolder, dash.Project, string(dash.Status), dash.details()})
+	}
+	if len(data) > 0 {
+		if err := output.HandlerTable(writer, []string{"UID", "GRAFANA FOLDER", "PROJECT", "STATUS", "DETAILS"}, data); err != nil {
+			return err
+		}
+	}
+	for _, warning := range r.DatasourceWarnings {
+		if err := output.HandleString(writer, fmt.Sprintf("warning: %s", warning)); err != nil {
+			return err
+		}
+	}
+	return output.HandleString(writer, fmt.Sprintf("%d dashboards: %d migrated, %d partially migrated, %d failed", r.Total, r.Migrated, r.Partial, r.Failed))
+}
@@ -0,0 +1,87 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// rule maps the Grafana folders whose title matches the regexp to a Perses project, and optionally to a Perses folder
+// in this project.
+type rule struct {
+	folderRegexp *regexp.Regexp
+	project      string
+	folder       string
+}
+
+// parseRule parses a rule with the syntax <regexp>=<project>[/<folder>].
+// The project and the folder can use the groups captured by the regexp, like $1.
+func parseRule(value string) (*rule, error) {
+	i := strings.LastIndex(value, "=")
+	if i <= 0 || i == len(value)-1 {
+		return nil, fmt.Errorf("invalid rule %q, the syntax is <regexp>=<project>[/<folder>]", value)
+	}
+	re, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", value[:i]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", value, err)
+	}
+	project, folder, _ := strings.Cut(value[i+1:], "/")
+	if len(project) == 0 {
+		return nil, fmt.Errorf("invalid rule %q, the project cannot be empty", value)
+	}
+	return &rule{
+		folderRegexp: re,
+		project:      project,
+		folder:       folder,
+	}, nil
+}
+
+// destination is where a Grafana dashboard is migrated.
+type destination struct {
+	project string
+	// folder is the Perses folder containing the dashboard. It's empty when the dashboard is not put in a folder.
+	folder string
+}
+
+// resolveDestination returns the destination of the dashboards of the given Grafana folder.
+// The first matching rule is used. When no rule matches, the dashboards go to the default project, or to a project named
+// after the Grafana folder when there is no default project.
+func resolveDestination(rules []*rule, defaultProject string, grafanaFolder string) destination {
+	for _, r := range rules {
+		match := r.folderRegexp.FindStringSubmatchIndex(grafanaFolder)
+		if match == nil {
+			continue
+		}
+		result := destination{
+			project: toName(string(r.folderRegexp.ExpandString(nil, r.project, grafanaFolder, match))),
+		}
+		if len(r.folder) > 0 {
+			result.folder = toName(string(r.folderRegexp.ExpandString(nil, r.folder, grafanaFolder, match)))
+		}
+		return result
+	}
+	if len(defaultProject) > 0 {
+		return destination{project: defaultProject}
+	}
+	return destination{project: toName(grafanaFolder)}
+}
+
+// toName converts a Grafana title to a valid Perses name.
+func toName(title string) string {
+	return strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
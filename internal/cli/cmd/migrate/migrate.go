@@ -17,11 +17,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 
 	"github.com/perses/perses/internal/api/plugin"
 	"github.com/perses/perses/internal/api/plugin/migrate"
 	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/cmd/migrate/datasource"
+	"github.com/perses/perses/internal/cli/cmd/migrate/grafana"
 	"github.com/perses/perses/internal/cli/config"
 	"github.com/perses/perses/internal/cli/file"
 	"github.com/perses/perses/internal/cli/opt"
@@ -30,7 +33,6 @@ import (
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	apiConfig "github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
-	"github.com/perses/spec/go/dashboard"
 	"github.com/spf13/cobra"
 )
 
@@ -44,10 +46,10 @@ type kubeMetadata struct {
 }
 
 type kubeCustomResource struct {
-	APIVersion string         `json:"apiVersion" yaml:"apiVersion"`
-	Kind       string         `json:"kind" yaml:"kind"`
-	Metadata   kubeMetadata   `json:"metadata" yaml:"metadata"`
-	Spec       dashboard.Spec `json:"spec" yaml:"spec"`
+	APIVersion string                         `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string                         `json:"kind" yaml:"kind"`
+	Metadata   kubeMetadata                   `json:"metadata" yaml:"metadata"`
+	Spec       modelV1.AnnotatedDashboardSpec `json:"spec" yaml:"spec"`
 }
 
 func createCustomResource(dash *modelV1.Dashboard) *kubeCustomResource {
@@ -82,6 +84,12 @@ type option struct {
 	pluginPath           string
 	online               bool
 	useDefaultDatasource bool
+	datasourceFile       string
+	datasourceReferences map[string]string
+	libraryPanelsFile    string
+	libraryPanels        []migrate.GrafanaLibraryPanel
+	reportPath           string
+	minScore             float64
 	mig                  migrate.Migration
 	apiClient            api.ClientInterface
 	migrationFormat      migrationFormat
@@ -95,6 +103,20 @@ func (o *option) Complete(args []string) error {
 		return outputErr
 	}
 	o.completeInput()
+	if len(o.datasourceFile) > 0 {
+		grafanaDatasources, err := datasource.ReadGrafanaDatasources(o.datasourceFile)
+		if err != nil {
+			return err
+		}
+		o.datasourceReferences = migrate.DatasourceReferences(grafanaDatasources)
+	}
+	if len(o.libraryPanelsFile) > 0 {
+		libraryPanels, err := readLibraryPanels(o.libraryPanelsFile)
+		if err != nil {
+			return err
+		}
+		o.libraryPanels = libraryPanels
+	}
 	if len(o.pluginPath) > 0 {
 		pl := plugin.New(apiConfig.Plugin{
 			Path: o.pluginPath,
@@ -115,6 +137,47 @@ func (o *option) Complete(args []string) error {
 	return nil
 }
 
+// readLibraryPanels reads the Grafana library panels from a file containing either the response of the Grafana API
+// listing the library panels, a list of library panels, or the library panels embedded in an exported dashboard.
+func readLibraryPanels(filePath string) ([]migrate.GrafanaLibraryPanel, error) {
+	var content any
+	if err := file.Unmarshal(filePath, &content); err != nil {
+		return nil, err
+	}
+	if object, ok := content.(map[string]any); ok {
+		if result, isAPIResponse := object["result"].(map[string]any); isAPIResponse {
+			object = result
+		}
+		if elements, isList := object["elements"]; isList {
+			content = elements
+		} else if elements, isExport := object["__elements"].(map[string]any); isExport {
+			object = elements
+		}
+		if _, isLibraryPanel := object["model"]; isLibraryPanel {
+			content = []any{object}
+		} else if _, isList := content.([]any); !isList {
+			var libraryPanels []any
+			for _, libraryPanel := range object {
+				libraryPanels = append(libraryPanels, libraryPanel)
+			}
+			content = libraryPanels
+		}
+	}
+	if _, ok := content.([]any); !ok {
+		return nil, fmt.Errorf("no Grafana library panel found in the file %q", filePath)
+	}
+	// The library panels are converted back to JSON, as it's how they are used during the migration.
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	var result []migrate.GrafanaLibraryPanel
+	if unmarshalErr := json.Unmarshal(data, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("unable to read the Grafana library panels from the file %q: %w", filePath, unmarshalErr)
+	}
+	return result, nil
+}
+
 func (o *option) completeInput() {
 	if len(o.rowInput) <= 0 {
 		return
@@ -141,6 +204,9 @@ func (o *option) Validate() error {
 	if !o.online && o.mig == nil {
 		return fmt.Errorf("offline migration requires --plugin.path to be specified, or use --online for server-side migration")
 	}
+	if o.minScore < 0 || o.minScore > 100 {
+		return fmt.Errorf("--min-score must be between 0 and 100")
+	}
 	return nil
 }
 
@@ -149,40 +215,87 @@ func (o *option) Execute() error {
 	if err := file.Unmarshal(o.File, &grafanaDashboard); err != nil {
 		return err
 	}
+	grafanaDashboard, err := migrate.ResolveLibraryPanels(grafanaDashboard, o.libraryPanels)
+	if err != nil {
+		return err
+	}
 	var persesDashboard *modelV1.Dashboard
-	var err error
+	var report *modelAPI.MigrationReport
 	if o.online {
-		persesDashboard, err = o.onlineExecution(grafanaDashboard)
+		persesDashboard, report, err = o.onlineExecution(grafanaDashboard)
 	} else {
-		persesDashboard, err = o.offlineExecution(grafanaDashboard)
+		persesDashboard, report, err = o.offlineExecution(grafanaDashboard)
 	}
 	if err != nil {
 		return err
 	}
+	for _, warning := range report.Warnings {
+		if outputErr := output.HandleString(o.errWriter, fmt.Sprintf("warning: %s", warning)); outputErr != nil {
+			return outputErr
+		}
+	}
+	if len(o.reportPath) > 0 {
+		if reportErr := o.writeReport(report); reportErr != nil {
+			return fmt.Errorf("unable to write the report: %w", reportErr)
+		}
+	}
 	persesDashboard.Metadata.Project = o.project
 
 	if o.migrationFormat == customResourceFormat || o.migrationFormat == customResourceShortFormat {
 		customResource := createCustomResource(persesDashboard)
-		return output.Handle(o.writer, o.Output, customResource)
+		err = output.Handle(o.writer, o.Output, customResource)
+	} else {
+		err = output.Handle(o.writer, o.Output, persesDashboard)
+	}
+	if err != nil {
+		return err
+	}
+	// The dashboard is still printed, so it can be inspected when the score is too low.
+	if report.Summary.Score < o.minScore {
+		return fmt.Errorf("the migration score %g is below the minimum score %g", report.Summary.Score, o.minScore)
+	}
+	return nil
+}
+
+func (o *option) writeReport(report *modelAPI.MigrationReport) error {
+	f, err := os.Create(o.reportPath) //nolint: gosec
+	if err != nil {
+		return err
 	}
-	return output.Handle(o.writer, o.Output, persesDashboard)
+	defer f.Close() //nolint: errcheck
+	return output.Handle(f, o.Output, report)
 }
 
-func (o *option) onlineExecution(grafanaDashboard json.RawMessage) (*modelV1.Dashboard, error) {
-	return o.apiClient.Migrate(&modelAPI.Migrate{
+func (o *option) onlineExecution(grafanaDashboard json.RawMessage) (*modelV1.Dashboard, *modelAPI.MigrationReport, error) {
+	body := &modelAPI.Migrate{
 		Input:                o.input,
 		GrafanaDashboard:     grafanaDashboard,
 		UseDefaultDatasource: o.useDefaultDatasource,
-	})
+		DatasourceReferences: o.datasourceReferences,
+	}
+	if len(o.reportPath) > 0 || o.minScore > 0 {
+		return o.apiClient.MigrateWithReport(body)
+	}
+	// The report is only requested when it's used, so the command keeps working with the servers that cannot provide it.
+	persesDashboard, err := o.apiClient.Migrate(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return persesDashboard, modelAPI.NewMigrationReport(), nil
 }
 
-func (o *option) offlineExecution(grafanaDashboard json.RawMessage) (*modelV1.Dashboard, error) {
+func (o *option) offlineExecution(grafanaDashboard json.RawMessage) (*modelV1.Dashboard, *modelAPI.MigrationReport, error) {
 	rawGrafanaDashboard := []byte(migrate.ReplaceInputValue(o.input, string(grafanaDashboard)))
 	dash := &migrate.SimplifiedDashboard{}
 	if err := json.Unmarshal(rawGrafanaDashboard, dash); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return o.mig.Migrate(dash, o.useDefaultDatasource)
+	persesDashboard, report, err := o.mig.Migrate(dash, o.useDefaultDatasource)
+	if err != nil {
+		return nil, nil, err
+	}
+	migrate.RewriteDatasourceReferences(persesDashboard, o.datasourceReferences)
+	return persesDashboard, report, nil
 }
 
 func (o *option) SetWriter(writer io.Writer) {
@@ -201,6 +314,9 @@ func NewCMD() *cobra.Command {
 		Example: `
 # Migrate a Grafana dashboard with input
 percli migrate -f ./dashboard.json --input=DS_PROMETHEUS=PrometheusDemo --online
+
+# Migrate a Grafana dashboard, write the migration report and fail if less than 80% of the panels, queries and variables are migrated
+percli migrate -f ./dashboard.json --online --report report.yaml --min-score 80
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return persesCMD.Run(o, cmd, args)
@@ -214,9 +330,15 @@ percli migrate -f ./dashboard.json --input=DS_PROMETHEUS=PrometheusDemo --online
 	cmd.Flags().StringVar(&o.pluginPath, "plugin.path", "", "Path to the Perses plugins.")
 	cmd.Flags().BoolVar(&o.online, "online", false, "When enabled, it can request the API to use it to perform the migration")
 	cmd.Flags().BoolVar(&o.useDefaultDatasource, "use-default-datasource", false, "When enabled, the default Perses datasource will be used for all panels. This will remove any reference to a specific datasource in the migrated dashboard.")
+	cmd.Flags().StringVar(&o.datasourceFile, "grafana-datasources", "", "Path to the file containing the Grafana datasources, as given to 'percli migrate datasource'. When set, the references to these datasources are replaced by the names of the migrated Perses datasources.")
+	cmd.Flags().StringVar(&o.libraryPanelsFile, "library-panels", "", "Path to the file containing the Grafana library panels used by the dashboard, as returned by the Grafana API /api/library-elements. The library panels embedded in a dashboard exported for sharing externally are used automatically.")
+	cmd.Flags().StringVar(&o.reportPath, "report", "", "Path to the file where the migration report is written. The report tells how each panel, query and variable has been migrated.")
+	cmd.Flags().Float64Var(&o.minScore, "min-score", 0, "Minimum migration score, between 0 and 100. The command fails when the percentage of the panels, queries and variables migrated is lower.")
 	cmd.Flags().StringVar(&o.project, "project", "", "The project to use for the migration. If not set, then the field 'project' in the dashboard will not be set. When the format 'cr' is used, the project will be set to the namespace of the custom resource.")
 	// When "online" flag is used, the CLI will call the endpoint /migrate that will then use the schema from the server.
 	// So no need to use / load the schemas with the CLI.
 	cmd.MarkFlagsMutuallyExclusive("plugin.path", "online")
+	cmd.AddCommand(datasource.NewCMD())
+	cmd.AddCommand(grafana.NewCMD())
 	return cmd
 }
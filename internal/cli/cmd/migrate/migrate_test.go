@@ -14,12 +14,15 @@
 package migrate
 
 import (
+	"bytes"
 	"path/filepath"
 	"testing"
 
 	cmdTest "github.com/perses/perses/internal/cli/test"
 	"github.com/perses/perses/internal/test"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
 )
 
 // reuse the test data from the API
@@ -73,6 +76,31 @@ func TestMigrateCMD(t *testing.T) {
 			IsErrorExpected: false,
 			ExpectedMessage: string(test.YAMLMarshalStrict(dashboard)) + "\n",
 		},
+		{
+			Title:           "migrate with a score below the minimum",
+			Args:            []string{"-f", pathToGrafanaDashboard, "--min-score", "80", "--plugin.path", filepath.Join(testDataFolder, "plugins")},
+			IsErrorExpected: true,
+			ExpectedMessage: "the migration score 63.6 is below the minimum score 80",
+		},
 	}
 	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
 }
+
+func TestMigrateCMDWithReport(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	cmd := NewCMD()
+	cmd.SetOut(bytes.NewBufferString(""))
+	cmd.SetArgs([]string{
+		"-f", filepath.Join(testDataFolder, "dashboards", "basic_grafana_dashboard.json"),
+		"--plugin.path", filepath.Join(testDataFolder, "plugins"),
+		"--report", reportPath,
+		"-o", "json",
+	})
+	if !assert.NoError(t, cmd.Execute()) {
+		return
+	}
+	var report modelAPI.MigrationReport
+	test.JSONUnmarshalFromFile(reportPath, &report)
+	assert.Equal(t, modelAPI.MigrationSummary{Migrated: 7, Fallback: 2, Dropped: 2, Score: 63.6}, report.Summary)
+	assert.Len(t, report.Items, 11)
+}
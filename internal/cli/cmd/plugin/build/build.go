@@ -15,6 +15,7 @@ package build
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"io"
@@ -25,6 +26,7 @@ import (
 	"github.com/mholt/archives"
 	"github.com/perses/perses/internal/api/archive"
 	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/internal/api/plugin/signature"
 	persesCMD "github.com/perses/perses/internal/cli/cmd"
 	"github.com/perses/perses/internal/cli/cmd/plugin/config"
 	"github.com/perses/perses/internal/cli/output"
@@ -43,6 +45,8 @@ type option struct {
 	isSchemaRequired          bool
 	schemaPathFromPackageJSON string
 	cueVendor                 *cueVendor
+	signingKeyPath            string
+	signingKey                ed25519.PrivateKey
 	writer                    io.Writer
 	errWriter                 io.Writer
 }
@@ -68,6 +72,13 @@ func (o *option) Complete(args []string) error {
 		moduleFileBackupPath: filepath.Join(o.pluginPath, moduleFileBackup),
 		vendorDirPath:        filepath.Join(o.pluginPath, plugin.CuelangModuleFolder, vendorDir),
 	}
+	if len(o.signingKeyPath) > 0 {
+		key, keyErr := signature.ReadPrivateKey(o.signingKeyPath)
+		if keyErr != nil {
+			return keyErr
+		}
+		o.signingKey = key
+	}
 	return nil
 }
 
@@ -125,12 +136,46 @@ func (o *option) Execute() error {
 	if err != nil {
 		return err
 	}
+	if o.signingKey != nil {
+		signatureFile, signErr := o.sign(files)
+		if signErr != nil {
+			return signErr
+		}
+		defer os.Remove(signatureFile) //nolint:errcheck
+		signatureFiles, signErr := archives.FilesFromDisk(context.Background(), nil, map[string]string{signatureFile: signature.FileName})
+		if signErr != nil {
+			return fmt.Errorf("unable to add the signature to the archive: %w", signErr)
+		}
+		files = append(files, signatureFiles...)
+	}
 	if archiveBuildErr := archive.Build(filepath.Join(o.pluginPath, fmt.Sprintf("%s-%s", manifest.Name, npmPackageData.Version)), o.archiveFormat, files); archiveBuildErr != nil {
 		return fmt.Errorf("archive creation failed: %w", archiveBuildErr)
 	}
 	return output.HandleString(o.writer, fmt.Sprintf("%s built successfully", manifest.Name))
 }
 
+// sign computes the signature of the files of the archive and writes it in a temporary file.
+// It returns the path to this file.
+func (o *option) sign(files []archives.FileInfo) (string, error) {
+	digest, err := signature.DigestFiles(files)
+	if err != nil {
+		return "", fmt.Errorf("unable to compute the digest of the plugin: %w", err)
+	}
+	data, err := signature.Sign(digest, o.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("unable to sign the plugin: %w", err)
+	}
+	signatureFile, err := os.CreateTemp("", "perses-plugin-*.sig")
+	if err != nil {
+		return "", fmt.Errorf("unable to create the signature file: %w", err)
+	}
+	defer signatureFile.Close() //nolint:errcheck
+	if _, err := signatureFile.Write(data); err != nil {
+		return "", fmt.Errorf("unable to write the signature file: %w", err)
+	}
+	return signatureFile.Name(), nil
+}
+
 func (o *option) computeArchiveFiles() ([]archives.FileInfo, error) {
 	list := make(map[string]string)
 	// add README and LICENSE if they are present as they are optional
@@ -194,6 +239,7 @@ func NewCMD() *cobra.Command {
   - static: folder containing the UI part
   - schemas: folder containing the schema files
   - cue.mod: folder containing the CUE module & eventual vendored dependencies
+  - perses-plugin.sig: the signature of the plugin, only when a signing key is provided
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return persesCMD.Run(o, cmd, args)
@@ -203,6 +249,7 @@ func NewCMD() *cobra.Command {
 	cmd.Flags().BoolVar(&o.skipNPMBuild, "skip.npm-build", false, "The command will run `npm run build` to ensure the frontend is built before creating the archive. If you want to skip this step, you can use this flag.")
 	cmd.Flags().BoolVar(&o.skipNPMInstall, "skip.npm-install", false, "The command will run `npm ci` if it doesn't find the node_modules folder. If you want to skip this step, you can use this flag.")
 	cmd.Flags().StringVar(&o.cfgPath, "config", "", "Relative path to the configuration file. It is relative, because it will use as a root path the one set with the flag ---plugin.path. By default, the command will look for a file named 'perses_plugin_config.yaml'")
+	cmd.Flags().StringVar(&o.signingKeyPath, "signing-key", "", "Path to the PEM encoded ed25519 private key used to sign the plugin. When set, the signature is added to the archive, so Perses can verify it when loading the plugin.")
 	cmd.Flags().StringVar(&o.pluginPath, "plugin.path", "", "Path to the plugin. By default, the command will look at the folder where the command is running.")
 
 	return cmd
@@ -0,0 +1,130 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/internal/api/plugin/registry"
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/file"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	persesCMD.Option
+	name         string
+	versionRange string
+	registryURL  string
+	registry     *common.URL
+	pluginPath   string
+	cachePath    string
+	timeout      time.Duration
+	writer       io.Writer
+	errWriter    io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("the name of the plugin to install is required")
+	}
+	o.name = args[0]
+	if len(o.registryURL) == 0 {
+		return fmt.Errorf("the flag --registry is required")
+	}
+	registryURL, err := common.ParseURL(o.registryURL)
+	if err != nil {
+		return fmt.Errorf("invalid registry URL %q: %w", o.registryURL, err)
+	}
+	o.registry = registryURL
+	if len(o.cachePath) == 0 {
+		dir, cacheErr := os.UserCacheDir()
+		if cacheErr != nil {
+			return fmt.Errorf("cannot determine system cache directory: %w", cacheErr)
+		}
+		o.cachePath = filepath.Join(dir, "perses", "plugins")
+	}
+	return nil
+}
+
+func (o *option) Validate() error {
+	requirement := config.PluginRequirement{Name: o.name, Version: o.versionRange, Registry: *o.registry}
+	return requirement.Verify()
+}
+
+func (o *option) Execute() error {
+	release, archivePath, err := registry.New(o.cachePath, o.timeout).Fetch(o.registry, o.name, o.versionRange)
+	if err != nil {
+		return err
+	}
+	version := strings.TrimPrefix(release.Version, "v")
+	targetFolder := filepath.Join(o.pluginPath, fmt.Sprintf("%s-%s", o.name, version))
+	exist, err := file.Exists(targetFolder)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return output.HandleString(o.writer, fmt.Sprintf("%s %s is already installed in %s", o.name, version, targetFolder))
+	}
+	if extractErr := plugin.ExtractArchive(archivePath, targetFolder); extractErr != nil {
+		return fmt.Errorf("unable to extract the plugin %q: %w", o.name, extractErr)
+	}
+	return output.HandleString(o.writer, fmt.Sprintf("%s %s installed in %s", o.name, version, targetFolder))
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "install <plugin name>",
+		Short: "Download a plugin from a registry and install it in a local plugin folder.",
+		Long: `The command resolves the highest version of the plugin matching the version range, downloads its archive from the registry,
+verifies its checksum and extracts it in the plugin folder. The archives downloaded are kept in a cache folder.
+
+It is useful to run Perses locally with the same plugins as the ones required in the configuration of the server.
+`,
+		Example: `
+# Install the latest version of the Prometheus plugin in the folder "plugins"
+percli plugin install Prometheus --registry https://registry.example.com
+
+# Install the highest 0.6.x version of the Prometheus plugin in a custom folder
+percli plugin install Prometheus --version "~0.6.0" --registry https://registry.example.com --plugin.path ./dev/plugins
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&o.registryURL, "registry", "", "URL of the plugin registry.")
+	cmd.Flags().StringVar(&o.versionRange, "version", "", "Semver range the version of the plugin must match, like \"^0.6.0\". By default, the latest version is installed.")
+	cmd.Flags().StringVar(&o.pluginPath, "plugin.path", config.DefaultPluginPath, "Path to the folder where the plugin is installed.")
+	cmd.Flags().StringVar(&o.cachePath, "cache.path", "", "Path to the folder where the archives downloaded are cached. By default, it is a folder in the user cache directory.")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", config.DefaultRegistryTimeout, "Maximum time given to each request sent to the registry.")
+	return cmd
+}
@@ -0,0 +1,109 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mholt/archives"
+	"github.com/perses/perses/internal/api/plugin/registry"
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildArchive(t *testing.T) []byte {
+	folder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{"name": "prometheus"}`), 0600))
+	files, err := archives.FilesFromDisk(context.Background(), nil, map[string]string{
+		filepath.Join(folder, "package.json"): "package.json",
+	})
+	assert.NoError(t, err)
+	buf := &bytes.Buffer{}
+	format := archives.CompressedArchive{Compression: archives.Gz{}, Archival: archives.Tar{}}
+	assert.NoError(t, format.Archive(context.Background(), buf, files))
+	return buf.Bytes()
+}
+
+func TestPluginInstallCMD(t *testing.T) {
+	archive := buildArchive(t)
+	checksum := sha256.Sum256(archive)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Prometheus/index.json", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(registry.Index{
+			Name: "Prometheus",
+			Releases: []registry.Release{
+				{Version: "0.6.0", URL: "Prometheus-0.6.0.tar.gz", SHA256: hex.EncodeToString(checksum[:])},
+			},
+		})
+	})
+	mux.HandleFunc("/Prometheus/Prometheus-0.6.0.tar.gz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	pluginPath := t.TempDir()
+	cachePath := t.TempDir()
+	targetFolder := filepath.Join(pluginPath, "Prometheus-0.6.0")
+
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "no plugin name",
+			Args:            []string{"--registry", server.URL},
+			IsErrorExpected: true,
+			ExpectedMessage: "the name of the plugin to install is required",
+		},
+		{
+			Title:           "no registry",
+			Args:            []string{"Prometheus"},
+			IsErrorExpected: true,
+			ExpectedMessage: "the flag --registry is required",
+		},
+		{
+			Title:                "invalid version range",
+			Args:                 []string{"Prometheus", "--registry", server.URL, "--version", "not a range"},
+			IsErrorExpected:      true,
+			ExpectedRegexMessage: `invalid version range "not a range" for the plugin requirement "Prometheus"`,
+		},
+		{
+			Title:           "no version matching",
+			Args:            []string{"Prometheus", "--registry", server.URL, "--version", "^1.0.0", "--plugin.path", pluginPath, "--cache.path", cachePath},
+			IsErrorExpected: true,
+			ExpectedMessage: `unable to resolve the version of the plugin "Prometheus": no release matches the version range "^1.0.0"`,
+		},
+		{
+			Title:           "install the plugin",
+			Args:            []string{"Prometheus", "--registry", server.URL, "--version", "^0.6.0", "--plugin.path", pluginPath, "--cache.path", cachePath},
+			IsErrorExpected: false,
+			ExpectedMessage: fmt.Sprintf("Prometheus 0.6.0 installed in %s\n", targetFolder),
+		},
+		{
+			Title:           "plugin already installed",
+			Args:            []string{"Prometheus", "--registry", server.URL, "--plugin.path", pluginPath, "--cache.path", cachePath},
+			IsErrorExpected: false,
+			ExpectedMessage: fmt.Sprintf("Prometheus 0.6.0 is already installed in %s\n", targetFolder),
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+	assert.FileExists(t, filepath.Join(targetFolder, "package.json"))
+}
@@ -16,6 +16,7 @@ package plugin
 import (
 	"github.com/perses/perses/internal/cli/cmd/plugin/build"
 	"github.com/perses/perses/internal/cli/cmd/plugin/generate"
+	"github.com/perses/perses/internal/cli/cmd/plugin/install"
 	"github.com/perses/perses/internal/cli/cmd/plugin/lint"
 	"github.com/perses/perses/internal/cli/cmd/plugin/list"
 	"github.com/perses/perses/internal/cli/cmd/plugin/start"
@@ -30,6 +31,7 @@ func NewCMD() *cobra.Command {
 	}
 	cmd.AddCommand(generate.NewCMD())
 	cmd.AddCommand(build.NewCMD())
+	cmd.AddCommand(install.NewCMD())
 	cmd.AddCommand(lint.NewCMD())
 	cmd.AddCommand(list.NewCMD())
 	cmd.AddCommand(start.NewCMD())
@@ -376,6 +376,10 @@ func (o *option) runMigrationTestsForPath(testDir string, migrateBuildInstance,
 			resultPlugin, resultIsEmpty, err = migrate.ExecuteQueryScript(migrateBuildInstance, inputData)
 		case v1plugin.KindPanel:
 			resultPlugin, resultIsEmpty, err = migrate.ExecutePanelScript(migrateBuildInstance, inputData)
+		case v1plugin.KindDatasource:
+			resultPlugin, resultIsEmpty, err = migrate.ExecuteDatasourceScript(migrateBuildInstance, inputData)
+		case v1plugin.KindAnnotation:
+			resultPlugin, resultIsEmpty, err = migrate.ExecuteAnnotationScript(migrateBuildInstance, inputData)
 		default:
 			return fmt.Errorf("unsupported migration schema kind: %s", pluginKind)
 		}
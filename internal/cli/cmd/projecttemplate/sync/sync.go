@@ -0,0 +1,98 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"fmt"
+	"io"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/client/api"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	persesCMD.Option
+	writer       io.Writer
+	errWriter    io.Writer
+	templateName string
+	apiClient    api.ClientInterface
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("you have to specify the name of the project template to sync")
+	}
+	o.templateName = args[0]
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+func (o *option) Validate() error {
+	return nil
+}
+
+func (o *option) Execute() error {
+	result, err := o.apiClient.V1().ProjectTemplate().Sync(o.templateName)
+	if err != nil {
+		return err
+	}
+	for _, project := range result.Synced {
+		if outErr := output.HandleString(o.writer, fmt.Sprintf("project %q has been synced", project)); outErr != nil {
+			return outErr
+		}
+	}
+	for _, failure := range result.Failures {
+		if outErr := output.HandleString(o.errWriter, fmt.Sprintf("project %q cannot be synced: %s", failure.Project, failure.Error)); outErr != nil {
+			return outErr
+		}
+	}
+	if len(result.Failures) > 0 {
+		return fmt.Errorf("%d project(s) cannot be synced with the project template %q", len(result.Failures), o.templateName)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "sync NAME",
+		Short: "Instantiate again a project template in every project created from it",
+		Long: `Instantiate again a project template in every project created from it.
+The resources of the template are created in the projects, or updated when they already exist.
+Resources removed from the template are not deleted from the projects.`,
+		Example: `
+# Sync the projects created from the template 'team'
+percli projecttemplate sync team
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	return cmd
+}
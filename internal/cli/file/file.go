@@ -93,11 +93,23 @@ func UnmarshalEntitiesFromDirectory(dir string) ([]modelAPI.Entity, []error) {
 	return entities, errors
 }
 
+// ListEntityFiles returns every JSON or YAML file found in the directory and its subdirectories.
+func ListEntityFiles(dir string) ([]string, error) {
+	return visit(dir)
+}
+
 func UnmarshalEntitiesFromFile(file string) ([]modelAPI.Entity, error) {
 	u := &unmarshaller{file: file}
 	return u.unmarshal()
 }
 
+// UnmarshalEntitiesFromData extracts the Perses resources from the content of a file that has already been read.
+// file is only used in the error messages.
+func UnmarshalEntitiesFromData(file string, data []byte) ([]modelAPI.Entity, error) {
+	u := &unmarshaller{file: file, data: data}
+	return u.unmarshal()
+}
+
 func visit(dir string) ([]string, error) {
 	var files []string
 	err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
@@ -160,6 +172,7 @@ func Copy(src, dst string) error {
 type unmarshaller struct {
 	isJSON  bool
 	file    string
+	data    []byte
 	objects []map[string]any
 }
 
@@ -172,7 +185,7 @@ func (u *unmarshaller) unmarshal() ([]modelAPI.Entity, error) {
 }
 
 func (u *unmarshaller) read() error {
-	data, isJSON, err := readAndDetect(u.file)
+	data, isJSON, err := u.readAndDetect()
 	if err != nil {
 		return err
 	}
@@ -200,6 +213,13 @@ func (u *unmarshaller) read() error {
 	return nil
 }
 
+func (u *unmarshaller) readAndDetect() ([]byte, bool, error) {
+	if u.data == nil {
+		return readAndDetect(u.file)
+	}
+	return u.data, isJSONContent(u.data), nil
+}
+
 func (u *unmarshaller) unmarshalEntities() ([]modelAPI.Entity, error) {
 	if len(u.objects) == 0 {
 		return nil, fmt.Errorf("unable to unmarshall data from the file %q, data is empty", u.file)
@@ -32,11 +32,15 @@ func readAndDetect(file string) (data []byte, isJSON bool, err error) {
 		return
 	}
 
-	// detecting file format
-	isJSON = json.Unmarshal(data, &json.RawMessage{}) == nil
+	isJSON = isJSONContent(data)
 	return
 }
 
+// isJSONContent is detecting the format of the data.
+func isJSONContent(data []byte) bool {
+	return json.Unmarshal(data, &json.RawMessage{}) == nil
+}
+
 func newReadFileErr(err error) error {
 	return fmt.Errorf("unable to read file, format invalid: %w", err)
 }
@@ -0,0 +1,181 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grafana is a minimal client of the Grafana HTTP API, used to migrate a whole Grafana instance to Perses.
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// searchPageSize is the number of dashboards requested per page. It's the maximum accepted by Grafana.
+	searchPageSize = 5000
+	// libraryPageSize is the number of library panels requested per page.
+	libraryPageSize = 100
+	// libraryPanelKind is the kind of the library elements that are panels.
+	libraryPanelKind = "1"
+	// GeneralFolder is the name of the folder Grafana uses for the dashboards that don't belong to any folder.
+	GeneralFolder = "General"
+)
+
+// DashboardHit is a dashboard returned by the search API.
+type DashboardHit struct {
+	UID         string `json:"uid"`
+	Title       string `json:"title"`
+	FolderUID   string `json:"folderUid,omitempty"`
+	FolderTitle string `json:"folderTitle,omitempty"`
+}
+
+// Folder returns the title of the folder containing the dashboard, or GeneralFolder when the dashboard is at the root.
+func (d DashboardHit) Folder() string {
+	if len(d.FolderTitle) == 0 {
+		return GeneralFolder
+	}
+	return d.FolderTitle
+}
+
+type dashboardResponse struct {
+	Dashboard json.RawMessage `json:"dashboard"`
+}
+
+type libraryElementsResponse struct {
+	Result struct {
+		TotalCount int               `json:"totalCount"`
+		Elements   []json.RawMessage `json:"elements"`
+	} `json:"result"`
+}
+
+type Client interface {
+	// SearchDashboards returns every dashboard the token can read.
+	SearchDashboards() ([]DashboardHit, error)
+	// GetDashboard returns the JSON model of a dashboard.
+	GetDashboard(uid string) (json.RawMessage, error)
+	// ListDatasources returns the list of the datasources as a JSON array.
+	// Grafana never returns the sensitive settings of the datasources, like the passwords.
+	ListDatasources() (json.RawMessage, error)
+	// ListLibraryPanels returns the library panels, each including the JSON model of the panel.
+	ListLibraryPanels() ([]json.RawMessage, error)
+}
+
+type client struct {
+	url        *url.URL
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client of the Grafana API available at the given URL.
+// The token is a service account token, or an API key on the older versions of Grafana.
+func NewClient(grafanaURL string, token string) (Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(grafanaURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Grafana URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid Grafana URL %q: the scheme must be http or https", grafanaURL)
+	}
+	return &client{
+		url:        u,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (c *client) SearchDashboards() ([]DashboardHit, error) {
+	var result []DashboardHit
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("type", "dash-db")
+		query.Set("limit", strconv.Itoa(searchPageSize))
+		query.Set("page", strconv.Itoa(page))
+		var hits []DashboardHit
+		if err := c.get("/api/search", query, &hits); err != nil {
+			return nil, err
+		}
+		result = append(result, hits...)
+		if len(hits) < searchPageSize {
+			return result, nil
+		}
+	}
+}
+
+func (c *client) GetDashboard(uid string) (json.RawMessage, error) {
+	response := &dashboardResponse{}
+	if err := c.get(fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(uid)), nil, response); err != nil {
+		return nil, err
+	}
+	if len(response.Dashboard) == 0 {
+		return nil, fmt.Errorf("the dashboard %q returned by Grafana is empty", uid)
+	}
+	return response.Dashboard, nil
+}
+
+func (c *client) ListDatasources() (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.get("/api/datasources", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *client) ListLibraryPanels() ([]json.RawMessage, error) {
+	var result []json.RawMessage
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("kind", libraryPanelKind)
+		query.Set("perPage", strconv.Itoa(libraryPageSize))
+		query.Set("page", strconv.Itoa(page))
+		response := &libraryElementsResponse{}
+		if err := c.get("/api/library-elements", query, response); err != nil {
+			return nil, err
+		}
+		result = append(result, response.Result.Elements...)
+		if len(response.Result.Elements) == 0 || len(result) >= response.Result.TotalCount {
+			return result, nil
+		}
+	}
+}
+
+func (c *client) get(path string, query url.Values, result any) error {
+	u := *c.url
+	u.Path += path
+	u.RawQuery = query.Encode()
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if len(c.token) > 0 {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned the status %d: %s", path, response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,119 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newGrafanaServer(t *testing.T, nbDashboards int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Unauthorized"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/grafana/api/search":
+			page := r.URL.Query().Get("page")
+			var hits []DashboardHit
+			// Only the first page is full, to check the pagination
+			if page == "1" {
+				for i := 0; i < searchPageSize; i++ {
+					hits = append(hits, DashboardHit{UID: fmt.Sprintf("dash-%d", i), Title: "Dashboard", FolderTitle: "Team A"})
+				}
+			} else if page == "2" {
+				for i := 0; i < nbDashboards-searchPageSize; i++ {
+					hits = append(hits, DashboardHit{UID: fmt.Sprintf("dash-%d", searchPageSize+i), Title: "Dashboard"})
+				}
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(hits))
+		case "/grafana/api/dashboards/uid/demo":
+			_, _ = w.Write([]byte(`{"meta":{"folderTitle":"Team A"},"dashboard":{"uid":"demo","title":"Demo"}}`))
+		case "/grafana/api/library-elements":
+			// Two library panels, returned one per page to check the pagination
+			page := r.URL.Query().Get("page")
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"result":{"totalCount":2,"page":%s,"perPage":1,"elements":[{"uid":"lib-%s","name":"Library %s","model":{"type":"stat"}}]}}`, page, page, page)))
+		case "/grafana/api/datasources":
+			_, _ = w.Write([]byte(`[{"uid":"prom","name":"Prometheus","type":"prometheus","secureJsonFields":{"basicAuthPassword":true}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Dashboard not found"}`))
+		}
+	}))
+}
+
+func TestSearchDashboards(t *testing.T) {
+	server := newGrafanaServer(t, searchPageSize+2)
+	defer server.Close()
+	c, err := NewClient(server.URL+"/grafana/", "token")
+	assert.NoError(t, err)
+	hits, err := c.SearchDashboards()
+	assert.NoError(t, err)
+	assert.Len(t, hits, searchPageSize+2)
+	assert.Equal(t, "Team A", hits[0].Folder())
+	assert.Equal(t, GeneralFolder, hits[searchPageSize+1].Folder())
+}
+
+func TestGetDashboard(t *testing.T) {
+	server := newGrafanaServer(t, 0)
+	defer server.Close()
+	c, err := NewClient(server.URL+"/grafana", "token")
+	assert.NoError(t, err)
+	dashboard, err := c.GetDashboard("demo")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"uid":"demo","title":"Demo"}`, string(dashboard))
+
+	_, err = c.GetDashboard("unknown")
+	assert.EqualError(t, err, `GET /api/dashboards/uid/unknown returned the status 404: {"message":"Dashboard not found"}`)
+
+	unauthorized, err := NewClient(server.URL+"/grafana", "")
+	assert.NoError(t, err)
+	_, err = unauthorized.GetDashboard("demo")
+	assert.EqualError(t, err, `GET /api/dashboards/uid/demo returned the status 401: {"message":"Unauthorized"}`)
+}
+
+func TestListDatasources(t *testing.T) {
+	server := newGrafanaServer(t, 0)
+	defer server.Close()
+	c, err := NewClient(server.URL+"/grafana", "token")
+	assert.NoError(t, err)
+	datasources, err := c.ListDatasources()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"uid":"prom","name":"Prometheus","type":"prometheus","secureJsonFields":{"basicAuthPassword":true}}]`, string(datasources))
+}
+
+func TestListLibraryPanels(t *testing.T) {
+	server := newGrafanaServer(t, 0)
+	defer server.Close()
+	c, err := NewClient(server.URL+"/grafana", "token")
+	assert.NoError(t, err)
+	libraryPanels, err := c.ListLibraryPanels()
+	assert.NoError(t, err)
+	if assert.Len(t, libraryPanels, 2) {
+		assert.JSONEq(t, `{"uid":"lib-1","name":"Library 1","model":{"type":"stat"}}`, string(libraryPanels[0]))
+		assert.JSONEq(t, `{"uid":"lib-2","name":"Library 2","model":{"type":"stat"}}`, string(libraryPanels[1]))
+	}
+}
+
+func TestNewClientInvalidURL(t *testing.T) {
+	_, err := NewClient("localhost:3000", "token")
+	assert.EqualError(t, err, `invalid Grafana URL "localhost:3000": the scheme must be http or https`)
+}
@@ -101,12 +101,26 @@ var resources = []resource{
 			"gvs",
 		},
 	},
+	{
+		kind:      modelV1.KindGroup,
+		shortTerm: "grp",
+		aliases: []string{
+			"groups",
+		},
+	},
 	{
 		kind: modelV1.KindProject,
 		aliases: []string{
 			"projects",
 		},
 	},
+	{
+		kind:      modelV1.KindProjectTemplate,
+		shortTerm: "pt",
+		aliases: []string{
+			"projecttemplates",
+		},
+	},
 	{
 		kind:      modelV1.KindRole,
 		shortTerm: "rl",
@@ -0,0 +1,72 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strconv"
+
+	"github.com/perses/perses/internal/cli/output"
+	v1 "github.com/perses/perses/pkg/client/api/v1"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type projectTemplate struct {
+	Service
+	apiClient v1.ProjectTemplateInterface
+}
+
+func (p *projectTemplate) CreateResource(entity modelAPI.Entity) (modelAPI.Entity, error) {
+	return p.apiClient.Create(entity.(*modelV1.ProjectTemplate))
+}
+
+func (p *projectTemplate) UpdateResource(entity modelAPI.Entity) (modelAPI.Entity, error) {
+	return p.apiClient.Update(entity.(*modelV1.ProjectTemplate))
+}
+
+func (p *projectTemplate) ListResource(prefix string) ([]modelAPI.Entity, error) {
+	return convertToEntityIfNoError(p.apiClient.List(prefix))
+}
+
+func (p *projectTemplate) GetResource(name string) (modelAPI.Entity, error) {
+	return p.apiClient.Get(name)
+}
+
+func (p *projectTemplate) DeleteResource(name string) error {
+	return p.apiClient.Delete(name)
+}
+
+func (p *projectTemplate) BuildMatrix(hits []modelAPI.Entity) [][]string {
+	var data [][]string
+	for _, hit := range hits {
+		entity := hit.(*modelV1.ProjectTemplate)
+		line := []string{
+			entity.Metadata.Name,
+			strconv.Itoa(len(entity.Spec.Parameters)),
+			strconv.Itoa(len(entity.Spec.Resources)),
+			output.FormatAge(entity.Metadata.UpdatedAt),
+		}
+		data = append(data, line)
+	}
+	return data
+}
+
+func (p *projectTemplate) GetColumHeader() []string {
+	return []string{
+		"NAME",
+		"PARAMETERS",
+		"RESOURCES",
+		"AGE",
+	}
+}
@@ -94,10 +94,18 @@ func New(kind modelV1.Kind, projectName string, apiClient api.ClientInterface) (
 		return &globalVariable{
 			apiClient: apiClient.V1().GlobalVariable(),
 		}, nil
+	case modelV1.KindGroup:
+		return &group{
+			apiClient: apiClient.V1().Group(),
+		}, nil
 	case modelV1.KindProject:
 		return &project{
 			apiClient: apiClient.V1().Project(),
 		}, nil
+	case modelV1.KindProjectTemplate:
+		return &projectTemplate{
+			apiClient: apiClient.V1().ProjectTemplate(),
+		}, nil
 	case modelV1.KindRole:
 		return &role{
 			apiClient: apiClient.V1().Role(projectName),
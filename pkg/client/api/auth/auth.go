@@ -30,9 +30,40 @@ import (
 
 const authResource = "auth"
 
+// TwoFactorRequiredError is returned by Login and ChangePassword when the user must complete the login
+// with a TOTP code, using LoginTwoFactor.
+type TwoFactorRequiredError struct {
+	Challenge *api.TwoFactorChallenge
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "two-factor authentication required"
+}
+
+// loginResponse is the response of the native login. Depending on whether the two-factor authentication is enabled,
+// it contains either the tokens or the challenge to solve.
+type loginResponse struct {
+	oauth2.Token
+	api.TwoFactorChallenge
+}
+
+func (r *loginResponse) result() (*oauth2.Token, error) {
+	if len(r.TwoFactorToken) > 0 {
+		return nil, &TwoFactorRequiredError{Challenge: &r.TwoFactorChallenge}
+	}
+	return &r.Token, nil
+}
+
 // Interface has methods to work with Auth resource
 type Interface interface {
+	// Login logs in a native user.
+	// If the two-factor authentication is enabled, it returns a *TwoFactorRequiredError.
 	Login(user, password string) (*oauth2.Token, error)
+	// LoginTwoFactor completes the login of a native user with a TOTP code or a recovery code.
+	LoginTwoFactor(request *api.TwoFactorLoginRequest) (*api.TwoFactorLoginResponse, error)
+	// ChangePassword changes the password of a native user and logs the user in.
+	// Like Login, it returns a *TwoFactorRequiredError if the two-factor authentication is enabled.
+	ChangePassword(request *api.PasswordChangeRequest) (*oauth2.Token, error)
 	Refresh(refreshToken string) (*oauth2.Token, error)
 	// DeviceCode is used for device_code auth flow
 	DeviceCode(authKind, authProvider string, opts ...oauth2.AuthCodeOption) (*oauth2.DeviceAuthResponse, error)
@@ -57,16 +88,42 @@ func (c *auth) Login(user string, password string) (*oauth2.Token, error) {
 		Login:    user,
 		Password: password,
 	}
-	result := &oauth2.Token{}
-
-	return result, c.client.Post().
+	result := &loginResponse{}
+	if err := c.client.Post().
 		APIVersion("").
 		Resource(fmt.Sprintf("%s/%s", authResource, "providers/native/login")).
 		Body(body).
 		Do().
+		Object(result); err != nil {
+		return nil, err
+	}
+	return result.result()
+}
+
+func (c *auth) LoginTwoFactor(request *api.TwoFactorLoginRequest) (*api.TwoFactorLoginResponse, error) {
+	result := &api.TwoFactorLoginResponse{}
+
+	return result, c.client.Post().
+		APIVersion("").
+		Resource(fmt.Sprintf("%s/%s", authResource, "providers/native/login/totp")).
+		Body(request).
+		Do().
 		Object(result)
 }
 
+func (c *auth) ChangePassword(request *api.PasswordChangeRequest) (*oauth2.Token, error) {
+	result := &loginResponse{}
+	if err := c.client.Post().
+		APIVersion("").
+		Resource(fmt.Sprintf("%s/%s", authResource, "providers/native/password")).
+		Body(request).
+		Do().
+		Object(result); err != nil {
+		return nil, err
+	}
+	return result.result()
+}
+
 func (c *auth) Refresh(refreshToken string) (*oauth2.Token, error) {
 	body := &api.RefreshRequest{RefreshToken: refreshToken}
 	result := &oauth2.Token{}
@@ -14,6 +14,9 @@
 package api
 
 import (
+	"fmt"
+	"net/url"
+
 	"github.com/perses/perses/pkg/client/api/auth"
 	v1 "github.com/perses/perses/pkg/client/api/v1"
 	"github.com/perses/perses/pkg/client/api/validate"
@@ -26,10 +29,19 @@ import (
 type ClientInterface interface {
 	RESTClient() *perseshttp.RESTClient
 	V1() v1.ClientInterface
+	// Migrate converts a Grafana dashboard to a Perses dashboard.
 	Migrate(body *api.Migrate) (*modelV1.Dashboard, error)
+	// MigrateWithReport converts a Grafana dashboard to a Perses dashboard.
+	// It also returns the report telling how each part of the Grafana dashboard has been migrated.
+	// It requires a server supporting the migration report.
+	MigrateWithReport(body *api.Migrate) (*modelV1.Dashboard, *api.MigrationReport, error)
+	// ExportGrafana converts a Perses dashboard to a Grafana dashboard in JSON.
+	// It also returns the report telling how each part of the Perses dashboard has been exported.
+	ExportGrafana(body *modelV1.Dashboard) (*api.GrafanaExport, error)
 	Validate() validate.Interface
 	Auth() auth.Interface
 	Config() (*apiConfig.Config, error)
+	CanI(body *api.CanIRequest) (*api.CanIResponse, error)
 }
 
 type client struct {
@@ -63,6 +75,50 @@ func (c *client) Migrate(body *api.Migrate) (*modelV1.Dashboard, error) {
 	return result, err
 }
 
+func (c *client) MigrateWithReport(body *api.Migrate) (*modelV1.Dashboard, *api.MigrationReport, error) {
+	result := &modelV1.MigrationResult{}
+	err := c.restClient.Post().
+		APIVersion("").
+		Resource("migrate").
+		Query(&reportQuery{report: true}).
+		Body(body).
+		Do().
+		Object(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result.Dashboard == nil {
+		// A server not supporting the report ignores the query parameter and returns the dashboard alone.
+		return nil, nil, fmt.Errorf("the server didn't return the migration report, it is likely too old to support it")
+	}
+	return result.Dashboard, result.Report, nil
+}
+
+type reportQuery struct {
+	report bool
+}
+
+func (q *reportQuery) GetValues() url.Values {
+	values := make(url.Values)
+	if q.report {
+		values["report"] = []string{"true"}
+	}
+	return values
+}
+
+func (c *client) ExportGrafana(body *modelV1.Dashboard) (*api.GrafanaExport, error) {
+	result := &api.GrafanaExport{}
+	err := c.restClient.Post().
+		APIVersion("").
+		Resource("export").
+		Name("grafana").
+		Query(&reportQuery{report: true}).
+		Body(body).
+		Do().
+		Object(result)
+	return result, err
+}
+
 func (c *client) Validate() validate.Interface {
 	return validate.New(c.restClient)
 }
@@ -80,3 +136,15 @@ func (c *client) Config() (*apiConfig.Config, error) {
 		Object(cfg)
 	return cfg, err
 }
+
+func (c *client) CanI(body *api.CanIRequest) (*api.CanIResponse, error) {
+	result := &api.CanIResponse{}
+	err := c.restClient.Post().
+		APIVersion("").
+		Resource("authz").
+		Name("can-i").
+		Body(body).
+		Do().
+		Object(result)
+	return result, err
+}
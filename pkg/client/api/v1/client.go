@@ -30,9 +30,11 @@ type ClientInterface interface {
 	GlobalRoleBinding() GlobalRoleBindingInterface
 	GlobalSecret() GlobalSecretInterface
 	GlobalVariable() GlobalVariableInterface
+	Group() GroupInterface
 	Health() HealthInterface
 	Plugin() PluginInterface
 	Project() ProjectInterface
+	ProjectTemplate() ProjectTemplateInterface
 	Role(project string) RoleInterface
 	RoleBinding(project string) RoleBindingInterface
 	Secret(project string) SecretInterface
@@ -91,6 +93,10 @@ func (c *client) GlobalVariable() GlobalVariableInterface {
 	return newGlobalVariable(c.restClient)
 }
 
+func (c *client) Group() GroupInterface {
+	return newGroup(c.restClient)
+}
+
 func (c *client) Health() HealthInterface {
 	return newHealth(c.restClient)
 }
@@ -103,6 +109,10 @@ func (c *client) Project() ProjectInterface {
 	return newProject(c.restClient)
 }
 
+func (c *client) ProjectTemplate() ProjectTemplateInterface {
+	return newProjectTemplate(c.restClient)
+}
+
 func (c *client) Role(project string) RoleInterface {
 	return newRole(c.restClient, project)
 }
@@ -16,7 +16,10 @@
 package v1
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/pkg/client/perseshttp"
+	"github.com/perses/perses/pkg/model/api"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 )
 
@@ -37,6 +40,12 @@ type UserInterface interface {
 	// It can be empty in case you want to get the full list of User available
 	List(prefix string) ([]*v1.PublicUser, error)
 	WhoAmI() (*v1.PublicUser, error)
+	// ResetPassword generates a one-time token the user can use to define a new password.
+	ResetPassword(name string) (*api.PasswordResetResponse, error)
+	// Suspend disables the user. The user cannot log in anymore and loses all its permissions.
+	Suspend(name string, reason string) (*v1.PublicUser, error)
+	// Resume enables a suspended user.
+	Resume(name string) (*v1.PublicUser, error)
 }
 
 type user struct {
@@ -109,3 +118,34 @@ func (c *user) WhoAmI() (*v1.PublicUser, error) {
 		Object(result)
 	return result, err
 }
+
+func (c *user) ResetPassword(name string) (*api.PasswordResetResponse, error) {
+	result := &api.PasswordResetResponse{}
+	err := c.client.Post().
+		Resource(userResource).
+		Name(fmt.Sprintf("%s/password/reset", name)).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *user) Suspend(name string, reason string) (*v1.PublicUser, error) {
+	result := &v1.PublicUser{}
+	err := c.client.Post().
+		Resource(userResource).
+		Name(fmt.Sprintf("%s/suspend", name)).
+		Body(&api.UserSuspendRequest{Reason: reason}).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *user) Resume(name string) (*v1.PublicUser, error) {
+	result := &v1.PublicUser{}
+	err := c.client.Post().
+		Resource(userResource).
+		Name(fmt.Sprintf("%s/resume", name)).
+		Do().
+		Object(result)
+	return result, err
+}
@@ -18,13 +18,17 @@
 package fakeapi
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/pkg/client/api"
 	v1 "github.com/perses/perses/pkg/client/api/v1"
 	"github.com/perses/perses/pkg/client/config"
 	"github.com/perses/perses/pkg/client/fake/api/v1"
 	"github.com/perses/perses/pkg/client/perseshttp"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	apiConfig "github.com/perses/perses/pkg/model/api/config"
 	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/model/api/v1/role"
 )
 
 type client struct {
@@ -59,3 +63,28 @@ func (c *client) Config() (*apiConfig.Config, error) {
 		Frontend:     apiConfig.Frontend{},
 	}, nil
 }
+
+// CanI allows the subject to read anything and denies everything else.
+func (c *client) CanI(body *modelAPI.CanIRequest) (*modelAPI.CanIResponse, error) {
+	evaluation := modelAPI.CanIEvaluation{
+		BindingKind: "GlobalRoleBinding",
+		Binding:     "viewer",
+		RoleKind:    "GlobalRole",
+		Role:        "viewer",
+		Subject:     fmt.Sprintf("User %s", body.User),
+		Allowed:     body.Action == role.ReadAction,
+	}
+	if evaluation.Allowed {
+		evaluation.Reason = fmt.Sprintf("the role grants %q on %q", body.Action, body.Scope)
+		return &modelAPI.CanIResponse{
+			Allowed:     true,
+			Reason:      fmt.Sprintf("allowed by GlobalRoleBinding %q of GlobalRole %q to %s", evaluation.Binding, evaluation.Role, evaluation.Subject),
+			Evaluations: []modelAPI.CanIEvaluation{evaluation},
+		}, nil
+	}
+	evaluation.Reason = fmt.Sprintf("the role doesn't grant %q on %q", body.Action, body.Scope)
+	return &modelAPI.CanIResponse{
+		Reason:      "none of the roles bound to the subject grant the permission",
+		Evaluations: []modelAPI.CanIEvaluation{evaluation},
+	}, nil
+}
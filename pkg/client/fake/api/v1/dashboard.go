@@ -16,7 +16,6 @@ package fakev1
 import (
 	v1 "github.com/perses/perses/pkg/client/api/v1"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
-	dashboardSpec "github.com/perses/spec/go/dashboard"
 )
 
 type dashboard struct {
@@ -45,7 +44,7 @@ func (d *dashboard) Get(name string) (*modelV1.Dashboard, error) {
 				Project: d.project,
 			},
 		},
-		Spec: dashboardSpec.Spec{},
+		Spec: modelV1.AnnotatedDashboardSpec{},
 	}, nil
 }
 func (d *dashboard) List(_ string) ([]*modelV1.Dashboard, error) {
@@ -21,6 +21,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/perses/perses/pkg/model/api/v1/common"
@@ -166,10 +167,10 @@ func (r *Request) Do() *Response {
 	// Deserialize the json response
 	if resp.Body != nil {
 		data, err := io.ReadAll(resp.Body)
-		return &Response{body: data, err: err, statusCode: resp.StatusCode}
+		return &Response{body: data, err: err, statusCode: resp.StatusCode, header: resp.Header}
 	}
 
-	return &Response{statusCode: resp.StatusCode}
+	return &Response{statusCode: resp.StatusCode, header: resp.Header}
 }
 
 // prepareRequest build the HTTP request that #Do function will execute
@@ -289,6 +290,7 @@ type Response struct {
 	body       []byte
 	err        error
 	statusCode int
+	header     http.Header
 }
 
 type errorResponse struct {
@@ -329,6 +331,23 @@ func (r *Response) Error() error {
 	return nil
 }
 
+// Warnings returns the messages of the Warning headers sent by the API.
+func (r *Response) Warnings() []string {
+	var result []string
+	for _, value := range r.header.Values("Warning") {
+		// The expected format is `299 - "message"`
+		_, msg, found := strings.Cut(value, " - ")
+		if !found {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(msg); err == nil {
+			msg = unquoted
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
 // Object stores the result into respObj.
 func (r *Response) Object(respObj any) error {
 	err := r.Error()
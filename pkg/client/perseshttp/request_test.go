@@ -15,6 +15,7 @@ package perseshttp
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/perses/perses/pkg/model/api/v1/common"
@@ -133,3 +134,13 @@ func TestRequest_URL(t *testing.T) {
 
 	}
 }
+
+func TestResponse_Warnings(t *testing.T) {
+	header := http.Header{}
+	header.Add("Warning", `299 - "the time range starting at \"now/d\" is not supported"`)
+	header.Add("Warning", `299 - unquoted message`)
+	header.Add("Warning", `malformed`)
+	response := &Response{header: header}
+	assert.Equal(t, []string{`the time range starting at "now/d" is not supported`, "unquoted message"}, response.Warnings())
+	assert.Empty(t, (&Response{}).Warnings())
+}
@@ -16,9 +16,11 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/perses/perses/pkg/model/api/v1/secret"
 	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"golang.org/x/oauth2"
 )
 
 type PublicAuth struct {
@@ -58,6 +60,138 @@ func (u *Auth) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// PasswordChangeRequiredMessage is the error message returned by the native login when the user must change the password first.
+const PasswordChangeRequiredMessage = "password change required"
+
+// UserDisabledMessage is the error message returned when a disabled user is trying to log in or to refresh the session.
+const UserDisabledMessage = "user is disabled"
+
+// PasswordChangeRequest is the body used by a native user to change the password.
+// The user proves its identity either with the current password or with the one-time token generated by an admin.
+type PasswordChangeRequest struct {
+	Login       string `json:"login"`
+	Password    string `json:"password,omitempty"`
+	ResetToken  string `json:"resetToken,omitempty"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (r *PasswordChangeRequest) UnmarshalJSON(data []byte) error {
+	var tmp PasswordChangeRequest
+	type plain PasswordChangeRequest
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*r = tmp
+	return nil
+}
+
+func (r *PasswordChangeRequest) validate() error {
+	if len(r.Login) == 0 {
+		return fmt.Errorf("login cannot be empty")
+	}
+	if len(r.Password) == 0 && len(r.ResetToken) == 0 {
+		return fmt.Errorf("password or resetToken must be provided")
+	}
+	if len(r.Password) > 0 && len(r.ResetToken) > 0 {
+		return fmt.Errorf("password and resetToken are mutually exclusive")
+	}
+	if len(r.NewPassword) == 0 {
+		return fmt.Errorf("newPassword cannot be empty")
+	}
+	return nil
+}
+
+// PasswordResetResponse is returned when an admin resets the password of a user.
+// The token must be sent to the user who can use it only once to define a new password.
+type PasswordResetResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// UserSuspendRequest is the body sent by an admin to suspend a user.
+type UserSuspendRequest struct {
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// TOTPEnrollment is returned when a user starts the enrollment of an authenticator app.
+// The URI contains the secret and is usually displayed as a QR code.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TOTPCode is the body used to confirm the enrollment of an authenticator app or to disable it.
+// The code can also be one of the recovery codes.
+type TOTPCode struct {
+	Code string `json:"code"`
+}
+
+func (c *TOTPCode) UnmarshalJSON(data []byte) error {
+	var tmp TOTPCode
+	type plain TOTPCode
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if len(tmp.Code) == 0 {
+		return fmt.Errorf("code cannot be empty")
+	}
+	*c = tmp
+	return nil
+}
+
+// TOTPRecoveryCodes contains the recovery codes generated when the enrollment of an authenticator app is confirmed.
+// Each code can be used once instead of a TOTP code. They are only returned once.
+type TOTPRecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// TwoFactorChallenge is returned by the native login instead of the tokens when the user must provide a second factor.
+// When the two-factor authentication is required but the user has not enrolled an authenticator app yet,
+// Enrollment contains the secret to add to the app before providing the first code.
+type TwoFactorChallenge struct {
+	TwoFactorToken string          `json:"twoFactorToken"`
+	Enrollment     *TOTPEnrollment `json:"enrollment,omitempty"`
+}
+
+// TwoFactorLoginRequest is the body used to complete the native login with a TOTP code or a recovery code.
+type TwoFactorLoginRequest struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+	Code           string `json:"code"`
+}
+
+func (r *TwoFactorLoginRequest) UnmarshalJSON(data []byte) error {
+	var tmp TwoFactorLoginRequest
+	type plain TwoFactorLoginRequest
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*r = tmp
+	return nil
+}
+
+func (r *TwoFactorLoginRequest) validate() error {
+	if len(r.TwoFactorToken) == 0 {
+		return fmt.Errorf("twoFactorToken cannot be empty")
+	}
+	if len(r.Code) == 0 {
+		return fmt.Errorf("code cannot be empty")
+	}
+	return nil
+}
+
+// TwoFactorLoginResponse is returned once the second factor is verified.
+// RecoveryCodes is only set when the login completed the enrollment of an authenticator app.
+type TwoFactorLoginResponse struct {
+	oauth2.Token
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
 // RefreshRequest represents the request used to refresh an access token from a refresh token.
 // Disclaimer: This is an exception to the general camelCase convention in the project, to respect oauth 2.0 specs.
 // -> https://datatracker.ietf.org/doc/html/rfc6749#section-6
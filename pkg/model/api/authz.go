@@ -0,0 +1,89 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+// CanIRequest is the body used by an admin to check if a subject is allowed to perform an action on a scope.
+// The subject is a user, a list of groups or both. With the Kubernetes authorization provider, a service account
+// is a user named system:serviceaccount:<namespace>:<name>.
+type CanIRequest struct {
+	User   string      `json:"user,omitempty" yaml:"user,omitempty"`
+	Groups []string    `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Action role.Action `json:"action" yaml:"action"`
+	Scope  role.Scope  `json:"scope" yaml:"scope"`
+	// Project is the project in which the permission is evaluated. It is ignored for global scopes.
+	// When empty, the permission is evaluated globally, i.e. across all projects.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+}
+
+func (r *CanIRequest) UnmarshalJSON(data []byte) error {
+	var tmp CanIRequest
+	type plain CanIRequest
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*r = tmp
+	return nil
+}
+
+func (r *CanIRequest) validate() error {
+	if len(r.User) == 0 && len(r.Groups) == 0 {
+		return fmt.Errorf("user or groups must be provided")
+	}
+	if len(r.Action) == 0 {
+		return fmt.Errorf("action cannot be empty")
+	}
+	if len(r.Scope) == 0 {
+		return fmt.Errorf("scope cannot be empty")
+	}
+	if len(r.Project) == 0 || role.IsGlobalScope(r.Scope) {
+		// Same value as v1.WildcardProject, that cannot be imported here.
+		r.Project = "*"
+	}
+	return nil
+}
+
+// CanIResponse is the result of a CanIRequest.
+// Evaluations explains, for every role binding that applies to the subject, if the bound role grants the permission.
+type CanIResponse struct {
+	Allowed     bool             `json:"allowed" yaml:"allowed"`
+	Reason      string           `json:"reason" yaml:"reason"`
+	Evaluations []CanIEvaluation `json:"evaluations,omitempty" yaml:"evaluations,omitempty"`
+}
+
+// CanIEvaluation is the evaluation of a single permission source.
+// With the native authorization provider, it is a RoleBinding or a GlobalRoleBinding and the role it refers to,
+// or the guest permissions when no binding is set.
+// With the Kubernetes authorization provider, it is the SubjectAccessReview done for the given Kubernetes Resource.
+type CanIEvaluation struct {
+	BindingKind string `json:"bindingKind,omitempty" yaml:"bindingKind,omitempty"`
+	Binding     string `json:"binding,omitempty" yaml:"binding,omitempty"`
+	RoleKind    string `json:"roleKind,omitempty" yaml:"roleKind,omitempty"`
+	Role        string `json:"role,omitempty" yaml:"role,omitempty"`
+	Project     string `json:"project,omitempty" yaml:"project,omitempty"`
+	// Subject is the subject of the binding matching the request, e.g. "User alice" or "Group dev".
+	Subject  string `json:"subject,omitempty" yaml:"subject,omitempty"`
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
+	Allowed  bool   `json:"allowed" yaml:"allowed"`
+	Reason   string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
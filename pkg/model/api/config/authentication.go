@@ -17,9 +17,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"slices"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/secret"
@@ -30,6 +34,11 @@ const (
 	DefaultAccessTokenTTL  = time.Minute * 15
 	DefaultRefreshTokenTTL = time.Hour * 24
 	DefaultProviderTimeout = time.Minute * 1
+	DefaultResetTokenTTL   = time.Hour * 24
+
+	DefaultHeaderAuthnUserHeader   = "X-Forwarded-User"
+	DefaultHeaderAuthnEmailHeader  = "X-Forwarded-Email"
+	DefaultHeaderAuthnGroupsHeader = "X-Forwarded-Groups"
 )
 
 type OAuthOverride struct {
@@ -132,10 +141,130 @@ func (p *Provider) Verify() error {
 	return nil
 }
 
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters a password must contain.
+	MinLength int `json:"min_length,omitempty" yaml:"min_length,omitempty"`
+	// RequireUppercase requires at least one uppercase letter in the password.
+	RequireUppercase bool `json:"require_uppercase,omitempty" yaml:"require_uppercase,omitempty"`
+	// RequireLowercase requires at least one lowercase letter in the password.
+	RequireLowercase bool `json:"require_lowercase,omitempty" yaml:"require_lowercase,omitempty"`
+	// RequireDigit requires at least one digit in the password.
+	RequireDigit bool `json:"require_digit,omitempty" yaml:"require_digit,omitempty"`
+	// RequireSpecial requires at least one character that is neither a letter nor a digit in the password.
+	RequireSpecial bool `json:"require_special,omitempty" yaml:"require_special,omitempty"`
+	// HistorySize is the number of previous passwords a user is not allowed to reuse.
+	HistorySize int `json:"history_size,omitempty" yaml:"history_size,omitempty"`
+}
+
+func (p *PasswordPolicy) Verify() error {
+	if p.MinLength < 0 {
+		return errors.New("password_policy.min_length cannot be negative")
+	}
+	if p.HistorySize < 0 {
+		return errors.New("password_policy.history_size cannot be negative")
+	}
+	return nil
+}
+
+// Check returns an error describing every rule of the policy the given password doesn't satisfy.
+func (p PasswordPolicy) Check(password string) error {
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSpecial = true
+		}
+	}
+	var violations []string
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("be at least %d characters long", p.MinLength))
+	}
+	if p.RequireUppercase && !hasUpper {
+		violations = append(violations, "contain an uppercase letter")
+	}
+	if p.RequireLowercase && !hasLower {
+		violations = append(violations, "contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "contain a digit")
+	}
+	if p.RequireSpecial && !hasSpecial {
+		violations = append(violations, "contain a special character")
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("password must %s", strings.Join(violations, ", "))
+	}
+	return nil
+}
+
+type NativeAuthnProvider struct {
+	// PasswordPolicy defines the rules a password must follow when it is set or changed.
+	PasswordPolicy PasswordPolicy `json:"password_policy,omitzero" yaml:"password_policy,omitempty"`
+	// ResetTokenTTL is the time to live of the one-time token generated when an admin resets the password of a user.
+	// By default, it is 24 hours.
+	ResetTokenTTL commonSpec.Duration `json:"reset_token_ttl,omitempty" yaml:"reset_token_ttl,omitempty"`
+	// RequireTwoFactor forces every native user to log in with a TOTP code.
+	// Users that haven't enrolled an authenticator app yet are asked to do it during the next login.
+	RequireTwoFactor bool `json:"require_two_factor,omitempty" yaml:"require_two_factor,omitempty"`
+}
+
 type K8sAuthnProvider struct {
 	Enable bool `json:"enable" yaml:"enable"`
 }
 
+// HeaderAuthnProvider configures the authentication delegated to a trusted reverse proxy (oauth2-proxy, Envoy ext_authz, ...).
+// When a request comes from one of the trusted proxies and carries the user header,
+// Perses trusts the identity given by the headers and creates the user on first sight.
+type HeaderAuthnProvider struct {
+	Enable bool `json:"enable" yaml:"enable"`
+	// UserHeader is the header containing the username. By default, it is "X-Forwarded-User".
+	UserHeader string `json:"user_header,omitempty" yaml:"user_header,omitempty"`
+	// EmailHeader is the header containing the email of the user. By default, it is "X-Forwarded-Email".
+	// It is used to build the username when the user header is empty.
+	EmailHeader string `json:"email_header,omitempty" yaml:"email_header,omitempty"`
+	// GroupsHeader is the header containing the groups of the user. By default, it is "X-Forwarded-Groups".
+	// Each group is synchronized with a Group resource so it can be used in role bindings.
+	GroupsHeader string `json:"groups_header,omitempty" yaml:"groups_header,omitempty"`
+	// GroupsSeparator is the separator used to split the groups header. By default, it is ",".
+	GroupsSeparator string `json:"groups_separator,omitempty" yaml:"groups_separator,omitempty"`
+	// TrustedCIDRs is the list of IP ranges of the reverse proxies allowed to set the headers.
+	// Headers coming from any other address are ignored.
+	TrustedCIDRs []string `json:"trusted_cidrs" yaml:"trusted_cidrs"`
+}
+
+func (p *HeaderAuthnProvider) Verify() error {
+	if !p.Enable {
+		return nil
+	}
+	if len(p.UserHeader) == 0 {
+		p.UserHeader = DefaultHeaderAuthnUserHeader
+	}
+	if len(p.EmailHeader) == 0 {
+		p.EmailHeader = DefaultHeaderAuthnEmailHeader
+	}
+	if len(p.GroupsHeader) == 0 {
+		p.GroupsHeader = DefaultHeaderAuthnGroupsHeader
+	}
+	if len(p.GroupsSeparator) == 0 {
+		p.GroupsSeparator = ","
+	}
+	if len(p.TrustedCIDRs) == 0 {
+		return errors.New("header authentication provider requires at least one trusted CIDR")
+	}
+	for _, cidr := range p.TrustedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid trusted CIDR %q: %w", cidr, err)
+		}
+	}
+	return nil
+}
+
 type OIDCLogout struct {
 	Enabled                 bool   `json:"enabled" yaml:"enabled"`
 	LogoutRedirectParamName string `json:"logout_redirect_param_name,omitempty" yaml:"logout_redirect_param_name,omitempty"`
@@ -181,13 +310,21 @@ func (p *OAuthProvider) Verify() error {
 
 type AuthenticationProviders struct {
 	EnableNative bool `json:"enable_native" yaml:"enable_native"`
+	// Native contains the configuration of the native authentication provider.
+	// It is only considered when EnableNative is true.
+	Native NativeAuthnProvider `json:"native,omitzero" yaml:"native,omitempty"`
 	// +optional
 	KubernetesProvider K8sAuthnProvider `json:"kubernetes,omitzero" yaml:"kubernetes,omitempty"`
-	OAuth              []OAuthProvider  `json:"oauth,omitempty" yaml:"oauth,omitempty"`
-	OIDC               []OIDCProvider   `json:"oidc,omitempty" yaml:"oidc,omitempty"`
+	// +optional
+	Header HeaderAuthnProvider `json:"header,omitzero" yaml:"header,omitempty"`
+	OAuth  []OAuthProvider     `json:"oauth,omitempty" yaml:"oauth,omitempty"`
+	OIDC   []OIDCProvider      `json:"oidc,omitempty" yaml:"oidc,omitempty"`
 }
 
 func (p *AuthenticationProviders) Verify() error {
+	if p.EnableNative && p.Native.ResetTokenTTL == 0 {
+		p.Native.ResetTokenTTL = commonSpec.Duration(DefaultResetTokenTTL)
+	}
 	var tmpOIDCSlugIDs []string
 	for _, prov := range p.OIDC {
 		var ok bool
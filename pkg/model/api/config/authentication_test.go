@@ -100,3 +100,65 @@ func TestAppendIfMissing(t *testing.T) {
 	assert.Len(t, slice, 3)
 	assert.False(t, ok3)
 }
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:        8,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSpecial:   true,
+	}
+	testSuite := []struct {
+		title    string
+		password string
+		errMsg   string
+	}{
+		{
+			title:    "valid password",
+			password: "Perses-2024",
+		},
+		{
+			title:    "too short",
+			password: "Pe-2024",
+			errMsg:   "password must be at least 8 characters long",
+		},
+		{
+			title:    "several rules not satisfied",
+			password: "persesperses",
+			errMsg:   "password must contain an uppercase letter, contain a digit, contain a special character",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			err := policy.Check(test.password)
+			if len(test.errMsg) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.errMsg)
+			}
+		})
+	}
+	assert.NoError(t, PasswordPolicy{}.Check("a"))
+}
+
+func TestHeaderAuthnProvider_Verify(t *testing.T) {
+	provider := HeaderAuthnProvider{Enable: true, TrustedCIDRs: []string{"10.0.0.0/8"}}
+	assert.NoError(t, provider.Verify())
+	assert.Equal(t, HeaderAuthnProvider{
+		Enable:          true,
+		UserHeader:      DefaultHeaderAuthnUserHeader,
+		EmailHeader:     DefaultHeaderAuthnEmailHeader,
+		GroupsHeader:    DefaultHeaderAuthnGroupsHeader,
+		GroupsSeparator: ",",
+		TrustedCIDRs:    []string{"10.0.0.0/8"},
+	}, provider)
+
+	noCIDR := HeaderAuthnProvider{Enable: true}
+	assert.ErrorContains(t, noCIDR.Verify(), "requires at least one trusted CIDR")
+
+	wrongCIDR := HeaderAuthnProvider{Enable: true, TrustedCIDRs: []string{"10.0.0.1"}}
+	assert.ErrorContains(t, wrongCIDR.Verify(), `invalid trusted CIDR "10.0.0.1"`)
+
+	assert.NoError(t, (&HeaderAuthnProvider{}).Verify())
+}
@@ -14,6 +14,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/perses/perses/pkg/model/api/v1/role"
@@ -28,6 +29,9 @@ var (
 	DefaultKubernetesAuthenticationTTL     = time.Minute * 2
 )
 
+// kubernetesPersesAPIGroup is the API group of the Perses custom resources.
+const kubernetesPersesAPIGroup = "perses.dev"
+
 type KubernetesAuthorizationProvider struct {
 	Enable bool `json:"enable,omitempty" yaml:"enable,omitempty"`
 	// The active user in the kubeconfig should have "create" permissions for the `TokenReview` and
@@ -44,6 +48,9 @@ type KubernetesAuthorizationProvider struct {
 	AuthorizerDenyTTL common.Duration `json:"authorizer_deny_ttl,omitempty" yaml:"authorizer_deny_ttl,omitempty"`
 	// time an authenticator response will be cached for. Default: 2m
 	AuthenticatorTTL common.Duration `json:"authenticator_ttl,omitempty" yaml:"authenticator_ttl,omitempty"`
+	// Resources overrides the Kubernetes resource used to check the permissions of a Perses scope.
+	// The scopes not listed keep their default resource.
+	Resources map[role.Scope]KubernetesResource `json:"resources,omitempty" yaml:"resources,omitempty"`
 }
 
 func (k *KubernetesAuthorizationProvider) Verify() error {
@@ -65,12 +72,62 @@ func (k *KubernetesAuthorizationProvider) Verify() error {
 	if k.AuthorizerAllowTTL == 0 {
 		k.AuthorizerAllowTTL = common.Duration(DefaultKubernetesAuthorizationAllowTTL)
 	}
-	if k.AuthenticatorTTL == 0 {
+	if k.AuthorizerDenyTTL == 0 {
 		k.AuthorizerDenyTTL = common.Duration(DefaultKubernetesAuthorizationDenyTTL)
 	}
+	resources := make(map[role.Scope]KubernetesResource, len(k.Resources))
+	for scope, resource := range k.Resources {
+		// The scope is parsed without considering the case, so we need to use its canonical value as the key.
+		s, err := role.GetScope(string(scope))
+		if err != nil {
+			return err
+		}
+		if *s == role.WildcardScope {
+			return fmt.Errorf("the Kubernetes resource of the wildcard scope cannot be overridden")
+		}
+		if _, exist := resources[*s]; exist {
+			return fmt.Errorf("the Kubernetes resource of the scope %q is defined more than once", *s)
+		}
+		if len(resource.Resource) == 0 {
+			return fmt.Errorf("the Kubernetes resource of the scope %q cannot be empty", *s)
+		}
+		if len(resource.Version) == 0 {
+			version, ok := defaultKubernetesResourceVersion(resource.Group)
+			if !ok {
+				return fmt.Errorf("the version of the Kubernetes resource of the scope %q is required for the API group %q", *s, resource.Group)
+			}
+			resource.Version = version
+		}
+		resources[*s] = resource
+	}
+	k.Resources = resources
 	return nil
 }
 
+// KubernetesResource is the Kubernetes resource against which the permissions of a Perses scope are checked.
+type KubernetesResource struct {
+	// Resource is the plural name of the resource, e.g. persesvariables.
+	Resource string `json:"resource" yaml:"resource"`
+	// Group is the API group of the resource. Empty means the core API group.
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// Version is the API version of the resource. Default: v1 for the core API group, v1alpha1 for the Perses API group.
+	// It is required for any other API group.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// defaultKubernetesResourceVersion returns the version to use when none is given.
+// There is no default for the API groups other than the core and the Perses ones.
+func defaultKubernetesResourceVersion(group string) (string, bool) {
+	switch group {
+	case "":
+		return "v1", true
+	case kubernetesPersesAPIGroup:
+		return "v1alpha1", true
+	default:
+		return "", false
+	}
+}
+
 type NativeAuthorizationProvider struct {
 	Enable bool `json:"enable,omitempty" yaml:"enable,omitempty"`
 	// CheckLatestUpdateInterval that checks if the RBAC cache needs to be refreshed with db content. Only for SQL database setup.
@@ -14,6 +14,7 @@
 package config
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -102,6 +103,10 @@ func (c *Config) Verify() error {
 	// Since a project variable can either depend on a global datasource or a project datasource,
 	// we need to disable the project variable if the global datasource is disabled and the project datasource is disabled.
 	c.Variable.Project.Disable = c.Variable.Project.Disable || (c.Datasource.Global.Disable && c.Datasource.Project.Disable)
+	// The plugins installed at runtime are executed in the browser of every user, so only an administrator must be able to install them.
+	if c.Plugin.EnableRuntimeInstall && !c.Security.EnableAuth {
+		return fmt.Errorf("plugin.enable_runtime_install requires security.enable_auth to be set")
+	}
 	return nil
 }
 
@@ -129,7 +129,8 @@ func TestJSONMarshalConfig(t *testing.T) {
   },
   "dashboard": {},
   "provisioning": {
-    "interval": "1h"
+    "interval": "1h",
+    "edit_policy": "warn"
   },
   "datasource": {
     "global": {
@@ -164,7 +165,10 @@ func TestJSONMarshalConfig(t *testing.T) {
     "archive_paths": [
       "plugins-archive"
     ],
-    "enable_dev": false
+    "enable_dev": false,
+    "signature": {
+      "policy": "off"
+    }
   }
 }`,
 		},
@@ -476,6 +480,9 @@ plugin:
 						DisableSignUp:   false,
 						Providers: AuthenticationProviders{
 							EnableNative: true,
+							Native: NativeAuthnProvider{
+								ResetTokenTTL: common.Duration(DefaultResetTokenTTL),
+							},
 						},
 					},
 					CORS: CORSConfig{
@@ -514,12 +521,14 @@ plugin:
 				Plugin: Plugin{
 					Path:         "custom/plugins",
 					ArchivePaths: []string{"custom/plugins/archive"},
+					Signature:    PluginSignature{Policy: PluginSignaturePolicyOff},
 				},
 				Provisioning: ProvisioningConfig{
 					Folders: []string{
 						"dev/data",
 					},
-					Interval: common.Duration(defaultInterval),
+					Interval:   common.Duration(defaultInterval),
+					EditPolicy: ProvisioningEditPolicyWarn,
 				},
 				EphemeralDashboard: EphemeralDashboard{
 					Enable:          false,
@@ -541,3 +550,10 @@ plugin:
 		})
 	}
 }
+
+func TestVerifyRuntimeInstallRequiresAuth(t *testing.T) {
+	c := &Config{Plugin: Plugin{EnableRuntimeInstall: true}}
+	assert.Error(t, c.Verify())
+	c.Security.EnableAuth = true
+	assert.NoError(t, c.Verify())
+}
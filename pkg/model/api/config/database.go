@@ -16,8 +16,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
+	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/secret"
 	"github.com/perses/spec/go/common"
 	"github.com/sirupsen/logrus"
@@ -151,9 +153,52 @@ func (s *SQL) Verify() error {
 	return nil
 }
 
+// defaultKubernetesDatabaseKinds is the list of resources stored as custom resources by default.
+var defaultKubernetesDatabaseKinds = []v1.Kind{v1.KindDashboard, v1.KindDatasource, v1.KindVariable}
+
+// KubernetesDatabase stores some resources as Perses custom resources through the Kubernetes API.
+// The other resources are still stored in the file or SQL database.
+type KubernetesDatabase struct {
+	// Kubeconfig is the path to the kubeconfig file. If not set, the service account of the pod is used.
+	Kubeconfig string `json:"kubeconfig,omitempty" yaml:"kubeconfig,omitempty"`
+	// APIVersion is the group and the version of the Perses custom resources.
+	APIVersion string `json:"api_version,omitempty" yaml:"api_version,omitempty"`
+	// Kinds is the list of resources stored as custom resources. Only Dashboard, Datasource and Variable are supported.
+	Kinds []v1.Kind `json:"kinds,omitempty" yaml:"kinds,omitempty"`
+	// ResyncInterval is the interval at which the cache of the custom resources is fully refreshed.
+	// In between, the cache is kept up to date by watching the custom resources.
+	ResyncInterval common.Duration `json:"resync_interval,omitempty" yaml:"resync_interval,omitempty"`
+	// SyncTimeout is the maximum time to wait for the cache to be filled when Perses starts.
+	SyncTimeout common.Duration `json:"sync_timeout,omitempty" yaml:"sync_timeout,omitempty"`
+}
+
+func (k *KubernetesDatabase) Verify() error {
+	if len(k.APIVersion) == 0 {
+		k.APIVersion = "perses.dev/v1alpha1"
+	}
+	if len(k.Kinds) == 0 {
+		k.Kinds = defaultKubernetesDatabaseKinds
+	}
+	for _, kind := range k.Kinds {
+		if !slices.Contains(defaultKubernetesDatabaseKinds, kind) {
+			return fmt.Errorf("%q cannot be stored in Kubernetes, only %v are supported", kind, defaultKubernetesDatabaseKinds)
+		}
+	}
+	if k.ResyncInterval == 0 {
+		k.ResyncInterval = common.Duration(10 * time.Minute)
+	}
+	if k.SyncTimeout == 0 {
+		k.SyncTimeout = common.Duration(time.Minute)
+	}
+	return nil
+}
+
 type Database struct {
 	File *File `json:"file,omitempty" yaml:"file,omitempty"`
 	SQL  *SQL  `json:"sql,omitempty" yaml:"sql,omitempty"`
+	// Kubernetes stores the dashboards, the datasources and the variables as custom resources.
+	// It is used alongside the file or the SQL database that stores the other resources.
+	Kubernetes *KubernetesDatabase `json:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
 }
 
 func (d *Database) Verify() error {
@@ -30,6 +30,14 @@ func (c *GlobalDatasourceConfig) Verify() error {
 	if c.Disable && len(c.Discovery) > 0 {
 		return fmt.Errorf("the global datasource is disabled, you cannot use the discovery feature")
 	}
+	var names []string
+	for _, d := range c.Discovery {
+		var ok bool
+		names, ok = appendIfMissing(names, d.Name)
+		if !ok {
+			return fmt.Errorf("several global datasource discoveries exist with the same name %q", d.Name)
+		}
+	}
 	return nil
 }
 
@@ -37,6 +45,17 @@ type ProjectDatasourceConfig struct {
 	// Disable is used to disable the project datasource feature.
 	// It will also remove the associated proxy.
 	Disable bool `json:"disable" yaml:"disable"`
+	// Discovery is the configuration that helps to generate a list of project datasource based on the discovery chosen.
+	// Be careful: the data coming from the discovery will totally override what exists in the database.
+	// Note that this is an experimental feature. Behavior and config may change in the future.
+	Discovery []ProjectDatasourceDiscovery `json:"discovery,omitempty" yaml:"discovery,omitempty"`
+}
+
+func (c *ProjectDatasourceConfig) Verify() error {
+	if c.Disable && len(c.Discovery) > 0 {
+		return fmt.Errorf("the project datasource is disabled, you cannot use the discovery feature")
+	}
+	return nil
 }
 
 type DatasourceConfig struct {
@@ -46,3 +65,19 @@ type DatasourceConfig struct {
 	// It will also disable the associated proxy.
 	DisableLocal bool `json:"disable_local" yaml:"disable_local"`
 }
+
+func (c *DatasourceConfig) Verify() error {
+	// The name of the discovery is used to tag the datasources it creates, so it must be unique whatever the scope.
+	var names []string
+	for _, d := range c.Global.Discovery {
+		names = append(names, d.Name)
+	}
+	for _, d := range c.Project.Discovery {
+		var ok bool
+		names, ok = appendIfMissing(names, d.Name)
+		if !ok {
+			return fmt.Errorf("several datasource discoveries exist with the same name %q", d.Name)
+		}
+	}
+	return nil
+}
@@ -19,10 +19,34 @@ import (
 	"time"
 
 	"github.com/perses/perses/pkg/client/config"
+	modelCommon "github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/spec/go/common"
 )
 
-const defaultRefreshInterval = common.Duration(time.Minute * 5)
+const (
+	defaultRefreshInterval = common.Duration(time.Minute * 5)
+	defaultGCGracePeriod   = common.Duration(time.Minute * 15)
+	// maxDiscoveryNameLength ensures the name of the discovery fits in the tag set on the discovered datasources.
+	maxDiscoveryNameLength = 40
+)
+
+const (
+	// DiscoveryGCActionDelete removes the orphaned datasources.
+	DiscoveryGCActionDelete = "delete"
+	// DiscoveryGCActionMark only tags the orphaned datasources.
+	DiscoveryGCActionMark = "mark"
+)
+
+const (
+	DNSRecordTypeSRV  = "SRV"
+	DNSRecordTypeA    = "A"
+	DNSRecordTypeAAAA = "AAAA"
+)
+
+const (
+	defaultDiscoveryScheme = "http"
+	defaultConsulURL       = "http://localhost:8500"
+)
 
 type HTTPDiscovery struct {
 	config.RestConfigClient `json:",inline" yaml:",inline"`
@@ -72,6 +96,18 @@ type KubernetesDiscovery struct {
 	PodConfiguration KubePodDiscovery `json:"pod_configuration,omitempty" yaml:"pod_configuration,omitempty"`
 	// The labels used to filter the list of resource when contacting the Kubernetes API.
 	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// AnnotationOptIn restricts the discovery to the resources with the annotation `perses.dev/datasource-discovery: "true"`.
+	// Whatever this setting, the resources with the annotation set to "false" are ignored.
+	AnnotationOptIn bool `json:"annotation_opt_in,omitempty" yaml:"annotation_opt_in,omitempty"`
+	// AnnotationAllowedSecrets is the list of secrets the annotation `perses.dev/datasource-secret` can reference.
+	// Anyone able to annotate a service or a pod can point a datasource to their own endpoint, so by default no secret can be used.
+	AnnotationAllowedSecrets []string `json:"annotation_allowed_secrets,omitempty" yaml:"annotation_allowed_secrets,omitempty"`
+	// EnableAnnotationHeaders allows the annotation `perses.dev/datasource-headers` to set the headers forwarded by the proxy.
+	EnableAnnotationHeaders bool `json:"enable_annotation_headers,omitempty" yaml:"enable_annotation_headers,omitempty"`
+	// NameTemplate is the Go template used to build the name of the datasources from the metadata of the service or the pod.
+	NameTemplate string `json:"name_template,omitempty" yaml:"name_template,omitempty"`
+	// DisplayNameTemplate is the Go template used to build the display name of the datasources from the metadata of the service or the pod.
+	DisplayNameTemplate string `json:"display_name_template,omitempty" yaml:"display_name_template,omitempty"`
 }
 
 func (d *KubernetesDiscovery) Verify() error {
@@ -87,8 +123,139 @@ func (d *KubernetesDiscovery) Verify() error {
 	return nil
 }
 
+type DNSDiscovery struct {
+	// DatasourcePluginKind is the name of the datasource plugin that should be filled when creating datasources found.
+	DatasourcePluginKind string `json:"datasource_plugin_kind" yaml:"datasource_plugin_kind"`
+	// Names is the list of DNS names to query.
+	Names []string `json:"names" yaml:"names"`
+	// Type is the type of the DNS query: SRV (default), A or AAAA.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Port of the datasources. It is required for the A and AAAA queries, as the records don't provide it.
+	Port int `json:"port,omitempty" yaml:"port,omitempty"`
+	// Scheme used to build the URL of the datasources. By default, it is http.
+	Scheme string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	// NameTemplate is the Go template used to build the name of the datasources from the labels of the target.
+	NameTemplate string `json:"name_template,omitempty" yaml:"name_template,omitempty"`
+}
+
+func (d *DNSDiscovery) Verify() error {
+	if len(d.DatasourcePluginKind) == 0 {
+		return fmt.Errorf("missing datasource plugin kind")
+	}
+	if len(d.Names) == 0 {
+		return fmt.Errorf("at least one DNS name must be set")
+	}
+	if len(d.Type) == 0 {
+		d.Type = DNSRecordTypeSRV
+	}
+	switch d.Type {
+	case DNSRecordTypeSRV:
+	case DNSRecordTypeA, DNSRecordTypeAAAA:
+		if d.Port <= 0 {
+			return fmt.Errorf("a port must be set when the DNS query type is %q", d.Type)
+		}
+	default:
+		return fmt.Errorf("invalid DNS query type %q, it must be %q, %q or %q", d.Type, DNSRecordTypeSRV, DNSRecordTypeA, DNSRecordTypeAAAA)
+	}
+	if len(d.Scheme) == 0 {
+		d.Scheme = defaultDiscoveryScheme
+	}
+	return nil
+}
+
+type ConsulDiscovery struct {
+	// The config of the HTTP client used to reach the Consul API. By default, the URL is http://localhost:8500.
+	// The ACL token can be passed with the authorization.
+	config.RestConfigClient `json:",inline" yaml:",inline"`
+	// DatasourcePluginKind is the name of the datasource plugin that should be filled when creating datasources found.
+	DatasourcePluginKind string `json:"datasource_plugin_kind" yaml:"datasource_plugin_kind"`
+	// Services is the list of services to discover. Leave empty to discover every service of the catalog.
+	Services []string `json:"services,omitempty" yaml:"services,omitempty"`
+	// Tags is the list of tags a service instance must have to be discovered.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Datacenter to query. By default, it is the datacenter of the Consul agent.
+	Datacenter string `json:"datacenter,omitempty" yaml:"datacenter,omitempty"`
+	// Scheme used to build the URL of the datasources. By default, it is http.
+	Scheme string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	// NameTemplate is the Go template used to build the name of the datasources from the labels of the target.
+	NameTemplate string `json:"name_template,omitempty" yaml:"name_template,omitempty"`
+}
+
+func (d *ConsulDiscovery) Verify() error {
+	if len(d.DatasourcePluginKind) == 0 {
+		return fmt.Errorf("missing datasource plugin kind")
+	}
+	if d.URL == nil {
+		d.URL = modelCommon.MustParseURL(defaultConsulURL)
+	}
+	if len(d.Scheme) == 0 {
+		d.Scheme = defaultDiscoveryScheme
+	}
+	return d.Validate()
+}
+
+type publicConsulDiscovery struct {
+	config.PublicRestConfigClient `json:",inline" yaml:",inline"`
+	DatasourcePluginKind          string   `json:"datasource_plugin_kind" yaml:"datasource_plugin_kind"`
+	Services                      []string `json:"services,omitempty" yaml:"services,omitempty"`
+	Tags                          []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Datacenter                    string   `json:"datacenter,omitempty" yaml:"datacenter,omitempty"`
+	Scheme                        string   `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	NameTemplate                  string   `json:"name_template,omitempty" yaml:"name_template,omitempty"`
+}
+
+func (d ConsulDiscovery) newPublic() publicConsulDiscovery {
+	return publicConsulDiscovery{
+		PublicRestConfigClient: *config.NewPublicRestConfigClient(&d.RestConfigClient),
+		DatasourcePluginKind:   d.DatasourcePluginKind,
+		Services:               d.Services,
+		Tags:                   d.Tags,
+		Datacenter:             d.Datacenter,
+		Scheme:                 d.Scheme,
+		NameTemplate:           d.NameTemplate,
+	}
+}
+
+func (d ConsulDiscovery) MarshalYAML() (any, error) {
+	return d.newPublic(), nil
+}
+
+func (d ConsulDiscovery) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.newPublic())
+}
+
+// DiscoveryGarbageCollection defines what happens to the datasources that are no longer returned by the discovery.
+type DiscoveryGarbageCollection struct {
+	// If set to true, the datasources created by the discovery and no longer returned by it are considered as orphaned.
+	Enable bool `json:"enable" yaml:"enable"`
+	// GracePeriod is how long a datasource must be missing from the discovery before being considered as orphaned.
+	// It avoids removing a datasource when a target is flapping. By default, it is 15 minutes.
+	GracePeriod common.Duration `json:"grace_period,omitempty" yaml:"grace_period,omitempty"`
+	// Action is what is done with an orphaned datasource. It can be "delete" (default) or "mark".
+	// With "mark", the datasource is kept but tagged as orphaned.
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+	// DryRun only logs the orphaned datasources without modifying them.
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+}
+
+func (d *DiscoveryGarbageCollection) Verify() error {
+	if !d.Enable {
+		return nil
+	}
+	if d.GracePeriod == 0 {
+		d.GracePeriod = defaultGCGracePeriod
+	}
+	if len(d.Action) == 0 {
+		d.Action = DiscoveryGCActionDelete
+	}
+	if d.Action != DiscoveryGCActionDelete && d.Action != DiscoveryGCActionMark {
+		return fmt.Errorf("invalid garbage collection action %q, it must be %q or %q", d.Action, DiscoveryGCActionDelete, DiscoveryGCActionMark)
+	}
+	return nil
+}
+
 type GlobalDatasourceDiscovery struct {
-	// The name of the discovery config. It is used for logging purposes only
+	// The name of the discovery config. It is used for logging purposes, and to tag the datasources created by the discovery.
 	Name string `json:"name" yaml:"name"`
 	// Refresh interval to re-query the endpoint.
 	RefreshInterval common.Duration `json:"refresh_interval,omitempty" yaml:"refresh_interval,omitempty"`
@@ -100,17 +267,77 @@ type GlobalDatasourceDiscovery struct {
 	// Kubernetes SD configurations allow retrieving global datasource from Kubernetes' REST API
 	// and always staying synchronized with the cluster state.
 	KubernetesDiscovery *KubernetesDiscovery `json:"kubernetes_sd,omitempty" yaml:"kubernetes_sd,omitempty"`
+	// DNS SD configurations allow retrieving global datasource from the SRV, A or AAAA records of DNS names.
+	DNSDiscovery *DNSDiscovery `json:"dns_sd,omitempty" yaml:"dns_sd,omitempty"`
+	// Consul SD configurations allow retrieving global datasource from the instances of services registered in Consul.
+	ConsulDiscovery *ConsulDiscovery `json:"consul_sd,omitempty" yaml:"consul_sd,omitempty"`
+	// GarbageCollection reconciles the datasources created by this discovery with the ones it currently returns.
+	GarbageCollection DiscoveryGarbageCollection `json:"garbage_collection,omitzero" yaml:"garbage_collection,omitempty"`
 }
 
 func (g *GlobalDatasourceDiscovery) Verify() error {
 	if len(g.Name) == 0 {
 		return fmt.Errorf("global datasource discovery name is empty")
 	}
+	if len(g.Name) > maxDiscoveryNameLength {
+		return fmt.Errorf("global datasource discovery name %q cannot contain more than %d characters", g.Name, maxDiscoveryNameLength)
+	}
 	if g.RefreshInterval == 0 {
 		g.RefreshInterval = defaultRefreshInterval
 	}
-	if g.HTTPDiscovery == nil && g.KubernetesDiscovery == nil {
+	if g.HTTPDiscovery == nil && g.KubernetesDiscovery == nil && g.DNSDiscovery == nil && g.ConsulDiscovery == nil {
 		return fmt.Errorf("no discovery has been defined for the global datasource discovery %q", g.Name)
 	}
 	return nil
 }
+
+// DiscoveryProject defines the project where the datasources found by a discovery are created.
+// Exactly one of the fields must be set.
+type DiscoveryProject struct {
+	// Name is the project where every datasource is created.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// FromNamespace uses the Kubernetes namespace of the service or the pod as project.
+	FromNamespace bool `json:"from_namespace,omitempty" yaml:"from_namespace,omitempty"`
+	// Label uses the value of the given label of the service or the pod as project.
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+	// Annotation uses the value of the given annotation of the service or the pod as project.
+	Annotation string `json:"annotation,omitempty" yaml:"annotation,omitempty"`
+}
+
+// IsKubernetesOnly returns true when the project is coming from the Kubernetes resources.
+func (p DiscoveryProject) IsKubernetesOnly() bool {
+	return p.FromNamespace || len(p.Label) > 0 || len(p.Annotation) > 0
+}
+
+func (p *DiscoveryProject) Verify() error {
+	nbSources := 0
+	if len(p.Name) > 0 {
+		nbSources++
+	}
+	if p.FromNamespace {
+		nbSources++
+	}
+	if len(p.Label) > 0 {
+		nbSources++
+	}
+	if len(p.Annotation) > 0 {
+		nbSources++
+	}
+	if nbSources != 1 {
+		return fmt.Errorf("exactly one of name, from_namespace, label or annotation must be set to define the project of the discovered datasources")
+	}
+	return nil
+}
+
+type ProjectDatasourceDiscovery struct {
+	GlobalDatasourceDiscovery `json:",inline" yaml:",inline"`
+	// Project defines the project where each datasource found is created.
+	Project DiscoveryProject `json:"project" yaml:"project"`
+}
+
+func (p *ProjectDatasourceDiscovery) Verify() error {
+	if p.Project.IsKubernetesOnly() && p.KubernetesDiscovery == nil {
+		return fmt.Errorf("the project of the datasources found by the discovery %q can only come from a Kubernetes resource with kubernetes_sd", p.Name)
+	}
+	return nil
+}
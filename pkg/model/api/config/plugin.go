@@ -14,8 +14,13 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	commonSpec "github.com/perses/spec/go/common"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,13 +32,79 @@ var (
 	DefaultPluginPathInContainer        = "/etc/perses/plugins"
 	DefaultArchivePluginPath            = "plugins-archive"
 	DefaultArchivePluginPathInContainer = "/etc/perses/plugins-archive"
+	DefaultRegistryCachePath            = "plugins-cache"
+	DefaultRegistryCachePathInContainer = "/etc/perses/plugins-cache"
 )
 
+const DefaultRegistryTimeout = time.Minute
+
 func isFileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
+type PluginSignaturePolicy string
+
+const (
+	// PluginSignaturePolicyEnforce refuses to load a plugin that is not signed by one of the trusted keys.
+	PluginSignaturePolicyEnforce PluginSignaturePolicy = "enforce"
+	// PluginSignaturePolicyWarn loads a plugin that is not signed by one of the trusted keys, but logs a warning.
+	PluginSignaturePolicyWarn PluginSignaturePolicy = "warn"
+	// PluginSignaturePolicyOff doesn't verify the signature of the plugins.
+	PluginSignaturePolicyOff PluginSignaturePolicy = "off"
+)
+
+type PluginSignature struct {
+	// Policy defines how Perses reacts when a plugin is not signed by one of the trusted keys.
+	// Possible values are "enforce", "warn" and "off". Default is "off".
+	Policy PluginSignaturePolicy `json:"policy,omitempty" yaml:"policy,omitempty"`
+	// TrustedKeys is the list of paths to the PEM encoded ed25519 public keys used to verify the signature of the plugins.
+	TrustedKeys []string `json:"trusted_keys,omitempty" yaml:"trusted_keys,omitempty"`
+}
+
+func (s *PluginSignature) Verify() error {
+	if len(s.Policy) == 0 {
+		s.Policy = PluginSignaturePolicyOff
+	}
+	if s.Policy != PluginSignaturePolicyEnforce && s.Policy != PluginSignaturePolicyWarn && s.Policy != PluginSignaturePolicyOff {
+		return fmt.Errorf("invalid plugin signature policy %q, it must be %q, %q or %q", s.Policy, PluginSignaturePolicyEnforce, PluginSignaturePolicyWarn, PluginSignaturePolicyOff)
+	}
+	if s.Policy != PluginSignaturePolicyOff && len(s.TrustedKeys) == 0 {
+		return fmt.Errorf("at least one trusted key is required when the plugin signature policy is %q", s.Policy)
+	}
+	for _, key := range s.TrustedKeys {
+		if !isFileExists(key) {
+			return fmt.Errorf("the trusted key %q does not exist", key)
+		}
+	}
+	return nil
+}
+
+type PluginRequirement struct {
+	// Name is the name of the plugin module to download.
+	Name string `json:"name" yaml:"name"`
+	// Version is the semver range the version of the plugin module must match, like "^0.6.0" or ">= 0.5.0, < 0.7.0".
+	// The highest version matching the range is downloaded. When empty, the latest version is downloaded.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Registry is the URL of the registry providing the plugin module.
+	Registry common.URL `json:"registry" yaml:"registry"`
+}
+
+func (r *PluginRequirement) Verify() error {
+	if len(r.Name) == 0 {
+		return fmt.Errorf("the name of a plugin requirement cannot be empty")
+	}
+	if r.Registry.IsNilOrEmpty() {
+		return fmt.Errorf("the registry of the plugin requirement %q cannot be empty", r.Name)
+	}
+	if len(r.Version) > 0 {
+		if _, err := semver.NewConstraint(r.Version); err != nil {
+			return fmt.Errorf("invalid version range %q for the plugin requirement %q: %w", r.Version, r.Name, err)
+		}
+	}
+	return nil
+}
+
 type Plugin struct {
 	// Path is the path to the directory containing the runtime plugins
 	Path string `json:"path,omitempty" yaml:"path,omitempty"`
@@ -46,6 +117,17 @@ type Plugin struct {
 	ArchivePaths []string `json:"archive_paths,omitempty" yaml:"archive_paths,omitempty"`
 	// DevEnvironment is the configuration to use when developing a plugin
 	EnableDev bool `json:"enable_dev" yaml:"enable_dev"`
+	// EnableRuntimeInstall allows the administrators to install, uninstall, enable and disable the plugins through the API, without restarting Perses.
+	EnableRuntimeInstall bool `json:"enable_runtime_install,omitempty" yaml:"enable_runtime_install,omitempty"`
+	// Signature is the configuration used to verify the signature of the plugins when they are loaded.
+	Signature PluginSignature `json:"signature,omitzero" yaml:"signature,omitempty"`
+	// Requirements is the list of plugin modules to download from a registry.
+	// They are downloaded and loaded when Perses is starting, or on demand through the API.
+	Requirements []PluginRequirement `json:"requirements,omitempty" yaml:"requirements,omitempty"`
+	// RegistryCachePath is the path to the folder where the archives downloaded from a registry are cached.
+	RegistryCachePath string `json:"registry_cache_path,omitempty" yaml:"registry_cache_path,omitempty"`
+	// RegistryTimeout is the maximum time given to a request sent to a registry, the download of an archive included.
+	RegistryTimeout commonSpec.Duration `json:"registry_timeout,omitempty" yaml:"registry_timeout,omitempty"`
 }
 
 func (p *Plugin) Verify() error {
@@ -73,5 +155,15 @@ func (p *Plugin) Verify() error {
 			p.ArchivePaths = append(p.ArchivePaths, DefaultArchivePluginPath)
 		}
 	}
+	if len(p.Requirements) > 0 && len(p.RegistryCachePath) == 0 {
+		if isFileExists(DefaultRegistryCachePathInContainer) {
+			p.RegistryCachePath = DefaultRegistryCachePathInContainer
+		} else {
+			p.RegistryCachePath = DefaultRegistryCachePath
+		}
+	}
+	if len(p.Requirements) > 0 && p.RegistryTimeout == 0 {
+		p.RegistryTimeout = commonSpec.Duration(DefaultRegistryTimeout)
+	}
 	return nil
 }
@@ -14,18 +14,136 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/perses/perses/pkg/model/api/v1/secret"
 	"github.com/perses/spec/go/common"
 )
 
+type ProvisioningEditPolicy string
+
+const (
+	// ProvisioningEditPolicyReject refuses any update or deletion done through the API on a provisioned resource.
+	ProvisioningEditPolicyReject ProvisioningEditPolicy = "reject"
+	// ProvisioningEditPolicyWarn accepts the changes but answers with a Warning header, as they will be overridden by the next provisioning.
+	ProvisioningEditPolicyWarn ProvisioningEditPolicy = "warn"
+	// ProvisioningEditPolicyAllow accepts the changes silently.
+	ProvisioningEditPolicyAllow ProvisioningEditPolicy = "allow"
+)
+
+const defaultGitBranch = "main"
+
+// GitProvisioning describes a git repository used as a provisioning source.
+type GitProvisioning struct {
+	// Name identifies the repository. It is recorded on every resource coming from it and used in the webhook URL.
+	Name string `json:"name" yaml:"name"`
+	// URL of the repository. Only the HTTP(S) and the local (file:// or absolute path) transports are supported.
+	URL string `json:"url" yaml:"url"`
+	// Branch to follow. It is mutually exclusive with Tag. Default is "main".
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// Tag to deploy. It is mutually exclusive with Branch.
+	Tag string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	// Path is the folder of the repository containing the resources. Default is the root of the repository.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Secret is the name of the GlobalSecret holding the credentials (basic auth or bearer token) and the TLS config
+	// used to reach the repository.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// Interval is the polling frequency. Default is the provisioning interval.
+	Interval common.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// WebhookSecret enables the webhook endpoint used to trigger a synchronization when the repository is pushed.
+	// The git server must sign the payload with it (GitHub, Gitea) or send it as a token (GitLab).
+	WebhookSecret secret.Hidden `json:"webhook_secret,omitempty" yaml:"webhook_secret,omitempty"`
+	// WebhookSecretFile is the path to a file containing the webhook secret.
+	WebhookSecretFile string `json:"webhook_secret_file,omitempty" yaml:"webhook_secret_file,omitempty"`
+}
+
+func (g *GitProvisioning) Verify() error {
+	if err := common.ValidateID(g.Name); err != nil {
+		return fmt.Errorf("invalid git provisioning name %q: %w", g.Name, err)
+	}
+	if len(g.URL) == 0 {
+		return fmt.Errorf("url of the git provisioning %q cannot be empty", g.Name)
+	}
+	if err := verifyGitURL(g.URL); err != nil {
+		return fmt.Errorf("invalid url of the git provisioning %q: %w", g.Name, err)
+	}
+	if len(g.Branch) > 0 && len(g.Tag) > 0 {
+		return fmt.Errorf("branch and tag of the git provisioning %q are mutually exclusive", g.Name)
+	}
+	if len(g.Branch) == 0 && len(g.Tag) == 0 {
+		g.Branch = defaultGitBranch
+	}
+	g.Path = strings.Trim(path.Clean("/"+g.Path), "/")
+	if len(g.WebhookSecret) > 0 && len(g.WebhookSecretFile) > 0 {
+		return errors.New("webhook_secret and webhook_secret_file are mutually exclusive. Use one or the other not both at the same time")
+	}
+	if len(g.WebhookSecretFile) > 0 {
+		data, err := os.ReadFile(g.WebhookSecretFile)
+		if err != nil {
+			return err
+		}
+		g.WebhookSecret = secret.Hidden(strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// verifyGitURL rejects the transports that are not supported (ssh, git, etc.),
+// so they fail at startup instead of at the first synchronization.
+func verifyGitURL(rawURL string) error {
+	if filepath.IsAbs(rawURL) {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "file":
+		return nil
+	case "":
+		return fmt.Errorf("%q is neither a URL nor an absolute path", rawURL)
+	}
+	return fmt.Errorf("the scheme %q is not supported, only http, https and file are", u.Scheme)
+}
+
 type ProvisioningConfig struct {
 	Folders []string `json:"folders,omitempty" yaml:"folders,omitempty"`
 	// Interval is the refresh frequency
 	Interval common.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Prune deletes the provisioned resources whose file has been removed from the folders.
+	Prune bool `json:"prune,omitempty" yaml:"prune,omitempty"`
+	// EditPolicy defines how the API reacts when a provisioned resource is updated or deleted.
+	// Possible values are "reject", "warn" and "allow". Default is "warn".
+	EditPolicy ProvisioningEditPolicy `json:"edit_policy,omitempty" yaml:"edit_policy,omitempty"`
+	// Git is the list of git repositories to provision the resources from.
+	Git []GitProvisioning `json:"git,omitempty" yaml:"git,omitempty"`
 }
 
 func (p *ProvisioningConfig) Verify() error {
 	if p.Interval <= 0 {
 		p.Interval = common.Duration(defaultInterval)
 	}
+	if len(p.EditPolicy) == 0 {
+		p.EditPolicy = ProvisioningEditPolicyWarn
+	}
+	names := make(map[string]bool, len(p.Git))
+	for i := range p.Git {
+		if names[p.Git[i].Name] {
+			return fmt.Errorf("git provisioning %q is defined more than once", p.Git[i].Name)
+		}
+		names[p.Git[i].Name] = true
+		if p.Git[i].Interval <= 0 {
+			p.Git[i].Interval = p.Interval
+		}
+	}
+	if p.EditPolicy != ProvisioningEditPolicyReject && p.EditPolicy != ProvisioningEditPolicyWarn && p.EditPolicy != ProvisioningEditPolicyAllow {
+		return fmt.Errorf("invalid provisioning edit_policy %q, it must be %q, %q or %q", p.EditPolicy, ProvisioningEditPolicyReject, ProvisioningEditPolicyWarn, ProvisioningEditPolicyAllow)
+	}
 	return nil
 }
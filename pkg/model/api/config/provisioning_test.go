@@ -0,0 +1,48 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitProvisioning_VerifyURL(t *testing.T) {
+	testSuites := []struct {
+		title string
+		url   string
+		err   string
+	}{
+		{title: "https", url: "https://github.com/my-org/dashboards.git"},
+		{title: "http", url: "http://git.example.com/dashboards.git"},
+		{title: "file", url: "file:///srv/git/dashboards.git"},
+		{title: "absolute path", url: "/srv/git/dashboards.git"},
+		{title: "ssh", url: "ssh://git@github.com/my-org/dashboards.git", err: `the scheme "ssh" is not supported`},
+		{title: "git", url: "git://github.com/my-org/dashboards.git", err: `the scheme "git" is not supported`},
+		{title: "scp-like", url: "git@github.com:my-org/dashboards.git", err: `invalid url of the git provisioning "dashboards"`},
+		{title: "relative path", url: "dashboards.git", err: `"dashboards.git" is neither a URL nor an absolute path`},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			g := GitProvisioning{Name: "dashboards", URL: test.url}
+			err := g.Verify()
+			if len(test.err) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+}
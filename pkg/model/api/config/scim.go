@@ -0,0 +1,53 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+)
+
+// SCIMConfig contains the configuration of the SCIM 2.0 server used by an identity provider to provision
+// the users and the groups in Perses.
+type SCIMConfig struct {
+	// Enable the SCIM 2.0 endpoints (/scim/v2/Users and /scim/v2/Groups).
+	Enable bool `json:"enable" yaml:"enable"`
+	// BearerToken is the token the identity provider must provide in the Authorization header to contact the SCIM endpoints.
+	BearerToken secret.Hidden `json:"bearer_token,omitempty" yaml:"bearer_token,omitempty"`
+	// BearerTokenFile is the path to a file containing the bearer token.
+	BearerTokenFile string `json:"bearer_token_file,omitempty" yaml:"bearer_token_file,omitempty"`
+}
+
+func (s *SCIMConfig) Verify() error {
+	if !s.Enable {
+		return nil
+	}
+	if len(s.BearerToken) > 0 && len(s.BearerTokenFile) > 0 {
+		return errors.New("scim.bearer_token and scim.bearer_token_file are mutually exclusive. Use one or the other not both at the same time")
+	}
+	if len(s.BearerTokenFile) > 0 {
+		data, err := os.ReadFile(s.BearerTokenFile)
+		if err != nil {
+			return err
+		}
+		s.BearerToken = secret.Hidden(strings.TrimSpace(string(data)))
+	}
+	if len(s.BearerToken) == 0 {
+		return errors.New("scim.bearer_token or scim.bearer_token_file must be provided when SCIM is enabled")
+	}
+	return nil
+}
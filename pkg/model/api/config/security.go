@@ -161,6 +161,8 @@ type Security struct {
 	Authentication AuthenticationConfig `json:"authentication,omitempty" yaml:"authentication,omitempty"`
 	// Configuration for the CORS middleware.
 	CORS CORSConfig `json:"cors,omitempty" yaml:"cors"`
+	// SCIM contains the configuration of the SCIM 2.0 server used to provision the users and the groups from an identity provider.
+	SCIM SCIMConfig `json:"scim,omitzero" yaml:"scim,omitempty"`
 }
 
 func (s *Security) Verify() error {
@@ -187,7 +189,8 @@ func (s *Security) Verify() error {
 	if s.EnableAuth && !s.Authentication.Providers.EnableNative &&
 		len(s.Authentication.Providers.OIDC) == 0 &&
 		len(s.Authentication.Providers.OAuth) == 0 &&
-		!s.Authentication.Providers.KubernetesProvider.Enable {
+		!s.Authentication.Providers.KubernetesProvider.Enable &&
+		!s.Authentication.Providers.Header.Enable {
 		return errors.New("impossible to enable auth if no authentication provider is setup")
 	}
 
@@ -203,5 +206,13 @@ func (s *Security) Verify() error {
 		return errors.New("kubernetes authorization and authentication providers must be enabled at the same time")
 	}
 
+	if s.Authentication.Providers.Header.Enable && s.Authentication.Providers.KubernetesProvider.Enable {
+		return errors.New("header and kubernetes authentication providers cannot be enabled at the same time")
+	}
+
+	if s.SCIM.Enable && (!s.EnableAuth || s.Authorization.Provider.Kubernetes.Enable) {
+		return errors.New("scim requires the auth to be enabled with the native authorization provider")
+	}
+
 	return nil
 }
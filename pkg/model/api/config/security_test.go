@@ -336,6 +336,9 @@ security:
 						DisableSignUp:   false,
 						Providers: AuthenticationProviders{
 							EnableNative: true,
+							Native: NativeAuthnProvider{
+								ResetTokenTTL: common.Duration(DefaultResetTokenTTL),
+							},
 						},
 					},
 				},
@@ -352,9 +355,11 @@ security:
 				Plugin: Plugin{
 					Path:         "plugins",
 					ArchivePaths: []string{"plugins-archive"},
+					Signature:    PluginSignature{Policy: PluginSignaturePolicyOff},
 				},
 				Provisioning: ProvisioningConfig{
-					Interval: common.Duration(defaultInterval),
+					Interval:   common.Duration(defaultInterval),
+					EditPolicy: ProvisioningEditPolicyWarn,
 				},
 			},
 		},
@@ -371,3 +376,92 @@ security:
 		})
 	}
 }
+
+func TestKubernetesAuthorizationProvider_VerifyResources(t *testing.T) {
+	testSuite := []struct {
+		title      string
+		yaml       string
+		result     map[role.Scope]KubernetesResource
+		errMessage string
+	}{
+		{
+			title: "resources with default version",
+			yaml: `
+enable: true
+resources:
+  variable:
+    resource: persesvariables
+    group: perses.dev
+  GlobalSecret:
+    resource: configmaps
+  Role:
+    resource: roles
+    group: rbac.authorization.k8s.io
+    version: v1
+`,
+			result: map[role.Scope]KubernetesResource{
+				role.VariableScope:     {Resource: "persesvariables", Group: "perses.dev", Version: "v1alpha1"},
+				role.GlobalSecretScope: {Resource: "configmaps", Version: "v1"},
+				role.RoleScope:         {Resource: "roles", Group: "rbac.authorization.k8s.io", Version: "v1"},
+			},
+		},
+		{
+			title: "version required for another API group",
+			yaml: `
+enable: true
+resources:
+  variable:
+    resource: variables
+    group: monitoring.example.com
+`,
+			errMessage: `the version of the Kubernetes resource of the scope "Variable" is required for the API group "monitoring.example.com"`,
+		},
+		{
+			title: "unknown scope",
+			yaml: `
+enable: true
+resources:
+  Panel:
+    resource: persespanels
+`,
+			errMessage: "unknown scope",
+		},
+		{
+			title: "empty resource",
+			yaml: `
+enable: true
+resources:
+  Folder:
+    group: perses.dev
+`,
+			errMessage: `the Kubernetes resource of the scope "Folder" cannot be empty`,
+		},
+		{
+			title: "same scope defined twice",
+			yaml: `
+enable: true
+resources:
+  Folder:
+    resource: persesfolders
+  folder:
+    resource: folders
+`,
+			errMessage: `the Kubernetes resource of the scope "Folder" is defined more than once`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			c := KubernetesAuthorizationProvider{}
+			err := config.NewResolver[KubernetesAuthorizationProvider]().
+				SetConfigData([]byte(test.yaml)).
+				Resolve(&c).
+				Verify()
+			if len(test.errMessage) == 0 {
+				assert.NoError(t, err)
+				assert.Equal(t, test.result, c.Resources)
+			} else {
+				assert.ErrorContains(t, err, test.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "encoding/json"
+
+// GrafanaExport is the result of the export of a Perses dashboard to Grafana, when the report is requested.
+type GrafanaExport struct {
+	// Dashboard is the Grafana dashboard in JSON.
+	Dashboard json.RawMessage `json:"dashboard" yaml:"dashboard"`
+	// Report tells how each panel, query and variable of the Perses dashboard has been exported.
+	Report *MigrationReport `json:"report" yaml:"report"`
+}
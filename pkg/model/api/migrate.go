@@ -16,12 +16,19 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 )
 
 type Migrate struct {
 	Input                map[string]string `json:"input,omitempty"`
 	GrafanaDashboard     json.RawMessage   `json:"grafanaDashboard"`
 	UseDefaultDatasource bool              `json:"useDefaultDatasource,omitempty"`
+	// LibraryPanels are the Grafana library panels used by the dashboard, as returned by the Grafana API.
+	// The library panels embedded in a dashboard exported for sharing externally don't need to be provided.
+	LibraryPanels []json.RawMessage `json:"libraryPanels,omitempty"`
+	// DatasourceReferences maps the UID and the name of each Grafana datasource to the name of the Perses datasource
+	// that replaces it. The datasource references of the migrated dashboard are rewritten accordingly.
+	DatasourceReferences map[string]string `json:"datasourceReferences,omitempty"`
 }
 
 func (m *Migrate) UnmarshalJSON(data []byte) error {
@@ -43,3 +50,78 @@ func (m *Migrate) validate() error {
 	}
 	return nil
 }
+
+// MigrationStatus tells how an element of a Grafana dashboard has been migrated.
+type MigrationStatus string
+
+const (
+	// MigrationStatusMigrated is used when a migration script converted the element.
+	MigrationStatusMigrated MigrationStatus = "migrated"
+	// MigrationStatusFallback is used when no migration script could convert the element, and it has been replaced by a placeholder.
+	MigrationStatusFallback MigrationStatus = "fallback"
+	// MigrationStatusDropped is used when the element is not part of the Perses dashboard.
+	MigrationStatusDropped MigrationStatus = "dropped"
+)
+
+type MigrationItemKind string
+
+const (
+	MigrationItemKindPanel      MigrationItemKind = "panel"
+	MigrationItemKindQuery      MigrationItemKind = "query"
+	MigrationItemKindVariable   MigrationItemKind = "variable"
+	MigrationItemKindAnnotation MigrationItemKind = "annotation"
+)
+
+// MigrationItem describes how a panel, a query, a variable or an annotation of a Grafana dashboard has been migrated.
+type MigrationItem struct {
+	Kind MigrationItemKind `json:"kind" yaml:"kind"`
+	// Name is the title of the panel, the refId of the query or the name of the variable or of the annotation.
+	Name string `json:"name" yaml:"name"`
+	// Ref is the JSON reference of the element in the Perses dashboard, like #/spec/panels/0_1.
+	// For a dropped query, it's the reference of the panel the query belonged to.
+	Ref string `json:"ref" yaml:"ref"`
+	// GrafanaType is the type of the panel or the variable, or the type of the datasource of the query, when known.
+	GrafanaType string          `json:"grafanaType,omitempty" yaml:"grafanaType,omitempty"`
+	Status      MigrationStatus `json:"status" yaml:"status"`
+	// Plugin is the kind of the Perses plugin used for the element.
+	Plugin string `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	// Reason explains why the element has not been migrated.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+type MigrationSummary struct {
+	Migrated int `json:"migrated" yaml:"migrated"`
+	Fallback int `json:"fallback" yaml:"fallback"`
+	Dropped  int `json:"dropped" yaml:"dropped"`
+	// Score is the percentage of the panels, queries, variables and annotations that have been migrated.
+	// It's 100 when the Grafana dashboard contains none of them.
+	Score float64 `json:"score" yaml:"score"`
+}
+
+// MigrationReport details the fidelity of the migration of a Grafana dashboard.
+// It also details the fidelity of the export of a Perses dashboard to Grafana.
+type MigrationReport struct {
+	Summary MigrationSummary `json:"summary" yaml:"summary"`
+	Items   []MigrationItem  `json:"items,omitempty" yaml:"items,omitempty"`
+	// Warnings lists the settings of the dashboard that couldn't be migrated.
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+func NewMigrationReport() *MigrationReport {
+	return &MigrationReport{Summary: MigrationSummary{Score: 100}}
+}
+
+// Add records the item and updates the summary accordingly.
+func (r *MigrationReport) Add(item MigrationItem) {
+	r.Items = append(r.Items, item)
+	switch item.Status {
+	case MigrationStatusMigrated:
+		r.Summary.Migrated++
+	case MigrationStatusFallback:
+		r.Summary.Fallback++
+	case MigrationStatusDropped:
+		r.Summary.Dropped++
+	}
+	total := r.Summary.Migrated + r.Summary.Fallback + r.Summary.Dropped
+	r.Summary.Score = math.Round(float64(r.Summary.Migrated)*1000/float64(total)) / 10
+}
@@ -0,0 +1,27 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// ProjectTemplateSyncResult is the result of the synchronization of the projects created from a ProjectTemplate.
+type ProjectTemplateSyncResult struct {
+	// Synced contains the projects in which the template has been instantiated again.
+	Synced []string `json:"synced" yaml:"synced"`
+	// Failures contains the projects that couldn't be synced.
+	Failures []ProjectTemplateSyncFailure `json:"failures,omitempty" yaml:"failures,omitempty"`
+}
+
+type ProjectTemplateSyncFailure struct {
+	Project string `json:"project" yaml:"project"`
+	Error   string `json:"error" yaml:"error"`
+}
@@ -0,0 +1,61 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "time"
+
+// ProvisioningStatus is the state of the last provisioning runs.
+type ProvisioningStatus struct {
+	// The status of the provisioning folders.
+	ProvisioningRunStatus `json:",inline" yaml:",inline"`
+	// Git contains the status of every git repository.
+	Git []GitProvisioningStatus `json:"git,omitempty" yaml:"git,omitempty"`
+}
+
+// ProvisioningRunStatus is the result of a provisioning run.
+type ProvisioningRunStatus struct {
+	// LastSync is the time the last provisioning run has started.
+	LastSync *time.Time `json:"lastSync,omitempty" yaml:"lastSync,omitempty"`
+	// Files is the number of files loaded during the last run.
+	Files int `json:"files" yaml:"files"`
+	// Failures contains every file that couldn't be (fully) provisioned.
+	Failures []ProvisioningFailure `json:"failures,omitempty" yaml:"failures,omitempty"`
+	// Drift contains the resources that have been modified through the API since they have been provisioned.
+	// They are restored to the content of their file.
+	Drift []ProvisionedResource `json:"drift,omitempty" yaml:"drift,omitempty"`
+	// Pruned contains the resources that have been deleted because their file has been removed.
+	Pruned []ProvisionedResource `json:"pruned,omitempty" yaml:"pruned,omitempty"`
+}
+
+type GitProvisioningStatus struct {
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url" yaml:"url"`
+	// Ref is the branch or the tag followed.
+	Ref string `json:"ref" yaml:"ref"`
+	// Commit is the last commit applied.
+	Commit                string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	ProvisioningRunStatus `json:",inline" yaml:",inline"`
+}
+
+type ProvisioningFailure struct {
+	File   string   `json:"file" yaml:"file"`
+	Errors []string `json:"errors" yaml:"errors"`
+}
+
+type ProvisionedResource struct {
+	Kind    string `json:"kind" yaml:"kind"`
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+	Name    string `json:"name" yaml:"name"`
+	File    string `json:"file" yaml:"file"`
+}
@@ -0,0 +1,84 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/spec/go/common"
+)
+
+type AnnotationDisplay struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Hidden hides the toggle of the annotation from the dashboard.
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+type AnnotationSpec struct {
+	Display *AnnotationDisplay `json:"display" yaml:"display"`
+	// Enabled tells whether the events of the annotation are displayed when landing on the dashboard.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Color is the color used to display the events on the panels.
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+	// Plugin is the query providing the events, like the deployments or the incidents.
+	Plugin common.Plugin `json:"plugin" yaml:"plugin"`
+}
+
+// Annotation is a query whose results are displayed as events on top of the panels of a dashboard.
+type Annotation struct {
+	Kind string         `json:"kind" yaml:"kind"`
+	Spec AnnotationSpec `json:"spec" yaml:"spec"`
+}
+
+func (a *Annotation) UnmarshalJSON(data []byte) error {
+	var tmp Annotation
+	type plain Annotation
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*a = tmp
+	return nil
+}
+
+func (a *Annotation) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp Annotation
+	type plain Annotation
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*a = tmp
+	return nil
+}
+
+func (a *Annotation) validate() error {
+	if a.Kind != string(plugin.KindAnnotation) {
+		return fmt.Errorf("invalid kind: %q for an annotation", a.Kind)
+	}
+	if a.Spec.Display == nil || len(a.Spec.Display.Name) == 0 {
+		return fmt.Errorf("the name of an annotation cannot be empty")
+	}
+	if len(a.Spec.Plugin.Kind) == 0 {
+		return fmt.Errorf("the plugin of the annotation %q cannot be empty", a.Spec.Display.Name)
+	}
+	return nil
+}
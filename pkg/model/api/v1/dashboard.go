@@ -135,10 +135,51 @@ func (d *DashboardSpec) validate() error {
 	return nil
 }
 
+// AnnotatedDashboardSpec is the specification of a dashboard, completed with the annotations of the dashboard.
+type AnnotatedDashboardSpec struct {
+	dashboardSpec.Spec `json:",inline" yaml:",inline"`
+	// Annotations is an optional list of queries whose results are displayed as events on top of the panels.
+	Annotations []Annotation `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// annotations is used to decode the annotations of a dashboard spec,
+// as the methods of the embedded struct would take precedence otherwise.
+type annotations struct {
+	Annotations []Annotation `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+func (ads *AnnotatedDashboardSpec) UnmarshalJSON(data []byte) error {
+	var dashboardSpecTmp dashboardSpec.Spec
+	if err := dashboardSpecTmp.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var annotationsTmp annotations
+	if err := json.Unmarshal(data, &annotationsTmp); err != nil {
+		return err
+	}
+	ads.Spec = dashboardSpecTmp
+	ads.Annotations = annotationsTmp.Annotations
+	return nil
+}
+
+func (ads *AnnotatedDashboardSpec) UnmarshalYAML(unmarshal func(any) error) error {
+	var dashboardSpecTmp dashboardSpec.Spec
+	if err := dashboardSpecTmp.UnmarshalYAML(unmarshal); err != nil {
+		return err
+	}
+	var annotationsTmp annotations
+	if err := unmarshal(&annotationsTmp); err != nil {
+		return err
+	}
+	ads.Spec = dashboardSpecTmp
+	ads.Annotations = annotationsTmp.Annotations
+	return nil
+}
+
 type Dashboard struct {
-	Kind     Kind               `json:"kind" yaml:"kind"`
-	Metadata ProjectMetadata    `json:"metadata" yaml:"metadata"`
-	Spec     dashboardSpec.Spec `json:"spec" yaml:"spec"`
+	Kind     Kind                   `json:"kind" yaml:"kind"`
+	Metadata ProjectMetadata        `json:"metadata" yaml:"metadata"`
+	Spec     AnnotatedDashboardSpec `json:"spec" yaml:"spec"`
 }
 
 func (d *Dashboard) GetMetadata() modelAPI.Metadata {
@@ -183,7 +224,7 @@ func (d *Dashboard) validate() error {
 	if d.Kind != KindDashboard {
 		return fmt.Errorf("invalid kind: %q for a Dashboard type", d.Kind)
 	}
-	if reflect.DeepEqual(d.Spec, dashboardSpec.Spec{}) {
+	if reflect.DeepEqual(d.Spec, AnnotatedDashboardSpec{}) {
 		return fmt.Errorf("spec cannot be empty")
 	}
 	return verifyAndSetJSONReferences(d.Spec.Layouts, d.Spec.Panels)
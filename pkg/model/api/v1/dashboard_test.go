@@ -48,7 +48,7 @@ func TestMarshalDashboard(t *testing.T) {
 						Project: "perses",
 					},
 				},
-				Spec: dashboard.Spec{
+				Spec: AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Variables: nil,
 					Panels: map[string]*dashboard.Panel{
 						"MyPanel": {
@@ -88,7 +88,7 @@ func TestMarshalDashboard(t *testing.T) {
 					},
 					Duration:        "6h",
 					RefreshInterval: "20s",
-				},
+				}},
 			},
 			result: `{
   "kind": "Dashboard",
@@ -154,7 +154,7 @@ func TestMarshalDashboard(t *testing.T) {
 						Project: "perses",
 					},
 				},
-				Spec: dashboard.Spec{
+				Spec: AnnotatedDashboardSpec{Spec: dashboard.Spec{
 					Variables: []dashboard.Variable{
 						{
 							Kind: variable.KindList,
@@ -228,7 +228,7 @@ func TestMarshalDashboard(t *testing.T) {
 					},
 					Duration:        "6h",
 					RefreshInterval: "15s",
-				},
+				}},
 			},
 			result: `{
   "kind": "Dashboard",
@@ -405,6 +405,24 @@ func TestUnmarshallDashboard(t *testing.T) {
         }
       }
     ],
+    "annotations": [
+      {
+        "kind": "Annotation",
+        "spec": {
+          "display": {
+            "name": "Deployments"
+          },
+          "enabled": true,
+          "color": "red",
+          "plugin": {
+            "kind": "PrometheusAnnotation",
+            "spec": {
+              "query": "changes(app_version[5m]) > 0"
+            }
+          }
+        }
+      }
+    ],
     "duration": "6h",
     "refreshInterval": "30s"
   }
@@ -436,65 +454,85 @@ func TestUnmarshallDashboard(t *testing.T) {
 				Project: "perses",
 			},
 		},
-		Spec: dashboard.Spec{
-			Variables: []dashboard.Variable{
-				{
-					Kind: variable.KindList,
-					Spec: &dashboard.ListVariableSpec{
-						ListSpec: variable.ListSpec{
-							Plugin: common.Plugin{
-								Kind: "PrometheusLabelNamesVariable",
-								Spec: map[string]any{
-									"matchers": []any{
-										"up",
+		Spec: AnnotatedDashboardSpec{
+			Spec: dashboard.Spec{
+				Variables: []dashboard.Variable{
+					{
+						Kind: variable.KindList,
+						Spec: &dashboard.ListVariableSpec{
+							ListSpec: variable.ListSpec{
+								Plugin: common.Plugin{
+									Kind: "PrometheusLabelNamesVariable",
+									Spec: map[string]any{
+										"matchers": []any{
+											"up",
+										},
+									},
+								},
+							},
+							Name: "labelName",
+						},
+					},
+					{
+						Kind: variable.KindList,
+						Spec: &dashboard.ListVariableSpec{
+							ListSpec: variable.ListSpec{
+								Plugin: common.Plugin{
+									Kind: "PrometheusLabelValuesVariable",
+									Spec: map[string]any{
+										"labelName": "$labelName",
+										"matchers": []any{
+											"up",
+										},
 									},
 								},
 							},
+							Name: "labelValue",
 						},
-						Name: "labelName",
 					},
 				},
-				{
-					Kind: variable.KindList,
-					Spec: &dashboard.ListVariableSpec{
-						ListSpec: variable.ListSpec{
-							Plugin: common.Plugin{
-								Kind: "PrometheusLabelValuesVariable",
-								Spec: map[string]any{
-									"labelName": "$labelName",
-									"matchers": []any{
-										"up",
+				Panels: map[string]*dashboard.Panel{"MyPanel": panel},
+				Layouts: []dashboard.Layout{
+					{
+						Kind: dashboard.KindGridLayout,
+						Spec: &dashboard.GridLayoutSpec{
+							Items: []dashboard.GridItem{
+								{
+									X:      0,
+									Y:      0,
+									Width:  3,
+									Height: 4,
+									Content: &common.JSONRef{
+										Ref:    "#/spec/panels/MyPanel",
+										Path:   []string{"spec", "panels", "MyPanel"},
+										Object: panel,
 									},
 								},
 							},
 						},
-						Name: "labelValue",
 					},
 				},
+				Duration:        "6h",
+				RefreshInterval: "30s",
 			},
-			Panels: map[string]*dashboard.Panel{"MyPanel": panel},
-			Layouts: []dashboard.Layout{
+			Annotations: []Annotation{
 				{
-					Kind: dashboard.KindGridLayout,
-					Spec: &dashboard.GridLayoutSpec{
-						Items: []dashboard.GridItem{
-							{
-								X:      0,
-								Y:      0,
-								Width:  3,
-								Height: 4,
-								Content: &common.JSONRef{
-									Ref:    "#/spec/panels/MyPanel",
-									Path:   []string{"spec", "panels", "MyPanel"},
-									Object: panel,
-								},
+					Kind: "Annotation",
+					Spec: AnnotationSpec{
+						Display: &AnnotationDisplay{
+							Name: "Deployments",
+						},
+						Enabled: true,
+						Color:   "red",
+						Plugin: common.Plugin{
+							Kind: "PrometheusAnnotation",
+							Spec: map[string]any{
+								"query": "changes(app_version[5m]) > 0",
 							},
 						},
 					},
 				},
 			},
-			Duration:        "6h",
-			RefreshInterval: "30s",
 		},
 	}
 	result := &Dashboard{}
@@ -522,6 +560,32 @@ func TestUnmarshalDashboardError(t *testing.T) {
 `,
 			err: fmt.Errorf("spec cannot be empty"),
 		},
+		{
+			title: "annotation without plugin",
+			jason: `
+{
+  "kind": "Dashboard",
+  "metadata": {
+    "name": "test",
+    "project": "perses"
+  },
+  "spec": {
+    "duration": "1h",
+    "annotations": [
+      {
+        "kind": "Annotation",
+        "spec": {
+          "display": {
+            "name": "Deployments"
+          }
+        }
+      }
+    ]
+  }
+}
+`,
+			err: fmt.Errorf("the plugin of the annotation \"Deployments\" cannot be empty"),
+		},
 	}
 	for _, test := range testSuite {
 		t.Run(test.title, func(t *testing.T) {
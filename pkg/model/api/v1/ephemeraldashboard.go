@@ -20,7 +20,6 @@ import (
 
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/spec/go/common"
-	"github.com/perses/spec/go/dashboard"
 )
 
 type EphemeralDashboardSpecBase struct {
@@ -49,7 +48,7 @@ func (edsb *EphemeralDashboardSpecBase) UnmarshalYAML(unmarshal func(any) error)
 
 type EphemeralDashboardSpec struct {
 	EphemeralDashboardSpecBase `json:",inline" yaml:",inline"`
-	dashboard.Spec             `json:",inline" yaml:",inline"`
+	AnnotatedDashboardSpec     `json:",inline" yaml:",inline"`
 }
 
 // NB custom unmarshalling is required, otherwise by default the TTL field
@@ -62,14 +61,14 @@ func (eds *EphemeralDashboardSpec) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	var dashboardSpecTmp dashboard.Spec
+	var dashboardSpecTmp AnnotatedDashboardSpec
 	if err := dashboardSpecTmp.UnmarshalJSON(data); err != nil {
 		return err
 	}
 
 	// Copy values to the fields of EphemeralDashboardSpec
 	eds.EphemeralDashboardSpecBase = ephemeralDashboardSpecBaseTmp
-	eds.Spec = dashboardSpecTmp
+	eds.AnnotatedDashboardSpec = dashboardSpecTmp
 
 	return nil
 }
@@ -81,14 +80,14 @@ func (eds *EphemeralDashboardSpec) UnmarshalYAML(unmarshal func(any) error) erro
 		return err
 	}
 
-	var dashboardSpecTmp dashboard.Spec
+	var dashboardSpecTmp AnnotatedDashboardSpec
 	if err := dashboardSpecTmp.UnmarshalYAML(unmarshal); err != nil {
 		return err
 	}
 
 	// Copy values to the fields of EphemeralDashboardSpec
 	eds.EphemeralDashboardSpecBase = ephemeralDashboardSpecBaseTmp
-	eds.Spec = dashboardSpecTmp
+	eds.AnnotatedDashboardSpec = dashboardSpecTmp
 
 	return nil
 }
@@ -47,7 +47,7 @@ func TestMarshalEphemeralDashboard(t *testing.T) {
 					EphemeralDashboardSpecBase{
 						TTL: common.Duration(24 * time.Hour),
 					},
-					dashboard.Spec{
+					AnnotatedDashboardSpec{Spec: dashboard.Spec{
 						Variables: nil,
 						Panels: map[string]*dashboard.Panel{
 							"MyPanel": {
@@ -87,7 +87,7 @@ func TestMarshalEphemeralDashboard(t *testing.T) {
 						},
 						Duration:        "6h",
 						RefreshInterval: "20s",
-					},
+					}},
 				},
 			},
 			result: `{
@@ -159,7 +159,7 @@ func TestMarshalEphemeralDashboard(t *testing.T) {
 					EphemeralDashboardSpecBase{
 						TTL: common.Duration(24 * time.Hour),
 					},
-					dashboard.Spec{
+					AnnotatedDashboardSpec{Spec: dashboard.Spec{
 						Variables: []dashboard.Variable{
 							{
 								Kind: variable.KindList,
@@ -233,7 +233,7 @@ func TestMarshalEphemeralDashboard(t *testing.T) {
 						},
 						Duration:        "6h",
 						RefreshInterval: "15s",
-					},
+					}},
 				},
 			},
 			result: `{
@@ -448,7 +448,7 @@ func TestUnmarshallEphemeralDashboard(t *testing.T) {
 			EphemeralDashboardSpecBase{
 				TTL: common.Duration(24 * time.Hour),
 			},
-			dashboard.Spec{
+			AnnotatedDashboardSpec{Spec: dashboard.Spec{
 				Variables: []dashboard.Variable{
 					{
 						Kind: variable.KindList,
@@ -507,7 +507,7 @@ func TestUnmarshallEphemeralDashboard(t *testing.T) {
 				},
 				Duration:        "6h",
 				RefreshInterval: "30s",
-			},
+			}},
 		},
 	}
 	result := &EphemeralDashboard{}
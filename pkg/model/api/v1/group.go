@@ -0,0 +1,94 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+)
+
+type GroupSpec struct {
+	Display *common.Display `json:"display,omitempty" yaml:"display,omitempty"`
+	// Members is the list of the users (metadata.name) belonging to the group.
+	Members []string `json:"members,omitempty" yaml:"members,omitempty"`
+	// ManagedBy is the authentication provider that created the group and manages its members, like "header".
+	// It is empty for the groups managed through the API or by SCIM.
+	ManagedBy string `json:"managedBy,omitempty" yaml:"managedBy,omitempty"`
+}
+
+// Has returns true if the user is a member of the group.
+func (g *GroupSpec) Has(username string) bool {
+	return slices.Contains(g.Members, username)
+}
+
+// Group is a set of users that can be used as a subject of a RoleBinding or a GlobalRoleBinding.
+type Group struct {
+	Kind     Kind      `json:"kind" yaml:"kind"`
+	Metadata Metadata  `json:"metadata" yaml:"metadata"`
+	Spec     GroupSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+func (g *Group) GetMetadata() modelAPI.Metadata {
+	return &g.Metadata
+}
+
+func (g *Group) GetKind() string {
+	return string(g.Kind)
+}
+
+func (g *Group) GetSpec() any {
+	return g.Spec
+}
+
+func (g *Group) UnmarshalJSON(data []byte) error {
+	var tmp Group
+	type plain Group
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*g = tmp
+	return nil
+}
+
+func (g *Group) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp Group
+	type plain Group
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*g = tmp
+	return nil
+}
+
+func (g *Group) validate() error {
+	if g.Kind != KindGroup {
+		return fmt.Errorf("invalid kind: %q for a Group type", g.Kind)
+	}
+	for _, member := range g.Spec.Members {
+		if len(member) == 0 {
+			return fmt.Errorf("group member cannot be empty")
+		}
+	}
+	return nil
+}
@@ -33,7 +33,9 @@ const (
 	KindGlobalRoleBinding  Kind = "GlobalRoleBinding"
 	KindGlobalVariable     Kind = "GlobalVariable"
 	KindGlobalSecret       Kind = "GlobalSecret"
+	KindGroup              Kind = "Group"
 	KindProject            Kind = "Project"
+	KindProjectTemplate    Kind = "ProjectTemplate"
 	KindRole               Kind = "Role"
 	KindRoleBinding        Kind = "RoleBinding"
 	KindSecret             Kind = "Secret"
@@ -51,7 +53,9 @@ var PluralKindMap = map[Kind]string{
 	KindGlobalRoleBinding:  "globalrolebindings",
 	KindGlobalSecret:       "globalsecrets",
 	KindGlobalVariable:     "globalvariables",
+	KindGroup:              "groups",
 	KindProject:            "projects",
+	KindProjectTemplate:    "projecttemplates",
 	KindRole:               "roles",
 	KindRoleBinding:        "rolebindings",
 	KindSecret:             "secrets",
@@ -118,8 +122,12 @@ func GetStruct(kind Kind) (modelAPI.Entity, error) {
 		return &GlobalSecret{}, nil
 	case KindGlobalVariable:
 		return &GlobalVariable{}, nil
+	case KindGroup:
+		return &Group{}, nil
 	case KindProject:
 		return &Project{}, nil
+	case KindProjectTemplate:
+		return &ProjectTemplate{}, nil
 	case KindRole:
 		return &Role{}, nil
 	case KindRoleBinding:
@@ -137,7 +145,7 @@ func GetStruct(kind Kind) (modelAPI.Entity, error) {
 
 func IsGlobal(kind Kind) bool {
 	switch kind {
-	case KindGlobalDatasource, KindGlobalRole, KindGlobalRoleBinding, KindGlobalSecret, KindGlobalVariable, KindProject, KindUser:
+	case KindGlobalDatasource, KindGlobalRole, KindGlobalRoleBinding, KindGlobalSecret, KindGlobalVariable, KindGroup, KindProject, KindProjectTemplate, KindUser:
 		return true
 	default:
 		return false
@@ -174,9 +182,15 @@ func GetKind(kind string) (*Kind, error) {
 	case strings.ToLower(string(KindGlobalVariable)):
 		result := KindGlobalVariable
 		return &result, nil
+	case strings.ToLower(string(KindGroup)):
+		result := KindGroup
+		return &result, nil
 	case strings.ToLower(string(KindProject)):
 		result := KindProject
 		return &result, nil
+	case strings.ToLower(string(KindProjectTemplate)):
+		result := KindProjectTemplate
+		return &result, nil
 	case strings.ToLower(string(KindRole)):
 		result := KindRole
 		return &result, nil
@@ -21,9 +21,40 @@ import (
 	"unicode/utf8"
 
 	"github.com/perses/common/set"
+	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/v1/common"
 )
 
+// ProvisioningSource records the file a resource has been provisioned from.
+type ProvisioningSource struct {
+	// File is the path of the file the resource is coming from.
+	// For a git repository, the path is relative to the root of the repository.
+	File string `json:"file" yaml:"file"`
+	// Hash is the sha256 of the resource as it is described in the file, the other resources of the file excluded.
+	Hash string `json:"hash" yaml:"hash"`
+	// Repository is the name of the git repository the file comes from. It is empty for the local folders.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	// Commit is the sha of the git commit the resource has been provisioned from.
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	// Modified is true when the resource has been edited through the API since the last provisioning.
+	Modified bool `json:"modified,omitempty" yaml:"modified,omitempty"`
+}
+
+// GetProvisioningSource returns the provisioning source stored in the metadata, if any.
+func GetProvisioningSource(metadata modelAPI.Metadata) *ProvisioningSource {
+	if m, ok := metadata.(interface{ GetProvisioning() *ProvisioningSource }); ok {
+		return m.GetProvisioning()
+	}
+	return nil
+}
+
+// SetProvisioningSource stores the provisioning source in the metadata when the metadata supports it.
+func SetProvisioningSource(metadata modelAPI.Metadata, source *ProvisioningSource) {
+	if m, ok := metadata.(interface{ SetProvisioning(*ProvisioningSource) }); ok {
+		m.SetProvisioning(source)
+	}
+}
+
 func NewMetadata(name string) *Metadata {
 	return &Metadata{
 		Name: name,
@@ -46,6 +77,9 @@ type Metadata struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MaxItems=20
 	Tags set.Set[string] `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Provisioning is set by the provisioning service on every resource it manages.
+	// +kubebuilder:validation:Optional
+	Provisioning *ProvisioningSource `json:"provisioning,omitempty" yaml:"provisioning,omitempty"`
 }
 
 func (m *Metadata) CreateNow() {
@@ -67,6 +101,14 @@ func (m *Metadata) GetName() string {
 	return m.Name
 }
 
+func (m *Metadata) GetProvisioning() *ProvisioningSource {
+	return m.Provisioning
+}
+
+func (m *Metadata) SetProvisioning(source *ProvisioningSource) {
+	m.Provisioning = source
+}
+
 func (m *Metadata) Flatten(sensitive bool) {
 	if !sensitive {
 		m.Name = strings.ToLower(m.Name)
@@ -145,9 +187,10 @@ type PublicMetadata struct {
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Format=date-time
 	// +kubebuilder:validation:Optional
-	UpdatedAt time.Time       `json:"updatedAt" yaml:"updatedAt"`
-	Version   uint64          `json:"version" yaml:"version"`
-	Tags      set.Set[string] `json:"tags,omitempty" yaml:"tags,omitempty"`
+	UpdatedAt    time.Time           `json:"updatedAt" yaml:"updatedAt"`
+	Version      uint64              `json:"version" yaml:"version"`
+	Tags         set.Set[string]     `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Provisioning *ProvisioningSource `json:"provisioning,omitempty" yaml:"provisioning,omitempty"`
 }
 
 func NewPublicMetadata(name string) PublicMetadata {
@@ -175,6 +218,10 @@ func (m *PublicMetadata) GetName() string {
 	return m.Name
 }
 
+func (m *PublicMetadata) GetProvisioning() *ProvisioningSource {
+	return m.Provisioning
+}
+
 func (m *PublicMetadata) Flatten(sensitive bool) {
 	if !sensitive {
 		m.Name = strings.ToLower(m.Name)
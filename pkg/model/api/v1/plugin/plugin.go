@@ -37,6 +37,7 @@ const (
 	KindLogQuery        Kind = "LogQuery"
 	KindQuery           Kind = "Query"
 	KindExplore         Kind = "Explore"
+	KindAnnotation      Kind = "Annotation"
 )
 
 func (k Kind) IsQuery() bool {
@@ -81,16 +82,33 @@ func (p *Plugin) UnmarshalYAML(unmarshal func(any) error) error {
 
 func (p *Plugin) validate() error {
 	if p.Kind != KindVariable && p.Kind != KindDatasource &&
-		p.Kind != KindPanel && !p.Kind.IsQuery() && p.Kind != KindExplore {
+		p.Kind != KindPanel && !p.Kind.IsQuery() && p.Kind != KindExplore && p.Kind != KindAnnotation {
 		return fmt.Errorf("invalid plugin kind %s", p.Kind)
 	}
 	return nil
 }
 
+// SignatureStatus is the result of the verification of the signature of a plugin module.
+type SignatureStatus string
+
+const (
+	// SignatureStatusVerified means the plugin module is signed by one of the trusted keys.
+	SignatureStatusVerified SignatureStatus = "verified"
+	// SignatureStatusUnsigned means the plugin module does not contain any signature.
+	SignatureStatusUnsigned SignatureStatus = "unsigned"
+	// SignatureStatusInvalid means the signature does not match the content of the plugin module or any of the trusted keys.
+	SignatureStatusInvalid SignatureStatus = "invalid"
+)
+
 type ModuleStatus struct {
-	IsLoaded bool   `json:"isLoaded" yaml:"isLoaded"`
-	InDev    bool   `json:"inDev" yaml:"inDev"`
-	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+	IsLoaded bool `json:"isLoaded" yaml:"isLoaded"`
+	InDev    bool `json:"inDev" yaml:"inDev"`
+	// Disabled is set when the plugin module has been disabled through the API. A disabled plugin module is not loaded.
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// Signature is the result of the verification of the signature of the plugin module.
+	// It is empty when the signature is not verified.
+	Signature SignatureStatus `json:"signature,omitempty" yaml:"signature,omitempty"`
+	Error     string          `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 type ModuleSpec struct {
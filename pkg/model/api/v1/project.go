@@ -21,8 +21,21 @@ import (
 	"github.com/perses/perses/pkg/model/api/v1/common"
 )
 
+// ProjectTemplateReference is the ProjectTemplate a project is created from.
+type ProjectTemplateReference struct {
+	// Name of the ProjectTemplate.
+	Name string `json:"name" yaml:"name"`
+	// Parameters are the values given to the parameters of the template.
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	// Owner is the user who instantiated the template. It's set by the server and used as `.Username` when the
+	// template is instantiated again, so syncing the template doesn't give the project to the user running the sync.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+}
+
 type ProjectSpec struct {
 	Display *common.Display `json:"display,omitempty" yaml:"display,omitempty"`
+	// Template is the ProjectTemplate instantiated when the project is created or when the template is synced.
+	Template *ProjectTemplateReference `json:"template,omitempty" yaml:"template,omitempty"`
 }
 
 type Project struct {
@@ -73,5 +86,8 @@ func (p *Project) validate() error {
 	if p.Kind != KindProject {
 		return fmt.Errorf("invalid kind: %q for a Project type", p.Kind)
 	}
+	if p.Spec.Template != nil && len(p.Spec.Template.Name) == 0 {
+		return fmt.Errorf("spec.template.name cannot be empty")
+	}
 	return nil
 }
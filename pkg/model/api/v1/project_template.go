@@ -0,0 +1,270 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+)
+
+const (
+	projectTemplateLeftDelim  = "${{"
+	projectTemplateRightDelim = "}}"
+)
+
+// projectTemplateKinds are the kinds a ProjectTemplate can instantiate, in the order they are created.
+// Secrets come first as they can be referenced by the datasources, and the roles before their bindings.
+var projectTemplateKinds = []Kind{KindSecret, KindDatasource, KindVariable, KindFolder, KindDashboard, KindRole, KindRoleBinding}
+
+var projectTemplateParameterNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type ProjectTemplateParameter struct {
+	// Name of the parameter, used in the placeholders with ${{ .Parameters.<name> }}
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Default is the value used when the project doesn't provide one.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Required means the project must provide a value.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+type ProjectTemplateResourceMetadata struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// ProjectTemplateResource is a project resource written without its project.
+// The name and every string of the spec can contain placeholders.
+type ProjectTemplateResource struct {
+	Kind     Kind                            `json:"kind" yaml:"kind"`
+	Metadata ProjectTemplateResourceMetadata `json:"metadata" yaml:"metadata"`
+	Spec     any                             `json:"spec" yaml:"spec"`
+}
+
+type ProjectTemplateSpec struct {
+	Display    *common.Display            `json:"display,omitempty" yaml:"display,omitempty"`
+	Parameters []ProjectTemplateParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Resources  []ProjectTemplateResource  `json:"resources" yaml:"resources"`
+}
+
+// ProjectTemplate is a set of resources instantiated in a project when it is created from the template.
+type ProjectTemplate struct {
+	Kind     Kind                `json:"kind" yaml:"kind"`
+	Metadata Metadata            `json:"metadata" yaml:"metadata"`
+	Spec     ProjectTemplateSpec `json:"spec" yaml:"spec"`
+}
+
+func (p *ProjectTemplate) GetMetadata() modelAPI.Metadata {
+	return &p.Metadata
+}
+
+func (p *ProjectTemplate) GetKind() string {
+	return string(p.Kind)
+}
+
+func (p *ProjectTemplate) GetSpec() any {
+	return p.Spec
+}
+
+func (p *ProjectTemplate) UnmarshalJSON(data []byte) error {
+	var tmp ProjectTemplate
+	type plain ProjectTemplate
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*p = tmp
+	return nil
+}
+
+func (p *ProjectTemplate) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp ProjectTemplate
+	type plain ProjectTemplate
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*p = tmp
+	return nil
+}
+
+func (p *ProjectTemplate) validate() error {
+	if p.Kind != KindProjectTemplate {
+		return fmt.Errorf("invalid kind: %q for a ProjectTemplate type", p.Kind)
+	}
+	// The placeholders are checked with empty values, the rendered resources are validated when a project is created.
+	data := ProjectTemplateData{Parameters: make(map[string]string)}
+	for _, param := range p.Spec.Parameters {
+		if !projectTemplateParameterNameRegexp.MatchString(param.Name) {
+			return fmt.Errorf("invalid parameter name %q, it must match %s", param.Name, projectTemplateParameterNameRegexp.String())
+		}
+		if _, ok := data.Parameters[param.Name]; ok {
+			return fmt.Errorf("parameter %q is defined more than once", param.Name)
+		}
+		if param.Required && len(param.Default) > 0 {
+			return fmt.Errorf("parameter %q cannot be required and have a default value", param.Name)
+		}
+		data.Parameters[param.Name] = ""
+	}
+	for i, resource := range p.Spec.Resources {
+		if !slices.Contains(projectTemplateKinds, resource.Kind) {
+			return fmt.Errorf("resources[%d]: kind %q cannot be part of a ProjectTemplate", i, resource.Kind)
+		}
+		if len(resource.Metadata.Name) == 0 {
+			return fmt.Errorf("resources[%d]: metadata.name cannot be empty", i)
+		}
+		if _, err := renderTemplateString(resource.Metadata.Name, data); err != nil {
+			return fmt.Errorf("resources[%d]: %w", i, err)
+		}
+		if _, err := renderTemplateValue(resource.Spec, data); err != nil {
+			return fmt.Errorf("resources[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ResolveParameters returns the value of every parameter of the template, using the default value when the
+// project doesn't provide one.
+func (p *ProjectTemplate) ResolveParameters(values map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(p.Spec.Parameters))
+	for _, param := range p.Spec.Parameters {
+		value, ok := values[param.Name]
+		if !ok {
+			if param.Required {
+				return nil, fmt.Errorf("parameter %q of the template %q is required", param.Name, p.Metadata.Name)
+			}
+			value = param.Default
+		}
+		result[param.Name] = value
+	}
+	for name := range values {
+		if _, ok := result[name]; !ok {
+			return nil, fmt.Errorf("parameter %q is not defined by the template %q", name, p.Metadata.Name)
+		}
+	}
+	return result, nil
+}
+
+// ProjectTemplateData is the data available in the placeholders of a ProjectTemplate.
+type ProjectTemplateData struct {
+	// Project is the name of the project.
+	Project string
+	// Username is the name of the user creating the project, or syncing it with the template.
+	// It is empty when the authentication is disabled.
+	Username string
+	// Parameters contains the value of every parameter, as returned by ResolveParameters.
+	Parameters map[string]string
+}
+
+// Render instantiates the resources of the template in the project.
+// The resources are validated, and sorted in the order they must be created.
+func (p *ProjectTemplate) Render(data ProjectTemplateData) ([]modelAPI.Entity, error) {
+	result := make([]modelAPI.Entity, 0, len(p.Spec.Resources))
+	for _, kind := range projectTemplateKinds {
+		for _, resource := range p.Spec.Resources {
+			if resource.Kind != kind {
+				continue
+			}
+			entity, err := renderTemplateResource(resource, data)
+			if err != nil {
+				return nil, fmt.Errorf("unable to render the %s %q of the template %q: %w", resource.Kind, resource.Metadata.Name, p.Metadata.Name, err)
+			}
+			result = append(result, entity)
+		}
+	}
+	return result, nil
+}
+
+func renderTemplateResource(resource ProjectTemplateResource, data ProjectTemplateData) (modelAPI.Entity, error) {
+	name, err := renderTemplateString(resource.Metadata.Name, data)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := renderTemplateValue(resource.Spec, data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(map[string]any{
+		"kind":     resource.Kind,
+		"metadata": NewProjectMetadata(data.Project, name),
+		"spec":     spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entity, err := GetStruct(resource.Kind)
+	if err != nil {
+		return nil, err
+	}
+	// Unmarshalling the resource is validating it.
+	if err := json.Unmarshal(raw, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// renderTemplateValue renders every string of a value decoded from JSON or YAML.
+func renderTemplateValue(value any, data any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return renderTemplateString(v, data)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			rendered, err := renderTemplateValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = rendered
+		}
+		return result, nil
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			rendered, err := renderTemplateValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, rendered)
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+func renderTemplateString(value string, data any) (string, error) {
+	if !strings.Contains(value, projectTemplateLeftDelim) {
+		return value, nil
+	}
+	tmpl, err := template.New("").Delims(projectTemplateLeftDelim, projectTemplateRightDelim).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", err
+	}
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
@@ -0,0 +1,210 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalProjectTemplateError(t *testing.T) {
+	testSuite := []struct {
+		title string
+		jason string
+		err   error
+	}{
+		{
+			title: "invalid parameter name",
+			jason: `
+{
+  "kind": "ProjectTemplate",
+  "metadata": {"name": "team"},
+  "spec": {
+    "parameters": [{"name": "team-name"}],
+    "resources": []
+  }
+}
+`,
+			err: fmt.Errorf("invalid parameter name \"team-name\", it must match ^[a-zA-Z_][a-zA-Z0-9_]*$"),
+		},
+		{
+			title: "parameter defined twice",
+			jason: `
+{
+  "kind": "ProjectTemplate",
+  "metadata": {"name": "team"},
+  "spec": {
+    "parameters": [{"name": "team"}, {"name": "team"}],
+    "resources": []
+  }
+}
+`,
+			err: fmt.Errorf("parameter \"team\" is defined more than once"),
+		},
+		{
+			title: "required parameter with a default value",
+			jason: `
+{
+  "kind": "ProjectTemplate",
+  "metadata": {"name": "team"},
+  "spec": {
+    "parameters": [{"name": "team", "required": true, "default": "perses"}],
+    "resources": []
+  }
+}
+`,
+			err: fmt.Errorf("parameter \"team\" cannot be required and have a default value"),
+		},
+		{
+			title: "global resource",
+			jason: `
+{
+  "kind": "ProjectTemplate",
+  "metadata": {"name": "team"},
+  "spec": {
+    "resources": [{"kind": "GlobalRole", "metadata": {"name": "admin"}, "spec": {}}]
+  }
+}
+`,
+			err: fmt.Errorf("resources[0]: kind \"GlobalRole\" cannot be part of a ProjectTemplate"),
+		},
+		{
+			title: "undefined parameter",
+			jason: `
+{
+  "kind": "ProjectTemplate",
+  "metadata": {"name": "team"},
+  "spec": {
+    "resources": [{"kind": "Role", "metadata": {"name": "${{ .Parameters.team }}"}, "spec": {}}]
+  }
+}
+`,
+			err: fmt.Errorf("resources[0]: template: :1:15: executing \"\" at <.Parameters.team>: map has no entry for key \"team\""),
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result := ProjectTemplate{}
+			assert.EqualError(t, json.Unmarshal([]byte(test.jason), &result), test.err.Error())
+		})
+	}
+}
+
+func TestProjectTemplateResolveParameters(t *testing.T) {
+	tmpl := &ProjectTemplate{
+		Kind:     KindProjectTemplate,
+		Metadata: *NewMetadata("team"),
+		Spec: ProjectTemplateSpec{
+			Parameters: []ProjectTemplateParameter{
+				{Name: "team", Required: true},
+				{Name: "env", Default: "prod"},
+			},
+		},
+	}
+	testSuite := []struct {
+		title  string
+		values map[string]string
+		result map[string]string
+		err    error
+	}{
+		{
+			title:  "default value used",
+			values: map[string]string{"team": "perses"},
+			result: map[string]string{"team": "perses", "env": "prod"},
+		},
+		{
+			title:  "default value overridden",
+			values: map[string]string{"team": "perses", "env": "dev"},
+			result: map[string]string{"team": "perses", "env": "dev"},
+		},
+		{
+			title:  "required parameter missing",
+			values: map[string]string{"env": "dev"},
+			err:    fmt.Errorf("parameter \"team\" of the template \"team\" is required"),
+		},
+		{
+			title:  "unknown parameter",
+			values: map[string]string{"team": "perses", "region": "eu"},
+			err:    fmt.Errorf("parameter \"region\" is not defined by the template \"team\""),
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := tmpl.ResolveParameters(test.values)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestProjectTemplateRender(t *testing.T) {
+	tmpl := ProjectTemplate{}
+	jason := `
+{
+  "kind": "ProjectTemplate",
+  "metadata": {"name": "team"},
+  "spec": {
+    "parameters": [{"name": "team", "required": true}],
+    "resources": [
+      {
+        "kind": "RoleBinding",
+        "metadata": {"name": "${{ .Parameters.team }}-viewers"},
+        "spec": {
+          "role": "viewer",
+          "subjects": [{"kind": "Group", "name": "${{ .Parameters.team }}"}, {"kind": "User", "name": "${{ .Username }}"}]
+        }
+      },
+      {
+        "kind": "Role",
+        "metadata": {"name": "viewer"},
+        "spec": {
+          "permissions": [{"actions": ["read"], "scopes": ["*"]}]
+        }
+      }
+    ]
+  }
+}
+`
+	assert.NoError(t, json.Unmarshal([]byte(jason), &tmpl))
+	result, err := tmpl.Render(ProjectTemplateData{
+		Project:    "perses",
+		Username:   "john",
+		Parameters: map[string]string{"team": "devs"},
+	})
+	assert.NoError(t, err)
+	// Roles are rendered first, so they exist when the bindings are created
+	expected := []modelAPI.Entity{
+		&Role{
+			Kind:     KindRole,
+			Metadata: *NewProjectMetadata("perses", "viewer"),
+			Spec: RoleSpec{
+				Permissions: []role.Permission{{Actions: []role.Action{role.ReadAction}, Scopes: []role.Scope{role.WildcardScope}}},
+			},
+		},
+		&RoleBinding{
+			Kind:     KindRoleBinding,
+			Metadata: *NewProjectMetadata("perses", "devs-viewers"),
+			Spec: RoleBindingSpec{
+				Role:     "viewer",
+				Subjects: []Subject{{Kind: KindGroup, Name: "devs"}, {Kind: KindUser, Name: "john"}},
+			},
+		},
+	}
+	assert.Equal(t, expected, result)
+}
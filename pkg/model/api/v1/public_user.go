@@ -14,12 +14,16 @@
 package v1
 
 import (
+	"time"
+
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/v1/secret"
 )
 
 type PublicNativeProvider struct {
-	Password secret.Hidden `json:"password,omitempty" yaml:"password,omitempty"`
+	Password           secret.Hidden `json:"password,omitempty" yaml:"password,omitempty"`
+	MustChangePassword bool          `json:"mustChangePassword,omitempty" yaml:"mustChangePassword,omitempty"`
+	TwoFactorEnabled   bool          `json:"twoFactorEnabled,omitempty" yaml:"twoFactorEnabled,omitempty"`
 }
 
 type PublicUserSpec struct {
@@ -27,6 +31,9 @@ type PublicUserSpec struct {
 	LastName       string               `json:"lastName,omitempty" yaml:"lastName,omitempty"`
 	NativeProvider PublicNativeProvider `json:"nativeProvider,omitempty" yaml:"nativeProvider,omitempty"`
 	OauthProviders []OAuthProvider      `json:"oauthProviders,omitempty" yaml:"oauthProviders,omitempty"`
+	Disabled       bool                 `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	DisabledReason string               `json:"disabledReason,omitempty" yaml:"disabledReason,omitempty"`
+	DisabledAt     *time.Time           `json:"disabledAt,omitempty" yaml:"disabledAt,omitempty"`
 }
 
 func NewPublicUserSpec(u UserSpec) PublicUserSpec {
@@ -34,9 +41,14 @@ func NewPublicUserSpec(u UserSpec) PublicUserSpec {
 		FirstName: u.FirstName,
 		LastName:  u.LastName,
 		NativeProvider: PublicNativeProvider{
-			Password: secret.Hidden(u.NativeProvider.Password),
+			Password:           secret.Hidden(u.NativeProvider.Password),
+			MustChangePassword: u.NativeProvider.MustChangePassword,
+			TwoFactorEnabled:   u.NativeProvider.TOTP.IsEnabled(),
 		},
 		OauthProviders: u.OauthProviders,
+		Disabled:       u.Disabled,
+		DisabledReason: u.DisabledReason,
+		DisabledAt:     u.DisabledAt,
 	}
 }
 
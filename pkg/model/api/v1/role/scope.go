@@ -31,7 +31,9 @@ const (
 	GlobalRoleBindingScope  Scope = "GlobalRoleBinding"
 	GlobalSecretScope       Scope = "GlobalSecret"
 	GlobalVariableScope     Scope = "GlobalVariable"
+	GroupScope              Scope = "Group"
 	ProjectScope            Scope = "Project"
+	ProjectTemplateScope    Scope = "ProjectTemplate"
 	RoleScope               Scope = "Role"
 	RoleBindingScope        Scope = "RoleBinding"
 	SecretScope             Scope = "Secret"
@@ -106,9 +108,15 @@ func GetScope(scope string) (*Scope, error) {
 	case strings.ToLower(string(GlobalVariableScope)):
 		result := GlobalVariableScope
 		return &result, nil
+	case strings.ToLower(string(GroupScope)):
+		result := GroupScope
+		return &result, nil
 	case strings.ToLower(string(ProjectScope)):
 		result := ProjectScope
 		return &result, nil
+	case strings.ToLower(string(ProjectTemplateScope)):
+		result := ProjectTemplateScope
+		return &result, nil
 	case strings.ToLower(string(RoleScope)):
 		result := RoleScope
 		return &result, nil
@@ -136,7 +144,7 @@ func IsGlobalScope(scope Scope) bool {
 	switch scope {
 	// ProjectScope is not global even if it should be. Owners of projects should be able to delete their own projects
 	// As ProjectScope is not Global, it can be added in Role scopes and allow this flow.
-	case GlobalDatasourceScope, GlobalRoleScope, GlobalRoleBindingScope, GlobalSecretScope, GlobalVariableScope, UserScope:
+	case GlobalDatasourceScope, GlobalRoleScope, GlobalRoleBindingScope, GlobalSecretScope, GlobalVariableScope, GroupScope, ProjectTemplateScope, UserScope:
 		return true
 	default:
 		return false
@@ -57,7 +57,7 @@ func (s *Subject) UnmarshalYAML(unmarshal func(any) error) error {
 }
 
 func (s *Subject) validate() error {
-	if s.Kind != KindUser {
+	if s.Kind != KindUser && s.Kind != KindGroup {
 		return fmt.Errorf("invalid kind: %q for a Subject kind", s.Kind)
 	}
 	if len(s.Name) == 0 {
@@ -16,6 +16,7 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	modelAPI "github.com/perses/perses/pkg/model/api"
 )
@@ -24,8 +25,51 @@ import (
 // It is used in the context of user permissions to indicate that the permission applies to all projects.
 const WildcardProject = "*"
 
+// PasswordResetToken is the one-time token generated when an admin resets the password of a user.
+// Only the hash of the token is stored.
+type PasswordResetToken struct {
+	Hash string `json:"hash" yaml:"hash"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	ExpiresAt time.Time `json:"expiresAt" yaml:"expiresAt"`
+}
+
+// IsExpired returns true if the token cannot be used anymore.
+func (t *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// TOTP contains the time-based one-time password configuration of a user, used as a second authentication factor.
+type TOTP struct {
+	// Secret is the secret shared with the authenticator app of the user, encrypted with the encryption key of the server.
+	Secret string `json:"secret" yaml:"secret"`
+	// Enabled is false as long as the user didn't confirm the enrollment with a valid code.
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// RecoveryCodes contains the hashes of the recovery codes that haven't been used yet.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty" yaml:"recoveryCodes,omitempty"`
+	// LastUsedStep is the time step of the last code accepted. It is used to prevent a code from being used twice.
+	LastUsedStep int64 `json:"lastUsedStep,omitempty" yaml:"lastUsedStep,omitempty"`
+}
+
+// IsEnabled returns true if the user completed the enrollment.
+func (t *TOTP) IsEnabled() bool {
+	return t != nil && t.Enabled
+}
+
 type NativeProvider struct {
 	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	// PasswordHistory contains the hashes of the previous passwords, the most recent first.
+	// It is managed by the server and used to prevent the reuse of a previous password.
+	PasswordHistory []string `json:"passwordHistory,omitempty" yaml:"passwordHistory,omitempty"`
+	// MustChangePassword forces the user to change the password before being able to log in again.
+	MustChangePassword bool `json:"mustChangePassword,omitempty" yaml:"mustChangePassword,omitempty"`
+	// ResetToken is set when an admin resets the password of the user.
+	// It is managed by the server.
+	ResetToken *PasswordResetToken `json:"resetToken,omitempty" yaml:"resetToken,omitempty"`
+	// TOTP is set when the user enrolls an authenticator app for the two-factor authentication.
+	// It is managed by the server.
+	TOTP *TOTP `json:"totp,omitempty" yaml:"totp,omitempty"`
 }
 
 type OAuthProvider struct {
@@ -39,6 +83,32 @@ type UserSpec struct {
 	LastName       string          `json:"lastName,omitempty" yaml:"lastName,omitempty"`
 	NativeProvider NativeProvider  `json:"nativeProvider,omitempty" yaml:"nativeProvider,omitempty"`
 	OauthProviders []OAuthProvider `json:"oauthProviders,omitempty" yaml:"oauthProviders,omitempty"`
+	// Disabled prevents the user from logging in and removes all the permissions of the user.
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// DisabledReason explains why the user has been suspended.
+	DisabledReason string `json:"disabledReason,omitempty" yaml:"disabledReason,omitempty"`
+	// DisabledAt is the time when the user has been suspended. It is managed by the server.
+	DisabledAt *time.Time `json:"disabledAt,omitempty" yaml:"disabledAt,omitempty"`
+}
+
+// Suspend disables the user. The time of the suspension is kept if the user was already disabled,
+// and the previous reason is kept if no reason is provided.
+func (u *UserSpec) Suspend(reason string) {
+	if !u.Disabled || u.DisabledAt == nil {
+		now := time.Now().UTC()
+		u.DisabledAt = &now
+	}
+	u.Disabled = true
+	if len(reason) > 0 {
+		u.DisabledReason = reason
+	}
+}
+
+// Resume enables the user and removes the details of the suspension.
+func (u *UserSpec) Resume() {
+	u.Disabled = false
+	u.DisabledReason = ""
+	u.DisabledAt = nil
 }
 
 type User struct {
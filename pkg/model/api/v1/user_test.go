@@ -0,0 +1,37 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSpec_SuspendResume(t *testing.T) {
+	spec := UserSpec{}
+	spec.Suspend("left the company")
+	assert.True(t, spec.Disabled)
+	assert.Equal(t, "left the company", spec.DisabledReason)
+	assert.NotNil(t, spec.DisabledAt)
+
+	// Suspending an already suspended user keeps the time of the first suspension.
+	disabledAt := *spec.DisabledAt
+	spec.Suspend("")
+	assert.Equal(t, disabledAt, *spec.DisabledAt)
+	assert.Equal(t, "left the company", spec.DisabledReason)
+
+	spec.Resume()
+	assert.Equal(t, UserSpec{}, spec)
+}
@@ -32,6 +32,7 @@ const MemoizedColumnHeaders = memo(GridColumnHeaders);
 export interface Row extends CommonRow {
   nativeProvider: boolean;
   oauthProviders: boolean;
+  disabled: boolean;
 }
 
 function NoUserRowOverlay(): ReactElement {
@@ -59,6 +59,7 @@ export function UserList(props: ListPropertiesWithCallbacks<UserResource>): Reac
           name: user.metadata.name,
           nativeProvider: !!user.spec.nativeProvider?.password,
           oauthProviders: !!user.spec.oauthProviders?.length,
+          disabled: !!user.spec.disabled,
           version: user.metadata.version,
           createdAt: user.metadata.createdAt,
           updatedAt: user.metadata.updatedAt,
@@ -137,6 +138,13 @@ export function UserList(props: ListPropertiesWithCallbacks<UserResource>): Reac
         flex: 3,
         minWidth: 150,
       },
+      {
+        field: 'disabled',
+        headerName: 'Disabled',
+        type: 'boolean',
+        flex: 2,
+        minWidth: 100,
+      },
       VERSION_COL_DEF,
       CREATED_AT_COL_DEF,
       UPDATED_AT_COL_DEF,
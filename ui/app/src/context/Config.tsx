@@ -174,5 +174,9 @@ export function useIsExternalAuthnProviderEnabled(): boolean {
 
 export function useIsDelegatedAuthnProviderEnabled(): boolean {
   const { config } = useConfigContext();
-  return !!config.security.authentication.providers.kubernetes?.enable;
+  const providers = config.security.authentication.providers;
+  // The header provider is only considered as delegated when there is no other way to log in.
+  const isHeaderOnly =
+    !!providers.header?.enable && !providers.enable_native && !providers.oidc?.length && !providers.oauth?.length;
+  return !!providers.kubernetes?.enable || isHeaderOnly;
 }
@@ -82,6 +82,19 @@ export interface Database {
 export interface ProvisioningConfig {
   interval: string;
   folders: string[];
+  prune?: boolean;
+  edit_policy?: 'reject' | 'warn' | 'allow';
+  git?: GitProvisioningConfig[];
+}
+
+export interface GitProvisioningConfig {
+  name: string;
+  url: string;
+  branch?: string;
+  tag?: string;
+  path?: string;
+  secret?: string;
+  interval?: string;
 }
 
 export interface AuthorizationConfig {
@@ -142,12 +155,24 @@ export interface AuthProviders {
   oauth: OauthProvider[];
   oidc: OIDCProvider[];
   kubernetes?: KubernetesProvider;
+  header?: HeaderProvider;
 }
 
 interface KubernetesProvider {
   enable: boolean;
 }
 
+interface HeaderProvider {
+  enable: boolean;
+  // remaining fields needed for backend configuration only
+  // ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+  // user_header?: string
+  // email_header?: string
+  // groups_header?: string
+  // groups_separator?: string
+  // trusted_cidrs: string[]
+}
+
 export interface AuthenticationConfig {
   access_token_ttl: string;
   refresh_token_ttl: string;
@@ -21,7 +21,9 @@ export type Kind =
   | 'GlobalRoleBinding'
   | 'GlobalSecret'
   | 'GlobalVariable'
+  | 'Group'
   | 'Project'
+  | 'ProjectTemplate'
   | 'Role'
   | 'RoleBinding'
   | 'Secret'
@@ -38,7 +40,9 @@ export const KINDS: Kind[] = [
   'GlobalRoleBinding',
   'GlobalSecret',
   'GlobalVariable',
+  'Group',
   'Project',
+  'ProjectTemplate',
   'Role',
   'RoleBinding',
   'Secret',
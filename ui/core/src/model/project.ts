@@ -20,6 +20,13 @@ export interface ProjectResource {
   spec?: ProjectSpec;
 }
 
+export interface ProjectTemplateReference {
+  name: string;
+  parameters?: Record<string, string>;
+  owner?: string;
+}
+
 export interface ProjectSpec {
   display?: Display;
+  template?: ProjectTemplateReference;
 }
@@ -23,6 +23,15 @@ export interface Metadata {
   updatedAt?: string;
   version?: number;
   tags?: string[];
+  provisioning?: ProvisioningSource;
+}
+
+export interface ProvisioningSource {
+  file: string;
+  hash: string;
+  repository?: string;
+  commit?: string;
+  modified?: boolean;
 }
 
 export interface ProjectMetadata extends Metadata {
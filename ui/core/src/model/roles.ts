@@ -36,6 +36,8 @@ export const GLOBAL_SCOPES = [
   'GlobalRoleBinding',
   'GlobalSecret',
   'GlobalVariable',
+  'Group',
+  'ProjectTemplate',
   'User',
 ];
 
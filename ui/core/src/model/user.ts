@@ -28,6 +28,9 @@ export interface UserSpec {
   lastName?: string;
   nativeProvider?: NativeProvider;
   oauthProviders?: OAuthProvider[];
+  disabled?: boolean;
+  disabledReason?: string;
+  disabledAt?: string;
 }
 
 export interface UserResource {
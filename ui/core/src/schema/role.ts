@@ -31,7 +31,9 @@ export const permissionSchema: z.ZodSchema<Permission> = z.object({
         'GlobalRoleBinding',
         'GlobalSecret',
         'GlobalVariable',
+        'Group',
         'Project',
+        'ProjectTemplate',
         'Role',
         'RoleBinding',
         'Secret',
@@ -31,6 +31,9 @@ export const userSpecSchema: z.ZodSchema<UserSpec> = z.object({
   lastName: z.string().optional(),
   nativeProvider: nativeProviderSchema.optional(),
   oauthProviders: z.array(oauthProvidersSchema).optional(),
+  disabled: z.boolean().optional(),
+  disabledReason: z.string().optional(),
+  disabledAt: z.string().optional(),
 });
 
 export const userSchema: z.ZodSchema<UserResource> = z.object({